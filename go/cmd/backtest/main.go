@@ -2,17 +2,22 @@
 package main
 
 import (
+	"context"
 	"encoding/json"
 	"flag"
 	"fmt"
+	"net/http"
 	"os"
+	"os/signal"
+	"strconv"
 	"strings"
+	"syscall"
 	"time"
 
 	botconfig "github.com/kasyap/delta-go/go/config"
 	"github.com/kasyap/delta-go/go/pkg/backtest"
 	"github.com/kasyap/delta-go/go/pkg/delta"
-	"github.com/kasyap/delta-go/go/pkg/features"
+	"github.com/kasyap/delta-go/go/pkg/risk"
 	"github.com/kasyap/delta-go/go/pkg/strategy"
 )
 
@@ -23,13 +28,61 @@ func main() {
 	endFlag := flag.String("end", "2025-01-01", "End date (YYYY-MM-DD)")
 	capitalFlag := flag.Float64("capital", 200, "Initial capital in USD")
 	leverageFlag := flag.Int("leverage", 10, "Leverage to use")
-	resolutionFlag := flag.String("resolution", "5m", "Candle resolution (1m, 5m, 15m, 1h)")
-	strategyFlag := flag.String("strategy", "all", "Strategy: scalper, funding, grid, all")
+	resolutionFlag := flag.String("resolution", "5m", "Candle resolution (1m, 5m, 15m, 30m, 1h, 2h, 4h, 6h, 1d, 7d, 30d)")
+	strategyFlag := flag.String("strategy", "all", "Strategy: scalper, funding, grid, breakout, all")
+	gridSpacingFlag := flag.String("grid-spacing", strategy.GridSpacingArithmetic, "Grid level spacing for -grid-sim: arithmetic or geometric")
+	gridTIFFlag := flag.String("grid-tif", backtest.TimeInForceGTC, "Time in force of -grid-sim limit orders: gtc (rest), ioc (partial fills) or fok (all-or-nothing)")
+	gridSimFlag := flag.Bool("grid-sim", false, "With -strategy grid, simulate every grid level as a resting limit order instead of boundary signals")
 	walkforwardFlag := flag.Bool("walkforward", false, "Enable walk-forward analysis")
+	wfMinTradesFlag := flag.Int("wf-min-trades", 5, "Walk-forward windows with fewer trades are left out of the stability score")
+	wfWarmupFlag := flag.Int("wf-warmup", 0, "Leading walk-forward windows left out of the stability score")
+	wfWeightFlag := flag.Bool("wf-weight-trades", false, "Weight walk-forward windows by trade count in the stability score")
+	wfMinCoverageFlag := flag.Float64("wf-min-coverage", 0, "Skip walk-forward test windows where any symbol has less than this fraction of expected candles (0 = off)")
 	jsonOutputFlag := flag.Bool("json", false, "Output results as JSON")
+	productsFlag := flag.String("products", "", "Contract spec overrides: SYMBOL=tickSize:contractValue,... (e.g. XRPUSD=0.0001:1)")
+	plotPointsFlag := flag.Int("plot-points", 0, "Downsample the JSON equity curve to at most N points (0 = full curve)")
+	latencyFlag := flag.Int("latency-ms", 50, "Signal-to-exchange latency; fills move into the bar by latency/bar duration (0 = fill at open)")
+	maxSlipFlag := flag.Float64("max-slippage-bps", 100, "Cap on modelled slippage per fill in bps of the bar mid (0 = uncapped)")
+	spreadFlag := flag.Float64("spread-bps", 0, "Assumed bid/ask spread in bps paid on every fill (0 = fill at mid)")
+	orderbookFlag := flag.String("orderbook", "", "Order book replay for OBI strategies and breakout confirmation: 'synthetic' (from candles) or path to a JSONL snapshot file")
+	allowReversalFlag := flag.Bool("allow-reversal", true, "Reverse a position on an opposite signal; when false it is only closed")
+	freezeWindowFlag := flag.Duration("freeze-window", 0, "Block new entries this long either side of funding times and -freeze-events (0 = off)")
+	freezeFundingFlag := flag.Bool("freeze-funding", true, "Apply -freeze-window around the 00:00/08:00/16:00 UTC funding times")
+	freezeEventsFlag := flag.String("freeze-events", "", "Comma-separated RFC3339 times of scheduled events to freeze entries around")
+	freezeFlattenFlag := flag.Bool("freeze-flatten", false, "Close open positions when a freeze window starts instead of holding them")
+	maxUnitsFlag := flag.Int("max-units", 1, "Units that may be open per symbol; same-direction signals add units up to this cap")
+	reversalCooldownFlag := flag.Duration("reversal-cooldown", 0, "With -allow-reversal=false, block new entries on a symbol this long after an opposite-signal close")
+	stopCooldownFlag := flag.Duration("stop-cooldown", 0, "Block new entries on a symbol this long after a stop-loss exit (e.g. 30m, 0 = off)")
+	strictFlag := flag.Bool("strict-no-lookahead", false, "Debug: feed strategies only closed bars and fail on any fill at or before its signal bar")
+	tradeContextFlag := flag.Bool("trade-context", false, "Record indicators, features and regime at entry on each trade (JSON output)")
+	fillGapsFlag := flag.Bool("fill-gaps", false, "Forward-fill missing candles with flat bars at the previous close")
+	maxGapFlag := flag.Int("max-gap-bars", 0, "Fail when a symbol is missing more than N consecutive bars (0 = no limit)")
+	seedFlag := flag.Int64("seed", 0, "Seed for synthetic funding rates; equal seeds reproduce a run exactly")
 	cacheDirFlag := flag.String("cache", ".backtest_cache", "Directory for cached data")
+	optimizeFlag := flag.String("optimize", "", "Path to JSON parameter grid; runs a grid search instead of a single backtest")
+	objectiveFlag := flag.String("objective", "sharpe", "Optimization objective: sharpe, calmar, return")
+	topFlag := flag.Int("top", 10, "Number of best parameter combinations to report")
+	workersFlag := flag.Int("workers", 0, "Parallel backtests for -optimize (0 = NumCPU)")
+	feeTiersFlag := flag.String("fee-tiers", "", "Volume fee tiers: minNotional:makerBps:takerBps,... (e.g. 1000000:1.5:4,5000000:1:3)")
+	feeSensitivityFlag := flag.String("fee-sensitivity", "", "Comma-separated fee/slippage multipliers to compare (e.g. 0.5,1,2)")
+	reconcileFlag := flag.String("reconcile", "", "Path to a JSONL live fill log; compares realized fees/slippage with the backtest cost model")
+	paramsFlag := flag.String("params", "", "Path to a JSON file of strategy parameters, e.g. {\"scalper\": {\"imbalance_threshold\": 0.6}}")
+	serveFlag := flag.String("serve", "", "Serve strategy signals over HTTP on this address (e.g. :8090) instead of backtesting")
 	flag.Parse()
 
+	if *serveFlag != "" {
+		fmt.Printf("Strategy server listening on %s (POST /analyze-features)\n", *serveFlag)
+		if err := http.ListenAndServe(*serveFlag, newStrategyServer().Handler()); err != nil {
+			fmt.Printf("Strategy server failed: %v\n", err)
+			os.Exit(1)
+		}
+		return
+	}
+
+	// Ctrl-C aborts data fetching and pending runs instead of waiting for them to finish
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
 	// Parse dates
 	start, err := time.Parse("2006-01-02", *startFlag)
 	if err != nil {
@@ -48,6 +101,54 @@ func main() {
 		symbols[i] = strings.TrimSpace(symbols[i])
 	}
 
+	// Register user-supplied contract specs before building the Products map
+	if err := registerProductOverrides(*productsFlag); err != nil {
+		fmt.Printf("Error parsing -products: %v\n", err)
+		os.Exit(1)
+	}
+
+	if *gridSpacingFlag != strategy.GridSpacingArithmetic && *gridSpacingFlag != strategy.GridSpacingGeometric {
+		fmt.Printf("Invalid -grid-spacing %q: use arithmetic or geometric\n", *gridSpacingFlag)
+		os.Exit(1)
+	}
+	switch *gridTIFFlag {
+	case backtest.TimeInForceGTC, backtest.TimeInForceIOC, backtest.TimeInForceFOK:
+	default:
+		fmt.Printf("Invalid -grid-tif %q: use gtc, ioc or fok\n", *gridTIFFlag)
+		os.Exit(1)
+	}
+
+	resolution, err := delta.NormalizeResolution(*resolutionFlag)
+	if err != nil {
+		fmt.Printf("Invalid -resolution: %v\n", err)
+		os.Exit(1)
+	}
+
+	freezeEvents, err := risk.ParseEventTimes(*freezeEventsFlag)
+	if err != nil {
+		fmt.Printf("Invalid -freeze-events: %v\n", err)
+		os.Exit(1)
+	}
+
+	feeTiers, err := parseFeeTiers(*feeTiersFlag)
+	if err != nil {
+		fmt.Printf("Invalid -fee-tiers: %v\n", err)
+		os.Exit(1)
+	}
+
+	var params strategyParams
+	if *paramsFlag != "" {
+		if *gridSimFlag {
+			fmt.Println("Error: -params does not apply to -grid-sim, which builds its grid from -grid-spacing")
+			os.Exit(1)
+		}
+		params, err = loadStrategyParams(*paramsFlag)
+		if err != nil {
+			fmt.Printf("Error loading -params: %v\n", err)
+			os.Exit(1)
+		}
+	}
+
 	// Initialize Products map for contract value conversions
 	products := make(map[string]*delta.Product)
 	for _, sym := range symbols {
@@ -56,19 +157,47 @@ func main() {
 
 	// Create backtest config
 	btConfig := backtest.Config{
-		StartTime:       start,
-		EndTime:         end,
-		Symbols:         symbols,
-		Resolution:      *resolutionFlag,
-		InitialCapital:  *capitalFlag,
-		Leverage:        *leverageFlag,
-		MakerFeeBps:     2.0,
-		TakerFeeBps:     5.0,
-		SlippageModel:   backtest.NewVolatilitySlippage(1.5, 0.5),
-		LatencyMs:       50,
-		SimulateFunding: true,
-		DataCacheDir:    *cacheDirFlag,
-		Products:        products,
+		StartTime:         start,
+		EndTime:           end,
+		Symbols:           symbols,
+		Resolution:        resolution,
+		InitialCapital:    *capitalFlag,
+		Leverage:          *leverageFlag,
+		MakerFeeBps:       2.0,
+		TakerFeeBps:       5.0,
+		FeeTiers:          feeTiers,
+		SlippageModel:     backtest.NewVolatilitySlippage(1.5, 0.5),
+		MaxSlippageBps:    *maxSlipFlag,
+		AssumedSpreadBps:  *spreadFlag,
+		LatencyMs:         *latencyFlag,
+		SimulateFunding:   true,
+		Seed:              *seedFlag,
+		DataCacheDir:      *cacheDirFlag,
+		StopCooldown:      *stopCooldownFlag,
+		AllowReversal:     *allowReversalFlag,
+		ReversalCooldown:  *reversalCooldownFlag,
+		MaxUnitsPerSymbol: *maxUnitsFlag,
+		EventFreeze: risk.EventFreeze{
+			Window:  *freezeWindowFlag,
+			Funding: *freezeFundingFlag,
+			Events:  freezeEvents,
+		},
+		FlattenOnEventFreeze: *freezeFlattenFlag,
+		GridTimeInForce:      *gridTIFFlag,
+		StrictNoLookahead:    *strictFlag,
+		RecordTradeContext:   *tradeContextFlag,
+		FillGaps:             *fillGapsFlag,
+		MaxGapBars:           *maxGapFlag,
+		Products:             products,
+	}
+
+	if *orderbookFlag != "" {
+		source, err := loadOrderbookSource(*orderbookFlag, *spreadFlag)
+		if err != nil {
+			fmt.Printf("Error loading -orderbook: %v\n", err)
+			os.Exit(1)
+		}
+		btConfig.OrderbookSource = source
 	}
 
 	// Create Delta client (for data fetching - using default config)
@@ -78,22 +207,134 @@ func main() {
 	// Create engine factory
 	engineFactory := func(cfg backtest.Config) *backtest.Engine {
 		engine := backtest.NewEngine(cfg, client)
-		registerStrategies(engine, *strategyFlag)
+		if _, err := registerStrategies(engine, *strategyFlag, params); err != nil {
+			fmt.Printf("Error: %v\n", err)
+			os.Exit(1)
+		}
 		return engine
 	}
 
-	if *walkforwardFlag {
+	// Catch a bad -strategy or -params before any data is fetched
+	if _, err := registerStrategies(backtest.NewEngine(btConfig, client), *strategyFlag, params); err != nil {
+		fmt.Printf("Error: %v\n", err)
+		os.Exit(1)
+	}
+
+	if *reconcileFlag != "" {
+		// Cost model validation against live fills, no backtest run needed
+		fills, err := backtest.LoadLiveFills(*reconcileFlag)
+		if err != nil {
+			fmt.Printf("Error loading live fills: %v\n", err)
+			os.Exit(1)
+		}
+		report := backtest.Reconcile(btConfig, fills)
+
+		if *jsonOutputFlag {
+			outputJSON(report)
+		} else {
+			fmt.Println(report.FormatReport())
+		}
+	} else if *optimizeFlag != "" {
+		// Grid search over strategy parameters
+		grid, err := backtest.LoadParamGrid(*optimizeFlag)
+		if err != nil {
+			fmt.Printf("Error loading parameter grid: %v\n", err)
+			os.Exit(1)
+		}
+		objective, err := backtest.ParseObjective(*objectiveFlag)
+		if err != nil {
+			fmt.Printf("Error: %v\n", err)
+			os.Exit(1)
+		}
+
+		optConfig := backtest.OptimizeConfig{
+			Objective: objective,
+			TopN:      *topFlag,
+			Workers:   *workersFlag,
+		}
+		report, err := backtest.NewOptimizer(btConfig, optConfig, engineFactory).RunContext(ctx, grid)
+		if err != nil {
+			fmt.Printf("Optimization failed: %v\n", err)
+			os.Exit(1)
+		}
+
+		if *jsonOutputFlag {
+			outputJSON(report)
+		} else {
+			fmt.Println(report.FormatReport())
+		}
+	} else if *feeSensitivityFlag != "" {
+		// Re-run the same backtest at scaled cost assumptions
+		mults, err := parseMultipliers(*feeSensitivityFlag)
+		if err != nil {
+			fmt.Printf("Error parsing -fee-sensitivity: %v\n", err)
+			os.Exit(1)
+		}
+
+		engine := engineFactory(btConfig)
+		results, err := engine.RunFeeSensitivityContext(ctx, mults)
+		if err != nil {
+			fmt.Printf("Fee sensitivity failed: %v\n", err)
+			os.Exit(1)
+		}
+
+		if *jsonOutputFlag {
+			byLabel := make(map[string]backtest.Metrics, len(results))
+			for m, metrics := range results {
+				metrics.EquityCurve = metrics.DownsampleEquity(*plotPointsFlag)
+				byLabel[strconv.FormatFloat(m, 'f', -1, 64)+"x"] = metrics
+			}
+			outputJSON(byLabel)
+		} else {
+			fmt.Println(backtest.FormatFeeSensitivity(results))
+		}
+	} else if *gridSimFlag && *strategyFlag == "grid" {
+		// Resting-order grid simulation, one grid per symbol
+		engine := engineFactory(btConfig)
+		gridConfig := strategy.DefaultGridConfig()
+		gridConfig.GridSpacing = *gridSpacingFlag
+		results := make(map[string]*backtest.GridResult, len(symbols))
+		for _, symbol := range symbols {
+			result, err := engine.RunGridContext(ctx, symbol, gridConfig)
+			if err != nil {
+				fmt.Printf("Grid simulation failed for %s: %v\n", symbol, err)
+				os.Exit(1)
+			}
+			results[symbol] = result
+		}
+
+		if *jsonOutputFlag {
+			for _, result := range results {
+				result.Metrics.EquityCurve = result.Metrics.DownsampleEquity(*plotPointsFlag)
+			}
+			outputJSON(results)
+		} else {
+			for _, symbol := range symbols {
+				fmt.Printf("\n=== %s ===\n", symbol)
+				fmt.Println(results[symbol].FormatReport())
+			}
+		}
+	} else if *walkforwardFlag {
 		// Walk-forward analysis
 		wfConfig := backtest.DefaultWalkForwardConfig()
+		wfConfig.MinTradesPerWindow = *wfMinTradesFlag
+		wfConfig.WarmupWindows = *wfWarmupFlag
+		wfConfig.WeightByTrades = *wfWeightFlag
+		wfConfig.MinCoverage = *wfMinCoverageFlag
 		analyzer := backtest.NewWalkForwardAnalyzer(btConfig, wfConfig, engineFactory)
 
-		result, err := analyzer.Run()
+		result, err := analyzer.RunContext(ctx)
 		if err != nil {
 			fmt.Printf("Walk-forward analysis failed: %v\n", err)
 			os.Exit(1)
 		}
 
 		if *jsonOutputFlag {
+			result.Combined.EquityCurve = result.Combined.DownsampleEquity(*plotPointsFlag)
+			for i := range result.Windows {
+				m := &result.Windows[i].TestMetrics
+				m.EquityCurve = m.DownsampleEquity(*plotPointsFlag)
+			}
 			outputJSON(result)
 		} else {
 			fmt.Println(result.Summary)
@@ -101,13 +342,14 @@ func main() {
 	} else {
 		// Single backtest
 		engine := engineFactory(btConfig)
-		result, err := engine.Run()
+		result, err := engine.RunContext(ctx)
 		if err != nil {
 			fmt.Printf("Backtest failed: %v\n", err)
 			os.Exit(1)
 		}
 
 		if *jsonOutputFlag {
+			result.Metrics.EquityCurve = result.Metrics.DownsampleEquity(*plotPointsFlag)
 			outputJSON(result)
 		} else {
 			fmt.Println(result.Metrics.FormatReport())
@@ -115,36 +357,129 @@ func main() {
 	}
 }
 
-// registerStrategies adds strategies to the engine based on flag
-func registerStrategies(engine *backtest.Engine, strategyType string) {
-	featuresEngine := features.NewEngine()
+// registerStrategies adds strategies to the engine based on flag and applies the -params
+// overrides to them. It returns the strategies keyed by -strategy name.
+func registerStrategies(engine *backtest.Engine, strategyType string, params strategyParams) (map[string]strategy.Strategy, error) {
+	// Share the backtest's features engine so the scalper sees replayed OBI snapshots
+	featuresEngine := engine.FeaturesEngine()
 
+	strategies := make(map[string]strategy.Strategy)
 	switch strategyType {
 	case "scalper":
 		scalper := strategy.NewFeeAwareScalper(strategy.DefaultScalperConfig(), featuresEngine)
 		engine.RegisterStrategy(scalper)
+		strategies["scalper"] = scalper
 
 	case "funding":
 		funding := strategy.NewFundingArbitrageStrategy(strategy.DefaultFundingArbitrageConfig())
 		engine.RegisterStrategy(funding)
+		strategies["funding"] = funding
 
 	case "grid":
 		grid := strategy.NewGridTradingStrategy(strategy.DefaultGridConfig(), "BTCUSD") // Default symbol
 		engine.RegisterStrategy(grid)
+		strategies["grid"] = grid
+
+	case "breakout":
+		breakout := strategy.NewHighVolBreakoutStrategy(strategy.DefaultBreakoutConfig())
+		engine.RegisterStrategy(breakout)
+		strategies["breakout"] = breakout
 
 	case "all":
-		// Register StrategySelector which combines all three
+		// Register StrategySelector which combines all four
 		scalper := strategy.NewFeeAwareScalper(strategy.DefaultScalperConfig(), featuresEngine)
 		funding := strategy.NewFundingArbitrageStrategy(strategy.DefaultFundingArbitrageConfig())
 		grid := strategy.NewGridTradingStrategy(strategy.DefaultGridConfig(), "BTCUSD")
+		breakout := strategy.NewHighVolBreakoutStrategy(strategy.DefaultBreakoutConfig())
 
-		selector := strategy.NewStrategySelector(scalper, funding, grid)
+		selector := strategy.NewStrategySelector(scalper, funding, grid, breakout)
 		engine.RegisterStrategy(selector)
+		strategies["scalper"], strategies["funding"], strategies["grid"], strategies["breakout"] = scalper, funding, grid, breakout
 
 	default:
-		fmt.Printf("Unknown strategy: %s\n", strategyType)
-		os.Exit(1)
+		return nil, fmt.Errorf("unknown strategy: %s", strategyType)
+	}
+
+	return strategies, params.apply(strategies)
+}
+
+// defaultSyntheticSpreadBps is used for synthetic books when no -spread-bps is given
+const defaultSyntheticSpreadBps = 2.0
+
+// loadOrderbookSource builds the -orderbook replay source
+func loadOrderbookSource(spec string, spreadBps float64) (backtest.OrderbookSource, error) {
+	if spec == "synthetic" {
+		if spreadBps <= 0 {
+			spreadBps = defaultSyntheticSpreadBps
+		}
+		return backtest.NewSyntheticOrderbook(spreadBps), nil
+	}
+	return backtest.LoadRecordedOrderbook(spec)
+}
+
+// registerProductOverrides parses "SYMBOL=tickSize:contractValue" pairs into delta overrides
+func registerProductOverrides(spec string) error {
+	for _, entry := range strings.Split(spec, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		symbol, values, ok := strings.Cut(entry, "=")
+		if !ok {
+			return fmt.Errorf("invalid override %q (want SYMBOL=tickSize:contractValue)", entry)
+		}
+		tickSize, contractValue, ok := strings.Cut(values, ":")
+		if !ok {
+			return fmt.Errorf("invalid override %q (want SYMBOL=tickSize:contractValue)", entry)
+		}
+		delta.RegisterProductOverride(strings.TrimSpace(symbol), strings.TrimSpace(tickSize), strings.TrimSpace(contractValue))
+	}
+	return nil
+}
+
+// parseMultipliers parses a comma-separated list of positive multipliers
+func parseMultipliers(spec string) ([]float64, error) {
+	var mults []float64
+	for _, field := range strings.Split(spec, ",") {
+		field = strings.TrimSpace(field)
+		if field == "" {
+			continue
+		}
+		m, err := strconv.ParseFloat(field, 64)
+		if err != nil || m < 0 {
+			return nil, fmt.Errorf("invalid multiplier %q", field)
+		}
+		mults = append(mults, m)
+	}
+	if len(mults) == 0 {
+		return nil, fmt.Errorf("no multipliers given")
+	}
+	return mults, nil
+}
+
+// parseFeeTiers parses "minNotional:makerBps:takerBps" volume tiers
+func parseFeeTiers(spec string) ([]backtest.FeeTier, error) {
+	var tiers []backtest.FeeTier
+	for _, entry := range strings.Split(spec, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		fields := strings.Split(entry, ":")
+		if len(fields) != 3 {
+			return nil, fmt.Errorf("invalid tier %q (want minNotional:makerBps:takerBps)", entry)
+		}
+		var values [3]float64
+		for i, f := range fields {
+			v, err := strconv.ParseFloat(strings.TrimSpace(f), 64)
+			if err != nil || v < 0 {
+				return nil, fmt.Errorf("invalid tier %q (want minNotional:makerBps:takerBps)", entry)
+			}
+			values[i] = v
+		}
+		tiers = append(tiers, backtest.FeeTier{MinVolume: values[0], MakerFeeBps: values[1], TakerFeeBps: values[2]})
 	}
+	return tiers, nil
 }
 
 func outputJSON(data interface{}) {
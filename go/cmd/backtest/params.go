@@ -0,0 +1,63 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/kasyap/delta-go/go/pkg/strategy"
+)
+
+// strategyParams maps a -strategy name (scalper, funding, grid) to the UpdateParams
+// overrides for that strategy, e.g. {"scalper": {"imbalance_threshold": 0.6}}
+type strategyParams map[string]map[string]interface{}
+
+// loadStrategyParams reads a -params file
+func loadStrategyParams(path string) (strategyParams, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read params file: %w", err)
+	}
+	var params strategyParams
+	if err := json.Unmarshal(data, &params); err != nil {
+		return nil, fmt.Errorf("failed to parse params file: %w", err)
+	}
+	return params, nil
+}
+
+// apply validates params against the strategies of the run, keyed by -strategy name, and
+// passes each its own overrides. Strategies outside the run and keys a strategy doesn't
+// read are errors rather than silently ignored.
+func (p strategyParams) apply(strategies map[string]strategy.Strategy) error {
+	for _, name := range sortedKeys(p) {
+		s, ok := strategies[name]
+		if !ok {
+			return fmt.Errorf("params for strategy %q, which this run does not include (have %s)",
+				name, strings.Join(sortedKeys(strategies), ", "))
+		}
+		if keyer, ok := s.(strategy.ParamKeyer); ok {
+			known := make(map[string]bool)
+			for _, k := range keyer.ParamKeys() {
+				known[k] = true
+			}
+			for _, k := range sortedKeys(p[name]) {
+				if !known[k] {
+					return fmt.Errorf("unknown %s param %q (known: %s)", name, k, strings.Join(keyer.ParamKeys(), ", "))
+				}
+			}
+		}
+		s.UpdateParams(p[name])
+	}
+	return nil
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
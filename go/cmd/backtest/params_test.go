@@ -0,0 +1,90 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/kasyap/delta-go/go/pkg/backtest"
+	"github.com/kasyap/delta-go/go/pkg/strategy"
+)
+
+func writeParamsFile(t *testing.T, body string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "params.json")
+	if err := os.WriteFile(path, []byte(body), 0o644); err != nil {
+		t.Fatalf("write params: %v", err)
+	}
+	return path
+}
+
+func TestRegisterStrategies_AppliesParamsFile(t *testing.T) {
+	params, err := loadStrategyParams(writeParamsFile(t, `{"scalper": {"imbalance_threshold": 0.8, "persistence_snapshots": 7}}`))
+	if err != nil {
+		t.Fatalf("loadStrategyParams() error = %v", err)
+	}
+
+	strategies, err := registerStrategies(backtest.NewEngine(backtest.Config{}, nil), "scalper", params)
+	if err != nil {
+		t.Fatalf("registerStrategies() error = %v", err)
+	}
+	scalper, ok := strategies["scalper"].(*strategy.FeeAwareScalper)
+	if !ok {
+		t.Fatalf("strategies = %v, want a scalper", strategies)
+	}
+	cfg := scalper.Config()
+	if cfg.ImbalanceThreshold != 0.8 || cfg.PersistenceSnapshots != 7 {
+		t.Errorf("scalper config = threshold %.2f, persistence %d; want 0.80, 7", cfg.ImbalanceThreshold, cfg.PersistenceSnapshots)
+	}
+	if def := strategy.DefaultScalperConfig(); cfg.TargetProfitBps != def.TargetProfitBps {
+		t.Errorf("target_profit_bps = %.1f, want default %.1f left untouched", cfg.TargetProfitBps, def.TargetProfitBps)
+	}
+}
+
+func TestRegisterStrategies_Breakout(t *testing.T) {
+	params, err := loadStrategyParams(writeParamsFile(t, `{"breakout": {"lookback": 30}}`))
+	if err != nil {
+		t.Fatalf("loadStrategyParams() error = %v", err)
+	}
+
+	for _, name := range []string{"breakout", "all"} {
+		strategies, err := registerStrategies(backtest.NewEngine(backtest.Config{}, nil), name, params)
+		if err != nil {
+			t.Fatalf("registerStrategies(%s) error = %v", name, err)
+		}
+		breakout, ok := strategies["breakout"].(*strategy.HighVolBreakoutStrategy)
+		if !ok {
+			t.Fatalf("registerStrategies(%s) = %v, want a breakout", name, strategies)
+		}
+		if got := breakout.Config().Lookback; got != 30 {
+			t.Errorf("registerStrategies(%s) breakout lookback = %d, want 30", name, got)
+		}
+	}
+}
+
+func TestRegisterStrategies_RejectsBadParams(t *testing.T) {
+	for _, tc := range []struct {
+		name, strategy, body, want string
+	}{
+		{"unknown param", "scalper", `{"scalper": {"imbalance_treshold": 0.8}}`, `unknown scalper param "imbalance_treshold"`},
+		{"unknown strategy", "all", `{"momentum": {"lookback": 20}}`, `params for strategy "momentum"`},
+		{"strategy not in run", "scalper", `{"grid": {"grid_levels": 8}}`, `params for strategy "grid"`},
+	} {
+		params, err := loadStrategyParams(writeParamsFile(t, tc.body))
+		if err != nil {
+			t.Fatalf("%s: loadStrategyParams() error = %v", tc.name, err)
+		}
+		_, err = registerStrategies(backtest.NewEngine(backtest.Config{}, nil), tc.strategy, params)
+		if err == nil || !strings.Contains(err.Error(), tc.want) {
+			t.Errorf("%s: error = %v, want it to contain %q", tc.name, err, tc.want)
+		}
+	}
+
+	if _, err := registerStrategies(backtest.NewEngine(backtest.Config{}, nil), "momentum", nil); err == nil {
+		t.Error("expected an error for an unknown -strategy")
+	}
+	if _, err := loadStrategyParams(writeParamsFile(t, `{"scalper": [1, 2]}`)); err == nil {
+		t.Error("expected a parse error for a malformed params file")
+	}
+}
@@ -0,0 +1,99 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sync"
+
+	"github.com/kasyap/delta-go/go/pkg/delta"
+	"github.com/kasyap/delta-go/go/pkg/features"
+	"github.com/kasyap/delta-go/go/pkg/strategy"
+)
+
+// analyzeFeaturesRequest is the /analyze-features body: market state for one symbol
+type analyzeFeaturesRequest struct {
+	Orderbook *delta.Orderbook `json:"orderbook"`
+	Ticker    *delta.Ticker    `json:"ticker"`
+	Candles   []delta.Candle   `json:"candles"`
+}
+
+// analyzeFeaturesResponse holds the computed features and each strategy's signal
+type analyzeFeaturesResponse struct {
+	Features features.MarketFeatures    `json:"features"`
+	Signals  map[string]strategy.Signal `json:"signals"`
+}
+
+// symbolStrategies is the per-symbol state of the feature-based strategies. The features
+// engine keeps OBI history across requests, which the scalper's persistence check reads.
+type symbolStrategies struct {
+	features   *features.Engine
+	strategies []strategy.Strategy
+}
+
+// strategyServer serves signals from the feature-based strategies (scalper, funding
+// arbitrage, grid) over HTTP
+type strategyServer struct {
+	mu      sync.Mutex
+	symbols map[string]*symbolStrategies
+}
+
+func newStrategyServer() *strategyServer {
+	return &strategyServer{symbols: make(map[string]*symbolStrategies)}
+}
+
+// Handler returns the server's routes
+func (s *strategyServer) Handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/analyze-features", s.handleAnalyzeFeatures)
+	return mux
+}
+
+// strategiesFor returns the symbol's strategies, creating them on first use. Callers hold s.mu.
+func (s *strategyServer) strategiesFor(symbol string) *symbolStrategies {
+	if st, ok := s.symbols[symbol]; ok {
+		return st
+	}
+	fe := features.NewEngine()
+	st := &symbolStrategies{
+		features: fe,
+		strategies: []strategy.Strategy{
+			strategy.NewFeeAwareScalper(strategy.DefaultScalperConfig(), fe),
+			strategy.NewFundingArbitrageStrategy(strategy.DefaultFundingArbitrageConfig()),
+			strategy.NewGridTradingStrategy(strategy.DefaultGridConfig(), symbol),
+		},
+	}
+	s.symbols[symbol] = st
+	return st
+}
+
+func (s *strategyServer) handleAnalyzeFeatures(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	var req analyzeFeaturesRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, fmt.Sprintf("invalid request: %v", err), http.StatusBadRequest)
+		return
+	}
+	if req.Ticker == nil || req.Ticker.Symbol == "" {
+		http.Error(w, "ticker with symbol is required", http.StatusBadRequest)
+		return
+	}
+
+	s.mu.Lock()
+	st := s.strategiesFor(req.Ticker.Symbol)
+	f := st.features.ComputeFeaturesWithFunding(req.Orderbook, req.Ticker, req.Candles)
+	resp := analyzeFeaturesResponse{Features: f, Signals: make(map[string]strategy.Signal, len(st.strategies))}
+	for _, strat := range st.strategies {
+		resp.Signals[strat.Name()] = strat.Analyze(f, req.Candles)
+	}
+	s.mu.Unlock()
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(resp); err != nil {
+		fmt.Printf("Warning: failed to write /analyze-features response: %v\n", err)
+	}
+}
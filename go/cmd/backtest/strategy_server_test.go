@@ -0,0 +1,59 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/kasyap/delta-go/go/pkg/strategy"
+)
+
+func TestStrategyServer_AnalyzeFeaturesFundingSignal(t *testing.T) {
+	srv := httptest.NewServer(newStrategyServer().Handler())
+	defer srv.Close()
+
+	// 0.1% per 8h funding = ~110% annualized basis, well above the 15% entry threshold
+	body := []byte(`{
+		"ticker": {"symbol": "BTCUSD", "close": "50000", "mark_price": "50010", "funding_rate": "0.001"},
+		"orderbook": {
+			"symbol": "BTCUSD",
+			"buy":  [{"price": "49999", "size": 100}, {"price": "49998", "size": 80}],
+			"sell": [{"price": "50001", "size": 90}, {"price": "50002", "size": 70}]
+		},
+		"candles": []
+	}`)
+	resp, err := http.Post(srv.URL+"/analyze-features", "application/json", bytes.NewReader(body))
+	if err != nil {
+		t.Fatalf("post: %v", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("status = %d, want 200", resp.StatusCode)
+	}
+
+	var out analyzeFeaturesResponse
+	if err := json.NewDecoder(resp.Body).Decode(&out); err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	if out.Features.BestBid != 49999 || out.Features.BasisAnnualized < 1 {
+		t.Errorf("features not built from the request: %+v", out.Features)
+	}
+	sig, ok := out.Signals["funding_arbitrage"]
+	if !ok {
+		t.Fatalf("no funding_arbitrage signal in %v", out.Signals)
+	}
+	if sig.Action != strategy.ActionSell || sig.Side != "sell" {
+		t.Errorf("funding signal = %v/%s (%s), want a short on positive funding", sig.Action, sig.Side, sig.Reason)
+	}
+}
+
+func TestStrategyServer_RejectsMissingTicker(t *testing.T) {
+	rec := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodPost, "/analyze-features", bytes.NewReader([]byte(`{"candles": []}`)))
+	newStrategyServer().Handler().ServeHTTP(rec, req)
+	if rec.Code != http.StatusBadRequest {
+		t.Errorf("status = %d, want 400", rec.Code)
+	}
+}
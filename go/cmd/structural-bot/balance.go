@@ -0,0 +1,43 @@
+package main
+
+import (
+	"log/slog"
+	"time"
+
+	"github.com/kasyap/delta-go/go/pkg/delta"
+)
+
+// minBalanceRefresh spaces equity fetches on the wall clock. Live trading cycles are further
+// apart, so every cycle refreshes; a replay at full speed doesn't fetch on every bar.
+const minBalanceRefresh = time.Second
+
+// accountEquity returns the account's net equity, falling back to the available balance of
+// product's settling asset (USDT without a product) when Delta doesn't report net equity
+func (bot *StructuralBot) accountEquity(product *delta.Product) (float64, error) {
+	equity, err := bot.deltaClient.GetNetEquity()
+	if err == nil {
+		return equity, nil
+	}
+	settling := "USDT"
+	if product != nil && product.SettlingAsset.Symbol != "" {
+		settling = product.SettlingAsset.Symbol
+	}
+	return bot.deltaClient.GetAvailableBalance(settling)
+}
+
+// refreshRiskBalance feeds the latest equity to the risk manager each trading cycle, so the
+// equity floor, daily loss limit, profit lock, circuit breaker and drawdown leverage act on
+// it. A failed fetch keeps the previous balance.
+func (bot *StructuralBot) refreshRiskBalance() {
+	if time.Since(bot.lastBalanceRefresh) < minBalanceRefresh {
+		return
+	}
+	bot.lastBalanceRefresh = time.Now()
+
+	equity, err := bot.accountEquity(nil)
+	if err != nil {
+		slog.Warn("Failed to refresh equity for risk checks", "error", err)
+		return
+	}
+	bot.riskManager.UpdateBalance(equity)
+}
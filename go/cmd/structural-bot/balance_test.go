@@ -0,0 +1,52 @@
+package main
+
+import (
+	"testing"
+	"time"
+
+	"github.com/kasyap/delta-go/go/config"
+	"github.com/kasyap/delta-go/go/pkg/delta"
+	"github.com/kasyap/delta-go/go/pkg/features"
+	"github.com/kasyap/delta-go/go/pkg/strategy"
+)
+
+// entryBot builds a bot on cfg whose entry loop gets a scalp buy on BTCUSD each cycle
+func entryBot(t *testing.T, cfg *config.Config) (*StructuralBot, *fakeExchange) {
+	t.Helper()
+	x := newFakeExchange(delta.Product{ID: 27, Symbol: "BTCUSD", ContractValue: "0.001", TickSize: "0.5"})
+	cfg.Symbols = []string{"BTCUSD"}
+	cfg.ScalperEnabled = true
+	cfg.MaxPositionPct = 10
+	if cfg.Leverage == 0 {
+		cfg.Leverage = 10
+	}
+	bot := newFakeExchangeBot(t, x, cfg)
+
+	signal := strategy.Signal{Action: strategy.ActionBuy, Side: "buy", Price: 50000, StopLoss: 49500, TakeProfit: 51000, Confidence: 1}
+	bot.selector = &scriptedSelector{name: scalpStrategyName, signals: []strategy.Signal{signal, signal, signal}}
+	bot.lastFeatures["BTCUSD"] = features.MarketFeatures{Symbol: "BTCUSD", MarkPrice: 50000}
+	bot.candles["BTCUSD"] = make([]delta.Candle, minStrategyCandles)
+	return bot, x
+}
+
+// tradingCycle runs one trading cycle, refreshing equity however recently it was fetched
+func tradingCycle(bot *StructuralBot) {
+	bot.lastBalanceRefresh = time.Time{}
+	bot.evaluateAndTrade()
+}
+
+func TestEvaluateAndTrade_EquityFloorBlocksEntries(t *testing.T) {
+	bot, x := entryBot(t, &config.Config{MaxDrawdownPct: 50, DailyLossLimitPct: -50, MinEquityFloor: 5000})
+
+	x.setBalance("4000")
+	tradingCycle(bot)
+	if orders := x.placed(); len(orders) != 0 {
+		t.Fatalf("placed %d orders with equity below the floor, want none", len(orders))
+	}
+
+	x.setBalance("6000")
+	tradingCycle(bot)
+	if orders := x.placed(); len(orders) != 1 {
+		t.Errorf("placed %d orders back above the floor, want the entry", len(orders))
+	}
+}
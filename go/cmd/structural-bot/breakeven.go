@@ -0,0 +1,48 @@
+package main
+
+import (
+	"time"
+
+	"github.com/kasyap/delta-go/go/pkg/logger"
+)
+
+// shouldMoveToBreakeven reports whether a scalp open for age, out of its fee window, has
+// been held long enough (fraction of the window) to protect at breakeven, and price puts
+// it in profit. Positions whose stop is already at or past the entry are left alone.
+func shouldMoveToBreakeven(pos *ScalpPosition, price float64, age, feeWindow time.Duration, fraction float64) bool {
+	if fraction <= 0 || feeWindow <= 0 || pos.EntryPrice <= 0 || price <= 0 || pos.StopHit {
+		return false
+	}
+	if age < time.Duration(float64(feeWindow)*fraction) {
+		return false
+	}
+	if pos.Side == "sell" {
+		return price < pos.EntryPrice && (pos.StopLoss <= 0 || pos.StopLoss > pos.EntryPrice)
+	}
+	return price > pos.EntryPrice && (pos.StopLoss <= 0 || pos.StopLoss < pos.EntryPrice)
+}
+
+// checkBreakeven amends a scalp's bracket stop to its entry once it has outlived
+// ScalpBreakevenAfter of its fee window in profit without reaching the target
+func (bot *StructuralBot) checkBreakeven(pos *ScalpPosition, feeWindow time.Duration) {
+	bot.mu.RLock()
+	ticker := bot.lastTickers[pos.Symbol]
+	price := 0.0
+	if ticker != nil {
+		price = ticker.ReferencePrice(bot.cfg.ExitPriceSource)
+	}
+	move := shouldMoveToBreakeven(pos, price, bot.now().Sub(pos.EntryTime), feeWindow, bot.cfg.ScalpBreakevenAfter)
+	bot.mu.RUnlock()
+	if !move {
+		return
+	}
+
+	if err := bot.MoveStopToBreakeven(pos, pos.EntryPrice); err != nil {
+		logger.WithTrade(pos.Symbol, scalpStrategyName).Error("Failed to move stop to breakeven",
+			logger.KeyOrderID, pos.OrderID, "error", err)
+		return
+	}
+	bot.mu.Lock()
+	pos.StopLoss = pos.EntryPrice
+	bot.mu.Unlock()
+}
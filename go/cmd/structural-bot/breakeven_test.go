@@ -0,0 +1,97 @@
+package main
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/kasyap/delta-go/go/config"
+	"github.com/kasyap/delta-go/go/pkg/delta"
+)
+
+func TestShouldMoveToBreakeven(t *testing.T) {
+	window := 10 * time.Minute
+	long := func() *ScalpPosition {
+		return &ScalpPosition{Symbol: "BTCUSD", Side: "buy", EntryPrice: 50000, StopLoss: 49900}
+	}
+	short := func() *ScalpPosition {
+		return &ScalpPosition{Symbol: "BTCUSD", Side: "sell", EntryPrice: 50000, StopLoss: 50100}
+	}
+
+	for _, tc := range []struct {
+		name     string
+		pos      *ScalpPosition
+		price    float64
+		age      time.Duration
+		fraction float64
+		want     bool
+	}{
+		{"long in profit past the gate", long(), 50050, 6 * time.Minute, 0.5, true},
+		{"long in profit before the gate", long(), 50050, 4 * time.Minute, 0.5, false},
+		{"long at a loss past the gate", long(), 49950, 6 * time.Minute, 0.5, false},
+		{"long flat past the gate", long(), 50000, 6 * time.Minute, 0.5, false},
+		{"short in profit past the gate", short(), 49950, 6 * time.Minute, 0.5, true},
+		{"short at a loss past the gate", short(), 50050, 6 * time.Minute, 0.5, false},
+		{"disabled", long(), 50050, 9 * time.Minute, 0, false},
+		{"stop already at entry", &ScalpPosition{Side: "buy", EntryPrice: 50000, StopLoss: 50000}, 50050, 6 * time.Minute, 0.5, false},
+		{"stop already hit", &ScalpPosition{Side: "buy", EntryPrice: 50000, StopLoss: 49900, StopHit: true}, 50050, 6 * time.Minute, 0.5, false},
+	} {
+		if got := shouldMoveToBreakeven(tc.pos, tc.price, tc.age, window, tc.fraction); got != tc.want {
+			t.Errorf("%s: shouldMoveToBreakeven = %v, want %v", tc.name, got, tc.want)
+		}
+	}
+}
+
+func TestCheckBreakeven_AmendsStopOnlyInProfit(t *testing.T) {
+	edits := 0
+	bot := newInitTestBot(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodPut && r.URL.Path == "/v2/orders/bracket" {
+			edits++
+		}
+		w.Write([]byte(`{"success":true,"result":{}}`))
+	})
+	bot.cfg.ScalpBreakevenAfter = 0.5
+	bot.cfg.ExitPriceSource = delta.PriceSourceMark
+	bot.productCache["BTCUSD"] = &delta.Product{ID: 27, Symbol: "BTCUSD", TickSize: "0.5"}
+	pos := &ScalpPosition{Symbol: "BTCUSD", Side: "buy", EntryTime: time.Now().Add(-6 * time.Minute), EntryPrice: 50000, StopLoss: 49900, OrderID: 42}
+	bot.scalpPositions["BTCUSD"] = pos
+
+	bot.lastTickers["BTCUSD"] = &delta.Ticker{Symbol: "BTCUSD", MarkPrice: 49950}
+	bot.checkBreakeven(pos, 10*time.Minute)
+	if edits != 0 || pos.StopLoss != 49900 {
+		t.Fatalf("losing scalp: edits = %d, stop = %.1f; want no amendment", edits, pos.StopLoss)
+	}
+
+	bot.lastTickers["BTCUSD"] = &delta.Ticker{Symbol: "BTCUSD", MarkPrice: 50050}
+	bot.checkBreakeven(pos, 10*time.Minute)
+	if edits != 1 || pos.StopLoss != 50000 {
+		t.Fatalf("profitable scalp: edits = %d, stop = %.1f; want one amendment to 50000", edits, pos.StopLoss)
+	}
+
+	// Already at breakeven: no further amendments
+	bot.checkBreakeven(pos, 10*time.Minute)
+	if edits != 1 {
+		t.Errorf("edits = %d after stop reached entry, want 1", edits)
+	}
+}
+
+func TestMoveStopToBreakeven_RoundsWithBracketPolicy(t *testing.T) {
+	for _, tc := range []struct {
+		policy, side, want string
+	}{
+		{delta.BracketRoundingConservative, "buy", "50000.0"},
+		{delta.BracketRoundingConservative, "sell", "50000.5"},
+		{delta.BracketRoundingNearest, "buy", "50000.5"},
+	} {
+		x := newFakeExchange(delta.Product{ID: 27, Symbol: "BTCUSD", ContractValue: "0.001", TickSize: "0.5"})
+		bot := newFakeExchangeBot(t, x, &config.Config{Symbols: []string{"BTCUSD"}, BracketRounding: tc.policy})
+		pos := &ScalpPosition{Symbol: "BTCUSD", Side: tc.side, Size: 10, EntryPrice: 50000.3, OrderID: 1}
+
+		if err := bot.MoveStopToBreakeven(pos, pos.EntryPrice); err != nil {
+			t.Fatalf("%s %s: MoveStopToBreakeven() error = %v", tc.policy, tc.side, err)
+		}
+		if stops := x.editedStops(); len(stops) != 1 || stops[0] != tc.want {
+			t.Errorf("%s %s: breakeven stop = %v, want %s", tc.policy, tc.side, stops, tc.want)
+		}
+	}
+}
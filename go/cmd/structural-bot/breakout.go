@@ -0,0 +1,83 @@
+package main
+
+import (
+	"strconv"
+
+	"github.com/kasyap/delta-go/go/pkg/delta"
+	"github.com/kasyap/delta-go/go/pkg/logger"
+	"github.com/kasyap/delta-go/go/pkg/strategy"
+)
+
+// executeBreakoutEntry places a breakout as a stop-market entry triggered at the breakout
+// close, so it only fills if price trades on through the break, with the signal's bracket
+// attached. The position is then managed like a scalp: bracket exits, order expiry
+// (ORDER_MAX_AGE "breakout") and settlement, with results recorded under the breakout.
+func (bot *StructuralBot) executeBreakoutEntry(signal strategy.Signal, product *delta.Product, symbol string) {
+	breakout := bot.driverSelector.GetBreakout()
+	if breakout == nil || !breakout.Config().Enabled {
+		return
+	}
+
+	tl := logger.WithTrade(symbol, breakout.Name()).With(logger.KeyAction, signal.Action)
+
+	signal, rr, ok := checkRewardRisk(bot.cfg, signal)
+	if !ok {
+		tl.Info("Breakout entry skipped: reward:risk below minimum", "reward_risk", rr, "min", bot.cfg.MinRewardRisk)
+		return
+	}
+
+	size, err := bot.entrySize(signal, product)
+	if err != nil {
+		tl.Error("Failed to size breakout entry", "error", err)
+		return
+	}
+
+	slPrice, tpPrice := delta.RoundBracketPrices(signal.Side, signal.StopLoss, signal.TakeProfit, product.TickSize, bot.bracketRounding(signal))
+
+	req := &delta.OrderRequest{
+		ProductID:              product.ID,
+		ProductSymbol:          symbol,
+		Size:                   size,
+		Side:                   signal.Side,
+		BracketStopLossPrice:   slPrice,
+		BracketTakeProfitPrice: tpPrice,
+		ClientOrderID:          bot.clientOrderID(symbol, signal.Side, size, "breakout"),
+	}
+
+	if err := bot.checkMargin(product, size, signal.Price); err != nil {
+		tl.Warn("Breakout entry skipped", "error", err)
+		return
+	}
+	if !bot.allowOrder("breakout", symbol) {
+		return
+	}
+
+	// Unrounded: the trigger is rounded away from the market so it can't sit inside the break
+	trigger := strconv.FormatFloat(signal.Price, 'f', -1, 64)
+	order, err := bot.deltaClient.PlaceStopEntryOrder(req, trigger, false)
+	if err != nil {
+		tl.Error("Failed to place breakout stop entry", "error", err)
+		return
+	}
+	bot.trackOrderExpiry("breakout", order.ID)
+
+	bot.mu.Lock()
+	bot.scalpPositions[symbol] = &ScalpPosition{
+		Symbol:     symbol,
+		Side:       signal.Side,
+		Size:       size,
+		EntryTime:  bot.now(),
+		EntryPrice: signal.Price,
+		OrderID:    order.ID,
+
+		Entries:      1,
+		LastAddPrice: signal.Price,
+		StopLoss:     signal.StopLoss,
+		TakeProfit:   signal.TakeProfit,
+		Strategy:     breakout.Name(),
+	}
+	bot.mu.Unlock()
+
+	tl.Info("Breakout stop entry", logger.KeyOrderID, order.ID, "side", signal.Side, "size", size,
+		"trigger", req.StopPrice, "stop_loss", slPrice, "take_profit", tpPrice)
+}
@@ -0,0 +1,43 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/kasyap/delta-go/go/config"
+	"github.com/kasyap/delta-go/go/pkg/delta"
+	"github.com/kasyap/delta-go/go/pkg/features"
+	"github.com/kasyap/delta-go/go/pkg/strategy"
+)
+
+func TestEvaluateAndTrade_PlacesBreakoutAsStopEntry(t *testing.T) {
+	x := newFakeExchange(delta.Product{ID: 27, Symbol: "BTCUSD", ContractValue: "0.001", TickSize: "0.5"})
+	x.setRestOrders(true)
+	bot := newFakeExchangeBot(t, x, &config.Config{Symbols: []string{"BTCUSD"}, BreakoutEnabled: true,
+		MaxPositionPct: 10, Leverage: 10, MaxDrawdownPct: 50, DailyLossLimitPct: -50})
+	signal := strategy.Signal{Action: strategy.ActionBuy, Side: "buy", Price: 50100.2, StopLoss: 49800, TakeProfit: 50700, Confidence: 1}
+	bot.selector = &scriptedSelector{name: breakoutStrategyName, signals: []strategy.Signal{signal}}
+	bot.lastFeatures["BTCUSD"] = features.MarketFeatures{Symbol: "BTCUSD", MarkPrice: 50100}
+	bot.candles["BTCUSD"] = make([]delta.Candle, minStrategyCandles)
+
+	tradingCycle(bot)
+
+	orders := x.placed()
+	if len(orders) != 1 {
+		t.Fatalf("placed %d orders, want the breakout entry", len(orders))
+	}
+	entry := orders[0]
+	if entry.OrderType != "market_order" || entry.StopOrderType != delta.StopOrderTypeStopLoss {
+		t.Errorf("entry = %s/%s, want a stop-market entry", entry.OrderType, entry.StopOrderType)
+	}
+	if entry.StopPrice != "50100.5" {
+		t.Errorf("trigger = %s, want the breakout close rounded up to 50100.5", entry.StopPrice)
+	}
+	if entry.BracketStopLossPrice == "" || entry.BracketTakeProfitPrice == "" {
+		t.Errorf("entry bracket = %q/%q, want both legs", entry.BracketStopLossPrice, entry.BracketTakeProfitPrice)
+	}
+
+	pos := bot.scalpPositions["BTCUSD"]
+	if pos == nil || pos.strategyName() != breakoutStrategyName {
+		t.Fatalf("tracked position = %+v, want one owned by the breakout", pos)
+	}
+}
@@ -0,0 +1,83 @@
+package main
+
+import (
+	"log/slog"
+	"sort"
+	"time"
+
+	"github.com/kasyap/delta-go/go/pkg/delta"
+	"github.com/kasyap/delta-go/go/pkg/logger"
+)
+
+// maxCandleBuffer is how many candles each symbol's trading buffer keeps, unless a strategy
+// needs more (see candleBufferSize)
+const maxCandleBuffer = 500
+
+// candleBufferSize is the trading buffer length: maxCandleBuffer, or the strategies'
+// required history when that is longer
+func (bot *StructuralBot) candleBufferSize() int {
+	return max(maxCandleBuffer, bot.driverSelector.RequiredHistory())
+}
+
+// mergeCandleHistory merges fetched candles into buf by timestamp: the result is sorted,
+// holds one bar per timestamp (fetched wins over buf) and keeps the last limit
+func mergeCandleHistory(buf, fetched []delta.Candle, limit int) []delta.Candle {
+	byTime := make(map[int64]delta.Candle, len(buf)+len(fetched))
+	for _, c := range buf {
+		byTime[c.Time] = c
+	}
+	for _, c := range fetched {
+		byTime[c.Time] = c
+	}
+
+	merged := make([]delta.Candle, 0, len(byTime))
+	for _, c := range byTime {
+		merged = append(merged, c)
+	}
+	sort.Slice(merged, func(i, j int) bool { return merged[i].Time < merged[j].Time })
+	if len(merged) > limit {
+		merged = merged[len(merged)-limit:]
+	}
+	return merged
+}
+
+// candleGap reports whether a streamed candle skips bars after the symbol's buffer, e.g.
+// between the startup REST snapshot and the first WebSocket candle or across a reconnect.
+// from is the buffer's last bar, which is refetched too since it may have been captured
+// while still forming.
+func (bot *StructuralBot) candleGap(symbol string, candle delta.Candle) (from int64, gap bool) {
+	step, err := delta.ParseResolution(bot.cfg.CandleInterval)
+	if err != nil {
+		return 0, false
+	}
+
+	bot.mu.RLock()
+	defer bot.mu.RUnlock()
+	candles := bot.candles[symbol]
+	if len(candles) == 0 {
+		return 0, false
+	}
+	last := candles[len(candles)-1].Time
+	return last, candle.Time > last+int64(step/time.Second)
+}
+
+// backfillCandles fetches the bars from from up to (not including) to over REST and merges
+// them into the symbol's buffer
+func (bot *StructuralBot) backfillCandles(symbol string, from, to int64) {
+	fetched, err := bot.deltaClient.GetCandles(symbol, bot.cfg.CandleInterval, time.Unix(from, 0), time.Unix(to, 0))
+	if err != nil {
+		slog.Warn("Failed to backfill candle gap", logger.KeySymbol, symbol, "from", from, "to", to, "error", err)
+		return
+	}
+	missing := fetched[:0]
+	for _, c := range fetched {
+		if c.Time >= from && c.Time < to {
+			missing = append(missing, c)
+		}
+	}
+
+	bot.mu.Lock()
+	defer bot.mu.Unlock()
+	bot.candles[symbol] = mergeCandleHistory(bot.candles[symbol], missing, bot.candleBufferSize())
+	slog.Info("Backfilled candles across a feed gap", logger.KeySymbol, symbol, "candles", len(missing))
+}
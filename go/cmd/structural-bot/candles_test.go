@@ -0,0 +1,123 @@
+package main
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/kasyap/delta-go/go/config"
+	"github.com/kasyap/delta-go/go/pkg/delta"
+)
+
+func TestHandleCandle_RoutesByResolution(t *testing.T) {
+	bot := NewStructuralBot(&config.Config{
+		Symbols:              []string{"BTCUSD"},
+		CandleInterval:       "5m",
+		RegimeCandleInterval: "1h",
+		APIRateLimitRPS:      8,
+	})
+
+	bot.handleCandle("BTCUSD", "5m", delta.Candle{Time: 0, Close: 100})
+	bot.handleCandle("BTCUSD", "5m", delta.Candle{Time: 300, Close: 101})
+	bot.handleCandle("BTCUSD", "1h", delta.Candle{Time: 0, Close: 200})
+	bot.handleCandle("BTCUSD", "1h", delta.Candle{Time: 0, Close: 201}) // Forming bar update
+	bot.handleCandle("BTCUSD", "", delta.Candle{Time: 600, Close: 102})
+
+	if got := bot.candles["BTCUSD"]; len(got) != 3 || got[2].Close != 102 {
+		t.Errorf("5m buffer = %+v, want 3 candles ending at close 102", got)
+	}
+	if got := bot.resCandles["1h"]["BTCUSD"]; len(got) != 1 || got[0].Close != 201 {
+		t.Errorf("1h buffer = %+v, want one updated candle with close 201", got)
+	}
+}
+
+func TestClosedCandles_OnlyAfterBarCloses(t *testing.T) {
+	bot := NewStructuralBot(&config.Config{
+		Symbols:            []string{"BTCUSD"},
+		CandleInterval:     "5m",
+		WaitForCandleClose: true,
+		APIRateLimitRPS:    8,
+	})
+
+	// evaluate returns the bar a strategy would act on, if any
+	evaluate := func() (delta.Candle, bool) {
+		candles, ok := bot.closedCandles("BTCUSD", bot.candles["BTCUSD"])
+		if !ok {
+			return delta.Candle{}, false
+		}
+		return candles[len(candles)-1], true
+	}
+
+	bot.handleCandle("BTCUSD", "5m", delta.Candle{Time: 0, Close: 100})
+	bot.handleCandle("BTCUSD", "5m", delta.Candle{Time: 0, Close: 105}) // Still forming
+	if _, ok := evaluate(); ok {
+		t.Fatal("evaluated before any bar closed")
+	}
+
+	bot.handleCandle("BTCUSD", "5m", delta.Candle{Time: 300, Close: 106}) // Bar 0 closes
+	bar, ok := evaluate()
+	if !ok {
+		t.Fatal("expected evaluation after bar close")
+	}
+	if bar.Time != 0 || bar.Close != 105 {
+		t.Errorf("acted on %+v, want closed bar 0 with close 105", bar)
+	}
+
+	bot.handleCandle("BTCUSD", "5m", delta.Candle{Time: 300, Close: 110}) // Forming update
+	if _, ok := evaluate(); ok {
+		t.Error("evaluated the same closed bar twice")
+	}
+
+	bot.handleCandle("BTCUSD", "5m", delta.Candle{Time: 600, Close: 111})
+	if bar, ok := evaluate(); !ok || bar.Time != 300 || bar.Close != 110 {
+		t.Errorf("got %+v (ok=%v), want closed bar 300 with close 110", bar, ok)
+	}
+}
+
+func TestHandleCandle_ReconcilesStreamWithRESTSnapshot(t *testing.T) {
+	fetches := 0
+	var gotStart, gotEnd string
+	bot := newInitTestBot(t, func(w http.ResponseWriter, r *http.Request) {
+		if strings.Contains(r.URL.Path, "/history/candles") {
+			fetches++
+			gotStart, gotEnd = r.URL.Query().Get("start"), r.URL.Query().Get("end")
+			// Newest first, with the streamed bar and a duplicate thrown in
+			w.Write([]byte(`{"success":true,"result":[
+				{"time":360,"close":106},{"time":300,"close":105},{"time":240,"close":104},
+				{"time":240,"close":104},{"time":180,"close":103},{"time":120,"close":102}]}`))
+			return
+		}
+		w.Write([]byte(`{"success":true,"result":{}}`))
+	})
+
+	// REST snapshot, newest first as Delta returns it; bar 120 was still forming
+	bot.candles["BTCUSD"] = mergeCandleHistory(nil, []delta.Candle{
+		{Time: 120, Close: 101.5}, {Time: 60, Close: 101}, {Time: 0, Close: 100},
+	}, maxCandleBuffer)
+
+	bot.handleCandle("BTCUSD", "1m", delta.Candle{Time: 60, Close: 999}) // Overlaps the snapshot
+	bot.handleCandle("BTCUSD", "1m", delta.Candle{Time: 360, Close: 106.5})
+	bot.handleCandle("BTCUSD", "1m", delta.Candle{Time: 420, Close: 107}) // Contiguous again
+
+	if fetches != 1 || gotStart != "120" || gotEnd != "360" {
+		t.Errorf("backfill fetches = %d (start=%s end=%s), want one for 120-360", fetches, gotStart, gotEnd)
+	}
+	got := bot.candles["BTCUSD"]
+	if len(got) != 8 {
+		t.Fatalf("buffer = %+v, want 8 contiguous bars 0..420", got)
+	}
+	for i, c := range got {
+		if c.Time != int64(i*60) {
+			t.Fatalf("bar %d at %d, want %d: %+v", i, c.Time, i*60, got)
+		}
+	}
+	if got[1].Close != 101 {
+		t.Errorf("bar 60 close = %.1f, want the snapshot's 101 (older stream bar dropped)", got[1].Close)
+	}
+	if got[2].Close != 102 {
+		t.Errorf("bar 120 close = %.1f, want the refetched 102", got[2].Close)
+	}
+	if got[6].Close != 106.5 {
+		t.Errorf("bar 360 close = %.1f, want the streamed 106.5", got[6].Close)
+	}
+}
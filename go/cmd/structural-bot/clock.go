@@ -0,0 +1,37 @@
+package main
+
+import (
+	"sync"
+	"time"
+
+	"github.com/kasyap/delta-go/go/pkg/strategy"
+)
+
+// botClock is the bot's time source for trading logic: the wall clock live and bar time
+// during a Replay. Feed liveness (the data watchdog) stays on the wall clock.
+type botClock struct {
+	mu    sync.RWMutex
+	clock strategy.Clock
+}
+
+func newBotClock() *botClock {
+	return &botClock{clock: strategy.RealClock{}}
+}
+
+func (c *botClock) Now() time.Time {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.clock.Now()
+}
+
+// set swaps the underlying clock
+func (c *botClock) set(clock strategy.Clock) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.clock = clock
+}
+
+// now is the current time for trading decisions
+func (bot *StructuralBot) now() time.Time {
+	return bot.clock.Now()
+}
@@ -0,0 +1,44 @@
+package main
+
+import (
+	"github.com/kasyap/delta-go/go/config"
+	"github.com/kasyap/delta-go/go/pkg/delta"
+	"github.com/kasyap/delta-go/go/pkg/strategy"
+)
+
+// confidenceFloor is the minimum signal confidence required to enter for a strategy: its
+// per-strategy override if set, else the global floor, lifted to the regime's floor if
+// higher and raised by the bump in high volatility
+func confidenceFloor(cfg *config.Config, strategyName string, regime delta.MarketRegime) float64 {
+	floor := cfg.SignalConfidenceFloor
+	if f, ok := cfg.StrategyConfidenceFloors[strategyName]; ok {
+		floor = f
+	}
+	if f, ok := cfg.RegimeConfidenceFloors[string(regime)]; ok && f > floor {
+		floor = f
+	}
+	if regime == delta.RegimeHighVol {
+		floor += cfg.SignalConfidenceHighVolBump
+	}
+	return floor
+}
+
+// regimeAllowsSide reports whether the regime alignment policy lets signal trade in regime.
+// Closes are always allowed; regimes without a policy entry allow both sides.
+func regimeAllowsSide(cfg *config.Config, regime delta.MarketRegime, signal strategy.Signal) bool {
+	if signal.Action == strategy.ActionClose {
+		return true
+	}
+	side := signal.Side
+	if side == "" {
+		side = string(signal.Action)
+	}
+	switch cfg.RegimeAllowedSides[string(regime)] {
+	case "none":
+		return false
+	case "buy", "sell":
+		return side == cfg.RegimeAllowedSides[string(regime)]
+	default:
+		return true
+	}
+}
@@ -0,0 +1,89 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/kasyap/delta-go/go/config"
+	"github.com/kasyap/delta-go/go/pkg/delta"
+	"github.com/kasyap/delta-go/go/pkg/strategy"
+)
+
+func TestConfidenceFloor(t *testing.T) {
+	cfg := &config.Config{
+		SignalConfidenceFloor:       0.5,
+		SignalConfidenceHighVolBump: 0.1,
+		StrategyConfidenceFloors:    map[string]float64{"grid_trading": 0.75},
+	}
+
+	tests := []struct {
+		name       string
+		strategy   string
+		regime     delta.MarketRegime
+		confidence float64
+		accept     bool
+	}{
+		{"default just below", "fee_aware_scalper", delta.RegimeRanging, 0.49, false},
+		{"default just above", "fee_aware_scalper", delta.RegimeRanging, 0.51, true},
+		{"high vol bump rejects", "fee_aware_scalper", delta.RegimeHighVol, 0.59, false},
+		{"high vol bump accepts", "fee_aware_scalper", delta.RegimeHighVol, 0.61, true},
+		{"override just below", "grid_trading", delta.RegimeRanging, 0.74, false},
+		{"override just above", "grid_trading", delta.RegimeRanging, 0.76, true},
+		{"override with bump", "grid_trading", delta.RegimeHighVol, 0.80, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			floor := confidenceFloor(cfg, tt.strategy, tt.regime)
+			if got := tt.confidence >= floor; got != tt.accept {
+				t.Errorf("confidence %.2f vs floor %.2f: accepted=%v, want %v", tt.confidence, floor, got, tt.accept)
+			}
+		})
+	}
+}
+
+func TestRegimeAllowsSide(t *testing.T) {
+	long := strategy.Signal{Action: strategy.ActionBuy, Side: "buy"}
+	short := strategy.Signal{Action: strategy.ActionSell, Side: "sell"}
+	closeSig := strategy.Signal{Action: strategy.ActionClose}
+
+	// Default policy: every side in every regime
+	cfg := &config.Config{}
+	for _, regime := range []delta.MarketRegime{delta.RegimeBull, delta.RegimeBear, delta.RegimeHighVol, ""} {
+		if !regimeAllowsSide(cfg, regime, long) || !regimeAllowsSide(cfg, regime, short) {
+			t.Errorf("default policy blocked a side in regime %q", regime)
+		}
+	}
+
+	cfg.RegimeAllowedSides = map[string]string{"bull": "buy", "high_volatility": "none"}
+	if regimeAllowsSide(cfg, delta.RegimeBull, short) {
+		t.Error("short allowed in bull regime, want blocked")
+	}
+	if !regimeAllowsSide(cfg, delta.RegimeBull, long) {
+		t.Error("long blocked in bull regime, want allowed")
+	}
+	if regimeAllowsSide(cfg, delta.RegimeHighVol, long) {
+		t.Error("long allowed in high volatility with sides none")
+	}
+	if !regimeAllowsSide(cfg, delta.RegimeBear, short) {
+		t.Error("short blocked in bear regime, which has no policy entry")
+	}
+	if !regimeAllowsSide(cfg, delta.RegimeHighVol, closeSig) {
+		t.Error("close blocked by the regime policy")
+	}
+}
+
+func TestConfidenceFloor_RegimeFloor(t *testing.T) {
+	cfg := &config.Config{
+		SignalConfidenceFloor:    0.5,
+		StrategyConfidenceFloors: map[string]float64{"grid_trading": 0.75},
+		RegimeConfidenceFloors:   map[string]float64{"bear": 0.7},
+	}
+	if got := confidenceFloor(cfg, "fee_aware_scalper", delta.RegimeBear); got != 0.7 {
+		t.Errorf("bear floor = %.2f, want the regime floor 0.70", got)
+	}
+	if got := confidenceFloor(cfg, "fee_aware_scalper", delta.RegimeBull); got != 0.5 {
+		t.Errorf("bull floor = %.2f, want the default 0.50", got)
+	}
+	if got := confidenceFloor(cfg, "grid_trading", delta.RegimeBear); got != 0.75 {
+		t.Errorf("grid bear floor = %.2f, want the higher strategy floor 0.75", got)
+	}
+}
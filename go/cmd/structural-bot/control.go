@@ -0,0 +1,236 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"time"
+)
+
+var errUnknownSymbol = errors.New("symbol not configured")
+
+// SetSymbolEnabled switches trading for a configured symbol on or off at runtime
+func (bot *StructuralBot) SetSymbolEnabled(symbol string, enabled bool) error {
+	if !bot.isConfiguredSymbol(symbol) {
+		return fmt.Errorf("%w: %s", errUnknownSymbol, symbol)
+	}
+
+	bot.mu.Lock()
+	if enabled {
+		delete(bot.disabledSymbols, symbol)
+	} else {
+		bot.disabledSymbols[symbol] = true
+	}
+	bot.mu.Unlock()
+
+	state := "enabled"
+	if !enabled {
+		state = "disabled"
+	}
+	log.Printf("Symbol %s %s", symbol, state)
+	return nil
+}
+
+func (bot *StructuralBot) isConfiguredSymbol(symbol string) bool {
+	for _, s := range bot.cfg.Symbols {
+		if s == symbol {
+			return true
+		}
+	}
+	return false
+}
+
+// tradableSymbols returns the configured symbols that are not disabled
+func (bot *StructuralBot) tradableSymbols() []string {
+	bot.mu.RLock()
+	defer bot.mu.RUnlock()
+
+	symbols := make([]string, 0, len(bot.cfg.Symbols))
+	for _, s := range bot.cfg.Symbols {
+		if !bot.disabledSymbols[s] {
+			symbols = append(symbols, s)
+		}
+	}
+	return symbols
+}
+
+// flattenSymbol closes the symbol's position and any funding hedge leg and cancels its
+// working orders. Local state is cleared only once the exchange calls succeed, so a failed
+// flatten leaves the position tracked and managed.
+func (bot *StructuralBot) flattenSymbol(symbol string) error {
+	bot.mu.RLock()
+	product := bot.productCache[symbol]
+	basis := bot.basisPositions[symbol]
+	scalp := bot.scalpPositions[symbol]
+	bot.mu.RUnlock()
+
+	if product == nil {
+		return fmt.Errorf("no product cached for %s", symbol)
+	}
+	if err := bot.deltaClient.CancelAllOrders(product.ID); err != nil {
+		return fmt.Errorf("failed to cancel orders for %s: %w", symbol, err)
+	}
+	pos, err := bot.deltaClient.GetPosition(product.ID)
+	if err != nil {
+		return fmt.Errorf("failed to get position for %s: %w", symbol, err)
+	}
+	closed, pnl := false, 0.0
+	if pos != nil && pos.Size != 0 {
+		side, size := "buy", pos.Size
+		if size < 0 {
+			side, size = "sell", -size
+		}
+		if err := bot.deltaClient.ClosePosition(symbol, product.ID, size, side); err != nil {
+			return fmt.Errorf("failed to close position for %s: %w", symbol, err)
+		}
+		closed, pnl = true, positionClosePnL(pos, bot.exitPrice(symbol), product)
+	}
+	if basis != nil {
+		hedgePnL, err := bot.closeHedgeLeg(symbol, basis.Hedge)
+		if err != nil {
+			return err
+		}
+		pnl += hedgePnL
+	}
+
+	switch {
+	case closed && basis != nil:
+		bot.recordTradeResult(symbol, fundingStrategyName, pnl)
+	case closed && scalp != nil:
+		bot.recordTradeResult(symbol, scalp.strategyName(), pnl)
+	case closed:
+		bot.recordTradeResult(symbol, scalpStrategyName, pnl)
+	case scalp != nil && bot.entryFilled(scalp):
+		// Already flat: the bracket closed the scalp before the flatten
+		bot.settleScalp(scalp, "bracket exit")
+	}
+	bot.forgetSymbol(symbol)
+	return nil
+}
+
+// forgetSymbol drops the local scalp, funding and grid state of a symbol that is flat
+func (bot *StructuralBot) forgetSymbol(symbol string) {
+	bot.mu.Lock()
+	_, hadScalp := bot.scalpPositions[symbol]
+	_, hadBasis := bot.basisPositions[symbol]
+	delete(bot.scalpPositions, symbol)
+	delete(bot.basisPositions, symbol)
+	for id, sym := range bot.gridOrderIDToSymbol {
+		if sym == symbol {
+			delete(bot.gridOrderIDToSymbol, id)
+		}
+	}
+	if bot.activeGridSymbol == symbol {
+		bot.activeGridSymbol = ""
+	}
+	bot.mu.Unlock()
+
+	if hadScalp {
+		if scalper := bot.driverSelector.GetScalper(); scalper != nil {
+			scalper.RecordExit(symbol)
+		}
+	}
+	if hadBasis {
+		if arb := bot.driverSelector.GetFundingArb(); arb != nil {
+			arb.RecordExit(symbol)
+		}
+	}
+}
+
+// controlHandler serves the runtime control endpoints:
+//
+//	GET  /status                                  GetStatus
+//	GET  /symbols                                 configured symbols and whether each trades
+//	POST /symbols/{symbol}/disable[?flatten=true] stop new entries, optionally closing the position
+//	POST /symbols/{symbol}/enable                 resume trading
+func (bot *StructuralBot) controlHandler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /status", func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(w, http.StatusOK, bot.GetStatus())
+	})
+	mux.HandleFunc("GET /symbols", func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(w, http.StatusOK, bot.symbolStatus())
+	})
+	mux.HandleFunc("POST /symbols/{symbol}/disable", func(w http.ResponseWriter, r *http.Request) {
+		symbol := r.PathValue("symbol")
+		if err := bot.SetSymbolEnabled(symbol, false); err != nil {
+			writeJSON(w, http.StatusNotFound, map[string]string{"error": err.Error()})
+			return
+		}
+		if r.URL.Query().Get("flatten") == "true" {
+			if err := bot.flattenSymbol(symbol); err != nil {
+				log.Printf("Failed to flatten %s: %v", symbol, err)
+				writeJSON(w, http.StatusBadGateway, map[string]any{"symbol": symbol, "enabled": false, "error": err.Error()})
+				return
+			}
+		}
+		writeJSON(w, http.StatusOK, map[string]any{"symbol": symbol, "enabled": false})
+	})
+	mux.HandleFunc("POST /symbols/{symbol}/enable", func(w http.ResponseWriter, r *http.Request) {
+		symbol := r.PathValue("symbol")
+		if err := bot.SetSymbolEnabled(symbol, true); err != nil {
+			writeJSON(w, http.StatusNotFound, map[string]string{"error": err.Error()})
+			return
+		}
+		writeJSON(w, http.StatusOK, map[string]any{"symbol": symbol, "enabled": true})
+	})
+	return mux
+}
+
+// symbolStatus maps each configured symbol to whether it is enabled for trading
+func (bot *StructuralBot) symbolStatus() map[string]bool {
+	bot.mu.RLock()
+	defer bot.mu.RUnlock()
+	status := make(map[string]bool, len(bot.cfg.Symbols))
+	for _, s := range bot.cfg.Symbols {
+		status[s] = !bot.disabledSymbols[s]
+	}
+	return status
+}
+
+// GetStatus reports live performance and symbol state, plus the shadow config's simulated
+// performance when shadow mode is on
+func (bot *StructuralBot) GetStatus() map[string]interface{} {
+	status := map[string]interface{}{
+		"performance": bot.perfTracker.Report(),
+		"symbols":     bot.symbolStatus(),
+	}
+	if bot.shadow != nil {
+		status["shadow"] = bot.shadow.Report()
+	}
+	return status
+}
+
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(v)
+}
+
+// startControlServer serves controlHandler on cfg.StatusAddr when one is configured
+func (bot *StructuralBot) startControlServer() {
+	if bot.cfg.StatusAddr == "" {
+		return
+	}
+	bot.controlServer = &http.Server{Addr: bot.cfg.StatusAddr, Handler: bot.controlHandler()}
+	go func() {
+		if err := bot.controlServer.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			log.Printf("Control server stopped: %v", err)
+		}
+	}()
+	log.Printf("Control server listening on %s", bot.cfg.StatusAddr)
+}
+
+func (bot *StructuralBot) stopControlServer() {
+	if bot.controlServer == nil {
+		return
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := bot.controlServer.Shutdown(ctx); err != nil {
+		log.Printf("Failed to shut down control server: %v", err)
+	}
+}
@@ -0,0 +1,44 @@
+package main
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+)
+
+func TestControlHandler_TogglesSymbol(t *testing.T) {
+	bot := newInitTestBot(t, func(w http.ResponseWriter, r *http.Request) {
+		t.Errorf("unexpected exchange request %s %s", r.Method, r.URL.Path)
+	})
+	srv := httptest.NewServer(bot.controlHandler())
+	defer srv.Close()
+
+	post := func(path string) int {
+		t.Helper()
+		resp, err := http.Post(srv.URL+path, "application/json", nil)
+		if err != nil {
+			t.Fatalf("POST %s: %v", path, err)
+		}
+		resp.Body.Close()
+		return resp.StatusCode
+	}
+
+	if code := post("/symbols/BTCUSD/disable"); code != http.StatusOK {
+		t.Fatalf("disable status = %d, want 200", code)
+	}
+	if got := bot.tradableSymbols(); !reflect.DeepEqual(got, []string{"NOPEUSD"}) {
+		t.Errorf("tradable after disable = %v, want [NOPEUSD]", got)
+	}
+
+	if code := post("/symbols/BTCUSD/enable"); code != http.StatusOK {
+		t.Fatalf("enable status = %d, want 200", code)
+	}
+	if got := bot.tradableSymbols(); !reflect.DeepEqual(got, []string{"BTCUSD", "NOPEUSD"}) {
+		t.Errorf("tradable after enable = %v, want [BTCUSD NOPEUSD]", got)
+	}
+
+	if code := post("/symbols/ETHUSD/disable"); code != http.StatusNotFound {
+		t.Errorf("unknown symbol status = %d, want 404", code)
+	}
+}
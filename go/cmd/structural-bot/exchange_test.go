@@ -0,0 +1,257 @@
+package main
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/kasyap/delta-go/go/config"
+	"github.com/kasyap/delta-go/go/pkg/delta"
+)
+
+// fakeExchange is an in-memory Delta stub: every order fills at once at fillPrice and moves
+// its product's position, which the positions endpoints report, unless restOrders is set.
+// Order books are not served, so limit closes fall back to market orders.
+type fakeExchange struct {
+	mu        sync.Mutex
+	products  map[string]delta.Product
+	positions map[int]int // Product ID -> signed contracts
+	orders    []delta.OrderRequest
+	byID      map[int64]*delta.Order
+	brackets  []int64  // Order IDs whose bracket was edited
+	stops     []string // Stop-loss price of each bracket edit
+	history   []delta.Order
+	leverages []int // Leverage set on any product, in order
+	fillPrice string
+	balance   string
+	nextID    int64
+
+	rejectOrders bool // Reject every order placement
+	restOrders   bool // Leave new orders open until fillOrder
+}
+
+func newFakeExchange(products ...delta.Product) *fakeExchange {
+	x := &fakeExchange{
+		products:  make(map[string]delta.Product),
+		positions: make(map[int]int),
+		byID:      make(map[int64]*delta.Order),
+		fillPrice: "50000",
+		balance:   "10000",
+	}
+	for _, p := range products {
+		x.products[p.Symbol] = p
+	}
+	return x
+}
+
+// newFakeExchangeBot builds a bot on cfg (its exchange fields are filled in) trading on x
+func newFakeExchangeBot(t *testing.T, x *fakeExchange, cfg *config.Config) *StructuralBot {
+	t.Helper()
+	srv := httptest.NewServer(http.HandlerFunc(x.serve))
+	t.Cleanup(srv.Close)
+
+	cfg.BaseURL = srv.URL + "/v2"
+	cfg.APIKey, cfg.APISecret = "k", "s"
+	cfg.APIRateLimitRPS = 100
+	if cfg.CandleInterval == "" {
+		cfg.CandleInterval = "1m"
+	}
+	client := delta.NewClient(cfg)
+	t.Cleanup(client.Close)
+	bot := NewStructuralBotWithClient(cfg, client)
+	for symbol, p := range x.products {
+		p := p
+		bot.productCache[symbol] = &p
+	}
+	return bot
+}
+
+func (x *fakeExchange) position(productID int) int {
+	x.mu.Lock()
+	defer x.mu.Unlock()
+	return x.positions[productID]
+}
+
+func (x *fakeExchange) setPosition(productID, size int) {
+	x.mu.Lock()
+	defer x.mu.Unlock()
+	x.positions[productID] = size
+}
+
+func (x *fakeExchange) setBalance(balance string) {
+	x.mu.Lock()
+	defer x.mu.Unlock()
+	x.balance = balance
+}
+
+func (x *fakeExchange) leverageCalls() []int {
+	x.mu.Lock()
+	defer x.mu.Unlock()
+	return append([]int(nil), x.leverages...)
+}
+
+func (x *fakeExchange) setRejectOrders(reject bool) {
+	x.mu.Lock()
+	defer x.mu.Unlock()
+	x.rejectOrders = reject
+}
+
+func (x *fakeExchange) setRestOrders(rest bool) {
+	x.mu.Lock()
+	defer x.mu.Unlock()
+	x.restOrders = rest
+}
+
+// fillOrder fills a resting order and moves its product's position
+func (x *fakeExchange) fillOrder(id int64) {
+	x.mu.Lock()
+	defer x.mu.Unlock()
+	o := x.byID[id]
+	x.move(o.ProductID, o.Side, o.UnfilledSize)
+	o.UnfilledSize = 0
+	o.State = "closed"
+	o.AverageFillPrice = x.fillPrice
+}
+
+// cancelOrder cancels a resting order, leaving it unfilled
+func (x *fakeExchange) cancelOrder(id int64) {
+	x.mu.Lock()
+	defer x.mu.Unlock()
+	x.byID[id].State = "cancelled"
+}
+
+// addHistory adds an order, e.g. a triggered bracket leg, to the order history
+func (x *fakeExchange) addHistory(order delta.Order) {
+	x.mu.Lock()
+	defer x.mu.Unlock()
+	x.history = append(x.history, order)
+}
+
+func (x *fakeExchange) editedStops() []string {
+	x.mu.Lock()
+	defer x.mu.Unlock()
+	return append([]string(nil), x.stops...)
+}
+
+func (x *fakeExchange) editedBrackets() []int64 {
+	x.mu.Lock()
+	defer x.mu.Unlock()
+	return append([]int64(nil), x.brackets...)
+}
+
+func (x *fakeExchange) move(productID int, side string, size int) {
+	if side == "buy" {
+		x.positions[productID] += size
+	} else {
+		x.positions[productID] -= size
+	}
+}
+
+func (x *fakeExchange) placed() []delta.OrderRequest {
+	x.mu.Lock()
+	defer x.mu.Unlock()
+	return append([]delta.OrderRequest(nil), x.orders...)
+}
+
+func (x *fakeExchange) serve(w http.ResponseWriter, r *http.Request) {
+	x.mu.Lock()
+	defer x.mu.Unlock()
+
+	ok := func(result any) {
+		json.NewEncoder(w).Encode(map[string]any{"success": true, "result": result})
+	}
+	path := strings.TrimPrefix(r.URL.Path, "/v2")
+	switch {
+	case r.Method == http.MethodGet && strings.HasPrefix(path, "/products/"):
+		p, found := x.products[strings.TrimPrefix(path, "/products/")]
+		if !found {
+			break
+		}
+		ok(p)
+		return
+	case r.Method == http.MethodPost && strings.HasPrefix(path, "/products/") && strings.HasSuffix(path, "/orders/leverage"):
+		var body struct {
+			Leverage string `json:"leverage"`
+		}
+		json.NewDecoder(r.Body).Decode(&body)
+		lev, _ := strconv.Atoi(body.Leverage)
+		x.leverages = append(x.leverages, lev)
+		ok(map[string]any{"leverage": body.Leverage})
+		return
+	case r.Method == http.MethodGet && path == "/wallet/balances":
+		ok([]map[string]string{{"asset_symbol": "USDT", "balance": x.balance, "available_balance": x.balance}})
+		return
+	case r.Method == http.MethodGet && path == "/positions/margined":
+		var list []map[string]any
+		for id, size := range x.positions {
+			if size != 0 {
+				list = append(list, map[string]any{"product_id": id, "size": size, "entry_price": x.fillPrice})
+			}
+		}
+		ok(list)
+		return
+	case r.Method == http.MethodGet && path == "/positions":
+		id, _ := strconv.Atoi(r.URL.Query().Get("product_id"))
+		ok(map[string]any{"product_id": id, "size": x.positions[id], "entry_price": x.fillPrice})
+		return
+	case r.Method == http.MethodPost && path == "/orders":
+		if x.rejectOrders {
+			w.WriteHeader(http.StatusBadRequest)
+			w.Write([]byte(`{"success":false,"error":{"code":"insufficient_margin"}}`))
+			return
+		}
+		var req delta.OrderRequest
+		json.NewDecoder(r.Body).Decode(&req)
+		x.orders = append(x.orders, req)
+		x.nextID++
+		o := &delta.Order{ID: x.nextID, ProductID: req.ProductID, Size: req.Size, Side: req.Side,
+			State: "closed", AverageFillPrice: x.fillPrice}
+		if x.restOrders {
+			o.State, o.UnfilledSize, o.AverageFillPrice = "open", req.Size, ""
+		} else {
+			x.move(req.ProductID, req.Side, req.Size)
+		}
+		x.byID[o.ID] = o
+		ok(o)
+		return
+	case r.Method == http.MethodGet && path == "/orders/history":
+		ok(x.history)
+		return
+	case r.Method == http.MethodGet && strings.HasPrefix(path, "/orders/"):
+		id, _ := strconv.ParseInt(strings.TrimPrefix(path, "/orders/"), 10, 64)
+		o, found := x.byID[id]
+		if !found {
+			break
+		}
+		ok(o)
+		return
+	case r.Method == http.MethodPut && path == "/orders/bracket":
+		var body struct {
+			ID       int64  `json:"id"`
+			StopLoss string `json:"bracket_stop_loss_price"`
+		}
+		json.NewDecoder(r.Body).Decode(&body)
+		x.brackets = append(x.brackets, body.ID)
+		x.stops = append(x.stops, body.StopLoss)
+		ok(map[string]any{})
+		return
+	case r.Method == http.MethodDelete && path == "/orders/all":
+		var body struct {
+			ProductID int `json:"product_id"`
+		}
+		json.NewDecoder(r.Body).Decode(&body)
+		for _, o := range x.byID {
+			if o.State == "open" && (body.ProductID == 0 || o.ProductID == body.ProductID) {
+				o.State = "cancelled"
+			}
+		}
+		ok(map[string]any{})
+		return
+	}
+	w.WriteHeader(http.StatusNotFound)
+	w.Write([]byte(`{"success":false,"error":{"code":"not_found"}}`))
+}
@@ -0,0 +1,116 @@
+package main
+
+import (
+	"log/slog"
+	"time"
+
+	"github.com/kasyap/delta-go/go/pkg/delta"
+	"github.com/kasyap/delta-go/go/pkg/logger"
+)
+
+// orderSweepInterval is how often resting orders are checked against their max age
+const orderSweepInterval = 5 * time.Second
+
+// trackOrderExpiry gives a just-placed order the max resting age configured for its strategy
+func (bot *StructuralBot) trackOrderExpiry(strategyKey string, orderID int64) {
+	bot.orderSweeper.Track(orderID, bot.cfg.OrderMaxAge[strategyKey])
+}
+
+func (bot *StructuralBot) orderSweepLoop() {
+	ticker := time.NewTicker(orderSweepInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-bot.stopChan:
+			return
+		case <-ticker.C:
+			bot.sweepExpiredOrders(bot.now())
+		}
+	}
+}
+
+// sweepExpiredOrders cancels resting orders past their max age and drops their bookkeeping
+func (bot *StructuralBot) sweepExpiredOrders(now time.Time) {
+	expired, err := bot.orderSweeper.Sweep(now)
+	if err != nil {
+		slog.Warn("Order expiry sweep failed", "error", err)
+	}
+	for _, order := range expired {
+		bot.onOrderExpired(order)
+	}
+}
+
+// onOrderExpired removes the unfilled part of a cancelled order from the strategy state it
+// was placed for. Scalp entries and pyramid adds shrink the scalp position, which is dropped
+// once nothing was filled; an untouched funding entry clears the funding position and
+// closes its hedge leg; a grid order stops counting against the grid's inventory cap.
+func (bot *StructuralBot) onOrderExpired(order delta.Order) {
+	owner, hedge := bot.dropExpiredOrder(order)
+	tl := logger.WithTrade(order.ProductSymbol, owner).With(logger.KeyOrderID, order.ID)
+	tl.Info("Cancelled order after max resting age", logger.KeyAction, "expire",
+		"unfilled", order.UnfilledSize, "size", order.Size)
+	if hedge != nil {
+		if _, err := bot.closeHedgeLeg(order.ProductSymbol, hedge); err != nil {
+			tl.Error("Failed to close hedge of expired funding entry", "error", err)
+		}
+	}
+}
+
+// dropExpiredOrder updates local state for an expired order and returns the strategy that
+// placed it (empty when untracked) and the hedge leg left behind by an untouched funding
+// entry, if any
+func (bot *StructuralBot) dropExpiredOrder(order delta.Order) (string, *HedgeLeg) {
+	bot.mu.Lock()
+	defer bot.mu.Unlock()
+
+	if _, ok := bot.gridOrderIDToSymbol[order.ID]; ok {
+		delete(bot.gridOrderIDToSymbol, order.ID)
+		gridTrader := bot.driverSelector.GetGridTrader()
+		if gridTrader == nil {
+			return "", nil
+		}
+		gridTrader.ReleaseOrder(order.ID, order.Size-order.UnfilledSize)
+		return gridTrader.Name(), nil
+	}
+
+	for symbol, pos := range bot.scalpPositions {
+		if !pos.hasOrder(order.ID) {
+			continue
+		}
+		pos.Size -= order.UnfilledSize
+		if pos.Size <= 0 {
+			delete(bot.scalpPositions, symbol)
+			if scalper := bot.driverSelector.GetScalper(); scalper != nil {
+				scalper.RecordExit(symbol)
+			}
+		}
+		return pos.strategyName(), nil
+	}
+
+	pos := bot.basisPositions[order.ProductSymbol]
+	if pos == nil {
+		return "", nil
+	}
+	if order.UnfilledSize != order.Size {
+		return fundingStrategyName, nil
+	}
+	delete(bot.basisPositions, order.ProductSymbol)
+	if fundingArb := bot.driverSelector.GetFundingArb(); fundingArb != nil {
+		fundingArb.RecordExit(order.ProductSymbol)
+	}
+	return fundingStrategyName, pos.Hedge
+}
+
+// hasOrder reports whether orderID is the entry or one of the add-ons of the position
+func (pos *ScalpPosition) hasOrder(orderID int64) bool {
+	if pos.OrderID == orderID {
+		return true
+	}
+	for _, id := range pos.AddOnOrderIDs {
+		if id == orderID {
+			return true
+		}
+	}
+	return false
+}
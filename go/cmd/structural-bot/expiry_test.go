@@ -0,0 +1,30 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/kasyap/delta-go/go/config"
+	"github.com/kasyap/delta-go/go/pkg/delta"
+)
+
+func TestOnOrderExpired_ClearsUnfilledEntries(t *testing.T) {
+	bot := NewStructuralBot(&config.Config{Symbols: []string{"BTCUSD", "ETHUSD"}, APIRateLimitRPS: 8})
+	bot.scalpPositions["BTCUSD"] = &ScalpPosition{Symbol: "BTCUSD", Size: 10, OrderID: 1, AddOnOrderIDs: []int64{2}}
+	bot.gridOrderIDToSymbol[3] = "ETHUSD"
+
+	// Partially filled add-on: keep the filled part
+	bot.onOrderExpired(delta.Order{ID: 2, ProductSymbol: "BTCUSD", Size: 4, UnfilledSize: 3})
+	if pos := bot.scalpPositions["BTCUSD"]; pos == nil || pos.Size != 7 {
+		t.Fatalf("after partial add-on expiry: %+v, want size 7", pos)
+	}
+
+	bot.onOrderExpired(delta.Order{ID: 1, ProductSymbol: "BTCUSD", Size: 7, UnfilledSize: 7})
+	if _, ok := bot.scalpPositions["BTCUSD"]; ok {
+		t.Error("unfilled scalp entry should drop the position")
+	}
+
+	bot.onOrderExpired(delta.Order{ID: 3, ProductSymbol: "ETHUSD", Size: 1, UnfilledSize: 1})
+	if _, ok := bot.gridOrderIDToSymbol[3]; ok {
+		t.Error("expired grid order should be untracked")
+	}
+}
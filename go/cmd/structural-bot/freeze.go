@@ -0,0 +1,44 @@
+package main
+
+import (
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/kasyap/delta-go/go/config"
+	"github.com/kasyap/delta-go/go/pkg/risk"
+)
+
+// inEventFreeze reports whether new entries are frozen at now. Entering a freeze is logged
+// once and, with FlattenOnEventFreeze, closes open positions.
+func (bot *StructuralBot) inEventFreeze(now time.Time) bool {
+	active := bot.eventFreeze.Active(now)
+
+	bot.mu.Lock()
+	starting := active && !bot.eventFrozen
+	ending := !active && bot.eventFrozen
+	bot.eventFrozen = active
+	bot.mu.Unlock()
+
+	switch {
+	case starting:
+		log.Printf("Event freeze: pausing entries for %v around funding/scheduled event", bot.cfg.EventFreezeWindow)
+		if bot.cfg.FlattenOnEventFreeze {
+			bot.flattenAll("event freeze")
+		}
+	case ending:
+		log.Println("Event freeze over - resuming entries")
+	}
+	return active
+}
+
+// parseEventFreezeTimes parses EVENT_FREEZE_TIMES, rejecting malformed entries rather than
+// silently dropping a freeze
+func parseEventFreezeTimes(cfg *config.Config) error {
+	times, err := risk.ParseEventTimes(cfg.EventFreezeSpec)
+	if err != nil {
+		return fmt.Errorf("EVENT_FREEZE_TIMES: %w", err)
+	}
+	cfg.EventFreezeTimes = times
+	return nil
+}
@@ -0,0 +1,50 @@
+package main
+
+import (
+	"testing"
+	"time"
+
+	"github.com/kasyap/delta-go/go/config"
+	"github.com/kasyap/delta-go/go/pkg/delta"
+)
+
+func TestParseEventFreezeTimes(t *testing.T) {
+	cfg := &config.Config{EventFreezeSpec: "2024-03-12T12:30:00Z, 2024-03-20T18:00:00Z"}
+	if err := parseEventFreezeTimes(cfg); err != nil {
+		t.Fatalf("parseEventFreezeTimes() error = %v", err)
+	}
+	want := time.Date(2024, 3, 12, 12, 30, 0, 0, time.UTC)
+	if len(cfg.EventFreezeTimes) != 2 || !cfg.EventFreezeTimes[0].Equal(want) {
+		t.Errorf("EventFreezeTimes = %v", cfg.EventFreezeTimes)
+	}
+
+	// A typo fails startup instead of quietly dropping the freeze
+	cfg = &config.Config{EventFreezeSpec: "2024-03-12T12:30:00Z,2024-03-20 18:00"}
+	if err := parseEventFreezeTimes(cfg); err == nil {
+		t.Errorf("parseEventFreezeTimes() accepted a malformed time, got %v", cfg.EventFreezeTimes)
+	}
+}
+
+func TestInEventFreeze_FailedFlattenKeepsPositionTracked(t *testing.T) {
+	event := time.Date(2024, 3, 12, 12, 30, 0, 0, time.UTC)
+	x := newFakeExchange(delta.Product{ID: 27, Symbol: "BTCUSD", ContractValue: "0.001", TickSize: "0.5"})
+	x.setPosition(27, 10)
+	bot := newFakeExchangeBot(t, x, &config.Config{
+		Symbols:              []string{"BTCUSD"},
+		EventFreezeWindow:    5 * time.Minute,
+		EventFreezeTimes:     []time.Time{event},
+		FlattenOnEventFreeze: true,
+	})
+	bot.scalpPositions["BTCUSD"] = &ScalpPosition{Symbol: "BTCUSD", Side: "buy", Size: 10, EntryPrice: 50000, OrderID: 1}
+	x.setRejectOrders(true)
+
+	if !bot.inEventFreeze(event.Add(-time.Minute)) {
+		t.Fatal("inEventFreeze() = false inside the window")
+	}
+	if _, ok := bot.scalpPositions["BTCUSD"]; !ok {
+		t.Fatal("scalp position forgotten although the close was rejected")
+	}
+	if pos := x.position(27); pos != 10 {
+		t.Errorf("position = %d, want 10 still open", pos)
+	}
+}
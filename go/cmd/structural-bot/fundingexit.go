@@ -0,0 +1,42 @@
+package main
+
+import (
+	"github.com/kasyap/delta-go/go/pkg/delta"
+	"github.com/kasyap/delta-go/go/pkg/features"
+	"github.com/kasyap/delta-go/go/pkg/logger"
+	"github.com/kasyap/delta-go/go/pkg/strategy"
+)
+
+// checkFundingExits runs the funding strategy on each held basis symbol and closes the
+// position, hedge leg included, on its exit signal: price stop or target, funding below the
+// exit threshold, or max holding time
+func (bot *StructuralBot) checkFundingExits(featuresMap map[string]features.MarketFeatures, candlesMap map[string][]delta.Candle) {
+	fundingArb := bot.driverSelector.GetFundingArb()
+	if fundingArb == nil {
+		return
+	}
+
+	bot.mu.RLock()
+	symbols := make([]string, 0, len(bot.basisPositions))
+	for symbol := range bot.basisPositions {
+		symbols = append(symbols, symbol)
+	}
+	bot.mu.RUnlock()
+
+	for _, symbol := range symbols {
+		f, ok := featuresMap[symbol]
+		if !ok {
+			continue
+		}
+		signal := fundingArb.Analyze(f, candlesMap[symbol])
+		if signal.Action != strategy.ActionClose {
+			continue
+		}
+
+		tl := logger.WithTrade(symbol, fundingStrategyName).With(logger.KeyAction, signal.Action)
+		tl.Info("Closing funding position", "reason", signal.Reason)
+		if err := bot.flattenSymbol(symbol); err != nil {
+			tl.Error("Failed to close funding position", "error", err)
+		}
+	}
+}
@@ -0,0 +1,14 @@
+package main
+
+import "github.com/kasyap/delta-go/go/pkg/logger"
+
+// allowOrder checks an order placement for strategyKey against the per-minute governor,
+// logging when it is blocked. Closes bypass it: they only reduce exposure.
+func (bot *StructuralBot) allowOrder(strategyKey, symbol string) bool {
+	if bot.orderGovernor.Allow(strategyKey, bot.now()) {
+		return true
+	}
+	logger.WithTrade(symbol, strategyKey).Warn("Order governor limit hit - skipping placement",
+		logger.KeyAction, "place", "max_per_minute", bot.cfg.MaxOrdersPerMinute)
+	return false
+}
@@ -0,0 +1,29 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/kasyap/delta-go/go/config"
+	"github.com/kasyap/delta-go/go/pkg/delta"
+	"github.com/kasyap/delta-go/go/pkg/features"
+	"github.com/kasyap/delta-go/go/pkg/strategy"
+)
+
+func TestExecuteGridEntry_CapsRestingLevels(t *testing.T) {
+	x := newFakeExchange(delta.Product{ID: 27, Symbol: "BTCUSD", ContractValue: "0.001", TickSize: "0.5"})
+	x.setRestOrders(true)
+	x.setBalance("100") // One contract per level
+	bot := newFakeExchangeBot(t, x, &config.Config{Symbols: []string{"BTCUSD"}, Leverage: 10, GridMaxInventory: 3})
+
+	grid := bot.driverSelector.GetGridTrader()
+	grid.Analyze(features.MarketFeatures{Symbol: "BTCUSD", HistoricalVol: 0.1, BestBid: 50000, BestAsk: 50000}, nil)
+	bot.executeGridEntry(strategy.Signal{}, bot.productCache["BTCUSD"], "BTCUSD")
+
+	sides := map[string]int{}
+	for _, o := range x.placed() {
+		sides[o.Side] += o.Size
+	}
+	if sides["buy"] != 3 || sides["sell"] != 3 {
+		t.Errorf("resting grid orders = %v contracts, want 3 each side at the cap", sides)
+	}
+}
@@ -0,0 +1,108 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/kasyap/delta-go/go/pkg/alert"
+	"github.com/kasyap/delta-go/go/pkg/delta"
+	"github.com/kasyap/delta-go/go/pkg/logger"
+)
+
+// HedgeLeg is the dated-future side of a hedged funding position
+type HedgeLeg struct {
+	Symbol    string
+	ProductID int
+	Side      string
+	Size      int
+	OrderID   int64
+}
+
+// placeHedgedFundingEntry opens a funding position as a hedged pair: a market order on the
+// dated future against the signal first, then the perp leg. Both legs must fill; if the perp
+// leg fails or times out the future is unwound at market and the attempt is alerted.
+func (bot *StructuralBot) placeHedgedFundingEntry(perpReq *delta.OrderRequest, symbol, hedgeSymbol string, notional, price float64) (*delta.Order, *HedgeLeg, error) {
+	hedgeProduct, err := bot.deltaClient.GetProductBySymbol(hedgeSymbol)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to get hedge product %s: %w", hedgeSymbol, err)
+	}
+	if ok, reason := delta.ProductTradeable(hedgeProduct, bot.now(), bot.cfg.MinTimeToSettlement); !ok {
+		return nil, nil, fmt.Errorf("hedge product %s is not tradeable: %s", hedgeSymbol, reason)
+	}
+	hedgeSize, err := delta.NotionalToContracts(notional, price, hedgeProduct)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to size hedge leg: %w", err)
+	}
+	if hedgeSize < 1 {
+		hedgeSize = 1
+	}
+
+	hedgeSide := "sell"
+	if perpReq.Side == "sell" {
+		hedgeSide = "buy"
+	}
+	hedgeReq := &delta.OrderRequest{
+		ProductID:     hedgeProduct.ID,
+		Size:          hedgeSize,
+		Side:          hedgeSide,
+		OrderType:     "market_order",
+		ClientOrderID: bot.clientOrderID(symbol, hedgeSide, hedgeSize, "funding:hedge:"+hedgeSymbol),
+	}
+
+	hedgeOrder, perpOrder, err := bot.deltaClient.PlaceHedgedPair(hedgeReq, perpReq, bot.cfg.BasisHedgeFillTimeout)
+	var hedgeErr *delta.HedgeLegError
+	if errors.As(err, &hedgeErr) {
+		level := alert.LevelWarn
+		if hedgeErr.UnwindErr != nil {
+			level = alert.LevelError
+		}
+		bot.alerter.Alert(level, fmt.Sprintf("[%s] Hedged funding entry aborted: %v", symbol, err))
+		return nil, nil, err
+	}
+	if err != nil {
+		return nil, nil, err
+	}
+
+	logger.WithTrade(symbol, fundingStrategyName).Info("Funding hedge", logger.KeyOrderID, hedgeOrder.ID,
+		"side", hedgeSide, "size", hedgeSize, "hedge_symbol", hedgeSymbol)
+	leg := &HedgeLeg{Symbol: hedgeSymbol, ProductID: hedgeProduct.ID, Side: hedgeSide, Size: hedgeSize, OrderID: hedgeOrder.ID}
+	return perpOrder, leg, nil
+}
+
+// closeHedgeLeg flattens a funding position's future leg with a reduce-only market order
+// for whatever the exchange still holds on it, and returns the PnL the close realized
+func (bot *StructuralBot) closeHedgeLeg(symbol string, leg *HedgeLeg) (float64, error) {
+	if leg == nil {
+		return 0, nil
+	}
+	pos, err := bot.deltaClient.GetPosition(leg.ProductID)
+	if err != nil {
+		return 0, fmt.Errorf("failed to get hedge position %s: %w", leg.Symbol, err)
+	}
+	if pos == nil || pos.Size == 0 {
+		return 0, nil
+	}
+	side, size := "sell", pos.Size
+	if size < 0 {
+		side, size = "buy", -size
+	}
+	order, err := bot.deltaClient.PlaceOrder(&delta.OrderRequest{
+		ProductID:  leg.ProductID,
+		Size:       size,
+		Side:       side,
+		OrderType:  "market_order",
+		ReduceOnly: true,
+	})
+	if err != nil {
+		return 0, fmt.Errorf("failed to close hedge leg %s: %w", leg.Symbol, err)
+	}
+
+	bot.mu.RLock()
+	product := bot.productCache[leg.Symbol]
+	bot.mu.RUnlock()
+	pnl := positionClosePnL(pos, parseFloatOrZero(order.AverageFillPrice), product)
+
+	logger.WithTrade(symbol, fundingStrategyName).Info("Funding hedge closed", logger.KeyOrderID, order.ID,
+		"side", side, "size", size, "hedge_symbol", leg.Symbol, "pnl", pnl)
+	return pnl, nil
+}
@@ -0,0 +1,143 @@
+package main
+
+import (
+	"testing"
+	"time"
+
+	"github.com/kasyap/delta-go/go/config"
+	"github.com/kasyap/delta-go/go/pkg/delta"
+	"github.com/kasyap/delta-go/go/pkg/features"
+	"github.com/kasyap/delta-go/go/pkg/strategy"
+)
+
+func hedgedFundingBot(t *testing.T) (*StructuralBot, *fakeExchange) {
+	t.Helper()
+	x := newFakeExchange(
+		delta.Product{ID: 27, Symbol: "BTCUSD", ContractValue: "0.001", TickSize: "0.5"},
+		delta.Product{ID: 90, Symbol: "BTCUSD_270625", ContractValue: "0.001", TickSize: "0.5"},
+	)
+	bot := newFakeExchangeBot(t, x, &config.Config{
+		Symbols:               []string{"BTCUSD"},
+		BasisTradeEnabled:     true,
+		MaxPositionPct:        10,
+		Leverage:              10,
+		BasisHedgeSymbols:     map[string]string{"BTCUSD": "BTCUSD_270625"},
+		BasisHedgeFillTimeout: 1,
+		BasisPriceStopPct:     2,
+	})
+	return bot, x
+}
+
+func TestHedgedFundingEntry_FlattenClosesBothLegs(t *testing.T) {
+	bot, x := hedgedFundingBot(t)
+	signal := strategy.Signal{Action: strategy.ActionSell, Side: "sell", Price: 50000, Confidence: 1}
+	bot.executeFundingArbEntry(signal, bot.productCache["BTCUSD"], "BTCUSD")
+
+	pos := bot.basisPositions["BTCUSD"]
+	if pos == nil || pos.Hedge == nil {
+		t.Fatalf("basis position = %+v, want one with a hedge leg", pos)
+	}
+	if h := pos.Hedge; h.Symbol != "BTCUSD_270625" || h.ProductID != 90 || h.Side != "buy" || h.Size < 1 {
+		t.Errorf("hedge leg = %+v", h)
+	}
+	if x.position(27) >= 0 || x.position(90) <= 0 {
+		t.Fatalf("after entry perp = %d, future = %d; want short perp, long future", x.position(27), x.position(90))
+	}
+
+	if err := bot.flattenSymbol("BTCUSD"); err != nil {
+		t.Fatalf("flattenSymbol() error = %v", err)
+	}
+	if perp, future := x.position(27), x.position(90); perp != 0 || future != 0 {
+		t.Errorf("after flatten perp = %d, future = %d; want both flat", perp, future)
+	}
+	if _, ok := bot.basisPositions["BTCUSD"]; ok {
+		t.Error("basis position still tracked after flatten")
+	}
+}
+
+func TestOnOrderExpired_ClosesHedgeOfUnfilledFundingEntry(t *testing.T) {
+	bot, x := hedgedFundingBot(t)
+	x.setPosition(90, 3)
+	bot.basisPositions["BTCUSD"] = &BasisPosition{Symbol: "BTCUSD", Side: "sell", Size: 3, OrderID: 5,
+		Hedge: &HedgeLeg{Symbol: "BTCUSD_270625", ProductID: 90, Side: "buy", Size: 3}}
+
+	bot.onOrderExpired(delta.Order{ID: 5, ProductSymbol: "BTCUSD", Size: 3, UnfilledSize: 3})
+
+	if future := x.position(90); future != 0 {
+		t.Errorf("future position = %d, want flat", future)
+	}
+	if _, ok := bot.basisPositions["BTCUSD"]; ok {
+		t.Error("basis position still tracked")
+	}
+}
+
+func TestFlattenSymbol_KeepsStateWhenCloseFails(t *testing.T) {
+	bot, x := hedgedFundingBot(t)
+	x.setPosition(27, -3)
+	x.setPosition(90, 3)
+	bot.basisPositions["BTCUSD"] = &BasisPosition{Symbol: "BTCUSD", Side: "sell", Size: 3,
+		Hedge: &HedgeLeg{Symbol: "BTCUSD_270625", ProductID: 90, Side: "buy", Size: 3}}
+	x.setRejectOrders(true)
+
+	if err := bot.flattenSymbol("BTCUSD"); err == nil {
+		t.Fatal("flattenSymbol() succeeded with orders rejected")
+	}
+	if _, ok := bot.basisPositions["BTCUSD"]; !ok {
+		t.Fatal("basis position forgotten although the close failed")
+	}
+
+	x.setRejectOrders(false)
+	if err := bot.flattenSymbol("BTCUSD"); err != nil {
+		t.Fatalf("retry error = %v", err)
+	}
+	if perp, future := x.position(27), x.position(90); perp != 0 || future != 0 {
+		t.Errorf("after retry perp = %d, future = %d; want both flat", perp, future)
+	}
+	if _, ok := bot.basisPositions["BTCUSD"]; ok {
+		t.Error("basis position still tracked after a successful flatten")
+	}
+}
+
+func TestCheckFundingExits_ClosesHedgedPositionOnExitSignal(t *testing.T) {
+	bot, x := hedgedFundingBot(t)
+	signal := strategy.Signal{Action: strategy.ActionSell, Side: "sell", Price: 50000, Confidence: 1}
+	bot.executeFundingArbEntry(signal, bot.productCache["BTCUSD"], "BTCUSD")
+	if bot.basisPositions["BTCUSD"] == nil {
+		t.Fatal("funding entry not tracked")
+	}
+
+	held := map[string]features.MarketFeatures{"BTCUSD": {Symbol: "BTCUSD", BasisAnnualized: 0.20, MarkPrice: 50500}}
+	bot.checkFundingExits(held, nil)
+	if bot.basisPositions["BTCUSD"] == nil {
+		t.Fatal("funding position closed before the price stop")
+	}
+
+	stopped := map[string]features.MarketFeatures{"BTCUSD": {Symbol: "BTCUSD", BasisAnnualized: 0.20, MarkPrice: 51500}}
+	bot.checkFundingExits(stopped, nil)
+	if perp, future := x.position(27), x.position(90); perp != 0 || future != 0 {
+		t.Errorf("after exit perp = %d, future = %d; want both flat", perp, future)
+	}
+	if _, ok := bot.basisPositions["BTCUSD"]; ok {
+		t.Error("basis position still tracked after exit")
+	}
+}
+
+func TestHedgedFundingEntry_SkipsSettlingHedge(t *testing.T) {
+	bot, x := hedgedFundingBot(t)
+	x.mu.Lock()
+	settling := x.products["BTCUSD_270625"]
+	settling.SettlementTime = bot.now().Add(30 * time.Minute).UTC().Format(time.RFC3339)
+	x.products["BTCUSD_270625"] = settling
+	x.mu.Unlock()
+	bot.cfg.MinTimeToSettlement = time.Hour
+
+	signal := strategy.Signal{Action: strategy.ActionSell, Side: "sell", Price: 50000, Confidence: 1}
+	bot.executeFundingArbEntry(signal, bot.productCache["BTCUSD"], "BTCUSD")
+
+	if orders := x.placed(); len(orders) != 0 {
+		t.Errorf("placed %v, want no legs on a hedge about to settle", orders)
+	}
+	if _, ok := bot.basisPositions["BTCUSD"]; ok {
+		t.Error("funding position tracked without a tradeable hedge")
+	}
+}
@@ -0,0 +1,109 @@
+package main
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/kasyap/delta-go/go/config"
+	"github.com/kasyap/delta-go/go/pkg/delta"
+)
+
+func newInitTestBot(t *testing.T, handler http.HandlerFunc) *StructuralBot {
+	t.Helper()
+	srv := httptest.NewServer(handler)
+	t.Cleanup(srv.Close)
+
+	cfg := &config.Config{
+		Symbols:         []string{"BTCUSD", "NOPEUSD"},
+		BaseURL:         srv.URL + "/v2",
+		APIKey:          "k",
+		APISecret:       "s",
+		APIRateLimitRPS: 100,
+		CandleInterval:  "1m",
+		IsTestnet:       true,
+	}
+	client := delta.NewClient(cfg)
+	t.Cleanup(client.Close)
+	return NewStructuralBotWithClient(cfg, client)
+}
+
+func TestInitialize_FailsFastOnAuthError(t *testing.T) {
+	calls := 0
+	bot := newInitTestBot(t, func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		w.WriteHeader(http.StatusUnauthorized)
+		w.Write([]byte(`{"success":false,"error":{"code":"UnauthorizedApiAccess","message":"bad key"}}`))
+	})
+
+	err := bot.Initialize()
+	if !delta.IsAuthError(err) {
+		t.Fatalf("Initialize() error = %v, want auth error", err)
+	}
+	if calls != 1 {
+		t.Errorf("requests = %d, want 1 (no retries, no further symbols)", calls)
+	}
+}
+
+func TestInitialize_SkipsUnknownSymbolAndRetriesTransient(t *testing.T) {
+	defer func(attempts int, backoff time.Duration) { initAttempts, initBackoff = attempts, backoff }(initAttempts, initBackoff)
+	initAttempts, initBackoff = 3, time.Millisecond
+
+	productCalls := 0
+	bot := newInitTestBot(t, func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case strings.HasSuffix(r.URL.Path, "/products/NOPEUSD"):
+			w.WriteHeader(http.StatusNotFound)
+			w.Write([]byte(`{"success":false,"error":{"code":"not_found","message":"no such product"}}`))
+		case strings.HasSuffix(r.URL.Path, "/products/BTCUSD"):
+			// A rate-limit code on a 4xx is not retried by the client itself, only by Initialize
+			productCalls++
+			if productCalls == 1 {
+				w.WriteHeader(http.StatusBadRequest)
+				w.Write([]byte(`{"success":false,"error":{"code":"rate_limit_exceeded","message":"slow down"}}`))
+				return
+			}
+			w.Write([]byte(`{"success":true,"result":{"id":27,"symbol":"BTCUSD","tick_size":"0.5"}}`))
+		case strings.Contains(r.URL.Path, "/history/candles"):
+			w.Write([]byte(`{"success":true,"result":[]}`))
+		default:
+			w.Write([]byte(`{"success":true,"result":{}}`))
+		}
+	})
+
+	if err := bot.Initialize(); err != nil {
+		t.Fatalf("Initialize() error = %v", err)
+	}
+	if _, ok := bot.productCache["BTCUSD"]; !ok {
+		t.Error("BTCUSD not loaded after transient failures")
+	}
+	if productCalls != 2 {
+		t.Errorf("product requests = %d, want 2", productCalls)
+	}
+	if _, ok := bot.productCache["NOPEUSD"]; ok {
+		t.Error("unknown symbol was loaded")
+	}
+}
+
+func TestHandleWSReconnectGiveUp_DeliversAlertBeforeStopping(t *testing.T) {
+	received := make(chan string, 1)
+	hook := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		time.Sleep(50 * time.Millisecond) // Slower than the bot's shutdown
+		received <- r.URL.Path
+	}))
+	t.Cleanup(hook.Close)
+
+	x := newFakeExchange(delta.Product{ID: 27, Symbol: "BTCUSD", ContractValue: "0.001", TickSize: "0.5"})
+	bot := newFakeExchangeBot(t, x, &config.Config{Symbols: []string{"BTCUSD"}, AlertWebhookURL: hook.URL})
+
+	bot.handleWSReconnectGiveUp(10, errors.New("dial refused"))
+
+	select {
+	case <-received:
+	default:
+		t.Fatal("Stop returned before the give-up alert was delivered")
+	}
+}
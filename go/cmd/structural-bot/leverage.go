@@ -0,0 +1,34 @@
+package main
+
+import (
+	"log/slog"
+
+	"github.com/kasyap/delta-go/go/pkg/logger"
+)
+
+// applyDynamicLeverage moves every traded product to the risk manager's drawdown-banded
+// leverage when the band changes. A failed update is retried on the next cycle.
+func (bot *StructuralBot) applyDynamicLeverage() {
+	target, changed := bot.riskManager.LeverageUpdate()
+	if !changed {
+		return
+	}
+
+	bot.mu.RLock()
+	productIDs := make(map[string]int, len(bot.productCache))
+	for sym, p := range bot.productCache {
+		productIDs[sym] = p.ID
+	}
+	bot.mu.RUnlock()
+
+	for sym, id := range productIDs {
+		if err := bot.deltaClient.SetLeverage(id, target); err != nil {
+			slog.Error("Failed to set leverage", logger.KeySymbol, sym, logger.KeyAction, "set_leverage",
+				"leverage", target, "error", err)
+			return
+		}
+	}
+	slog.Info("Leverage changed for current drawdown", logger.KeyAction, "set_leverage",
+		"from", bot.riskManager.Leverage(), "to", target)
+	bot.riskManager.CommitLeverage(target)
+}
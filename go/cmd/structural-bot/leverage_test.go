@@ -0,0 +1,66 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/kasyap/delta-go/go/config"
+	"github.com/kasyap/delta-go/go/pkg/delta"
+	"github.com/kasyap/delta-go/go/pkg/strategy"
+)
+
+func TestEvaluateAndTrade_LowersLeverageInDrawdown(t *testing.T) {
+	bot, x := entryBot(t, &config.Config{
+		MaxDrawdownPct:          50,
+		DailyLossLimitPct:       -50,
+		Leverage:                10,
+		LeverageDrawdownBandPct: 2,
+		LeverageStepPerBand:     2,
+		MinLeverage:             1,
+	})
+
+	x.setBalance("10000")
+	tradingCycle(bot)
+	if calls := x.leverageCalls(); len(calls) != 0 {
+		t.Fatalf("leverage set to %v at the equity peak, want no change", calls)
+	}
+
+	// 3% below the peak is one band down
+	bot.forgetSymbol("BTCUSD")
+	x.setBalance("9700")
+	tradingCycle(bot)
+	if calls := x.leverageCalls(); len(calls) != 1 || calls[0] != 8 {
+		t.Fatalf("leverage calls = %v, want one at 8x", calls)
+	}
+	if got := bot.riskManager.Leverage(); got != 8 {
+		t.Errorf("risk manager leverage = %d, want 8", got)
+	}
+}
+
+func TestFundingEntry_SizesAtRiskManagerLeverage(t *testing.T) {
+	x := newFakeExchange(delta.Product{ID: 27, Symbol: "BTCUSD", ContractValue: "0.001", TickSize: "0.5"})
+	bot := newFakeExchangeBot(t, x, &config.Config{
+		Symbols:           []string{"BTCUSD"},
+		BasisTradeEnabled: true,
+		MaxPositionPct:    10,
+		Leverage:          10,
+	})
+	signal := strategy.Signal{Action: strategy.ActionSell, Side: "sell", Price: 50000, Confidence: 1}
+	entrySize := func() int {
+		t.Helper()
+		bot.executeFundingArbEntry(signal, bot.productCache["BTCUSD"], "BTCUSD")
+		orders := x.placed()
+		if len(orders) == 0 {
+			t.Fatal("no funding entry placed")
+		}
+		return orders[len(orders)-1].Size
+	}
+
+	first := entrySize()
+	bot.forgetSymbol("BTCUSD")
+	// A drawdown band cut exchange leverage from 10x to 5x
+	bot.riskManager.CommitLeverage(5)
+
+	if second := entrySize(); second*2 != first {
+		t.Errorf("entry at 5x = %d contracts, want half of the 10x entry's %d", second, first)
+	}
+}
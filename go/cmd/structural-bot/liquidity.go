@@ -0,0 +1,70 @@
+package main
+
+import (
+	"log/slog"
+	"time"
+
+	"github.com/kasyap/delta-go/go/pkg/delta"
+	"github.com/kasyap/delta-go/go/pkg/logger"
+)
+
+// notionalVolume24h estimates a symbol's 24h traded notional in USD: the ticker's turnover
+// when Delta reports it, otherwise the last day of candles (contracts × close × contract
+// value), scaled up when the candles cover less than a day. Returns 0 without data.
+func notionalVolume24h(ticker *delta.Ticker, candles []delta.Candle, resolution string, product *delta.Product) float64 {
+	if ticker != nil && ticker.Turnover > 0 {
+		return ticker.Turnover
+	}
+	step, err := delta.ParseResolution(resolution)
+	if err != nil || len(candles) == 0 {
+		return 0
+	}
+	cv := 1.0
+	if product != nil {
+		if v, err := delta.ParseContractValue(product); err == nil && v > 0 {
+			cv = v
+		}
+	}
+
+	bars := int(24 * time.Hour / step)
+	if bars > len(candles) {
+		bars = len(candles)
+	}
+	volume := 0.0
+	for _, c := range candles[len(candles)-bars:] {
+		volume += c.Volume * c.Close * cv
+	}
+	return volume * float64(24*time.Hour) / float64(time.Duration(bars)*step)
+}
+
+// liquidSymbols drops multi-asset candidates whose 24h notional volume is below
+// MinSymbolVolumeUSD, so thin markets aren't traded into. Symbols in keep (e.g. ones with
+// an open position) always pass. A symbol is logged when it drops out and when it returns.
+func (bot *StructuralBot) liquidSymbols(symbols []string, keep map[string]bool) []string {
+	minVolume := bot.cfg.MinSymbolVolumeUSD
+	if !bot.cfg.MultiAssetMode || minVolume <= 0 {
+		return symbols
+	}
+
+	bot.mu.Lock()
+	defer bot.mu.Unlock()
+	liquid := make([]string, 0, len(symbols))
+	for _, symbol := range symbols {
+		volume := notionalVolume24h(bot.lastTickers[symbol], bot.candles[symbol], bot.cfg.CandleInterval, bot.productCache[symbol])
+		if volume >= minVolume || keep[symbol] {
+			if bot.illiquidSymbols[symbol] {
+				slog.Info("Back above the volume floor, trading resumed", logger.KeySymbol, symbol,
+					logger.KeyAction, "resume", "volume_24h", volume)
+				delete(bot.illiquidSymbols, symbol)
+			}
+			liquid = append(liquid, symbol)
+			continue
+		}
+		if !bot.illiquidSymbols[symbol] {
+			slog.Info("Excluded below the volume floor", logger.KeySymbol, symbol,
+				logger.KeyAction, "exclude", "volume_24h", volume, "floor", minVolume)
+			bot.illiquidSymbols[symbol] = true
+		}
+	}
+	return liquid
+}
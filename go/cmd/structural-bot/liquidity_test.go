@@ -0,0 +1,58 @@
+package main
+
+import (
+	"math"
+	"reflect"
+	"testing"
+
+	"github.com/kasyap/delta-go/go/config"
+	"github.com/kasyap/delta-go/go/pkg/delta"
+)
+
+func TestLiquidSymbols_FiltersThinSymbol(t *testing.T) {
+	bot := NewStructuralBot(&config.Config{
+		Symbols:            []string{"BTCUSD", "ALTUSD", "ETHUSD"},
+		CandleInterval:     "1h",
+		MultiAssetMode:     true,
+		MinSymbolVolumeUSD: 1_000_000,
+		APIRateLimitRPS:    8,
+	})
+	bot.lastTickers["BTCUSD"] = &delta.Ticker{Symbol: "BTCUSD", Turnover: 50_000_000}
+	// No turnover on the ticker: 24 hourly bars of 100 contracts at $2 with cv 1 = $4,800
+	bot.lastTickers["ALTUSD"] = &delta.Ticker{Symbol: "ALTUSD"}
+	for i := 0; i < 24; i++ {
+		bot.candles["ALTUSD"] = append(bot.candles["ALTUSD"], delta.Candle{Time: int64(i * 3600), Close: 2, Volume: 100})
+	}
+	bot.productCache["ALTUSD"] = &delta.Product{Symbol: "ALTUSD", ContractValue: "1"}
+	bot.lastTickers["ETHUSD"] = &delta.Ticker{Symbol: "ETHUSD", Turnover: 20_000_000}
+
+	got := bot.liquidSymbols(bot.tradableSymbols(), nil)
+	if want := []string{"BTCUSD", "ETHUSD"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("candidates = %v, want %v", got, want)
+	}
+	if !bot.illiquidSymbols["ALTUSD"] {
+		t.Error("ALTUSD not recorded as excluded")
+	}
+
+	// An open position keeps the symbol in play so exits and reversals still run
+	if got := bot.liquidSymbols([]string{"ALTUSD"}, map[string]bool{"ALTUSD": true}); len(got) != 1 {
+		t.Errorf("held symbol filtered out: %v", got)
+	}
+
+	bot.cfg.MultiAssetMode = false
+	if got := bot.liquidSymbols([]string{"ALTUSD"}, nil); len(got) != 1 {
+		t.Errorf("filter applied outside multi-asset mode: %v", got)
+	}
+}
+
+func TestNotionalVolume24h_ScalesShortHistory(t *testing.T) {
+	// Six hourly bars of 10 contracts at $50,000 with cv 0.001 = $3,000, a quarter of a day
+	candles := make([]delta.Candle, 6)
+	for i := range candles {
+		candles[i] = delta.Candle{Time: int64(i * 3600), Close: 50000, Volume: 10}
+	}
+	product := &delta.Product{Symbol: "BTCUSD", ContractValue: "0.001"}
+	if got := notionalVolume24h(nil, candles, "1h", product); math.Abs(got-12000) > 1e-6 {
+		t.Errorf("volume = %.2f, want 12000", got)
+	}
+}
@@ -2,17 +2,22 @@ package main
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
 	"log"
 	"log/slog"
+	"math"
+	"net/http"
 	"os"
 	"os/signal"
+	"sort"
 	"strconv"
 	"sync"
 	"syscall"
 	"time"
 
 	"github.com/kasyap/delta-go/go/config"
+	"github.com/kasyap/delta-go/go/pkg/alert"
 	"github.com/kasyap/delta-go/go/pkg/delta"
 	"github.com/kasyap/delta-go/go/pkg/features"
 	"github.com/kasyap/delta-go/go/pkg/logger"
@@ -20,21 +25,85 @@ import (
 	"github.com/kasyap/delta-go/go/pkg/strategy"
 )
 
+// Strategy names for trade logs where the strategy itself isn't at hand
+const (
+	scalpStrategyName    = "fee_aware_scalper"
+	breakoutStrategyName = "high_vol_breakout"
+	fundingStrategyName  = "funding_arbitrage"
+)
+
 type ScalpPosition struct {
 	Symbol     string
 	Side       string
 	Size       int
 	EntryTime  time.Time
-	EntryPrice float64
+	EntryPrice float64 // Blended entry across all units
 	OrderID    int64
+
+	// Pyramiding bookkeeping
+	Entries       int     // Units in the position, including the initial entry
+	LastAddPrice  float64 // Entry price of the most recent unit
+	StopLoss      float64
+	TakeProfit    float64
+	AddOnOrderIDs []int64
+	PendingAdd    *PendingAdd // Add-on order placed but not yet filled
+	StopHit       bool        // Mark crossed StopLoss; the bracket stop has triggered
+	Strategy      string      // Strategy that opened the position ("" = the scalper)
+}
+
+// strategyName is the strategy the position's trade results are recorded under
+func (pos *ScalpPosition) strategyName() string {
+	if pos.Strategy != "" {
+		return pos.Strategy
+	}
+	return scalpStrategyName
+}
+
+// PendingAdd is a resting pyramid add-on. It joins the position, and the held units'
+// stops are tightened, only once it fills.
+type PendingAdd struct {
+	OrderID   int64
+	ProductID int
+	Size      int
+	Price     float64
+	StopLoss  float64
+	StopPrice string // Rounded bracket stop for the held units
+}
+
+// BasisPosition is an open funding arbitrage position. A hedged one also holds a dated
+// future against the perp, which must be closed with it.
+type BasisPosition struct {
+	Symbol  string
+	Side    string
+	Size    int
+	OrderID int64
+	Hedge   *HedgeLeg // nil when unhedged
+}
+
+// minRegimeCandles is the minimum number of regime-timeframe candles needed for detection
+const minRegimeCandles = 50
+
+// rollingSharpeWindow is the number of performance snapshots behind the reported rolling Sharpe
+const rollingSharpeWindow = 50
+
+// minStrategyCandles is the minimum number of candles before a symbol's strategies are consulted
+const minStrategyCandles = 50
+
+// minFeatureCandles is the minimum number of candles needed to compute features (volatility window)
+const minFeatureCandles = 20
+
+type regimeState struct {
+	Regime     delta.MarketRegime
+	Confidence float64
 }
 
 type PerformanceSnapshot struct {
-	Timestamp     time.Time
-	Equity        float64
-	RealizedPnL   float64
-	UnrealizedPnL float64
-	Positions     int
+	Timestamp     time.Time `json:"timestamp"`
+	Equity        float64   `json:"equity"`
+	RealizedPnL   float64   `json:"realized_pnl"`
+	UnrealizedPnL float64   `json:"unrealized_pnl"`
+	FundingPaid   float64   `json:"funding_paid"` // Sum of realized_funding across positions, as reported by the API
+	Positions     int       `json:"positions"`
 }
 
 type PerformanceTracker struct {
@@ -65,6 +134,20 @@ func (pt *PerformanceTracker) Record(s PerformanceSnapshot) {
 	}
 }
 
+// SetStartEquity seeds the baseline (e.g. from a previous session) so PnL stays continuous
+func (pt *PerformanceTracker) SetStartEquity(equity float64) {
+	pt.mu.Lock()
+	defer pt.mu.Unlock()
+	pt.startEquity = equity
+}
+
+// StartEquity returns the PnL baseline
+func (pt *PerformanceTracker) StartEquity() float64 {
+	pt.mu.RLock()
+	defer pt.mu.RUnlock()
+	return pt.startEquity
+}
+
 func (pt *PerformanceTracker) Report() map[string]interface{} {
 	pt.mu.RLock()
 	defer pt.mu.RUnlock()
@@ -85,38 +168,187 @@ func (pt *PerformanceTracker) Report() map[string]interface{} {
 		"last_timestamp":   last.Timestamp,
 		"realized_pnl":     last.RealizedPnL,
 		"unrealized_pnl":   last.UnrealizedPnL,
+		"funding_paid":     last.FundingPaid,
 		"open_positions":   last.Positions,
 		"snapshots_stored": len(pt.snapshots),
+		"rolling_sharpe":   pt.rollingSharpe(rollingSharpeWindow),
 	}
 }
 
+// RollingSharpe returns the annualized Sharpe of the equity changes over the last window
+// snapshots, or 0 until that many have been recorded. Snapshot spacing sets the annualization.
+func (pt *PerformanceTracker) RollingSharpe(window int) float64 {
+	pt.mu.RLock()
+	defer pt.mu.RUnlock()
+	return pt.rollingSharpe(window)
+}
+
+func (pt *PerformanceTracker) rollingSharpe(window int) float64 {
+	if window < 3 || len(pt.snapshots) < window {
+		return 0
+	}
+	recent := pt.snapshots[len(pt.snapshots)-window:]
+
+	returns := make([]float64, 0, window-1)
+	for i := 1; i < len(recent); i++ {
+		if prev := recent[i-1].Equity; prev > 0 {
+			returns = append(returns, (recent[i].Equity-prev)/prev)
+		}
+	}
+	span := recent[len(recent)-1].Timestamp.Sub(recent[0].Timestamp)
+	if len(returns) < 2 || span <= 0 {
+		return 0
+	}
+
+	mean := 0.0
+	for _, r := range returns {
+		mean += r
+	}
+	mean /= float64(len(returns))
+	variance := 0.0
+	for _, r := range returns {
+		variance += (r - mean) * (r - mean)
+	}
+	stdDev := math.Sqrt(variance / float64(len(returns)))
+	if stdDev == 0 {
+		return 0
+	}
+
+	periodsPerYear := float64(365*24*time.Hour) / (float64(span) / float64(window-1))
+	return mean / stdDev * math.Sqrt(periodsPerYear)
+}
+
 type StructuralBot struct {
 	cfg            *config.Config
 	deltaClient    *delta.Client
 	wsClient       *delta.WebSocketClient
 	riskManager    *risk.RiskManager
 	driverSelector *strategy.DriverSelector
+	selector       signalSelector // Picks entry signals; the driver selector outside tests
 	perfTracker    *PerformanceTracker
+	perfLog        *PerfLog
+	alerter        alert.Alerter
+	watchdog       *DataWatchdog
+	orderSweeper   *delta.OrderSweeper
+	orderGovernor  *risk.OrderGovernor
+	eventFreeze    risk.EventFreeze
+	shadow         *ShadowTrader // Candidate config evaluated alongside live trading (nil = off)
+	clock          *botClock     // Shared with the strategies
 
 	mu                  sync.RWMutex
 	currentProduct      *delta.Product
 	candles             map[string][]delta.Candle
+	resCandles          map[string]map[string][]delta.Candle // Other streamed resolutions: resolution -> symbol -> candles
+	closedBars          map[string]int64                     // Open time of each symbol's latest closed bar
+	evaluatedBars       map[string]int64                     // Latest closed bar already evaluated for entries
 	lastTickers         map[string]*delta.Ticker
 	lastOrderbooks      map[string]*delta.Orderbook
 	lastFeatures        map[string]features.MarketFeatures
 	scalpPositions      map[string]*ScalpPosition
-	basisPositions      map[string]bool
+	basisPositions      map[string]*BasisPosition
 	gridOrderIDToSymbol map[int64]string
 	activeGridSymbol    string
+	disabledSymbols     map[string]bool      // Symbols switched off at runtime via the control server
+	illiquidSymbols     map[string]bool      // Symbols below MinSymbolVolumeUSD as of the last trading cycle
+	untradeableSymbols  map[string]string    // Symbols whose product can't take orders, with the reason
+	productCheckedAt    map[string]time.Time // When each cached product's status was last fetched
+	eventFrozen         bool                 // Inside an event freeze as of the last trading cycle
+	controlServer       *http.Server
 	isRunning           bool
 	stopChan            chan struct{}
 	stopOnce            sync.Once
 	lastPerfUpdate      time.Time
+	lastScalpReconcile  time.Time // Bot time scalps were last reconciled against exchange positions
+	lastBalanceRefresh  time.Time // Wall time of the last equity fetch for risk checks
 	productCache        map[string]*delta.Product
+	regimeDetector      features.RegimeDetector
+	regimes             map[string]regimeState
+	regimeConfirm       map[string]*regimeConfirmation // Per-symbol confirmation of regime changes
+	orderSeq            map[string]uint64              // Per-symbol sequence hashed into client order IDs
 }
 
 func NewStructuralBot(cfg *config.Config) *StructuralBot {
-	driverCfg := strategy.DriverSelectorConfig{
+	return NewStructuralBotWithClient(cfg, delta.NewClient(cfg))
+}
+
+// NewStructuralBotWithClient creates a bot that trades through deltaClient, so several bots
+// can run on separate (sub-)accounts from one config
+func NewStructuralBotWithClient(cfg *config.Config, deltaClient *delta.Client) *StructuralBot {
+	perfTracker := NewPerformanceTracker(500)
+	var perfLog *PerfLog
+	if cfg.PerfLogPath != "" {
+		perfLog = NewPerfLog(cfg.PerfLogPath, int64(cfg.PerfLogMaxMB)*1024*1024)
+		startEquity, ok, err := perfLog.LoadStartEquity()
+		if err != nil {
+			log.Printf("Warning: failed to read perf log %s: %v", cfg.PerfLogPath, err)
+		} else if ok {
+			perfTracker.SetStartEquity(startEquity)
+			log.Printf("Resumed start equity %.2f from %s", startEquity, cfg.PerfLogPath)
+		}
+	}
+
+	alerter := alert.New(cfg)
+	deltaClient.SetAlerter(alerter)
+	riskManager := risk.NewRiskManager(cfg)
+	riskManager.SetAlerter(alerter)
+
+	var shadow *ShadowTrader
+	if cfg.ShadowEnabled {
+		shadow = NewShadowTrader(strategy.NewDriverSelector(driverSelectorConfig(shadowConfig(cfg))), cfg)
+	}
+
+	eventFreeze := risk.EventFreeze{
+		Window:  cfg.EventFreezeWindow,
+		Funding: cfg.EventFreezeFunding,
+		Events:  cfg.EventFreezeTimes,
+	}
+
+	clock := newBotClock()
+	driverSelector := strategy.NewDriverSelector(driverSelectorConfig(cfg))
+	driverSelector.SetClock(clock)
+
+	return &StructuralBot{
+		cfg:                 cfg,
+		deltaClient:         deltaClient,
+		wsClient:            delta.NewWebSocketClient(cfg),
+		riskManager:         riskManager,
+		alerter:             alerter,
+		driverSelector:      driverSelector,
+		selector:            driverSelector,
+		shadow:              shadow,
+		clock:               clock,
+		perfTracker:         perfTracker,
+		perfLog:             perfLog,
+		watchdog:            NewDataWatchdog(cfg.StaleDataTimeout),
+		orderSweeper:        delta.NewOrderSweeper(deltaClient),
+		orderGovernor:       risk.NewOrderGovernor(cfg.MaxOrdersPerMinute),
+		eventFreeze:         eventFreeze,
+		candles:             make(map[string][]delta.Candle),
+		resCandles:          make(map[string]map[string][]delta.Candle),
+		closedBars:          make(map[string]int64),
+		evaluatedBars:       make(map[string]int64),
+		orderSeq:            make(map[string]uint64),
+		lastTickers:         make(map[string]*delta.Ticker),
+		lastOrderbooks:      make(map[string]*delta.Orderbook),
+		lastFeatures:        make(map[string]features.MarketFeatures),
+		scalpPositions:      make(map[string]*ScalpPosition),
+		basisPositions:      make(map[string]*BasisPosition),
+		gridOrderIDToSymbol: make(map[int64]string),
+		activeGridSymbol:    "",
+		disabledSymbols:     make(map[string]bool),
+		illiquidSymbols:     make(map[string]bool),
+		untradeableSymbols:  make(map[string]string),
+		productCheckedAt:    make(map[string]time.Time),
+		stopChan:            make(chan struct{}),
+		productCache:        make(map[string]*delta.Product),
+		regimes:             make(map[string]regimeState),
+		regimeConfirm:       make(map[string]*regimeConfirmation),
+	}
+}
+
+// driverSelectorConfig builds the strategy configuration for cfg
+func driverSelectorConfig(cfg *config.Config) strategy.DriverSelectorConfig {
+	return strategy.DriverSelectorConfig{
 		ScalperConfig: strategy.ScalperConfig{
 			ImbalanceThreshold:   cfg.ScalpImbalanceThreshold,
 			PersistenceSnapshots: cfg.ScalpPersistenceCount,
@@ -124,8 +356,10 @@ func NewStructuralBot(cfg *config.Config) *StructuralBot {
 			MaxLossBps:           cfg.ScalpMaxLossBps,
 			MinSpreadBps:         1.0,
 			MaxSpreadBps:         10.0,
+			MaxSpreadPercentile:  cfg.ScalpMaxSpreadPctile,
 			ScalpWindowBTC:       30 * time.Minute,
 			ScalpWindowOther:     15 * time.Minute,
+			FeeWindows:           cfg.ScalpFeeWindows,
 			ConfirmationPricePct: 0.02,
 			Enabled:              cfg.ScalperEnabled,
 		},
@@ -134,57 +368,89 @@ func NewStructuralBot(cfg *config.Config) *StructuralBot {
 			ExitThresholdAnnualized:  cfg.BasisExitThreshold,
 			MaxHoldingHours:          24,
 			MaxPositionPct:           33.0,
+			PriceStopPct:             cfg.BasisPriceStopPct,
+			PriceTargetPct:           cfg.BasisPriceTargetPct,
+			PriceSource:              cfg.ExitPriceSource,
 			Enabled:                  cfg.BasisTradeEnabled,
 		},
-		GridConfig: strategy.DefaultGridConfig(),
+		GridConfig:     gridConfig(cfg),
+		BreakoutConfig: breakoutConfig(cfg),
 	}
+}
 
-	return &StructuralBot{
-		cfg:                 cfg,
-		deltaClient:         delta.NewClient(cfg),
-		wsClient:            delta.NewWebSocketClient(cfg),
-		riskManager:         risk.NewRiskManager(cfg),
-		driverSelector:      strategy.NewDriverSelector(driverCfg),
-		perfTracker:         NewPerformanceTracker(500),
-		candles:             make(map[string][]delta.Candle),
-		lastTickers:         make(map[string]*delta.Ticker),
-		lastOrderbooks:      make(map[string]*delta.Orderbook),
-		lastFeatures:        make(map[string]features.MarketFeatures),
-		scalpPositions:      make(map[string]*ScalpPosition),
-		basisPositions:      make(map[string]bool),
-		gridOrderIDToSymbol: make(map[int64]string),
-		activeGridSymbol:    "",
-		stopChan:            make(chan struct{}),
-		productCache:        make(map[string]*delta.Product),
-	}
+// breakoutConfig is the default breakout, enabled per BreakoutEnabled
+func breakoutConfig(cfg *config.Config) strategy.BreakoutConfig {
+	c := strategy.DefaultBreakoutConfig()
+	c.Enabled = cfg.BreakoutEnabled
+	return c
+}
+
+// gridConfig is the default grid with the configured inventory cap
+func gridConfig(cfg *config.Config) strategy.GridConfig {
+	grid := strategy.DefaultGridConfig()
+	grid.MaxInventory = cfg.GridMaxInventory
+	return grid
+}
+
+// SetRegimeDetector installs the detector used by the regime update loop
+func (bot *StructuralBot) SetRegimeDetector(d features.RegimeDetector) {
+	bot.mu.Lock()
+	defer bot.mu.Unlock()
+	bot.regimeDetector = d
 }
 
 func (bot *StructuralBot) Initialize() error {
 	log.Println("Initializing structural trading bot...")
 
 	for _, symbol := range bot.cfg.Symbols {
-		product, err := bot.deltaClient.GetProductBySymbol(symbol)
-		if err != nil {
+		var product *delta.Product
+		err := withInitRetry(func() (err error) {
+			product, err = bot.deltaClient.GetProductBySymbol(symbol)
+			return err
+		})
+		switch {
+		case delta.IsAuthError(err):
+			return fmt.Errorf("failed to get product for %s: %w", symbol, err)
+		case delta.IsNotFound(err):
+			log.Printf("Warning: unknown symbol %s, skipping: %v", symbol, err)
+			continue
+		case err != nil:
 			log.Printf("Warning: failed to get product for %s: %v", symbol, err)
 			continue
 		}
 
+		// A halted product is still cached: the status refresh in tradeableProducts brings
+		// it back once it trades again
 		bot.productCache[symbol] = product
-		if bot.currentProduct == nil {
+		bot.productCheckedAt[symbol] = bot.now()
+		if ok, reason := delta.ProductTradeable(product, bot.now(), bot.cfg.MinTimeToSettlement); !ok {
+			slog.Warn("Product not tradeable, excluded", logger.KeySymbol, symbol, "reason", reason)
+			bot.untradeableSymbols[symbol] = reason
+		} else if bot.currentProduct == nil {
 			bot.currentProduct = product
 		}
 		log.Printf("Loaded product: %s (ID: %d)", symbol, product.ID)
 
 		if err := bot.deltaClient.SetLeverage(product.ID, bot.cfg.Leverage); err != nil {
+			if delta.IsAuthError(err) {
+				return fmt.Errorf("failed to set leverage for %s: %w", symbol, err)
+			}
 			log.Printf("Warning: failed to set leverage for %s: %v", symbol, err)
 		}
 
-		candles, err := bot.deltaClient.GetRecentCandles(symbol, bot.cfg.CandleInterval, 200)
+		// Enough history for the most demanding strategy's indicators to warm up
+		history := max(bot.cfg.CandleHistoryBars, bot.driverSelector.RequiredHistory())
+		var candles []delta.Candle
+		err = withInitRetry(func() (err error) {
+			candles, err = bot.deltaClient.GetRecentCandles(symbol, bot.cfg.CandleInterval, history)
+			return err
+		})
 		if err != nil {
 			log.Printf("Warning: failed to get initial candles for %s: %v", symbol, err)
 			continue
 		}
-		bot.candles[symbol] = candles
+		// Delta doesn't promise an order; the WS merge expects oldest first
+		bot.candles[symbol] = mergeCandleHistory(nil, candles, bot.candleBufferSize())
 
 		orderbook, err := bot.deltaClient.GetOrderbook(symbol)
 		if err == nil {
@@ -204,6 +470,27 @@ func (bot *StructuralBot) Initialize() error {
 	return nil
 }
 
+// Retries of transient API errors during Initialize, on top of the client's own retries
+var (
+	initAttempts = 3
+	initBackoff  = 2 * time.Second
+)
+
+// withInitRetry runs op, backing off and retrying while it fails with a transient error
+func withInitRetry(op func() error) error {
+	var err error
+	for attempt := 1; attempt <= initAttempts; attempt++ {
+		if err = op(); err == nil || !delta.IsTransient(err) {
+			return err
+		}
+		if attempt < initAttempts {
+			log.Printf("Transient API error, retrying in %v: %v", time.Duration(attempt)*initBackoff, err)
+			time.Sleep(time.Duration(attempt) * initBackoff)
+		}
+	}
+	return err
+}
+
 func (bot *StructuralBot) Start() error {
 	bot.mu.Lock()
 	if bot.isRunning {
@@ -214,17 +501,26 @@ func (bot *StructuralBot) Start() error {
 	bot.mu.Unlock()
 
 	bot.wsClient.OnTicker(bot.handleTicker)
-	bot.wsClient.OnCandleWithSymbol(bot.handleCandleWithSymbol)
+	bot.wsClient.OnCandleWithSymbolResolution(bot.handleCandle)
 	bot.wsClient.OnOrderbook(bot.handleOrderbook)
 	bot.wsClient.OnError(bot.handleWSError)
+	bot.wsClient.OnReconnect(bot.handleWSReconnect)
+	bot.wsClient.OnReconnectFailed(bot.handleWSReconnectFailed)
+	bot.wsClient.OnReconnectGiveUp(bot.handleWSReconnectGiveUp)
 
 	if err := bot.wsClient.Connect(); err != nil {
 		return fmt.Errorf("failed to connect websocket: %w", err)
 	}
 
+	now := time.Now()
 	for _, symbol := range bot.cfg.Symbols {
+		bot.watchdog.Touch(tickerChannel(symbol), now)
+		bot.watchdog.Touch(candleChannel(symbol), now)
 		bot.wsClient.SubscribeTicker(symbol)
 		bot.wsClient.SubscribeCandles(symbol, bot.cfg.CandleInterval)
+		if interval := bot.regimeInterval(); interval != bot.cfg.CandleInterval {
+			bot.wsClient.SubscribeCandles(symbol, interval)
+		}
 		bot.wsClient.SubscribeOrderbook(symbol)
 		bot.wsClient.SubscribeFundingRate([]string{symbol})
 	}
@@ -233,6 +529,10 @@ func (bot *StructuralBot) Start() error {
 	go bot.featureUpdateLoop()
 	go bot.scalpExitMonitor()
 	go bot.gridFillMonitor()
+	go bot.regimeLoop()
+	go bot.staleDataMonitor()
+	go bot.orderSweepLoop()
+	bot.startControlServer()
 
 	log.Printf("Structural bot started - Symbols: %v", bot.cfg.Symbols)
 	return nil
@@ -275,6 +575,7 @@ func (bot *StructuralBot) scalpExitMonitor() {
 		case <-bot.stopChan:
 			return
 		case <-ticker.C:
+			bot.checkPendingAdds()
 			bot.checkScalpExits()
 		}
 	}
@@ -294,6 +595,174 @@ func (bot *StructuralBot) gridFillMonitor() {
 	}
 }
 
+func (bot *StructuralBot) staleDataMonitor() {
+	ticker := time.NewTicker(1 * time.Second)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-bot.stopChan:
+			return
+		case <-ticker.C:
+			bot.checkStaleData(time.Now())
+		}
+	}
+}
+
+// checkStaleData runs the market data dead-man's switch. Entries are paused while any
+// ticker/candle channel is stale; open positions are flattened on the transition to
+// stale when FlattenOnStaleData is set.
+func (bot *StructuralBot) checkStaleData(now time.Time) {
+	staleChannels, changed := bot.watchdog.Check(now)
+	if !changed {
+		return
+	}
+
+	if len(staleChannels) == 0 {
+		log.Println("Market data fresh again - resuming entries")
+		return
+	}
+
+	log.Printf("Market data stale (no update in %v on %v) - pausing entries",
+		bot.cfg.StaleDataTimeout, staleChannels)
+	if bot.cfg.FlattenOnStaleData {
+		bot.flattenAll("stale market data")
+	}
+}
+
+// flattenAll flattens every cached symbol through flattenSymbol, so a symbol whose close
+// fails stays tracked and the closes that succeed are recorded as trade results
+func (bot *StructuralBot) flattenAll(reason string) {
+	log.Printf("Flattening all positions: %s", reason)
+
+	bot.mu.RLock()
+	symbols := make([]string, 0, len(bot.productCache))
+	for sym := range bot.productCache {
+		symbols = append(symbols, sym)
+	}
+	bot.mu.RUnlock()
+	sort.Strings(symbols)
+
+	for _, sym := range symbols {
+		if err := bot.flattenSymbol(sym); err != nil {
+			logger.WithTrade(sym, bot.positionOwner(sym)).Error("Failed to flatten",
+				logger.KeyAction, "flatten", "reason", reason, "error", err)
+		}
+	}
+}
+
+func tickerChannel(symbol string) string { return "ticker:" + symbol }
+
+func candleChannel(symbol string) string { return "candle:" + symbol }
+
+func (bot *StructuralBot) regimeLoop() {
+	period := bot.cfg.RegimeCheckPeriod
+	if period <= 0 {
+		period = 5 * time.Minute
+	}
+	bot.updateMarketRegime()
+
+	ticker := time.NewTicker(period)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-bot.stopChan:
+			return
+		case <-ticker.C:
+			bot.updateMarketRegime()
+		}
+	}
+}
+
+// updateMarketRegime runs the regime detector on RegimeCandleInterval candles per symbol.
+// Streamed candles are aggregated up when they cover enough history, otherwise the
+// higher-timeframe series is fetched over REST. Symbols with fewer than minRegimeCandles
+// candles are skipped. With FlattenOnRegimeChange, a confirmed change of regime (see
+// regimeConfirmation) closes positions that conflict with the new one.
+func (bot *StructuralBot) updateMarketRegime() {
+	bot.mu.RLock()
+	detector := bot.regimeDetector
+	candlesMap := make(map[string][]delta.Candle)
+	for sym, candles := range bot.candles {
+		candlesMap[sym] = make([]delta.Candle, len(candles))
+		copy(candlesMap[sym], candles)
+	}
+	bot.mu.RUnlock()
+
+	if detector == nil {
+		return
+	}
+
+	for _, symbol := range bot.cfg.Symbols {
+		candles := bot.regimeCandles(symbol, candlesMap[symbol])
+		if err := strategy.RequireCandles(candles, minRegimeCandles); err != nil {
+			slog.Warn("Skipping regime update", logger.KeySymbol, symbol, "interval", bot.regimeInterval(), "error", err)
+			continue
+		}
+
+		regime, confidence, err := detector.DetectRegime(symbol, candles)
+		if err != nil {
+			slog.Error("Regime detection failed", logger.KeySymbol, symbol, "error", err)
+			continue
+		}
+
+		bot.mu.Lock()
+		prev, known := bot.regimes[symbol]
+		bot.regimes[symbol] = regimeState{Regime: regime, Confidence: confidence}
+		confirm := bot.regimeConfirm[symbol]
+		if confirm == nil {
+			confirm = &regimeConfirmation{}
+			bot.regimeConfirm[symbol] = confirm
+		}
+		confirmed := confirm.observe(regime, bot.cfg.RegimeConfirmations)
+		bot.mu.Unlock()
+
+		if known && prev.Regime != regime {
+			logger.WithTrade(symbol, bot.positionOwner(symbol)).Info("Regime changed", logger.KeyAction, "regime_change",
+				"from", prev.Regime, "to", regime, "confidence", confidence)
+		}
+		if confirmed && bot.cfg.FlattenOnRegimeChange {
+			bot.flattenOnRegimeChange(symbol, regime)
+		}
+	}
+}
+
+func (bot *StructuralBot) regimeInterval() string {
+	if bot.cfg.RegimeCandleInterval == "" {
+		return bot.cfg.CandleInterval
+	}
+	return bot.cfg.RegimeCandleInterval
+}
+
+// regimeCandles returns the regime-timeframe series for a symbol, preferring candles
+// streamed at that resolution, then aggregated trading candles, then REST
+func (bot *StructuralBot) regimeCandles(symbol string, streamed []delta.Candle) []delta.Candle {
+	interval := bot.regimeInterval()
+	if interval == bot.cfg.CandleInterval {
+		return streamed
+	}
+
+	bot.mu.RLock()
+	direct := append([]delta.Candle(nil), bot.resCandles[interval][symbol]...)
+	bot.mu.RUnlock()
+	if len(direct) >= minRegimeCandles {
+		return direct
+	}
+
+	aggregated := delta.AggregateCandles(streamed, interval)
+	if len(aggregated) >= minRegimeCandles {
+		return aggregated
+	}
+
+	fetched, err := bot.deltaClient.GetRecentCandles(symbol, interval, 2*minRegimeCandles)
+	if err != nil {
+		slog.Warn("Failed to fetch regime candles", logger.KeySymbol, symbol, "interval", interval, "error", err)
+		return aggregated
+	}
+	return fetched
+}
+
 func (bot *StructuralBot) updateFeatures() {
 	bot.mu.RLock()
 	tickersMap := make(map[string]*delta.Ticker)
@@ -317,13 +786,17 @@ func (bot *StructuralBot) updateFeatures() {
 		ob := orderbooksMap[symbol]
 		candles := candlesMap[symbol]
 
-		if tick == nil || len(candles) < 20 {
+		if tick == nil || strategy.RequireCandles(candles, minFeatureCandles) != nil {
 			continue
 		}
 
 		f := engine.ComputeFeaturesWithFunding(ob, tick, candles)
 
 		bot.mu.Lock()
+		if rs, ok := bot.regimes[symbol]; ok {
+			f.HMMRegime = rs.Regime
+			f.HMMConfidence = rs.Confidence
+		}
 		bot.lastFeatures[symbol] = f
 		bot.mu.Unlock()
 	}
@@ -335,6 +808,10 @@ func (bot *StructuralBot) evaluateAndTrade() {
 	candlesMap := make(map[string][]delta.Candle)
 	productsMap := make(map[string]*delta.Product)
 	scalpHasPosition := len(bot.scalpPositions) > 0
+	scalpSymbols := make(map[string]bool, len(bot.scalpPositions))
+	for sym := range bot.scalpPositions {
+		scalpSymbols[sym] = true
+	}
 	basisHasPosition := len(bot.basisPositions) > 0
 	for sym, f := range bot.lastFeatures {
 		featuresMap[sym] = f
@@ -348,15 +825,27 @@ func (bot *StructuralBot) evaluateAndTrade() {
 	}
 	bot.mu.RUnlock()
 
+	if bot.watchdog.IsStale() {
+		return
+	}
+	bot.refreshRiskBalance()
+	bot.evaluateShadow(featuresMap, candlesMap)
+	bot.checkFundingExits(featuresMap, candlesMap)
+	if bot.inEventFreeze(bot.now()) {
+		return
+	}
+
 	canTrade, reason := bot.riskManager.CanTrade()
 	if !canTrade {
 		log.Printf("Trading blocked: %s", reason)
 		return
 	}
+	bot.applyDynamicLeverage()
 
-	for _, symbol := range bot.cfg.Symbols {
+	symbols := bot.tradeableProducts(bot.tradableSymbols(), bot.now())
+	for _, symbol := range bot.liquidSymbols(symbols, scalpSymbols) {
 		f, ok := featuresMap[symbol]
-		if !ok || len(candlesMap[symbol]) < 50 {
+		if !ok || strategy.RequireCandles(candlesMap[symbol], minStrategyCandles) != nil {
 			continue
 		}
 
@@ -365,19 +854,54 @@ func (bot *StructuralBot) evaluateAndTrade() {
 			continue
 		}
 
-		if scalpHasPosition || basisHasPosition {
+		// Recently stopped out, or closed on an opposite signal with reversals off:
+		// don't re-enter into the same move
+		now := bot.now()
+		if bot.riskManager.StopCooldownRemaining(symbol, now) > 0 || bot.riskManager.ReversalCooldownRemaining(symbol, now) > 0 {
+			continue
+		}
+
+		// An open position blocks new entries; the held scalp symbol is still evaluated
+		// for aligned add-ons and opposite-signal reversals
+		held := scalpSymbols[symbol]
+		if basisHasPosition || (scalpHasPosition && !held) {
 			continue
 		}
 
 		candles := candlesMap[symbol]
-		selected, signal := bot.driverSelector.SelectStrategy(f, candles)
+		if bot.cfg.WaitForCandleClose {
+			var ok bool
+			if candles, ok = bot.closedCandles(symbol, candles); !ok {
+				continue
+			}
+		}
+		selected, signal := bot.selector.SelectStrategy(f, candles)
 
 		if signal.Action == strategy.ActionNone {
 			continue
 		}
 
-		log.Printf("[%s] Signal: %s %s (strategy=%s, driver=%s, confidence=%.2f)",
-			symbol, signal.Action, signal.Side, selected.Name, selected.Driver, signal.Confidence)
+		tl := logger.WithTrade(symbol, selected.Name)
+		if floor := confidenceFloor(bot.cfg, selected.Name, f.HMMRegime); signal.Confidence < floor {
+			tl.Info("Skipping signal: confidence below floor",
+				logger.KeyAction, signal.Action, "confidence", signal.Confidence, "floor", floor)
+			continue
+		}
+		if !regimeAllowsSide(bot.cfg, f.HMMRegime, signal) {
+			tl.Info("Skipping signal: side not allowed in regime",
+				logger.KeyAction, signal.Action, "side", signal.Side, "regime", f.HMMRegime)
+			continue
+		}
+
+		if held {
+			if selected.Name == "fee_aware_scalper" {
+				bot.handleHeldScalpSignal(signal, product, symbol, f.HMMRegime)
+			}
+			continue
+		}
+
+		tl.Info("Signal", logger.KeyAction, signal.Action, "side", signal.Side,
+			"driver", selected.Driver, "confidence", signal.Confidence)
 
 		switch selected.Name {
 		case "fee_aware_scalper":
@@ -386,6 +910,8 @@ func (bot *StructuralBot) evaluateAndTrade() {
 			bot.executeFundingArbEntry(signal, product, symbol)
 		case "grid_trading":
 			bot.executeGridEntry(signal, product, symbol)
+		case breakoutStrategyName:
+			bot.executeBreakoutEntry(signal, product, symbol)
 		}
 
 		bot.updatePerformanceIfDue(false, product)
@@ -399,58 +925,121 @@ func (bot *StructuralBot) executeScalpEntry(signal strategy.Signal, product *del
 		return
 	}
 
-	balance, err := bot.deltaClient.GetAvailableBalance("USDT")
-	if err != nil {
-		log.Printf("Failed to get balance: %v", err)
+	tl := logger.WithTrade(symbol, scalper.Name()).With(logger.KeyAction, signal.Action)
+
+	signal, rr, ok := checkRewardRisk(bot.cfg, signal)
+	if !ok {
+		tl.Info("Scalp entry skipped: reward:risk below minimum", "reward_risk", rr, "min", bot.cfg.MinRewardRisk)
 		return
 	}
 
-	positionValue := balance * (bot.cfg.MaxPositionPct / 100) * float64(bot.cfg.Leverage)
-	size, err := delta.NotionalToContracts(positionValue, signal.Price, product)
+	size, err := bot.entrySize(signal, product)
 	if err != nil {
-		log.Printf("Failed to calculate scalp size: %v", err)
+		tl.Error("Failed to size scalp entry", "error", err)
 		return
 	}
-	if size < 1 {
-		size = 1
-	}
 
-	slPrice, _ := delta.RoundToTickSize(signal.StopLoss, product.TickSize)
-	tpPrice, _ := delta.RoundToTickSize(signal.TakeProfit, product.TickSize)
+	slPrice, tpPrice := delta.RoundBracketPrices(signal.Side, signal.StopLoss, signal.TakeProfit, product.TickSize, bot.bracketRounding(signal))
 
 	req := &delta.OrderRequest{
 		ProductID:              product.ID,
 		Size:                   size,
 		Side:                   signal.Side,
 		OrderType:              "limit_order",
-		LimitPrice:             fmt.Sprintf("%.2f", signal.Price),
+		LimitPrice:             delta.FormatPrice(signal.Price, product),
 		BracketStopLossPrice:   slPrice,
 		BracketTakeProfitPrice: tpPrice,
 		TimeInForce:            "gtc",
+		ClientOrderID:          bot.clientOrderID(symbol, signal.Side, size, "scalp"),
+	}
+
+	if err := bot.checkMargin(product, size, signal.Price); err != nil {
+		tl.Warn("Scalp entry skipped", "error", err)
+		return
+	}
+	if !bot.allowOrder("scalp", symbol) {
+		return
 	}
 
 	order, err := bot.deltaClient.PlaceOrder(req)
 	if err != nil {
-		log.Printf("Failed to place scalp order: %v", err)
+		tl.Error("Failed to place scalp order", "error", err)
 		return
 	}
+	bot.trackOrderExpiry("scalp", order.ID)
 
 	bot.mu.Lock()
 	bot.scalpPositions[symbol] = &ScalpPosition{
 		Symbol:     symbol,
 		Side:       signal.Side,
 		Size:       size,
-		EntryTime:  time.Now(),
+		EntryTime:  bot.now(),
 		EntryPrice: signal.Price,
 		OrderID:    order.ID,
+
+		Entries:      1,
+		LastAddPrice: signal.Price,
+		StopLoss:     signal.StopLoss,
+		TakeProfit:   signal.TakeProfit,
 	}
 	bot.mu.Unlock()
 
 	// Track entry in scalper for fee windows
 	scalper.RecordEntry(symbol)
 
-	log.Printf("[%s] Scalp entry: %s %d contracts @ %.2f (SL: %s, TP: %s)",
-		symbol, signal.Side, size, signal.Price, slPrice, tpPrice)
+	tl.Info("Scalp entry", logger.KeyOrderID, order.ID, "side", signal.Side, "size", size,
+		"price", signal.Price, "stop_loss", slPrice, "take_profit", tpPrice)
+}
+
+// entrySize sizes a directional entry at MaxPositionPct of the available balance at the
+// current leverage, scaled by the signal's confidence and the loss streak and profit lock
+// multipliers; at least one contract
+func (bot *StructuralBot) entrySize(signal strategy.Signal, product *delta.Product) (int, error) {
+	balance, err := bot.deltaClient.GetAvailableBalance("USDT")
+	if err != nil {
+		return 0, fmt.Errorf("failed to get balance: %w", err)
+	}
+
+	positionValue := balance * (bot.cfg.MaxPositionPct / 100) * float64(bot.riskManager.Leverage())
+	positionValue *= bot.riskManager.ConfidenceMultiplier(signal.Confidence) * bot.riskManager.LossStreakMultiplier() * bot.riskManager.ProfitLockMultiplier()
+	size, err := delta.NotionalToContracts(positionValue, signal.Price, product)
+	if err != nil {
+		return 0, fmt.Errorf("failed to calculate size: %w", err)
+	}
+	return max(size, 1), nil
+}
+
+// bracketRounding is the signal's own bracket rounding policy, else the configured default
+func (bot *StructuralBot) bracketRounding(signal strategy.Signal) string {
+	if signal.BracketRounding != "" {
+		return signal.BracketRounding
+	}
+	return bot.cfg.BracketRounding
+}
+
+// checkMargin verifies that a new order of size contracts at price fits in the margin left
+// by the account's open positions, so it is not placed only to be rejected by the exchange
+func (bot *StructuralBot) checkMargin(product *delta.Product, size int, price float64) error {
+	notional, err := delta.ContractsToNotional(size, price, product)
+	if err != nil {
+		return fmt.Errorf("failed to compute notional: %w", err)
+	}
+
+	wallet, err := bot.deltaClient.GetWalletByAsset("USDT")
+	if err != nil {
+		return fmt.Errorf("failed to get wallet: %w", err)
+	}
+	balance, err := strconv.ParseFloat(wallet.Balance, 64)
+	if err != nil {
+		return fmt.Errorf("failed to parse wallet balance: %w", err)
+	}
+
+	positions, err := bot.deltaClient.GetPositions()
+	if err != nil {
+		return fmt.Errorf("failed to get positions: %w", err)
+	}
+
+	return bot.riskManager.CheckMargin(notional, product, balance, positions)
 }
 
 func (bot *StructuralBot) executeFundingArbEntry(signal strategy.Signal, product *delta.Product, symbol string) {
@@ -459,45 +1048,71 @@ func (bot *StructuralBot) executeFundingArbEntry(signal strategy.Signal, product
 		return
 	}
 
+	tl := logger.WithTrade(symbol, fundingArb.Name()).With(logger.KeyAction, signal.Action)
+
 	balance, err := bot.deltaClient.GetAvailableBalance("USDT")
 	if err != nil {
-		log.Printf("Failed to get balance: %v", err)
+		tl.Error("Failed to get balance", "error", err)
 		return
 	}
 
-	targetNotional := balance * (bot.cfg.MaxPositionPct / 100) * 5.0
+	targetNotional := balance * (bot.cfg.MaxPositionPct / 100) * float64(bot.riskManager.Leverage())
+	targetNotional *= bot.riskManager.ConfidenceMultiplier(signal.Confidence) * bot.riskManager.LossStreakMultiplier() * bot.riskManager.ProfitLockMultiplier()
 	perpSize, err := delta.NotionalToContracts(targetNotional, signal.Price, product)
 	if err != nil {
-		log.Printf("Failed to calculate funding arb size: %v", err)
+		tl.Error("Failed to calculate funding arb size", "error", err)
 		return
 	}
 	if perpSize < 1 {
 		perpSize = 1
 	}
 
-	// Note: Hedge execution removed - Delta India only offers perpetuals, no dated futures
+	// Delta India only offers perpetuals, so the perp leg is unhedged unless a dated
+	// future is configured for the symbol in BasisHedgeSymbols
 
 	req := &delta.OrderRequest{
-		ProductID:   product.ID,
-		Size:        perpSize,
-		Side:        signal.Side,
-		OrderType:   "limit_order",
-		LimitPrice:  fmt.Sprintf("%.2f", signal.Price),
-		TimeInForce: "gtc",
+		ProductID:     product.ID,
+		Size:          perpSize,
+		Side:          signal.Side,
+		OrderType:     "limit_order",
+		LimitPrice:    delta.FormatPrice(signal.Price, product),
+		TimeInForce:   "gtc",
+		ClientOrderID: bot.clientOrderID(symbol, signal.Side, perpSize, "funding"),
 	}
 
-	order, err := bot.deltaClient.PlaceOrder(req)
+	if err := bot.checkMargin(product, perpSize, signal.Price); err != nil {
+		tl.Warn("Funding arb entry skipped", "error", err)
+		return
+	}
+	if !bot.allowOrder("funding", symbol) {
+		return
+	}
+
+	var order *delta.Order
+	var hedge *HedgeLeg
+	if hedgeSymbol := bot.cfg.BasisHedgeSymbols[symbol]; hedgeSymbol != "" {
+		order, hedge, err = bot.placeHedgedFundingEntry(req, symbol, hedgeSymbol, targetNotional, signal.Price)
+	} else {
+		order, err = bot.deltaClient.PlaceOrder(req)
+	}
 	if err != nil {
-		log.Printf("Failed to place funding arb order: %v", err)
+		tl.Error("Failed to place funding arb order", "error", err)
 		return
 	}
+	bot.trackOrderExpiry("funding", order.ID)
 
 	bot.mu.Lock()
-	bot.basisPositions[symbol] = true
+	bot.basisPositions[symbol] = &BasisPosition{
+		Symbol:  symbol,
+		Side:    signal.Side,
+		Size:    perpSize,
+		OrderID: order.ID,
+		Hedge:   hedge,
+	}
 	bot.mu.Unlock()
 
-	fundingArb.RecordEntry(symbol, signal.Side, 0.0)
-	log.Printf("[%s] Funding Arb entry: %s %d contracts @ %.2f (Order ID: %d)", symbol, signal.Side, perpSize, signal.Price, order.ID)
+	fundingArb.RecordEntry(symbol, signal.Side, 0.0, signal.Price)
+	tl.Info("Funding arb entry", logger.KeyOrderID, order.ID, "side", signal.Side, "size", perpSize, "price", signal.Price)
 }
 
 func (bot *StructuralBot) executeGridEntry(signal strategy.Signal, product *delta.Product, symbol string) {
@@ -506,22 +1121,24 @@ func (bot *StructuralBot) executeGridEntry(signal strategy.Signal, product *delt
 		return
 	}
 
+	tl := logger.WithTrade(symbol, gridTrader.Name())
+
 	levels := gridTrader.GetLevels()
 	if len(levels) == 0 {
-		log.Printf("[%s] Grid trading activated but no levels calculated", symbol)
+		tl.Warn("Grid trading activated but no levels calculated")
 		return
 	}
 
 	balance, err := bot.deltaClient.GetAvailableBalance("USDT")
 	if err != nil {
-		log.Printf("Failed to get balance for grid: %v", err)
+		tl.Error("Failed to get balance for grid", "error", err)
 		return
 	}
 
-	totalGridNotional := balance * 0.05 * float64(bot.cfg.Leverage)
+	totalGridNotional := balance * 0.05 * float64(bot.riskManager.Leverage())
 	sizePerLevel, err := delta.NotionalToContracts(totalGridNotional, levels[0].Price, product)
 	if err != nil {
-		log.Printf("Failed to calculate grid size: %v", err)
+		tl.Error("Failed to calculate grid size", "error", err)
 		return
 	}
 	if sizePerLevel < 1 {
@@ -529,27 +1146,44 @@ func (bot *StructuralBot) executeGridEntry(signal strategy.Signal, product *delt
 	}
 
 	placedOrders := 0
-	for _, level := range levels {
+	for i, level := range levels {
 		if !level.IsActive {
 			continue
 		}
+		if !gridTrader.CanPlace(symbol, level.Side, sizePerLevel) {
+			tl.Info("Grid level skipped: inventory at max", "side", level.Side,
+				"inventory", gridTrader.Inventory(symbol), "max", bot.cfg.GridMaxInventory)
+			continue
+		}
+
+		if !bot.allowOrder("grid", symbol) {
+			break
+		}
 
 		priceStr, _ := delta.RoundToTickSize(level.Price, product.TickSize)
 
 		req := &delta.OrderRequest{
-			ProductID:   product.ID,
-			Size:        sizePerLevel,
-			Side:        level.Side,
-			OrderType:   "limit_order",
-			LimitPrice:  priceStr,
-			TimeInForce: "gtc",
+			ProductID:     product.ID,
+			Size:          sizePerLevel,
+			Side:          level.Side,
+			OrderType:     "limit_order",
+			LimitPrice:    priceStr,
+			TimeInForce:   "gtc",
+			ReduceOnly:    gridTrader.IsReduceOnly(symbol, level.Side),
+			ClientOrderID: bot.clientOrderID(symbol, level.Side, sizePerLevel, "grid:"+priceStr),
 		}
 
 		order, err := bot.deltaClient.PlaceOrder(req)
 		if err != nil {
-			log.Printf("[%s] Failed to place grid order at %s: %v", symbol, priceStr, err)
+			tl.Error("Failed to place grid order", "side", level.Side, "price", priceStr, "error", err)
+			// Remaining levels need the same margin, so stop instead of hammering the API
+			if delta.RejectReasonOf(err) == delta.RejectInsufficientMargin {
+				break
+			}
 			continue
 		}
+		bot.trackOrderExpiry("grid", order.ID)
+		gridTrader.AssignOrder(i, symbol, order.ID, sizePerLevel)
 
 		bot.mu.Lock()
 		bot.gridOrderIDToSymbol[order.ID] = symbol
@@ -558,7 +1192,7 @@ func (bot *StructuralBot) executeGridEntry(signal strategy.Signal, product *delt
 		placedOrders++
 	}
 
-	log.Printf("[%s] Grid trading activated: placed %d/%d orders (size: %d contracts)", symbol, placedOrders, len(levels), sizePerLevel)
+	tl.Info("Grid trading activated", "placed", placedOrders, "levels", len(levels), "size", sizePerLevel)
 }
 
 func (bot *StructuralBot) checkScalpExits() {
@@ -573,17 +1207,94 @@ func (bot *StructuralBot) checkScalpExits() {
 		positions = append(positions, p)
 	}
 	bot.mu.RUnlock()
+	if len(positions) == 0 {
+		return
+	}
+
+	// Bracket exits are reconciled on the performance cadence, not every tick
+	var held map[int]int
+	if now := bot.now(); now.Sub(bot.lastScalpReconcile) >= perfUpdateInterval {
+		bot.lastScalpReconcile = now
+		var err error
+		if held, err = bot.heldSizes(); err != nil {
+			slog.Warn("Failed to reconcile scalp positions", "error", err)
+		}
+	}
 
 	for _, pos := range positions {
+		bot.checkStopHit(pos)
+		if held != nil && bot.reconcileScalp(pos, held) {
+			continue
+		}
+		bot.checkBreakeven(pos, scalper.GetFeeWindow(pos.Symbol))
+
 		feeWindowActive := scalper.ShouldCloseForFees(pos.Symbol)
-		timeRemaining := scalper.GetFeeWindow(pos.Symbol) - time.Since(pos.EntryTime)
+		timeRemaining := scalper.GetFeeWindow(pos.Symbol) - bot.now().Sub(pos.EntryTime)
 
 		if timeRemaining < 30*time.Second && timeRemaining > 0 && feeWindowActive {
-			log.Printf("Fee window expiring in %v for %s - consider closing", timeRemaining, pos.Symbol)
+			logger.WithTrade(pos.Symbol, scalper.Name()).Info("Fee window expiring - consider closing",
+				logger.KeyOrderID, pos.OrderID, "remaining", timeRemaining)
 		}
 	}
 }
 
+// checkStopHit starts the post-stop cooldown once the exit price source crosses a scalp's stop.
+// The exchange bracket executes the exit; reconcileScalp records its result.
+func (bot *StructuralBot) checkStopHit(pos *ScalpPosition) {
+	bot.mu.Lock()
+	defer bot.mu.Unlock()
+
+	ticker := bot.lastTickers[pos.Symbol]
+	if pos.StopHit || pos.StopLoss <= 0 || ticker == nil {
+		return
+	}
+	price := ticker.ReferencePrice(bot.cfg.ExitPriceSource)
+	if price <= 0 {
+		return
+	}
+	hit := price <= pos.StopLoss
+	if pos.Side == "sell" {
+		hit = price >= pos.StopLoss
+	}
+	if !hit {
+		return
+	}
+
+	pos.StopHit = true
+	bot.riskManager.RecordStopLoss(pos.Symbol, bot.now())
+	if bot.cfg.StopCooldown > 0 {
+		logger.WithTrade(pos.Symbol, scalpStrategyName).Info("Stop-loss hit - pausing entries",
+			logger.KeyOrderID, pos.OrderID, "price", price, "cooldown", bot.cfg.StopCooldown)
+	}
+}
+
+// MoveStopToBreakeven amends the scalp's bracket stop-loss to the entry price in place,
+// leaving the take-profit untouched. If the bracket has already triggered, the scalp is
+// settled and no longer tracked.
+func (bot *StructuralBot) MoveStopToBreakeven(pos *ScalpPosition, entryPrice float64) error {
+	bot.mu.RLock()
+	product := bot.productCache[pos.Symbol]
+	bot.mu.RUnlock()
+	if product == nil {
+		return fmt.Errorf("no product cached for %s", pos.Symbol)
+	}
+
+	tl := logger.WithTrade(pos.Symbol, scalpStrategyName).With(logger.KeyOrderID, pos.OrderID)
+	// Rounded with the configured bracket policy, like the entry's bracket
+	slPrice, _ := delta.RoundBracketPrices(pos.Side, entryPrice, 0, product.TickSize, bot.cfg.BracketRounding)
+	err := bot.deltaClient.EditBracket(pos.OrderID, product.ID, slPrice, "")
+	if errors.Is(err, delta.ErrBracketNotFound) {
+		bot.settleScalp(pos, "bracket already triggered")
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("move stop to breakeven for %s: %w", pos.Symbol, err)
+	}
+
+	tl.Info("Stop moved to breakeven", "stop_loss", slPrice)
+	return nil
+}
+
 func (bot *StructuralBot) checkGridFills() {
 	bot.mu.RLock()
 	gridOrderIDs := make([]int64, 0, len(bot.gridOrderIDToSymbol))
@@ -604,52 +1315,112 @@ func (bot *StructuralBot) checkGridFills() {
 	for _, orderID := range gridOrderIDs {
 		order, err := bot.deltaClient.GetOrderByID(orderID)
 		if err != nil {
-			log.Printf("Failed to get grid order %d: %v", orderID, err)
+			slog.Error("Failed to get grid order", logger.KeyStrategy, gridTrader.Name(), logger.KeyOrderID, orderID, "error", err)
 			continue
 		}
 
-		if order.State == "filled" || order.State == "closed" {
+		if delta.ParseOrderState(order.State).IsFilled() {
 			signal := gridTrader.OnFill(orderID)
 			bot.mu.Lock()
 			delete(bot.gridOrderIDToSymbol, orderID)
 			bot.mu.Unlock()
 
 			if signal.Action != strategy.ActionNone {
-				log.Printf("[GRID] Order %d filled: %s", orderID, signal.Reason)
+				slog.Info("Grid order filled", logger.KeyStrategy, gridTrader.Name(), logger.KeyOrderID, orderID,
+					logger.KeyAction, signal.Action, "reason", signal.Reason)
 			}
 		}
 	}
 }
 
 func (bot *StructuralBot) handleTicker(ticker delta.Ticker) {
+	bot.watchdog.Touch(tickerChannel(ticker.Symbol), time.Now())
 	bot.mu.Lock()
 	defer bot.mu.Unlock()
 	bot.lastTickers[ticker.Symbol] = &ticker
 }
 
-func (bot *StructuralBot) handleCandleWithSymbol(symbol string, candle delta.Candle) {
+// handleCandle routes a streamed candle to its resolution's buffer. Candles at CandleInterval
+// (or without a resolution) feed the trading buffer; others are kept per resolution. Bars
+// missing between the trading buffer and the streamed candle are backfilled over REST first.
+func (bot *StructuralBot) handleCandle(symbol, resolution string, candle delta.Candle) {
+	if resolution == "" || resolution == bot.cfg.CandleInterval {
+		bot.watchdog.Touch(candleChannel(symbol), time.Now())
+		if from, gap := bot.candleGap(symbol, candle); gap {
+			bot.backfillCandles(symbol, from, candle.Time)
+		}
+		bot.mu.Lock()
+		defer bot.mu.Unlock()
+		prev := bot.candles[symbol]
+		candles, appended := mergeCandle(prev, candle, bot.candleBufferSize())
+		if appended && len(prev) > 0 {
+			// A new bar opening closes the previous one
+			bot.closedBars[symbol] = prev[len(prev)-1].Time
+		}
+		bot.candles[symbol] = candles
+		return
+	}
+
+	bot.mu.Lock()
+	defer bot.mu.Unlock()
+	bySymbol, ok := bot.resCandles[resolution]
+	if !ok {
+		bySymbol = make(map[string][]delta.Candle)
+		bot.resCandles[resolution] = bySymbol
+	}
+	bySymbol[symbol], _ = mergeCandle(bySymbol[symbol], candle, maxCandleBuffer)
+}
+
+// closedCandles drops the forming bar from a symbol's candles and reports whether a bar
+// has closed since the last call, marking it evaluated so each bar is acted on once
+func (bot *StructuralBot) closedCandles(symbol string, candles []delta.Candle) ([]delta.Candle, bool) {
 	bot.mu.Lock()
 	defer bot.mu.Unlock()
 
-	if _, ok := bot.candles[symbol]; !ok {
-		bot.candles[symbol] = make([]delta.Candle, 0)
+	closed, ok := bot.closedBars[symbol]
+	if last, done := bot.evaluatedBars[symbol]; !ok || (done && last == closed) || len(candles) < 2 {
+		return nil, false
 	}
+	bot.evaluatedBars[symbol] = closed
+	return candles[:len(candles)-1], true
+}
 
-	candles := bot.candles[symbol]
+// clientOrderID returns the client_order_id for an order on symbol, hashed from the symbol's
+// latest candle and its next order sequence number rather than wall time, so a re-entry on
+// the same candle gets a fresh ID
+func (bot *StructuralBot) clientOrderID(symbol, side string, size int, tag string) string {
+	bot.mu.Lock()
+	bot.orderSeq[symbol]++
+	seq := bot.orderSeq[symbol]
+	var candleTime time.Time
+	if candles := bot.candles[symbol]; len(candles) > 0 {
+		candleTime = time.Unix(candles[len(candles)-1].Time, 0)
+	}
+	bot.mu.Unlock()
+
+	return delta.GenerateClientOrderID(symbol, side, size, candleTime, seq, tag)
+}
+
+// mergeCandle updates the forming bar in place or appends a new one, keeping the last
+// limit; older candles are dropped. Reports whether a new bar was appended.
+func mergeCandle(candles []delta.Candle, candle delta.Candle, limit int) ([]delta.Candle, bool) {
+	appended := false
 	if len(candles) > 0 {
 		lastCandle := &candles[len(candles)-1]
 		if candle.Time == lastCandle.Time {
 			candles[len(candles)-1] = candle
 		} else if candle.Time > lastCandle.Time {
 			candles = append(candles, candle)
-			if len(candles) > 500 {
-				candles = candles[len(candles)-500:]
+			appended = true
+			if len(candles) > limit {
+				candles = candles[len(candles)-limit:]
 			}
 		}
 	} else {
 		candles = append(candles, candle)
+		appended = true
 	}
-	bot.candles[symbol] = candles
+	return candles, appended
 }
 
 func (bot *StructuralBot) handleOrderbook(data json.RawMessage) {
@@ -666,6 +1437,28 @@ func (bot *StructuralBot) handleWSError(err error) {
 	log.Printf("WebSocket error: %v", err)
 }
 
+func (bot *StructuralBot) handleWSReconnect() {
+	log.Println("WebSocket reconnected - resetting market data watchdog")
+	bot.watchdog.Reset(time.Now())
+}
+
+// reconnectAlertEvery is how many consecutive failed reconnects trigger (and repeat) an alert
+const reconnectAlertEvery = 5
+
+func (bot *StructuralBot) handleWSReconnectFailed(attempt int, err error) {
+	if attempt%reconnectAlertEvery == 0 {
+		bot.alerter.Alert(alert.LevelError, fmt.Sprintf("WebSocket reconnect failed %d times in a row: %v", attempt, err))
+	}
+}
+
+// handleWSReconnectGiveUp stops the bot once the WebSocket client has exhausted
+// MaxReconnectAttempts, rather than trading blind. Open positions keep their exchange-side
+// brackets.
+func (bot *StructuralBot) handleWSReconnectGiveUp(attempts int, err error) {
+	bot.alerter.Alert(alert.LevelError, fmt.Sprintf("WebSocket gave up after %d failed reconnects (%v); stopping bot", attempts, err))
+	bot.Stop()
+}
+
 func (bot *StructuralBot) Stop() {
 	bot.stopOnce.Do(func() {
 		log.Println("Stopping structural bot...")
@@ -673,28 +1466,27 @@ func (bot *StructuralBot) Stop() {
 		bot.isRunning = false
 		bot.mu.Unlock()
 		close(bot.stopChan)
+		bot.stopControlServer()
 		bot.wsClient.Close()
 		bot.deltaClient.Close()
+		// Deliver queued alerts, e.g. the one that stopped the bot, before the process exits
+		alert.Close(bot.alerter)
 		log.Println("Bot stopped")
 	})
 }
 
+// perfUpdateInterval spaces performance snapshots and the reconciliation of scalps closed by
+// their bracket on the exchange
+const perfUpdateInterval = 60 * time.Second
+
 func (bot *StructuralBot) updatePerformanceIfDue(force bool, product *delta.Product) {
-	if !force && time.Since(bot.lastPerfUpdate) < 60*time.Second {
+	if !force && bot.now().Sub(bot.lastPerfUpdate) < perfUpdateInterval {
 		return
 	}
 
-	equity, err := bot.deltaClient.GetNetEquity()
+	equity, err := bot.accountEquity(product)
 	if err != nil {
-		settling := "USDT"
-		if product != nil && product.SettlingAsset.Symbol != "" {
-			settling = product.SettlingAsset.Symbol
-		}
-		if bal, err := bot.deltaClient.GetAvailableBalance(settling); err == nil {
-			equity = bal
-		} else {
-			return
-		}
+		return
 	}
 
 	positions, err := bot.deltaClient.GetPositions()
@@ -702,24 +1494,26 @@ func (bot *StructuralBot) updatePerformanceIfDue(force bool, product *delta.Prod
 		return
 	}
 
-	realized, unrealized := 0.0, 0.0
-	open := 0
-	for _, p := range positions {
-		if p.Size != 0 {
-			open++
-		}
-		realized += parseFloatOrZero(p.RealizedPnL)
-		unrealized += parseFloatOrZero(p.UnrealizedPnL)
+	bot.mu.RLock()
+	marks := make(map[string]float64, len(bot.lastTickers))
+	for sym, t := range bot.lastTickers {
+		marks[sym] = t.MarkPrice
 	}
+	products := make(map[string]*delta.Product, len(bot.productCache))
+	for sym, p := range bot.productCache {
+		products[sym] = p
+	}
+	bot.mu.RUnlock()
 
-	bot.perfTracker.Record(PerformanceSnapshot{
-		Timestamp:     time.Now(),
-		Equity:        equity,
-		RealizedPnL:   realized,
-		UnrealizedPnL: unrealized,
-		Positions:     open,
-	})
-	bot.lastPerfUpdate = time.Now()
+	snap := snapshotFromPositions(bot.now(), equity, positions, marks, products)
+	bot.perfTracker.Record(snap)
+	bot.lastPerfUpdate = bot.now()
+
+	if bot.perfLog != nil {
+		if err := bot.perfLog.Append(snap, bot.perfTracker.StartEquity()); err != nil {
+			log.Printf("Warning: failed to write perf log: %v", err)
+		}
+	}
 
 	// Log Heartbeat to Console
 	stats := bot.perfTracker.Report()
@@ -727,6 +1521,27 @@ func (bot *StructuralBot) updatePerformanceIfDue(force bool, product *delta.Prod
 	logger.ConsoleLog("INFO", msg)
 }
 
+// snapshotFromPositions aggregates PnL and funding across the account's positions. Unrealized
+// PnL is recomputed with delta.ComputeUnrealizedPnL, as in the backtester, for positions with
+// a known mark price and product; others keep the exchange's figure.
+func snapshotFromPositions(ts time.Time, equity float64, positions []delta.Position, marks map[string]float64, products map[string]*delta.Product) PerformanceSnapshot {
+	snap := PerformanceSnapshot{Timestamp: ts, Equity: equity}
+	for _, p := range positions {
+		if p.Size != 0 {
+			snap.Positions++
+		}
+		snap.RealizedPnL += parseFloatOrZero(p.RealizedPnL)
+		mark, product := marks[p.ProductSymbol], products[p.ProductSymbol]
+		if mark > 0 && product != nil && p.Size != 0 {
+			snap.UnrealizedPnL += p.UnrealizedPnLAt(mark, product)
+		} else {
+			snap.UnrealizedPnL += parseFloatOrZero(p.UnrealizedPnL)
+		}
+		snap.FundingPaid += parseFloatOrZero(p.RealizedFunding)
+	}
+	return snap
+}
+
 func formatHeartbeat(stats map[string]interface{}) string {
 	pnlAbs := stats["pnl_abs"].(float64)
 	pnlPct := stats["pnl_pct"].(float64)
@@ -747,6 +1562,25 @@ func parseFloatOrZero(s string) float64 {
 	return f
 }
 
+// normalizeIntervals rewrites the configured candle intervals to their canonical names,
+// rejecting unsupported ones before anything subscribes with them
+func normalizeIntervals(cfg *config.Config) error {
+	interval, err := delta.NormalizeResolution(cfg.CandleInterval)
+	if err != nil {
+		return fmt.Errorf("CANDLE_INTERVAL: %w", err)
+	}
+	cfg.CandleInterval = interval
+
+	if cfg.RegimeCandleInterval != "" {
+		interval, err := delta.NormalizeResolution(cfg.RegimeCandleInterval)
+		if err != nil {
+			return fmt.Errorf("REGIME_CANDLE_INTERVAL: %w", err)
+		}
+		cfg.RegimeCandleInterval = interval
+	}
+	return nil
+}
+
 func main() {
 	cfg := config.LoadConfig()
 
@@ -765,11 +1599,38 @@ func main() {
 
 	slog.Info("Delta Exchange Structural Trading Bot v2.0", "strategy", "Real-time structural drivers (no ML)")
 
+	if cfg.Account != "" {
+		acctCfg, err := cfg.ForAccount(cfg.Account)
+		if err != nil {
+			log.Fatalf("Failed to select account: %v", err)
+		}
+		cfg = acctCfg
+		slog.Info("Trading on account", "account", cfg.Account, "subaccount", cfg.SubAccountID)
+	}
+
 	if cfg.APIKey == "" || cfg.APISecret == "" {
 		log.Fatal("DELTA_API_KEY and DELTA_API_SECRET environment variables are required")
 	}
+	if err := normalizeIntervals(cfg); err != nil {
+		log.Fatalf("Invalid configuration: %v", err)
+	}
+	if err := parseEventFreezeTimes(cfg); err != nil {
+		log.Fatalf("Invalid configuration: %v", err)
+	}
 
 	bot := NewStructuralBot(cfg)
+	if cfg.HMMEndpoint != "" {
+		hmm, err := features.NewHMMClient(features.HMMClientConfig{
+			Endpoint:     cfg.HMMEndpoint,
+			AuthMode:     cfg.HMMAuthMode,
+			Token:        cfg.HMMAuthToken,
+			APIKeyHeader: cfg.HMMAPIKeyHeader,
+		})
+		if err != nil {
+			log.Fatalf("Failed to configure HMM client: %v", err)
+		}
+		bot.SetRegimeDetector(hmm)
+	}
 	if err := bot.Initialize(); err != nil {
 		log.Fatalf("Failed to initialize bot: %v", err)
 	}
@@ -780,7 +1641,10 @@ func main() {
 
 	sigChan := make(chan os.Signal, 1)
 	signal.Notify(sigChan, syscall.SIGINT, syscall.SIGTERM)
-	<-sigChan
+	select {
+	case <-sigChan:
+	case <-bot.stopChan: // Stopped itself, e.g. after the WebSocket gave up
+	}
 
 	bot.Stop()
 }
@@ -0,0 +1,120 @@
+package main
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"sync"
+)
+
+// perfLogEntry is one JSONL line: a snapshot plus the PnL baseline at the time
+type perfLogEntry struct {
+	PerformanceSnapshot
+	StartEquity float64 `json:"start_equity"`
+}
+
+// PerfLog appends performance snapshots to a JSONL file so the equity curve can be
+// reconstructed across restarts. Once the file exceeds maxBytes it is rotated to
+// <path>.1 (replacing any previous rotation) and a fresh file is started.
+type PerfLog struct {
+	mu       sync.Mutex
+	path     string
+	maxBytes int64
+}
+
+// NewPerfLog creates a perf log; a non-positive maxBytes disables rotation
+func NewPerfLog(path string, maxBytes int64) *PerfLog {
+	return &PerfLog{path: path, maxBytes: maxBytes}
+}
+
+// Append writes one snapshot line, rotating first if the file is over the size limit
+func (pl *PerfLog) Append(snap PerformanceSnapshot, startEquity float64) error {
+	data, err := json.Marshal(perfLogEntry{PerformanceSnapshot: snap, StartEquity: startEquity})
+	if err != nil {
+		return fmt.Errorf("failed to encode snapshot: %v", err)
+	}
+	data = append(data, '\n')
+
+	pl.mu.Lock()
+	defer pl.mu.Unlock()
+
+	if err := pl.rotateIfNeeded(); err != nil {
+		return fmt.Errorf("failed to rotate perf log: %v", err)
+	}
+
+	f, err := os.OpenFile(pl.path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		return err
+	}
+	// Single write per line so a crash leaves at most one truncated trailing line
+	if _, err := f.Write(data); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+func (pl *PerfLog) rotateIfNeeded() error {
+	if pl.maxBytes <= 0 {
+		return nil
+	}
+	info, err := os.Stat(pl.path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if info.Size() < pl.maxBytes {
+		return nil
+	}
+	return os.Rename(pl.path, pl.path+".1")
+}
+
+// LoadStartEquity returns the start equity from the most recent snapshot, checking the
+// rotated file if the current one is missing or empty. Malformed lines (e.g. a write cut
+// short by a crash) are skipped.
+func (pl *PerfLog) LoadStartEquity() (float64, bool, error) {
+	pl.mu.Lock()
+	defer pl.mu.Unlock()
+
+	for _, path := range []string{pl.path, pl.path + ".1"} {
+		entry, ok, err := lastPerfEntry(path)
+		if err != nil {
+			return 0, false, err
+		}
+		if ok && entry.StartEquity > 0 {
+			return entry.StartEquity, true, nil
+		}
+	}
+	return 0, false, nil
+}
+
+func lastPerfEntry(path string) (perfLogEntry, bool, error) {
+	var last perfLogEntry
+	f, err := os.Open(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return last, false, nil
+	}
+	if err != nil {
+		return last, false, err
+	}
+	defer f.Close()
+
+	found := false
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		var entry perfLogEntry
+		if err := json.Unmarshal(scanner.Bytes(), &entry); err != nil {
+			continue
+		}
+		last = entry
+		found = true
+	}
+	if err := scanner.Err(); err != nil {
+		return last, false, err
+	}
+	return last, found, nil
+}
@@ -0,0 +1,80 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/kasyap/delta-go/go/config"
+)
+
+func TestPerfLog_ReloadsStartEquity(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "perf.jsonl")
+	pl := NewPerfLog(path, 0)
+
+	pt := NewPerformanceTracker(10)
+	ts := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	for i, equity := range []float64{1000, 1010, 990} {
+		snap := PerformanceSnapshot{Timestamp: ts.Add(time.Duration(i) * time.Minute), Equity: equity}
+		pt.Record(snap)
+		if err := pl.Append(snap, pt.StartEquity()); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// Simulate a crash mid-write: the truncated trailing line must be ignored
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_WRONLY, 0644)
+	if err != nil {
+		t.Fatal(err)
+	}
+	f.WriteString(`{"timestamp":"2024-01-01T00:03:00Z","equity":98`)
+	f.Close()
+
+	got, ok, err := NewPerfLog(path, 0).LoadStartEquity()
+	if err != nil || !ok {
+		t.Fatalf("LoadStartEquity: ok=%v err=%v", ok, err)
+	}
+	if got != 1000 {
+		t.Errorf("start equity = %v, want 1000", got)
+	}
+
+	// A restarted bot keeps the original baseline for PnL
+	bot := NewStructuralBot(&config.Config{Symbols: []string{"BTCUSD"}, APIRateLimitRPS: 8, PerfLogPath: path})
+	bot.perfTracker.Record(PerformanceSnapshot{Timestamp: ts.Add(time.Hour), Equity: 1100})
+	if pct := bot.perfTracker.Report()["pnl_pct"].(float64); pct != 10 {
+		t.Errorf("pnl_pct after restart = %v, want 10", pct)
+	}
+}
+
+func TestPerfLog_RotatesAndFallsBack(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "perf.jsonl")
+	pl := NewPerfLog(path, 1) // rotate on every write after the first
+
+	snap := PerformanceSnapshot{Timestamp: time.Now(), Equity: 500}
+	if err := pl.Append(snap, 500); err != nil {
+		t.Fatal(err)
+	}
+	if err := pl.Append(snap, 500); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Stat(path + ".1"); err != nil {
+		t.Fatalf("expected rotated file: %v", err)
+	}
+
+	// With the current file gone, the baseline comes from the rotated one
+	if err := os.Remove(path); err != nil {
+		t.Fatal(err)
+	}
+	got, ok, err := pl.LoadStartEquity()
+	if err != nil || !ok || got != 500 {
+		t.Errorf("LoadStartEquity = %v, %v, %v; want 500, true, nil", got, ok, err)
+	}
+}
+
+func TestPerfLog_MissingFile(t *testing.T) {
+	_, ok, err := NewPerfLog(filepath.Join(t.TempDir(), "none.jsonl"), 0).LoadStartEquity()
+	if err != nil || ok {
+		t.Errorf("expected no baseline and no error, got ok=%v err=%v", ok, err)
+	}
+}
@@ -0,0 +1,72 @@
+package main
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"github.com/kasyap/delta-go/go/pkg/delta"
+)
+
+func TestPerformanceTracker_ReportsFundingPaid(t *testing.T) {
+	positions := []delta.Position{
+		{Size: 10, RealizedPnL: "1.5", UnrealizedPnL: "-0.5", RealizedFunding: "2.25", ProductSymbol: "BTCUSD"},
+		{Size: -4, RealizedPnL: "0", UnrealizedPnL: "0.75", RealizedFunding: "-0.75", ProductSymbol: "ETHUSD"},
+		{Size: 0, RealizedPnL: "", UnrealizedPnL: "", RealizedFunding: "", ProductSymbol: "SOLUSD"},
+	}
+
+	pt := NewPerformanceTracker(10)
+	pt.Record(snapshotFromPositions(time.Now(), 1000, positions, nil, nil))
+	report := pt.Report()
+
+	if got := report["funding_paid"].(float64); math.Abs(got-1.5) > 1e-9 {
+		t.Errorf("funding_paid = %v, want 1.5", got)
+	}
+	if got := report["realized_pnl"].(float64); math.Abs(got-1.5) > 1e-9 {
+		t.Errorf("realized_pnl = %v, want 1.5", got)
+	}
+	if got := report["open_positions"].(int); got != 2 {
+		t.Errorf("open_positions = %d, want 2", got)
+	}
+}
+
+func TestSnapshotFromPositions_RecomputesUnrealizedAtMark(t *testing.T) {
+	positions := []delta.Position{
+		{Size: 10, EntryPrice: "50000", UnrealizedPnL: "99", ProductSymbol: "BTCUSD"},
+		{Size: -4, EntryPrice: "3000", UnrealizedPnL: "0.75", ProductSymbol: "ETHUSD"},
+	}
+	marks := map[string]float64{"BTCUSD": 50500}
+	products := map[string]*delta.Product{
+		"BTCUSD": {Symbol: "BTCUSD", ContractValue: "0.001"},
+		"ETHUSD": {Symbol: "ETHUSD", ContractValue: "0.01"},
+	}
+
+	snap := snapshotFromPositions(time.Now(), 1000, positions, marks, products)
+
+	// BTC long recomputed at the mark (10 * 0.001 * 500); ETH has no mark and keeps the API value
+	if want := 5.0 + 0.75; math.Abs(snap.UnrealizedPnL-want) > 1e-9 {
+		t.Errorf("unrealized PnL = %.4f, want %.4f", snap.UnrealizedPnL, want)
+	}
+}
+
+func TestPerformanceTracker_RollingSharpe(t *testing.T) {
+	pt := NewPerformanceTracker(100)
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	equity := 1000.0
+	for i := 0; i < 30; i++ {
+		// Rising with a small wobble so returns have non-zero variance
+		equity += 2 + float64(i%3)
+		pt.Record(PerformanceSnapshot{Timestamp: start.Add(time.Duration(i) * time.Minute), Equity: equity})
+	}
+
+	if got := pt.RollingSharpe(50); got != 0 {
+		t.Errorf("RollingSharpe with fewer snapshots than the window = %v, want 0", got)
+	}
+	got := pt.RollingSharpe(20)
+	if got <= 0 {
+		t.Fatalf("RollingSharpe(20) = %v, want positive for rising equity", got)
+	}
+	if _, ok := pt.Report()["rolling_sharpe"].(float64); !ok {
+		t.Error("report is missing rolling_sharpe")
+	}
+}
@@ -0,0 +1,214 @@
+package main
+
+import (
+	"fmt"
+	"strconv"
+
+	"github.com/kasyap/delta-go/go/pkg/delta"
+	"github.com/kasyap/delta-go/go/pkg/logger"
+	"github.com/kasyap/delta-go/go/pkg/strategy"
+)
+
+// canPyramid reports whether a fresh signal may add a unit to a winning position: same
+// direction, add-on count below maxAdds and price advanced at least stepPct from the last unit
+func canPyramid(pos *ScalpPosition, side string, price float64, maxAdds int, stepPct float64) (bool, string) {
+	if maxAdds <= 0 {
+		return false, "pyramiding disabled"
+	}
+	if pos.PendingAdd != nil {
+		return false, "add-on order pending"
+	}
+	if side != pos.Side {
+		return false, "signal opposes position"
+	}
+	if pos.Entries-1 >= maxAdds {
+		return false, fmt.Sprintf("max pyramid entries reached (%d)", maxAdds)
+	}
+	if pos.LastAddPrice <= 0 || price <= 0 {
+		return false, "missing price"
+	}
+
+	advancePct := (price - pos.LastAddPrice) / pos.LastAddPrice * 100
+	if pos.Side == "sell" {
+		advancePct = -advancePct
+	}
+	if advancePct < stepPct {
+		return false, fmt.Sprintf("price advanced %.2f%% < step %.2f%%", advancePct, stepPct)
+	}
+	return true, ""
+}
+
+// pyramidStop is the tightened stop after an add: it trails to the previous unit's entry,
+// never loosening the current stop
+func pyramidStop(pos *ScalpPosition) float64 {
+	stop := pos.LastAddPrice
+	if pos.StopLoss <= 0 {
+		return stop
+	}
+	if pos.Side == "sell" {
+		if pos.StopLoss < stop {
+			return pos.StopLoss
+		}
+		return stop
+	}
+	if pos.StopLoss > stop {
+		return pos.StopLoss
+	}
+	return stop
+}
+
+// addUnit records an add-on fill, blending the entry price and tightening the stop
+func (pos *ScalpPosition) addUnit(size int, price, stopLoss float64, orderID int64) {
+	total := pos.Size + size
+	pos.EntryPrice = (pos.EntryPrice*float64(pos.Size) + price*float64(size)) / float64(total)
+	pos.Size = total
+	pos.Entries++
+	pos.LastAddPrice = price
+	pos.StopLoss = stopLoss
+	if orderID != 0 {
+		pos.AddOnOrderIDs = append(pos.AddOnOrderIDs, orderID)
+	}
+}
+
+// executeScalpPyramid adds a unit to an existing scalp position when the signal is aligned
+// and price has advanced by the configured step. The add is sized on aggregate risk and
+// rests as a pending add until checkPendingAdds sees it fill.
+func (bot *StructuralBot) executeScalpPyramid(signal strategy.Signal, product *delta.Product, symbol string, regime delta.MarketRegime) {
+	bot.mu.RLock()
+	pos, ok := bot.scalpPositions[symbol]
+	var snapshot ScalpPosition
+	if ok {
+		snapshot = *pos
+	}
+	bot.mu.RUnlock()
+	if !ok {
+		return
+	}
+
+	tl := logger.WithTrade(symbol, scalpStrategyName).With(logger.KeyAction, signal.Action)
+	if can, reason := canPyramid(&snapshot, signal.Side, signal.Price, bot.cfg.MaxPyramidEntries, bot.cfg.PyramidStepPct); !can {
+		tl.Info("Pyramid skipped", "reason", reason)
+		return
+	}
+
+	newStop := pyramidStop(&snapshot)
+	addSignal := signal
+	addSignal.StopLoss = newStop
+	if _, rr, ok := checkRewardRisk(bot.cfg, addSignal); !ok {
+		tl.Info("Pyramid skipped: reward:risk below minimum", "reward_risk", rr, "min", bot.cfg.MinRewardRisk)
+		return
+	}
+
+	balance, err := bot.deltaClient.GetAvailableBalance("USDT")
+	if err != nil {
+		tl.Error("Failed to get balance", "error", err)
+		return
+	}
+
+	size := bot.riskManager.CalculatePyramidSize(balance, snapshot.Size, snapshot.EntryPrice, signal.Price, newStop, regime, product)
+	if size < 1 {
+		tl.Info("Pyramid skipped", "reason", "no risk budget left for aggregate position")
+		return
+	}
+
+	slPrice, tpPrice := delta.RoundBracketPrices(signal.Side, newStop, signal.TakeProfit, product.TickSize, bot.bracketRounding(signal))
+
+	req := &delta.OrderRequest{
+		ProductID:              product.ID,
+		Size:                   size,
+		Side:                   signal.Side,
+		OrderType:              "limit_order",
+		LimitPrice:             delta.FormatPrice(signal.Price, product),
+		BracketStopLossPrice:   slPrice,
+		BracketTakeProfitPrice: tpPrice,
+		TimeInForce:            "gtc",
+		ClientOrderID:          bot.clientOrderID(symbol, signal.Side, size, fmt.Sprintf("pyramid:%d", snapshot.Entries)),
+	}
+
+	if err := bot.checkMargin(product, size, signal.Price); err != nil {
+		tl.Warn("Pyramid skipped", "error", err)
+		return
+	}
+	if !bot.allowOrder("pyramid", symbol) {
+		return
+	}
+
+	order, err := bot.deltaClient.PlaceOrder(req)
+	if err != nil {
+		tl.Error("Failed to place pyramid order", "error", err)
+		return
+	}
+	bot.trackOrderExpiry("pyramid", order.ID)
+
+	bot.mu.Lock()
+	if pos, ok := bot.scalpPositions[symbol]; ok {
+		pos.PendingAdd = &PendingAdd{OrderID: order.ID, ProductID: product.ID, Size: size, Price: signal.Price, StopLoss: newStop, StopPrice: slPrice}
+	}
+	bot.mu.Unlock()
+
+	tl.Info("Pyramid add placed", logger.KeyOrderID, order.ID, "add", snapshot.Entries, "side", signal.Side, "size", size,
+		"price", signal.Price, "stop_loss", slPrice)
+}
+
+// checkPendingAdds resolves resting pyramid add-ons. A filled add, or the filled part of
+// one that was cancelled or expired, joins the position and the held units' stops are
+// tightened; an add that ends unfilled is dropped.
+func (bot *StructuralBot) checkPendingAdds() {
+	pending := make(map[string]*PendingAdd)
+	bot.mu.RLock()
+	for symbol, pos := range bot.scalpPositions {
+		if pos.PendingAdd != nil {
+			pending[symbol] = pos.PendingAdd
+		}
+	}
+	bot.mu.RUnlock()
+
+	for symbol, add := range pending {
+		tl := logger.WithTrade(symbol, scalpStrategyName).With(logger.KeyOrderID, add.OrderID)
+		order, err := bot.deltaClient.GetOrderByID(add.OrderID)
+		if err != nil {
+			tl.Error("Failed to check pyramid order", "error", err)
+			continue
+		}
+		if !delta.ParseOrderState(order.State).IsTerminal() {
+			continue
+		}
+
+		filled := order.Size - order.UnfilledSize
+		price := add.Price
+		if avg, err := strconv.ParseFloat(order.AverageFillPrice, 64); err == nil && avg > 0 {
+			price = avg
+		}
+
+		var held []int64
+		var snapshot ScalpPosition
+		bot.mu.Lock()
+		pos, ok := bot.scalpPositions[symbol]
+		if !ok || pos.PendingAdd != add {
+			bot.mu.Unlock()
+			continue
+		}
+		pos.PendingAdd = nil
+		if filled > 0 {
+			held = append([]int64{pos.OrderID}, pos.AddOnOrderIDs...)
+			pos.addUnit(filled, price, add.StopLoss, add.OrderID)
+		}
+		snapshot = *pos
+		bot.mu.Unlock()
+
+		if filled <= 0 {
+			tl.Info("Pyramid add not filled", "state", order.State)
+			continue
+		}
+
+		// Tighten the stops on the units already held
+		for _, id := range held {
+			if err := bot.deltaClient.EditBracket(id, add.ProductID, add.StopPrice, ""); err != nil {
+				tl.Error("Failed to tighten stop", "bracket_order_id", id, "error", err)
+			}
+		}
+
+		tl.Info("Pyramid add filled", "add", snapshot.Entries-1, "size", filled, "price", price,
+			"total_size", snapshot.Size, "blended_entry", snapshot.EntryPrice, "stop_loss", add.StopPrice)
+	}
+}
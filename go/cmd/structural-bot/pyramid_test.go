@@ -0,0 +1,147 @@
+package main
+
+import (
+	"math"
+	"testing"
+
+	"github.com/kasyap/delta-go/go/config"
+	"github.com/kasyap/delta-go/go/pkg/delta"
+	"github.com/kasyap/delta-go/go/pkg/strategy"
+)
+
+func TestCanPyramid_RequiresStepThreshold(t *testing.T) {
+	pos := &ScalpPosition{Side: "buy", Size: 10, EntryPrice: 100, Entries: 1, LastAddPrice: 100, StopLoss: 98}
+
+	if ok, _ := canPyramid(pos, "buy", 100.4, 2, 0.5); ok {
+		t.Fatal("expected add to be refused before price advances by the step")
+	}
+	if ok, _ := canPyramid(pos, "sell", 101, 2, 0.5); ok {
+		t.Fatal("expected opposing signal to be refused")
+	}
+	if ok, reason := canPyramid(pos, "buy", 100.5, 2, 0.5); !ok {
+		t.Fatalf("expected add after step threshold, got refusal: %s", reason)
+	}
+
+	stop := pyramidStop(pos)
+	pos.addUnit(10, 100.5, stop, 42)
+	if pos.Size != 20 || pos.Entries != 2 {
+		t.Fatalf("unexpected position after add: size=%d entries=%d", pos.Size, pos.Entries)
+	}
+	if math.Abs(pos.EntryPrice-100.25) > 1e-9 {
+		t.Errorf("blended entry = %.4f, want 100.25", pos.EntryPrice)
+	}
+	if pos.StopLoss != 100 {
+		t.Errorf("stop = %.2f, want tightened to previous unit entry 100", pos.StopLoss)
+	}
+
+	// The next add is measured from the last unit, not the original entry
+	if ok, _ := canPyramid(pos, "buy", 100.8, 2, 0.5); ok {
+		t.Error("expected add to be refused until price advances a step from the last unit")
+	}
+}
+
+func TestCanPyramid_RefusesBeyondMax(t *testing.T) {
+	pos := &ScalpPosition{Side: "sell", Size: 5, EntryPrice: 100, Entries: 1, LastAddPrice: 100, StopLoss: 102}
+
+	if ok, reason := canPyramid(pos, "sell", 99, 1, 0.5); !ok {
+		t.Fatalf("expected first add on a winning short, got refusal: %s", reason)
+	}
+	pos.addUnit(5, 99, pyramidStop(pos), 0)
+	if pos.StopLoss != 100 {
+		t.Errorf("short stop = %.2f, want 100", pos.StopLoss)
+	}
+
+	if ok, _ := canPyramid(pos, "sell", 95, 1, 0.5); ok {
+		t.Error("expected add beyond MaxPyramidEntries to be refused")
+	}
+	if ok, _ := canPyramid(pos, "sell", 95, 0, 0.5); ok {
+		t.Error("expected pyramiding to be disabled with max 0")
+	}
+}
+
+func pyramidBot(t *testing.T) (*StructuralBot, *fakeExchange) {
+	t.Helper()
+	x := newFakeExchange(delta.Product{ID: 27, Symbol: "BTCUSD", ContractValue: "0.001", TickSize: "0.5"})
+	x.setPosition(27, 2)
+	x.setRestOrders(true)
+	bot := newFakeExchangeBot(t, x, &config.Config{
+		Symbols:           []string{"BTCUSD"},
+		MaxPositionPct:    10,
+		Leverage:          10,
+		RiskPerTradePct:   1,
+		MaxPyramidEntries: 2,
+		PyramidStepPct:    0.5,
+	})
+	bot.scalpPositions["BTCUSD"] = &ScalpPosition{Symbol: "BTCUSD", Side: "buy", Size: 2, EntryPrice: 50000,
+		OrderID: 7, Entries: 1, LastAddPrice: 50000, StopLoss: 49000}
+	signal := strategy.Signal{Action: strategy.ActionBuy, Side: "buy", Price: 50500, TakeProfit: 52000, Confidence: 1}
+	bot.executeScalpPyramid(signal, bot.productCache["BTCUSD"], "BTCUSD", delta.RegimeRanging)
+	return bot, x
+}
+
+func TestScalpPyramid_AppliesAddOnlyOnFill(t *testing.T) {
+	bot, x := pyramidBot(t)
+
+	pos := bot.scalpPositions["BTCUSD"]
+	if pos.PendingAdd == nil {
+		t.Fatal("expected the add-on order to be pending")
+	}
+	if pos.Size != 2 || pos.Entries != 1 || pos.StopLoss != 49000 || len(x.editedBrackets()) != 0 {
+		t.Fatalf("resting add changed the position: %+v, edited brackets %v", pos, x.editedBrackets())
+	}
+	if ok, _ := canPyramid(pos, "buy", 51000, 2, 0.5); ok {
+		t.Error("expected no further add while one is pending")
+	}
+
+	bot.checkPendingAdds()
+	if pos.PendingAdd == nil || pos.Size != 2 {
+		t.Fatalf("open add-on was applied before filling: %+v", pos)
+	}
+
+	add := pos.PendingAdd
+	x.fillOrder(add.OrderID)
+	bot.checkPendingAdds()
+	if pos.PendingAdd != nil || pos.Size != 2+add.Size || pos.Entries != 2 || pos.StopLoss != 50000 {
+		t.Fatalf("position after fill = %+v, want add of %d applied with stop 50000", pos, add.Size)
+	}
+	if edited := x.editedBrackets(); len(edited) != 1 || edited[0] != 7 {
+		t.Errorf("edited brackets = %v, want the initial unit's order 7", edited)
+	}
+}
+
+func TestScalpPyramid_DropsUnfilledAdd(t *testing.T) {
+	bot, x := pyramidBot(t)
+
+	pos := bot.scalpPositions["BTCUSD"]
+	x.cancelOrder(pos.PendingAdd.OrderID)
+	bot.checkPendingAdds()
+	if pos.PendingAdd != nil || pos.Size != 2 || pos.Entries != 1 || pos.StopLoss != 49000 {
+		t.Fatalf("position after cancelled add = %+v, want it unchanged", pos)
+	}
+	if len(x.editedBrackets()) != 0 {
+		t.Errorf("stops tightened for an add that never filled: %v", x.editedBrackets())
+	}
+}
+
+func TestScalpPyramid_SkipsAddBelowMinRewardRisk(t *testing.T) {
+	x := newFakeExchange(delta.Product{ID: 27, Symbol: "BTCUSD", ContractValue: "0.001", TickSize: "0.5"})
+	bot := newFakeExchangeBot(t, x, &config.Config{
+		Symbols:           []string{"BTCUSD"},
+		MaxPositionPct:    10,
+		Leverage:          10,
+		RiskPerTradePct:   1,
+		MaxPyramidEntries: 2,
+		PyramidStepPct:    0.5,
+		MinRewardRisk:     1.5,
+	})
+	bot.scalpPositions["BTCUSD"] = &ScalpPosition{Symbol: "BTCUSD", Side: "buy", Size: 2, EntryPrice: 50000,
+		OrderID: 7, Entries: 1, LastAddPrice: 50000, StopLoss: 49000}
+
+	// The add's stop trails to 50000: risking 500 to make 300
+	signal := strategy.Signal{Action: strategy.ActionBuy, Side: "buy", Price: 50500, TakeProfit: 50800, Confidence: 1}
+	bot.executeScalpPyramid(signal, bot.productCache["BTCUSD"], "BTCUSD", delta.RegimeRanging)
+
+	if orders := x.placed(); len(orders) != 0 {
+		t.Errorf("placed %d add-on orders at 0.6:1, want none", len(orders))
+	}
+}
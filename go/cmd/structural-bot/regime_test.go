@@ -0,0 +1,56 @@
+package main
+
+import (
+	"testing"
+	"time"
+
+	"github.com/kasyap/delta-go/go/config"
+	"github.com/kasyap/delta-go/go/pkg/delta"
+)
+
+type stubRegimeDetector struct {
+	got []delta.Candle
+}
+
+func (d *stubRegimeDetector) DetectRegime(symbol string, candles []delta.Candle) (delta.MarketRegime, float64, error) {
+	d.got = candles
+	return delta.RegimeHighVol, 0.8, nil
+}
+
+func TestUpdateMarketRegime_AggregatesToRegimeInterval(t *testing.T) {
+	bot := NewStructuralBot(&config.Config{
+		Symbols:              []string{"BTCUSD"},
+		CandleInterval:       "5m",
+		RegimeCandleInterval: "1h",
+		APIRateLimitRPS:      8,
+	})
+	detector := &stubRegimeDetector{}
+	bot.SetRegimeDetector(detector)
+
+	// 600 x 5m candles = 50 hourly candles
+	base := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC).Unix()
+	candles := make([]delta.Candle, 600)
+	for i := range candles {
+		p := 50000 + float64(i)
+		candles[i] = delta.Candle{Time: base + int64(i*300), Open: p, High: p + 5, Low: p - 5, Close: p + 1, Volume: 1}
+	}
+	bot.candles["BTCUSD"] = candles
+
+	bot.updateMarketRegime()
+
+	if len(detector.got) != minRegimeCandles {
+		t.Fatalf("detector got %d candles, want %d", len(detector.got), minRegimeCandles)
+	}
+	first := detector.got[0]
+	if first.Time != base || first.Open != 50000 || first.Close != 50012 || first.Volume != 12 {
+		t.Errorf("unexpected first hourly candle: %+v", first)
+	}
+	if step := detector.got[1].Time - detector.got[0].Time; step != 3600 {
+		t.Errorf("hourly step = %d, want 3600", step)
+	}
+
+	rs, ok := bot.regimes["BTCUSD"]
+	if !ok || rs.Regime != delta.RegimeHighVol || rs.Confidence != 0.8 {
+		t.Errorf("regime not stored: %+v", rs)
+	}
+}
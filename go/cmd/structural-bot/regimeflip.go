@@ -0,0 +1,119 @@
+package main
+
+import (
+	"github.com/kasyap/delta-go/go/config"
+	"github.com/kasyap/delta-go/go/pkg/delta"
+	"github.com/kasyap/delta-go/go/pkg/logger"
+	"github.com/kasyap/delta-go/go/pkg/strategy"
+)
+
+// regimeConfirmation debounces regime changes: a new regime is confirmed once detected
+// on enough consecutive checks, so a single flip of the detector doesn't close positions
+type regimeConfirmation struct {
+	confirmed delta.MarketRegime
+	candidate delta.MarketRegime
+	count     int
+}
+
+// observe records one detection and reports whether it confirms a change from the last
+// confirmed regime, after need detections in a row (need <= 1 confirms at once). The
+// first detection only sets the baseline.
+func (c *regimeConfirmation) observe(regime delta.MarketRegime, need int) bool {
+	switch {
+	case c.confirmed == "":
+		c.confirmed = regime
+		return false
+	case regime == c.confirmed:
+		c.candidate, c.count = "", 0
+		return false
+	case regime == c.candidate:
+		c.count++
+	default:
+		c.candidate, c.count = regime, 1
+	}
+	if c.count < need {
+		return false
+	}
+	c.confirmed, c.candidate, c.count = regime, "", 0
+	return true
+}
+
+// regimeConflicts reports whether a position on side should not be held in regime. The
+// regime alignment policy decides for regimes it lists; otherwise longs conflict with bear
+// and shorts with bull.
+func regimeConflicts(cfg *config.Config, regime delta.MarketRegime, side string) bool {
+	if _, ok := cfg.RegimeAllowedSides[string(regime)]; ok {
+		return !regimeAllowsSide(cfg, regime, strategy.Signal{Side: side})
+	}
+	switch regime {
+	case delta.RegimeBull:
+		return side == "sell"
+	case delta.RegimeBear:
+		return side == "buy"
+	default:
+		return false
+	}
+}
+
+// positionOwner returns the strategy holding symbol's tracked position, empty when none does
+func (bot *StructuralBot) positionOwner(symbol string) string {
+	bot.mu.RLock()
+	defer bot.mu.RUnlock()
+	if pos := bot.scalpPositions[symbol]; pos != nil {
+		return pos.strategyName()
+	}
+	if bot.basisPositions[symbol] != nil {
+		return fundingStrategyName
+	}
+	return ""
+}
+
+// flattenOnRegimeChange closes the symbol's position and cancels its resting orders when
+// the position conflicts with the regime it just flipped to. Without an exchange position,
+// a tracked scalp entry still resting is judged by its side. A hedged funding position is
+// market-neutral and is left open.
+func (bot *StructuralBot) flattenOnRegimeChange(symbol string, regime delta.MarketRegime) {
+	bot.mu.RLock()
+	product := bot.productCache[symbol]
+	scalp := bot.scalpPositions[symbol]
+	basis := bot.basisPositions[symbol]
+	bot.mu.RUnlock()
+
+	owner := ""
+	switch {
+	case scalp != nil:
+		owner = scalpStrategyName
+	case basis != nil && basis.Hedge != nil:
+		return
+	case basis != nil:
+		owner = fundingStrategyName
+	}
+
+	tl := logger.WithTrade(symbol, owner).With(logger.KeyAction, "regime_flatten")
+	if product == nil {
+		return
+	}
+
+	side := ""
+	pos, err := bot.deltaClient.GetPosition(product.ID)
+	if err != nil {
+		tl.Error("Failed to get position on regime change", "error", err)
+		return
+	}
+	switch {
+	case pos != nil && pos.Size > 0:
+		side = "buy"
+	case pos != nil && pos.Size < 0:
+		side = "sell"
+	case scalp != nil:
+		side = scalp.Side
+	}
+	if side == "" || !regimeConflicts(bot.cfg, regime, side) {
+		return
+	}
+
+	tl.Info("Flattening position that conflicts with new regime", "side", side, "regime", regime)
+	if err := bot.flattenSymbol(symbol); err != nil {
+		tl.Error("Failed to flatten on regime change", "error", err)
+	}
+}
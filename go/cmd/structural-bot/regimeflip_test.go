@@ -0,0 +1,170 @@
+package main
+
+import (
+	"encoding/json"
+	"net/http"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/kasyap/delta-go/go/config"
+	"github.com/kasyap/delta-go/go/pkg/delta"
+)
+
+type mapRegimeDetector struct {
+	regimes map[string]delta.MarketRegime
+}
+
+func (d *mapRegimeDetector) DetectRegime(symbol string, candles []delta.Candle) (delta.MarketRegime, float64, error) {
+	return d.regimes[symbol], 0.9, nil
+}
+
+func TestRegimeConflicts(t *testing.T) {
+	cfg := &config.Config{RegimeAllowedSides: map[string]string{"high_volatility": "none", "ranging": "sell"}}
+	tests := []struct {
+		regime delta.MarketRegime
+		side   string
+		want   bool
+	}{
+		{delta.RegimeBear, "buy", true},
+		{delta.RegimeBear, "sell", false},
+		{delta.RegimeBull, "sell", true},
+		{delta.RegimeBull, "buy", false},
+		{delta.RegimeLowVol, "buy", false},
+		{delta.RegimeHighVol, "sell", true},
+		{delta.RegimeRanging, "buy", true},
+		{delta.RegimeRanging, "sell", false},
+	}
+	for _, tt := range tests {
+		if got := regimeConflicts(cfg, tt.regime, tt.side); got != tt.want {
+			t.Errorf("regimeConflicts(%s, %s) = %v, want %v", tt.regime, tt.side, got, tt.want)
+		}
+	}
+}
+
+func TestUpdateMarketRegime_FlattensConflictingPositions(t *testing.T) {
+	var mu sync.Mutex
+	var cancelled []int
+	var orders []delta.OrderRequest
+	bot := newInitTestBot(t, func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		switch {
+		case r.Method == http.MethodGet && r.URL.Path == "/v2/positions":
+			size := 5 // BTCUSD long
+			if r.URL.Query().Get("product_id") == "2" {
+				size = 3 // NOPEUSD long
+			}
+			json.NewEncoder(w).Encode(map[string]any{"success": true, "result": map[string]any{"size": size}})
+		case r.Method == http.MethodDelete && r.URL.Path == "/v2/orders/all":
+			var body struct {
+				ProductID int `json:"product_id"`
+			}
+			json.NewDecoder(r.Body).Decode(&body)
+			cancelled = append(cancelled, body.ProductID)
+			w.Write([]byte(`{"success":true,"result":{}}`))
+		case r.Method == http.MethodPost && r.URL.Path == "/v2/orders":
+			var req delta.OrderRequest
+			json.NewDecoder(r.Body).Decode(&req)
+			orders = append(orders, req)
+			w.Write([]byte(`{"success":true,"result":{"id":7,"state":"closed"}}`))
+		default:
+			// No book or product lookups: the close falls back to a market order
+			w.WriteHeader(http.StatusNotFound)
+			w.Write([]byte(`{"success":false,"error":{"code":"not_found"}}`))
+		}
+	})
+	bot.cfg.FlattenOnRegimeChange = true
+	bot.cfg.RegimeConfirmations = 2
+	bot.productCache["BTCUSD"] = &delta.Product{ID: 1, Symbol: "BTCUSD"}
+	bot.productCache["NOPEUSD"] = &delta.Product{ID: 2, Symbol: "NOPEUSD"}
+	bot.scalpPositions["BTCUSD"] = &ScalpPosition{Symbol: "BTCUSD", Side: "buy", Size: 5}
+
+	base := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC).Unix()
+	for _, sym := range bot.cfg.Symbols {
+		candles := make([]delta.Candle, minRegimeCandles)
+		for i := range candles {
+			candles[i] = delta.Candle{Time: base + int64(i*60), Open: 100, High: 101, Low: 99, Close: 100}
+		}
+		bot.candles[sym] = candles
+	}
+
+	detector := &mapRegimeDetector{regimes: map[string]delta.MarketRegime{
+		"BTCUSD": delta.RegimeBull, "NOPEUSD": delta.RegimeBull,
+	}}
+	bot.SetRegimeDetector(detector)
+
+	// The first detection is not a change
+	bot.updateMarketRegime()
+	if len(cancelled) != 0 || len(orders) != 0 {
+		t.Fatalf("first detection flattened: cancelled %v, orders %+v", cancelled, orders)
+	}
+
+	// Longs conflict with bear but not with ranging; one detection doesn't confirm the change
+	detector.regimes["BTCUSD"] = delta.RegimeBear
+	detector.regimes["NOPEUSD"] = delta.RegimeRanging
+	bot.updateMarketRegime()
+	mu.Lock()
+	if len(cancelled) != 0 || len(orders) != 0 {
+		t.Fatalf("unconfirmed change flattened: cancelled %v, orders %+v", cancelled, orders)
+	}
+	mu.Unlock()
+	bot.updateMarketRegime()
+
+	mu.Lock()
+	defer mu.Unlock()
+	if len(cancelled) != 1 || cancelled[0] != 1 {
+		t.Errorf("cancelled orders for products %v, want [1]", cancelled)
+	}
+	if len(orders) != 1 {
+		t.Fatalf("placed %d orders, want 1 close: %+v", len(orders), orders)
+	}
+	if o := orders[0]; o.ProductID != 1 || o.Side != "sell" || o.Size != 5 {
+		t.Errorf("close order = %+v, want sell 5 on product 1", o)
+	}
+	if _, ok := bot.scalpPositions["BTCUSD"]; ok {
+		t.Error("BTCUSD scalp position still tracked after flatten")
+	}
+	if rs := bot.regimes["BTCUSD"]; rs.Regime != delta.RegimeBear {
+		t.Errorf("BTCUSD regime = %s, want bear", rs.Regime)
+	}
+}
+
+func TestRegimeConfirmation_Observe(t *testing.T) {
+	var c regimeConfirmation
+	steps := []struct {
+		regime delta.MarketRegime
+		want   bool
+	}{
+		{delta.RegimeBull, false}, // Baseline
+		{delta.RegimeBear, false},
+		{delta.RegimeBull, false}, // Back to the confirmed regime resets the streak
+		{delta.RegimeBear, false},
+		{delta.RegimeRanging, false}, // A different candidate restarts it
+		{delta.RegimeRanging, false},
+		{delta.RegimeRanging, true},
+		{delta.RegimeRanging, false}, // Already confirmed
+	}
+	for i, s := range steps {
+		if got := c.observe(s.regime, 3); got != s.want {
+			t.Errorf("step %d (%s): observe = %v, want %v", i, s.regime, got, s.want)
+		}
+	}
+}
+
+func TestFlattenOnRegimeChange_LeavesHedgedFundingOpen(t *testing.T) {
+	bot, x := hedgedFundingBot(t)
+	x.setPosition(27, -3)
+	x.setPosition(90, 3)
+	bot.basisPositions["BTCUSD"] = &BasisPosition{Symbol: "BTCUSD", Side: "sell", Size: 3,
+		Hedge: &HedgeLeg{Symbol: "BTCUSD_270625", ProductID: 90, Side: "buy", Size: 3}}
+
+	bot.flattenOnRegimeChange("BTCUSD", delta.RegimeBull)
+
+	if perp, future := x.position(27), x.position(90); perp != -3 || future != 3 {
+		t.Errorf("perp = %d, future = %d; want the hedged pair untouched", perp, future)
+	}
+	if n := len(x.placed()); n != 0 {
+		t.Errorf("placed %d orders, want none", n)
+	}
+}
@@ -0,0 +1,143 @@
+package main
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/kasyap/delta-go/go/pkg/delta"
+	"github.com/kasyap/delta-go/go/pkg/strategy"
+)
+
+// replayClock paces a replay against the wall clock: speed simulated seconds pass per wall
+// second, and speed <= 0 runs as fast as possible
+type replayClock struct {
+	speed     float64
+	simStart  time.Time
+	wallStart time.Time
+}
+
+func newReplayClock(speed float64, simStart time.Time) *replayClock {
+	return &replayClock{speed: speed, simStart: simStart, wallStart: time.Now()}
+}
+
+// wait blocks until simulated time sim is due, returning false if stop closes first
+func (c *replayClock) wait(sim time.Time, stop <-chan struct{}) bool {
+	if c.speed <= 0 {
+		select {
+		case <-stop:
+			return false
+		default:
+			return true
+		}
+	}
+
+	due := c.wallStart.Add(time.Duration(float64(sim.Sub(c.simStart)) / c.speed))
+	timer := time.NewTimer(time.Until(due))
+	defer timer.Stop()
+	select {
+	case <-stop:
+		return false
+	case <-timer.C:
+		return true
+	}
+}
+
+// replayBar is one symbol's candle in the replay sequence
+type replayBar struct {
+	symbol string
+	candle delta.Candle
+}
+
+// replayBars interleaves per-symbol candles into time order, breaking ties by symbol so
+// every run sees the same sequence
+func replayBars(candles map[string][]delta.Candle) []replayBar {
+	var bars []replayBar
+	for symbol, series := range candles {
+		for _, c := range series {
+			bars = append(bars, replayBar{symbol: symbol, candle: c})
+		}
+	}
+	sort.Slice(bars, func(i, j int) bool {
+		if bars[i].candle.Time != bars[j].candle.Time {
+			return bars[i].candle.Time < bars[j].candle.Time
+		}
+		return bars[i].symbol < bars[j].symbol
+	})
+	return bars
+}
+
+// Replay runs historical CandleInterval candles through the live handlers at speed times
+// real time (<= 0 = as fast as possible). Each bar is delivered as a ticker and a candle, and
+// once every symbol's bar for a timestamp is in, one trading cycle runs: features, exits,
+// grid fills and entries, with the regime refreshed every RegimeCheckPeriod of replayed time.
+// The bot and its strategies run on bar time for the duration. Orders still go to the
+// configured client, so a replay is refused unless the config targets the testnet.
+// The live loops must not be running; Stop aborts a replay in progress.
+func (bot *StructuralBot) Replay(candles map[string][]delta.Candle, speed float64) error {
+	if !bot.cfg.IsTestnet {
+		return fmt.Errorf("replay places real orders: refusing to run against a non-testnet account")
+	}
+
+	bot.mu.Lock()
+	if bot.isRunning {
+		bot.mu.Unlock()
+		return fmt.Errorf("bot already running")
+	}
+	bot.isRunning = true
+	bot.mu.Unlock()
+	defer func() {
+		bot.mu.Lock()
+		bot.isRunning = false
+		bot.mu.Unlock()
+	}()
+
+	bars := replayBars(candles)
+	if len(bars) == 0 {
+		return fmt.Errorf("no candles to replay")
+	}
+
+	regimePeriod := int64(bot.cfg.RegimeCheckPeriod / time.Second)
+	if regimePeriod <= 0 {
+		regimePeriod = int64(5 * time.Minute / time.Second)
+	}
+	clock := newReplayClock(speed, time.Unix(bars[0].candle.Time, 0))
+	barClock := strategy.NewSimClock(time.Unix(bars[0].candle.Time, 0))
+	bot.clock.set(barClock)
+	defer bot.clock.set(strategy.RealClock{})
+
+	var lastRegime int64
+	for i := 0; i < len(bars); {
+		ts := bars[i].candle.Time
+		if !clock.wait(time.Unix(ts, 0), bot.stopChan) {
+			return fmt.Errorf("replay stopped at %s", time.Unix(ts, 0).UTC().Format(time.RFC3339))
+		}
+		barClock.Set(time.Unix(ts, 0))
+
+		for ; i < len(bars) && bars[i].candle.Time == ts; i++ {
+			b := bars[i]
+			bot.handleTicker(delta.Ticker{
+				Symbol:    b.symbol,
+				Open:      b.candle.Open,
+				High:      b.candle.High,
+				Low:       b.candle.Low,
+				Close:     b.candle.Close,
+				MarkPrice: b.candle.Close,
+				Volume:    b.candle.Volume,
+				Timestamp: ts * 1_000_000,
+			})
+			bot.handleCandle(b.symbol, bot.cfg.CandleInterval, b.candle)
+		}
+
+		if ts-lastRegime >= regimePeriod {
+			bot.updateMarketRegime()
+			lastRegime = ts
+		}
+		bot.updateFeatures()
+		bot.checkPendingAdds()
+		bot.checkScalpExits()
+		bot.checkGridFills()
+		bot.evaluateAndTrade()
+	}
+	return nil
+}
@@ -0,0 +1,160 @@
+package main
+
+import (
+	"math"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/kasyap/delta-go/go/pkg/delta"
+)
+
+func TestReplayClock_PacesBySpeed(t *testing.T) {
+	start := time.Unix(0, 0)
+	clock := newReplayClock(6000, start)
+	stop := make(chan struct{})
+
+	begin := time.Now()
+	if !clock.wait(start.Add(time.Minute), stop) {
+		t.Fatal("wait returned false without a stop")
+	}
+	if elapsed := time.Since(begin); elapsed < 9*time.Millisecond {
+		t.Errorf("a minute at 6000x took %v, want about 10ms", elapsed)
+	}
+
+	close(stop)
+	if clock.wait(start.Add(time.Hour), stop) {
+		t.Error("wait returned true after stop")
+	}
+}
+
+func TestReplay_DaysAtMaxSpeed(t *testing.T) {
+	bot := newInitTestBot(t, func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case strings.HasSuffix(r.URL.Path, "/products/BTCUSD"):
+			w.Write([]byte(`{"success":true,"result":{"id":27,"symbol":"BTCUSD","tick_size":"0.5","contract_value":"0.001"}}`))
+		case strings.Contains(r.URL.Path, "/products/"):
+			w.WriteHeader(http.StatusNotFound)
+			w.Write([]byte(`{"success":false,"error":{"code":"not_found","message":"no such product"}}`))
+		case strings.Contains(r.URL.Path, "/history/candles"):
+			w.Write([]byte(`{"success":true,"result":[]}`))
+		default:
+			w.Write([]byte(`{"success":true,"result":[]}`))
+		}
+	})
+	if err := bot.Initialize(); err != nil {
+		t.Fatalf("Initialize() error = %v", err)
+	}
+
+	// Three days of 1m bars oscillating around 50k
+	const bars = 3 * 24 * 60
+	series := make([]delta.Candle, bars)
+	for i := range series {
+		price := 50000 + 500*math.Sin(float64(i)/90)
+		series[i] = delta.Candle{
+			Time: int64(i * 60), Open: price, High: price + 20, Low: price - 20, Close: price + 5, Volume: 10,
+		}
+	}
+
+	// Status readers race the replay the way the control server would
+	stopReaders := make(chan struct{})
+	readersDone := make(chan struct{})
+	go func() {
+		defer close(readersDone)
+		for {
+			select {
+			case <-stopReaders:
+				return
+			default:
+				bot.GetStatus()
+			}
+		}
+	}()
+
+	done := make(chan error, 1)
+	go func() { done <- bot.Replay(map[string][]delta.Candle{"BTCUSD": series}, 0) }()
+	select {
+	case err := <-done:
+		if err != nil {
+			t.Fatalf("Replay() error = %v", err)
+		}
+	case <-time.After(60 * time.Second):
+		t.Fatal("replay did not finish: deadlock?")
+	}
+	close(stopReaders)
+	<-readersDone
+
+	got := bot.candles["BTCUSD"]
+	if len(got) != maxCandleBuffer || got[len(got)-1].Time != series[bars-1].Time {
+		t.Errorf("candle buffer has %d bars ending at %d, want %d ending at %d",
+			len(got), got[len(got)-1].Time, maxCandleBuffer, series[bars-1].Time)
+	}
+	if tick := bot.lastTickers["BTCUSD"]; tick == nil || tick.Close != series[bars-1].Close {
+		t.Errorf("last ticker = %+v, want the final bar's close", tick)
+	}
+	if status := bot.GetStatus(); !status["symbols"].(map[string]bool)["BTCUSD"] {
+		t.Errorf("status = %+v, want BTCUSD enabled", status)
+	}
+	if bot.isRunning {
+		t.Error("bot still marked running after the replay")
+	}
+}
+
+func TestReplay_RejectsRunningBot(t *testing.T) {
+	bot := newInitTestBot(t, func(w http.ResponseWriter, r *http.Request) {})
+	bot.isRunning = true
+	if err := bot.Replay(map[string][]delta.Candle{"BTCUSD": {{Time: 0}}}, 0); err == nil {
+		t.Error("Replay() on a running bot succeeded")
+	}
+}
+
+func TestReplay_RefusesNonTestnet(t *testing.T) {
+	bot := newInitTestBot(t, func(w http.ResponseWriter, r *http.Request) {})
+	bot.cfg.IsTestnet = false
+	err := bot.Replay(map[string][]delta.Candle{"BTCUSD": {{Time: 0}}}, 0)
+	if err == nil || !strings.Contains(err.Error(), "non-testnet") {
+		t.Errorf("Replay() on a live account error = %v, want a refusal", err)
+	}
+}
+
+// clockRegimeDetector records the bot's time on each regime check
+type clockRegimeDetector struct {
+	bot  *StructuralBot
+	seen []time.Time
+}
+
+func (d *clockRegimeDetector) DetectRegime(string, []delta.Candle) (delta.MarketRegime, float64, error) {
+	d.seen = append(d.seen, d.bot.now())
+	return delta.RegimeRanging, 0.9, nil
+}
+
+func TestReplay_RunsOnBarTime(t *testing.T) {
+	bot := newInitTestBot(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"success":true,"result":[]}`))
+	})
+	bot.cfg.Symbols = []string{"BTCUSD"}
+	bot.cfg.RegimeCheckPeriod = time.Hour
+	detector := &clockRegimeDetector{bot: bot}
+	bot.SetRegimeDetector(detector)
+
+	series := make([]delta.Candle, 3*60)
+	for i := range series {
+		series[i] = delta.Candle{Time: int64(i * 60), Open: 50000, High: 50010, Low: 49990, Close: 50000, Volume: 1}
+	}
+	if err := bot.Replay(map[string][]delta.Candle{"BTCUSD": series}, 0); err != nil {
+		t.Fatalf("Replay() error = %v", err)
+	}
+
+	if len(detector.seen) == 0 {
+		t.Fatal("no regime checks ran during the replay")
+	}
+	for _, at := range detector.seen {
+		if at.Unix()%3600 != 0 || at.After(time.Unix(3*3600, 0)) {
+			t.Errorf("regime check at %v, want on an hourly bar of the replay", at.UTC())
+		}
+	}
+	if now := bot.now(); time.Since(now) > time.Minute {
+		t.Errorf("bot clock = %v after the replay, want the wall clock restored", now)
+	}
+}
@@ -0,0 +1,148 @@
+package main
+
+import (
+	"math"
+
+	"github.com/kasyap/delta-go/go/pkg/delta"
+	"github.com/kasyap/delta-go/go/pkg/logger"
+)
+
+// closedPnL is the PnL realized by closing size contracts of side entered at entry, at
+// price; 0 when either price is unknown
+func closedPnL(side string, size int, entry, price float64, product *delta.Product) float64 {
+	if entry <= 0 || price <= 0 || product == nil {
+		return 0
+	}
+	return delta.ComputeUnrealizedPnL(side, size, entry, price, product)
+}
+
+// exitPrice is the latest exit price source for symbol, or 0 before its first ticker
+func (bot *StructuralBot) exitPrice(symbol string) float64 {
+	bot.mu.RLock()
+	defer bot.mu.RUnlock()
+	if ticker := bot.lastTickers[symbol]; ticker != nil {
+		return ticker.ReferencePrice(bot.cfg.ExitPriceSource)
+	}
+	return 0
+}
+
+// positionClosePnL is the PnL realized by closing an exchange position at price, falling back
+// to the exchange's unrealized PnL when no price is known
+func positionClosePnL(pos *delta.Position, price float64, product *delta.Product) float64 {
+	if price > 0 && product != nil {
+		return pos.UnrealizedPnLAt(price, product)
+	}
+	return parseFloatOrZero(pos.UnrealizedPnL)
+}
+
+// recordTradeResult feeds a closed trade's PnL to the risk manager, which drives the loss
+// streak pause and loss streak sizing
+func (bot *StructuralBot) recordTradeResult(symbol, strategyName string, pnl float64) {
+	bot.riskManager.RecordTradeResult(pnl)
+	logger.WithTrade(symbol, strategyName).Info("Trade result recorded", "pnl", pnl)
+}
+
+// scalpExitPrice estimates where the bracket closed pos when its exit fill can't be found:
+// its stop once hit, else whichever of its stop and take-profit price is nearer, else price
+// itself
+func scalpExitPrice(pos *ScalpPosition, price float64) float64 {
+	switch {
+	case pos.StopHit && pos.StopLoss > 0:
+		return pos.StopLoss
+	case price <= 0:
+		return pos.TakeProfit
+	case pos.TakeProfit > 0 && pos.StopLoss > 0:
+		if math.Abs(price-pos.TakeProfit) < math.Abs(price-pos.StopLoss) {
+			return pos.TakeProfit
+		}
+		return pos.StopLoss
+	}
+	return price
+}
+
+// bracketExitPrice is the average fill price of the bracket stop-loss or take-profit order
+// that closed pos, from the product's order history since the entry; 0 if none is found
+func (bot *StructuralBot) bracketExitPrice(pos *ScalpPosition, product *delta.Product) float64 {
+	if product == nil {
+		return 0
+	}
+	orders, err := bot.deltaClient.GetOrderHistory(product.ID, pos.EntryTime)
+	if err != nil {
+		logger.WithTrade(pos.Symbol, pos.strategyName()).Warn("Failed to fetch bracket exit fill",
+			logger.KeyOrderID, pos.OrderID, "error", err)
+		return 0
+	}
+
+	exitSide := "sell"
+	if pos.Side == "sell" {
+		exitSide = "buy"
+	}
+	for _, order := range orders {
+		if order.Side != exitSide || order.Size-order.UnfilledSize <= 0 {
+			continue
+		}
+		if order.StopOrderType != delta.StopOrderTypeStopLoss && order.StopOrderType != delta.StopOrderTypeTakeProfit {
+			continue
+		}
+		if price := parseFloatOrZero(order.AverageFillPrice); price > 0 {
+			return price
+		}
+	}
+	return 0
+}
+
+// heldSizes returns the signed exchange position size per product ID
+func (bot *StructuralBot) heldSizes() (map[int]int, error) {
+	positions, err := bot.deltaClient.GetPositions()
+	if err != nil {
+		return nil, err
+	}
+	held := make(map[int]int, len(positions))
+	for _, p := range positions {
+		held[p.ProductID] = p.Size
+	}
+	return held, nil
+}
+
+// entryFilled reports whether any of the scalp's entry order filled
+func (bot *StructuralBot) entryFilled(pos *ScalpPosition) bool {
+	order, err := bot.deltaClient.GetOrderByID(pos.OrderID)
+	return err == nil && order.Size-order.UnfilledSize > 0
+}
+
+// reconcileScalp settles a tracked scalp whose filled entry the exchange no longer holds: its
+// bracket stop or take-profit closed it. It reports whether the scalp was settled.
+func (bot *StructuralBot) reconcileScalp(pos *ScalpPosition, held map[int]int) bool {
+	bot.mu.RLock()
+	product := bot.productCache[pos.Symbol]
+	bot.mu.RUnlock()
+	if product == nil || held[product.ID] != 0 || !bot.entryFilled(pos) {
+		return false
+	}
+	bot.settleScalp(pos, "bracket exit")
+	return true
+}
+
+// settleScalp records the result of a scalp the exchange has already closed and stops
+// tracking it
+func (bot *StructuralBot) settleScalp(pos *ScalpPosition, reason string) {
+	bot.mu.Lock()
+	if bot.scalpPositions[pos.Symbol] != pos {
+		bot.mu.Unlock()
+		return
+	}
+	delete(bot.scalpPositions, pos.Symbol)
+	product := bot.productCache[pos.Symbol]
+	bot.mu.Unlock()
+
+	price := bot.bracketExitPrice(pos, product)
+	if price <= 0 {
+		price = scalpExitPrice(pos, bot.exitPrice(pos.Symbol))
+	}
+	logger.WithTrade(pos.Symbol, pos.strategyName()).Info("Scalp closed on exchange",
+		logger.KeyOrderID, pos.OrderID, "reason", reason, "exit_price", price)
+	bot.recordTradeResult(pos.Symbol, pos.strategyName(), closedPnL(pos.Side, pos.Size, pos.EntryPrice, price, product))
+	if scalper := bot.driverSelector.GetScalper(); scalper != nil {
+		scalper.RecordExit(pos.Symbol)
+	}
+}
@@ -0,0 +1,236 @@
+package main
+
+import (
+	"testing"
+	"time"
+
+	"github.com/kasyap/delta-go/go/config"
+	"github.com/kasyap/delta-go/go/pkg/delta"
+	"github.com/kasyap/delta-go/go/pkg/strategy"
+)
+
+func scalpResultBot(t *testing.T) (*StructuralBot, *fakeExchange) {
+	t.Helper()
+	x := newFakeExchange(delta.Product{ID: 27, Symbol: "BTCUSD", ContractValue: "0.001", TickSize: "0.5"})
+	x.setPosition(27, 10)
+	x.byID[1] = &delta.Order{ID: 1, ProductID: 27, Size: 10, Side: "buy", State: "closed", AverageFillPrice: "50000"}
+	bot := newFakeExchangeBot(t, x, &config.Config{Symbols: []string{"BTCUSD"}})
+	bot.scalpPositions["BTCUSD"] = &ScalpPosition{Symbol: "BTCUSD", Side: "buy", Size: 10, EntryPrice: 50000,
+		OrderID: 1, Entries: 1, LastAddPrice: 50000, StopLoss: 49500, TakeProfit: 51000}
+	return bot, x
+}
+
+func consecutiveLosses(bot *StructuralBot) int {
+	return bot.riskManager.GetRiskMetrics()["consecutive_losses"].(int)
+}
+
+func TestFlattenSymbol_RecordsTradeResult(t *testing.T) {
+	bot, _ := scalpResultBot(t)
+	bot.lastTickers["BTCUSD"] = &delta.Ticker{Symbol: "BTCUSD", MarkPrice: 49800}
+
+	if err := bot.flattenSymbol("BTCUSD"); err != nil {
+		t.Fatalf("flattenSymbol() error = %v", err)
+	}
+	if n := consecutiveLosses(bot); n != 1 {
+		t.Errorf("consecutive losses = %d, want the losing flatten recorded", n)
+	}
+}
+
+func TestCheckScalpExits_RecordsStopOnce(t *testing.T) {
+	bot, x := scalpResultBot(t)
+	bot.lastTickers["BTCUSD"] = &delta.Ticker{Symbol: "BTCUSD", MarkPrice: 49400}
+
+	// The stop is crossed but the bracket has not filled yet
+	bot.checkScalpExits()
+	if n := consecutiveLosses(bot); n != 0 {
+		t.Fatalf("consecutive losses = %d before the bracket closed, want 0", n)
+	}
+
+	x.setPosition(27, 0)
+	bot.lastScalpReconcile = time.Time{} // The next reconciliation is due
+	bot.checkScalpExits()
+	bot.lastScalpReconcile = time.Time{}
+	bot.checkScalpExits()
+	if n := consecutiveLosses(bot); n != 1 {
+		t.Errorf("consecutive losses = %d after the stop filled, want 1", n)
+	}
+	if _, ok := bot.scalpPositions["BTCUSD"]; ok {
+		t.Error("scalp still tracked after the exchange closed it")
+	}
+}
+
+func TestCheckScalpExits_TakeProfitResetsLossStreak(t *testing.T) {
+	bot, x := scalpResultBot(t)
+	bot.riskManager.RecordTradeResult(-10)
+	bot.riskManager.RecordTradeResult(-10)
+
+	bot.lastTickers["BTCUSD"] = &delta.Ticker{Symbol: "BTCUSD", MarkPrice: 51020}
+	x.setPosition(27, 0)
+	bot.checkScalpExits()
+
+	if n := consecutiveLosses(bot); n != 0 {
+		t.Errorf("consecutive losses = %d after a take-profit exit, want the streak reset", n)
+	}
+	if _, ok := bot.scalpPositions["BTCUSD"]; ok {
+		t.Error("scalp still tracked after its take-profit filled")
+	}
+}
+
+func TestCheckScalpExits_ReconcilesOnPerformanceCadence(t *testing.T) {
+	bot, x := scalpResultBot(t)
+	bot.lastTickers["BTCUSD"] = &delta.Ticker{Symbol: "BTCUSD", MarkPrice: 50000}
+	bot.checkScalpExits()
+
+	// Closed by the bracket's take-profit, filled above its trigger
+	x.setPosition(27, 0)
+	x.addHistory(delta.Order{ID: 9, ProductID: 27, Size: 10, Side: "sell", State: "closed",
+		StopOrderType: delta.StopOrderTypeTakeProfit, AverageFillPrice: "51010"})
+	bot.checkScalpExits()
+	if _, ok := bot.scalpPositions["BTCUSD"]; !ok {
+		t.Fatal("scalp settled before the next reconciliation was due")
+	}
+
+	pos := bot.scalpPositions["BTCUSD"]
+	if price := bot.bracketExitPrice(pos, bot.productCache["BTCUSD"]); price != 51010 {
+		t.Errorf("exit price = %v, want the take-profit's 51010 fill rather than its trigger", price)
+	}
+
+	bot.lastScalpReconcile = bot.now().Add(-perfUpdateInterval)
+	bot.checkScalpExits()
+	if _, ok := bot.scalpPositions["BTCUSD"]; ok {
+		t.Fatal("scalp still tracked after its bracket closed it")
+	}
+}
+
+func TestFlattenSymbol_SettlesScalpClosedByBracket(t *testing.T) {
+	bot, x := scalpResultBot(t)
+	bot.lastTickers["BTCUSD"] = &delta.Ticker{Symbol: "BTCUSD", MarkPrice: 49400}
+	bot.checkStopHit(bot.scalpPositions["BTCUSD"])
+	x.setPosition(27, 0)
+
+	if err := bot.flattenSymbol("BTCUSD"); err != nil {
+		t.Fatalf("flattenSymbol() error = %v", err)
+	}
+	if n := consecutiveLosses(bot); n != 1 {
+		t.Errorf("consecutive losses = %d, want the stopped-out scalp recorded", n)
+	}
+}
+
+func TestScalpEntry_ShrinksAfterRecordedLoss(t *testing.T) {
+	x := newFakeExchange(delta.Product{ID: 27, Symbol: "BTCUSD", ContractValue: "0.001", TickSize: "0.5"})
+	bot := newFakeExchangeBot(t, x, &config.Config{
+		Symbols:          []string{"BTCUSD"},
+		ScalperEnabled:   true,
+		MaxPositionPct:   10,
+		Leverage:         10,
+		LossStreakSizing: "anti-martingale",
+		LossStreakFactor: 0.5,
+		LossStreakFloor:  0.25,
+	})
+	signal := strategy.Signal{Action: strategy.ActionBuy, Side: "buy", Price: 50000, StopLoss: 49500, TakeProfit: 51000, Confidence: 1}
+	entrySize := func() int {
+		t.Helper()
+		bot.executeScalpEntry(signal, bot.productCache["BTCUSD"], "BTCUSD")
+		orders := x.placed()
+		if len(orders) == 0 {
+			t.Fatal("no scalp entry placed")
+		}
+		return orders[len(orders)-1].Size
+	}
+
+	first := entrySize()
+	bot.lastTickers["BTCUSD"] = &delta.Ticker{Symbol: "BTCUSD", MarkPrice: 49800}
+	if err := bot.flattenSymbol("BTCUSD"); err != nil {
+		t.Fatalf("flattenSymbol() error = %v", err)
+	}
+
+	if second := entrySize(); second*2 != first {
+		t.Errorf("entry after a loss = %d contracts, want half of the first entry's %d", second, first)
+	}
+}
+
+func TestFundingEntry_ShrinksAfterRecordedLoss(t *testing.T) {
+	x := newFakeExchange(delta.Product{ID: 27, Symbol: "BTCUSD", ContractValue: "0.001", TickSize: "0.5"})
+	bot := newFakeExchangeBot(t, x, &config.Config{
+		Symbols:           []string{"BTCUSD"},
+		BasisTradeEnabled: true,
+		MaxPositionPct:    10,
+		Leverage:          10,
+		LossStreakSizing:  "anti-martingale",
+		LossStreakFactor:  0.5,
+		LossStreakFloor:   0.25,
+	})
+	signal := strategy.Signal{Action: strategy.ActionSell, Side: "sell", Price: 50000, Confidence: 1}
+	entrySize := func() int {
+		t.Helper()
+		bot.executeFundingArbEntry(signal, bot.productCache["BTCUSD"], "BTCUSD")
+		orders := x.placed()
+		if len(orders) == 0 {
+			t.Fatal("no funding entry placed")
+		}
+		return orders[len(orders)-1].Size
+	}
+
+	first := entrySize()
+	bot.lastTickers["BTCUSD"] = &delta.Ticker{Symbol: "BTCUSD", MarkPrice: 50200}
+	if err := bot.flattenSymbol("BTCUSD"); err != nil {
+		t.Fatalf("flattenSymbol() error = %v", err)
+	}
+
+	if second := entrySize(); second*2 != first {
+		t.Errorf("entry after a loss = %d contracts, want half of the first entry's %d", second, first)
+	}
+}
+
+func TestScalpEntry_ShrinksUnderProfitLock(t *testing.T) {
+	x := newFakeExchange(delta.Product{ID: 27, Symbol: "BTCUSD", ContractValue: "0.001", TickSize: "0.5"})
+	bot := newFakeExchangeBot(t, x, &config.Config{
+		Symbols:              []string{"BTCUSD"},
+		ScalperEnabled:       true,
+		MaxPositionPct:       10,
+		Leverage:             10,
+		MaxDrawdownPct:       50,
+		DailyLossLimitPct:    -50,
+		DailyProfitTargetPct: 3,
+		ProfitLockMode:       "reduce",
+		ProfitLockSizeMult:   0.5,
+	})
+	signal := strategy.Signal{Action: strategy.ActionBuy, Side: "buy", Price: 50000, StopLoss: 49500, TakeProfit: 51000, Confidence: 1}
+
+	bot.riskManager.UpdateBalance(10000)
+	bot.executeScalpEntry(signal, bot.productCache["BTCUSD"], "BTCUSD")
+	bot.forgetSymbol("BTCUSD")
+	bot.riskManager.UpdateBalance(10400)
+	bot.executeScalpEntry(signal, bot.productCache["BTCUSD"], "BTCUSD")
+
+	orders := x.placed()
+	if len(orders) != 2 {
+		t.Fatalf("placed %d orders, want 2 entries", len(orders))
+	}
+	if first, second := orders[0].Size, orders[1].Size; second*2 != first {
+		t.Errorf("entry under the profit lock = %d contracts, want half of %d", second, first)
+	}
+}
+
+func TestEvaluateAndTrade_ProfitTargetLocksEntries(t *testing.T) {
+	bot, x := entryBot(t, &config.Config{
+		MaxDrawdownPct:       50,
+		DailyLossLimitPct:    -50,
+		DailyProfitTargetPct: 3,
+		ProfitLockMode:       "stop",
+	})
+
+	x.setBalance("10000")
+	tradingCycle(bot)
+	if orders := x.placed(); len(orders) != 1 {
+		t.Fatalf("placed %d orders before the target, want the entry", len(orders))
+	}
+
+	// Up 4% on the day: no new positions until tomorrow
+	bot.forgetSymbol("BTCUSD")
+	x.setBalance("10400")
+	tradingCycle(bot)
+	if orders := x.placed(); len(orders) != 1 {
+		t.Errorf("placed %d orders after the daily target, want no new entry", len(orders))
+	}
+}
@@ -0,0 +1,50 @@
+package main
+
+import (
+	"github.com/kasyap/delta-go/go/pkg/delta"
+	"github.com/kasyap/delta-go/go/pkg/logger"
+	"github.com/kasyap/delta-go/go/pkg/strategy"
+)
+
+// handleHeldScalpSignal acts on a scalper signal for a symbol that already holds a scalp.
+// An aligned signal may pyramid; an opposite one flattens the symbol (resting entry and
+// bracket orders are cancelled first, then whatever the exchange holds is closed) and, with
+// AllowReversal, opens the other side. Otherwise the symbol waits out ReversalCooldown.
+func (bot *StructuralBot) handleHeldScalpSignal(signal strategy.Signal, product *delta.Product, symbol string, regime delta.MarketRegime) {
+	bot.mu.RLock()
+	pos, ok := bot.scalpPositions[symbol]
+	var side string
+	var size int
+	if ok {
+		side, size = pos.Side, pos.Size
+	}
+	bot.mu.RUnlock()
+	if !ok {
+		return
+	}
+
+	if signal.Side == side {
+		if bot.cfg.MaxPyramidEntries > 0 {
+			bot.executeScalpPyramid(signal, product, symbol, regime)
+		}
+		return
+	}
+
+	tl := logger.WithTrade(symbol, scalpStrategyName).With(logger.KeyAction, signal.Action)
+	tl.Info("Opposite signal - closing scalp", "side", side, "size", size, "confidence", signal.Confidence)
+	if err := bot.flattenSymbol(symbol); err != nil {
+		tl.Error("Failed to close scalp on opposite signal", "error", err)
+		return
+	}
+
+	if !bot.cfg.AllowReversal {
+		bot.riskManager.RecordReversalExit(symbol, bot.now())
+		if bot.cfg.ReversalCooldown > 0 {
+			tl.Info("Reversal disabled - pausing entries", "cooldown", bot.cfg.ReversalCooldown)
+		}
+		return
+	}
+
+	tl.Info("Reversing", "side", signal.Side)
+	bot.executeScalpEntry(signal, product, symbol)
+}
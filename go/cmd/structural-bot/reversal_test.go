@@ -0,0 +1,71 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/kasyap/delta-go/go/config"
+	"github.com/kasyap/delta-go/go/pkg/delta"
+	"github.com/kasyap/delta-go/go/pkg/strategy"
+)
+
+func TestHandleHeldScalpSignal_CancelsUnfilledEntry(t *testing.T) {
+	x := newFakeExchange(delta.Product{ID: 27, Symbol: "BTCUSD", ContractValue: "0.001", TickSize: "0.5"})
+	x.setRestOrders(true)
+	bot := newFakeExchangeBot(t, x, &config.Config{Symbols: []string{"BTCUSD"}, ScalperEnabled: true, MaxPositionPct: 10, Leverage: 10})
+	buy := strategy.Signal{Action: strategy.ActionBuy, Side: "buy", Price: 50000, StopLoss: 49500, TakeProfit: 51000, Confidence: 1}
+	bot.executeScalpEntry(buy, bot.productCache["BTCUSD"], "BTCUSD")
+	entryID := bot.scalpPositions["BTCUSD"].OrderID
+
+	sell := strategy.Signal{Action: strategy.ActionSell, Side: "sell", Price: 50000, Confidence: 1}
+	bot.handleHeldScalpSignal(sell, bot.productCache["BTCUSD"], "BTCUSD", delta.RegimeRanging)
+
+	if orders := x.placed(); len(orders) != 1 {
+		t.Fatalf("placed %d orders, want only the entry: nothing is held to close", len(orders))
+	}
+	if state := x.byID[entryID].State; state != "cancelled" {
+		t.Errorf("resting entry state = %q, want cancelled so it can't fill untracked", state)
+	}
+	if _, ok := bot.scalpPositions["BTCUSD"]; ok {
+		t.Error("scalp still tracked after the opposite signal")
+	}
+}
+
+func TestHandleHeldScalpSignal_ClosesHeldSize(t *testing.T) {
+	bot, x := scalpResultBot(t)
+	bot.lastTickers["BTCUSD"] = &delta.Ticker{Symbol: "BTCUSD", MarkPrice: 50100}
+	// Only 4 of the tracked 10 contracts filled
+	x.setPosition(27, 4)
+
+	sell := strategy.Signal{Action: strategy.ActionSell, Side: "sell", Price: 50100, Confidence: 1}
+	bot.handleHeldScalpSignal(sell, bot.productCache["BTCUSD"], "BTCUSD", delta.RegimeRanging)
+
+	orders := x.placed()
+	if len(orders) != 1 || orders[0].Side != "sell" || orders[0].Size != 4 {
+		t.Fatalf("orders = %+v, want one sell of the 4 held contracts", orders)
+	}
+	if x.position(27) != 0 {
+		t.Errorf("position after close = %d, want flat", x.position(27))
+	}
+}
+
+func TestExecuteScalpEntry_ReentryOnSameCandleGetsFreshClientOrderID(t *testing.T) {
+	x := newFakeExchange(delta.Product{ID: 27, Symbol: "BTCUSD", ContractValue: "0.001", TickSize: "0.5"})
+	bot := newFakeExchangeBot(t, x, &config.Config{Symbols: []string{"BTCUSD"}, ScalperEnabled: true, MaxPositionPct: 10, Leverage: 10})
+	bot.candles["BTCUSD"] = []delta.Candle{{Time: 1700000000, Close: 50000}}
+	bot.lastTickers["BTCUSD"] = &delta.Ticker{Symbol: "BTCUSD", MarkPrice: 50000}
+	buy := strategy.Signal{Action: strategy.ActionBuy, Side: "buy", Price: 50000, StopLoss: 49500, TakeProfit: 51000, Confidence: 1}
+
+	bot.executeScalpEntry(buy, bot.productCache["BTCUSD"], "BTCUSD")
+	bot.flattenSymbol("BTCUSD")
+	bot.executeScalpEntry(buy, bot.productCache["BTCUSD"], "BTCUSD")
+
+	var ids []string
+	for _, order := range x.placed() {
+		if order.Side == "buy" {
+			ids = append(ids, order.ClientOrderID)
+		}
+	}
+	if len(ids) != 2 || ids[0] == "" || ids[0] == ids[1] {
+		t.Errorf("entry client order IDs = %q, want two distinct IDs", ids)
+	}
+}
@@ -0,0 +1,20 @@
+package main
+
+import (
+	"github.com/kasyap/delta-go/go/config"
+	"github.com/kasyap/delta-go/go/pkg/risk"
+	"github.com/kasyap/delta-go/go/pkg/strategy"
+)
+
+// checkRewardRisk fills a signal's missing stop or target from StopLossPct/TakeProfitPct and
+// reports the resulting reward:risk and whether it clears MinRewardRisk (0 = no minimum).
+// It guards the bracketed directional entries: scalps, their pyramid add-ons and breakouts.
+// Funding entries are exempt, as they are held for the funding carry and exit on funding,
+// holding time or the basis price stop rather than a target; so are grid levels, resting
+// limit orders without a bracket that are capped by GridMaxInventory instead.
+func checkRewardRisk(cfg *config.Config, signal strategy.Signal) (strategy.Signal, float64, bool) {
+	signal.StopLoss, signal.TakeProfit = risk.DefaultBracket(signal.Side, signal.Price,
+		signal.StopLoss, signal.TakeProfit, cfg.StopLossPct, cfg.TakeProfitPct)
+	rr := risk.RewardRisk(signal.Side, signal.Price, signal.StopLoss, signal.TakeProfit)
+	return signal, rr, cfg.MinRewardRisk <= 0 || rr >= cfg.MinRewardRisk
+}
@@ -0,0 +1,54 @@
+package main
+
+import (
+	"math"
+	"testing"
+
+	"github.com/kasyap/delta-go/go/config"
+	"github.com/kasyap/delta-go/go/pkg/strategy"
+)
+
+func TestCheckRewardRisk(t *testing.T) {
+	cfg := &config.Config{MinRewardRisk: 1.5, StopLossPct: 2, TakeProfitPct: 4}
+
+	// 0.5:1 - risking 2 to make 1
+	if _, rr, ok := checkRewardRisk(cfg, strategy.Signal{Side: "buy", Price: 100, StopLoss: 98, TakeProfit: 101}); ok {
+		t.Errorf("0.5:1 long accepted (rr=%.2f)", rr)
+	}
+	// 2:1 short
+	if _, rr, ok := checkRewardRisk(cfg, strategy.Signal{Side: "sell", Price: 100, StopLoss: 101, TakeProfit: 98}); !ok || math.Abs(rr-2) > 1e-9 {
+		t.Errorf("2:1 short rejected (rr=%.2f)", rr)
+	}
+
+	// Missing legs come from StopLossPct/TakeProfitPct first: 4% over 2% = 2:1
+	sig, rr, ok := checkRewardRisk(cfg, strategy.Signal{Side: "buy", Price: 100})
+	if !ok || math.Abs(sig.StopLoss-98) > 1e-9 || math.Abs(sig.TakeProfit-104) > 1e-9 {
+		t.Errorf("defaults = SL %.2f TP %.2f rr %.2f ok %v, want 98/104 accepted", sig.StopLoss, sig.TakeProfit, rr, ok)
+	}
+
+	// A filled-in 4% target doesn't rescue a wide stop: 4 over 5 is below the minimum
+	if _, rr, ok := checkRewardRisk(cfg, strategy.Signal{Side: "buy", Price: 100, StopLoss: 95}); ok {
+		t.Errorf("0.8:1 with a default target accepted (rr=%.2f)", rr)
+	}
+
+	cfg.MinRewardRisk = 0
+	if _, _, ok := checkRewardRisk(cfg, strategy.Signal{Side: "buy", Price: 100, StopLoss: 98, TakeProfit: 101}); !ok {
+		t.Error("rejected with the minimum off")
+	}
+}
+
+func TestCheckRewardRisk_DefaultScalpBracketClearsDefaultMinimum(t *testing.T) {
+	cfg := config.LoadConfig()
+	// At the max spread the scalper gives up half the spread from its target
+	mid, spreadBps := 50000.0, 10.0
+	signal := strategy.Signal{
+		Side:       "buy",
+		Price:      mid,
+		StopLoss:   mid * (1 - cfg.ScalpMaxLossBps/10000),
+		TakeProfit: mid * (1 + (cfg.ScalpTargetBps-spreadBps/2)/10000),
+	}
+	if _, rr, ok := checkRewardRisk(cfg, signal); !ok || cfg.MinRewardRisk <= 0 {
+		t.Errorf("default scalp bracket rr=%.2f against default minimum %.2f: accepted=%v, want a minimum it clears",
+			rr, cfg.MinRewardRisk, ok)
+	}
+}
@@ -0,0 +1,276 @@
+package main
+
+import (
+	"sync"
+	"time"
+
+	"github.com/kasyap/delta-go/go/config"
+	"github.com/kasyap/delta-go/go/pkg/delta"
+	"github.com/kasyap/delta-go/go/pkg/features"
+	"github.com/kasyap/delta-go/go/pkg/logger"
+	"github.com/kasyap/delta-go/go/pkg/strategy"
+)
+
+// maxShadowSignals caps the shadow signal log kept in memory
+const maxShadowSignals = 500
+
+// recentShadowSignals is how many of the latest shadow signals GetStatus reports
+const recentShadowSignals = 10
+
+// signalSelector picks a strategy and signal from market data; strategy.DriverSelector
+// implements it
+type signalSelector interface {
+	SelectStrategy(f features.MarketFeatures, candles []delta.Candle) (strategy.SelectedStrategy, strategy.Signal)
+}
+
+// ShadowSignal is a signal produced by the shadow config
+type ShadowSignal struct {
+	Time       time.Time `json:"time"`
+	Symbol     string    `json:"symbol"`
+	Strategy   string    `json:"strategy"`
+	Action     string    `json:"action"`
+	Side       string    `json:"side"`
+	Price      float64   `json:"price"`
+	Confidence float64   `json:"confidence"`
+}
+
+type shadowPosition struct {
+	Side       string
+	EntryPrice float64
+	Notional   float64
+	StopLoss   float64
+	TakeProfit float64
+	LastPrice  float64
+}
+
+// ShadowTrader runs a candidate strategy config on the same market data as the live bot.
+// Its signals are filled on paper at the signal price, one position per symbol, and its
+// equity is tracked in a PerformanceTracker of its own. It never places orders.
+type ShadowTrader struct {
+	selector       signalSelector
+	perf           *PerformanceTracker
+	maxPositionPct float64
+	leverage       int
+	feeBps         float64
+	priceSource    string
+
+	mu        sync.Mutex
+	cash      float64 // Simulated equity excluding open positions (0 = not yet seeded)
+	realized  float64
+	positions map[string]*shadowPosition
+	signals   []ShadowSignal
+	trades    int
+	wins      int
+}
+
+// NewShadowTrader creates a shadow trader sized like the live bot in cfg
+func NewShadowTrader(selector signalSelector, cfg *config.Config) *ShadowTrader {
+	return &ShadowTrader{
+		selector:       selector,
+		perf:           NewPerformanceTracker(500),
+		maxPositionPct: cfg.MaxPositionPct,
+		leverage:       cfg.Leverage,
+		feeBps:         cfg.ShadowFeeBps,
+		priceSource:    cfg.ExitPriceSource,
+		positions:      make(map[string]*shadowPosition),
+	}
+}
+
+// shadowConfig is cfg with the candidate overrides applied
+func shadowConfig(cfg *config.Config) *config.Config {
+	out := *cfg
+	if cfg.ShadowScalpImbalanceThreshold > 0 {
+		out.ScalpImbalanceThreshold = cfg.ShadowScalpImbalanceThreshold
+	}
+	if cfg.ShadowScalpTargetBps > 0 {
+		out.ScalpTargetBps = cfg.ShadowScalpTargetBps
+	}
+	if cfg.ShadowScalpMaxLossBps > 0 {
+		out.ScalpMaxLossBps = cfg.ShadowScalpMaxLossBps
+	}
+	if cfg.ShadowBasisEntryThreshold > 0 {
+		out.BasisEntryThreshold = cfg.ShadowBasisEntryThreshold
+	}
+	if cfg.ShadowBasisExitThreshold > 0 {
+		out.BasisExitThreshold = cfg.ShadowBasisExitThreshold
+	}
+	return &out
+}
+
+// Seed starts the simulated account at equity; later calls are ignored
+func (st *ShadowTrader) Seed(equity float64) {
+	st.mu.Lock()
+	defer st.mu.Unlock()
+	if st.cash == 0 && equity > 0 {
+		st.cash = equity
+	}
+}
+
+// Evaluate runs the shadow config on one symbol's market data, closes the simulated
+// position on a stop, target, close or opposite signal, opens one on an entry signal and
+// records the resulting equity. Nothing happens until the account is seeded.
+func (st *ShadowTrader) Evaluate(symbol string, f features.MarketFeatures, candles []delta.Candle, now time.Time) {
+	price := f.ReferencePrice(st.priceSource)
+
+	st.mu.Lock()
+	defer st.mu.Unlock()
+	if st.cash == 0 || price <= 0 {
+		return
+	}
+
+	if pos := st.positions[symbol]; pos != nil {
+		pos.LastPrice = price
+		if shadowExitHit(pos, price) {
+			st.close(symbol, price)
+		}
+	}
+
+	selected, signal := st.selector.SelectStrategy(f, candles)
+	if signal.Action != strategy.ActionNone {
+		fill := signal.Price
+		if fill <= 0 {
+			fill = price
+		}
+		st.recordSignal(ShadowSignal{
+			Time:       now,
+			Symbol:     symbol,
+			Strategy:   selected.Name,
+			Action:     string(signal.Action),
+			Side:       signal.Side,
+			Price:      fill,
+			Confidence: signal.Confidence,
+		})
+		logger.WithTrade(symbol, selected.Name).Info("Shadow signal", logger.KeyAction, signal.Action,
+			"side", signal.Side, "confidence", signal.Confidence)
+		st.apply(symbol, signal, fill)
+	}
+
+	st.recordSnapshot(now)
+}
+
+// shadowExitHit reports whether price has reached the position's stop or target
+func shadowExitHit(pos *shadowPosition, price float64) bool {
+	if pos.Side == "buy" {
+		return (pos.StopLoss > 0 && price <= pos.StopLoss) || (pos.TakeProfit > 0 && price >= pos.TakeProfit)
+	}
+	return (pos.StopLoss > 0 && price >= pos.StopLoss) || (pos.TakeProfit > 0 && price <= pos.TakeProfit)
+}
+
+func (st *ShadowTrader) apply(symbol string, signal strategy.Signal, fill float64) {
+	pos := st.positions[symbol]
+	if signal.Action == strategy.ActionClose {
+		if pos != nil {
+			st.close(symbol, fill)
+		}
+		return
+	}
+
+	side := signal.Side
+	if side == "" {
+		side = string(signal.Action)
+	}
+	if pos != nil {
+		if pos.Side == side {
+			return
+		}
+		st.close(symbol, fill)
+	}
+
+	notional := st.cash * (st.maxPositionPct / 100) * float64(max(st.leverage, 1))
+	if notional <= 0 {
+		return
+	}
+	st.cash -= notional * st.feeBps / 10000
+	st.positions[symbol] = &shadowPosition{
+		Side:       side,
+		EntryPrice: fill,
+		Notional:   notional,
+		StopLoss:   signal.StopLoss,
+		TakeProfit: signal.TakeProfit,
+		LastPrice:  fill,
+	}
+}
+
+func (st *ShadowTrader) close(symbol string, price float64) {
+	pos := st.positions[symbol]
+	pnl := shadowUnrealized(pos, price) - pos.Notional*st.feeBps/10000
+	st.cash += pnl
+	st.realized += pnl
+	st.trades++
+	if pnl > 0 {
+		st.wins++
+	}
+	delete(st.positions, symbol)
+}
+
+func shadowUnrealized(pos *shadowPosition, price float64) float64 {
+	pnl := pos.Notional * (price - pos.EntryPrice) / pos.EntryPrice
+	if pos.Side == "sell" {
+		pnl = -pnl
+	}
+	return pnl
+}
+
+func (st *ShadowTrader) recordSignal(s ShadowSignal) {
+	st.signals = append(st.signals, s)
+	if len(st.signals) > maxShadowSignals {
+		st.signals = st.signals[len(st.signals)-maxShadowSignals:]
+	}
+}
+
+func (st *ShadowTrader) recordSnapshot(now time.Time) {
+	unrealized := 0.0
+	for _, pos := range st.positions {
+		unrealized += shadowUnrealized(pos, pos.LastPrice)
+	}
+	st.perf.Record(PerformanceSnapshot{
+		Timestamp:     now,
+		Equity:        st.cash + unrealized,
+		RealizedPnL:   st.realized,
+		UnrealizedPnL: unrealized,
+		Positions:     len(st.positions),
+	})
+}
+
+// Signals returns a copy of the recorded shadow signals, oldest first
+func (st *ShadowTrader) Signals() []ShadowSignal {
+	st.mu.Lock()
+	defer st.mu.Unlock()
+	return append([]ShadowSignal(nil), st.signals...)
+}
+
+// Report is the shadow PerformanceTracker report plus trade counts and the latest signals
+func (st *ShadowTrader) Report() map[string]interface{} {
+	report := st.perf.Report()
+
+	st.mu.Lock()
+	defer st.mu.Unlock()
+	winRate := 0.0
+	if st.trades > 0 {
+		winRate = float64(st.wins) / float64(st.trades) * 100
+	}
+	recent := st.signals[max(len(st.signals)-recentShadowSignals, 0):]
+	report["trades"] = st.trades
+	report["win_rate"] = winRate
+	report["signals_recorded"] = len(st.signals)
+	report["recent_signals"] = append([]ShadowSignal(nil), recent...)
+	return report
+}
+
+// evaluateShadow runs the shadow config over every tradable symbol. It sees the same
+// features and candles as the live evaluation but none of the live risk gates.
+func (bot *StructuralBot) evaluateShadow(featuresMap map[string]features.MarketFeatures, candlesMap map[string][]delta.Candle) {
+	if bot.shadow == nil {
+		return
+	}
+	bot.shadow.Seed(bot.perfTracker.StartEquity())
+
+	now := bot.now()
+	for _, symbol := range bot.tradableSymbols() {
+		f, ok := featuresMap[symbol]
+		if !ok || strategy.RequireCandles(candlesMap[symbol], minStrategyCandles) != nil {
+			continue
+		}
+		bot.shadow.Evaluate(symbol, f, candlesMap[symbol], now)
+	}
+}
@@ -0,0 +1,79 @@
+package main
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/kasyap/delta-go/go/pkg/delta"
+	"github.com/kasyap/delta-go/go/pkg/features"
+	"github.com/kasyap/delta-go/go/pkg/strategy"
+)
+
+// scriptedSelector returns its signals in order, then no signal, from strategy name
+// ("candidate" when unset)
+type scriptedSelector struct {
+	name    string
+	signals []strategy.Signal
+}
+
+func (s *scriptedSelector) SelectStrategy(f features.MarketFeatures, candles []delta.Candle) (strategy.SelectedStrategy, strategy.Signal) {
+	name := s.name
+	if name == "" {
+		name = "candidate"
+	}
+	if len(s.signals) == 0 {
+		return strategy.SelectedStrategy{Name: name}, strategy.Signal{Action: strategy.ActionNone}
+	}
+	sig := s.signals[0]
+	s.signals = s.signals[1:]
+	return strategy.SelectedStrategy{Name: name}, sig
+}
+
+func TestShadow_RecordsSignalsWithoutPlacingOrders(t *testing.T) {
+	requests := 0
+	bot := newInitTestBot(t, func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		t.Errorf("shadow mode hit the exchange: %s %s", r.Method, r.URL.Path)
+	})
+	bot.cfg.MaxPositionPct = 10
+	bot.cfg.Leverage = 1
+	bot.perfTracker.SetStartEquity(1000)
+	bot.shadow = NewShadowTrader(&scriptedSelector{signals: []strategy.Signal{
+		{Action: strategy.ActionBuy, Side: "buy", Price: 100, Confidence: 0.7},
+		{Action: strategy.ActionSell, Side: "sell", Price: 110, Confidence: 0.6},
+	}}, bot.cfg)
+
+	candles := map[string][]delta.Candle{"BTCUSD": make([]delta.Candle, 50)}
+	feats := func(price float64) map[string]features.MarketFeatures {
+		return map[string]features.MarketFeatures{"BTCUSD": {SpotPrice: price, MarkPrice: price}}
+	}
+	bot.evaluateShadow(feats(100), candles)
+	bot.evaluateShadow(feats(110), candles)
+
+	if requests != 0 {
+		t.Fatalf("exchange requests = %d, want 0", requests)
+	}
+	signals := bot.shadow.Signals()
+	if len(signals) != 2 || signals[0].Side != "buy" || signals[1].Side != "sell" || signals[0].Strategy != "candidate" {
+		t.Fatalf("recorded signals = %+v, want buy then sell from candidate", signals)
+	}
+
+	shadow, ok := bot.GetStatus()["shadow"].(map[string]interface{})
+	if !ok {
+		t.Fatal("GetStatus has no shadow report")
+	}
+	// Long 100 notional from 100 to 110 nets +10, reversed into a short that is still open
+	if shadow["trades"] != 1 || shadow["open_positions"] != 1 || shadow["win_rate"] != 100.0 {
+		t.Errorf("shadow report = %+v, want 1 winning trade and 1 open position", shadow)
+	}
+	if pnl := shadow["realized_pnl"].(float64); pnl < 9.9 || pnl > 10 {
+		t.Errorf("shadow realized PnL = %.4f, want 10 less fees", pnl)
+	}
+}
+
+func TestGetStatus_NoShadowWhenDisabled(t *testing.T) {
+	bot := newInitTestBot(t, func(w http.ResponseWriter, r *http.Request) {})
+	if _, ok := bot.GetStatus()["shadow"]; ok {
+		t.Error("shadow report present with shadow mode off")
+	}
+}
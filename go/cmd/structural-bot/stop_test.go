@@ -0,0 +1,30 @@
+package main
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/kasyap/delta-go/go/pkg/delta"
+)
+
+func TestCheckStopHit_PriceSource(t *testing.T) {
+	for _, tc := range []struct {
+		source string
+		want   bool
+	}{
+		{delta.PriceSourceMark, false},
+		{delta.PriceSourceLast, true},
+	} {
+		bot := newInitTestBot(t, func(w http.ResponseWriter, r *http.Request) {})
+		bot.cfg.ExitPriceSource = tc.source
+		// Last trade wicks through the long's stop; mark stays above it
+		bot.lastTickers["BTCUSD"] = &delta.Ticker{Symbol: "BTCUSD", MarkPrice: 49600, Close: 49400}
+		pos := &ScalpPosition{Symbol: "BTCUSD", Side: "buy", EntryTime: time.Now(), EntryPrice: 50000, StopLoss: 49500}
+
+		bot.checkStopHit(pos)
+		if pos.StopHit != tc.want {
+			t.Errorf("source %s: StopHit = %v, want %v", tc.source, pos.StopHit, tc.want)
+		}
+	}
+}
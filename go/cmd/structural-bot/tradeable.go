@@ -0,0 +1,60 @@
+package main
+
+import (
+	"log/slog"
+	"time"
+
+	"github.com/kasyap/delta-go/go/pkg/delta"
+	"github.com/kasyap/delta-go/go/pkg/logger"
+)
+
+// productStatusTTL is how long a cached product's trading status is trusted before the
+// product is fetched again
+const productStatusTTL = 5 * time.Minute
+
+// tradeableProducts drops symbols whose product can't take new orders: not live, halted,
+// or a dated contract within MinTimeToSettlement of settling (see delta.ProductTradeable).
+// Cached products older than productStatusTTL are re-fetched first; a failed fetch keeps
+// the cached one. A symbol is logged when it drops out and when it returns.
+func (bot *StructuralBot) tradeableProducts(symbols []string, now time.Time) []string {
+	for _, symbol := range symbols {
+		bot.mu.RLock()
+		checked, cached := bot.productCheckedAt[symbol], bot.productCache[symbol] != nil
+		bot.mu.RUnlock()
+		if !cached || now.Sub(checked) < productStatusTTL {
+			continue
+		}
+		product, err := bot.deltaClient.GetProductBySymbol(symbol)
+		if err != nil {
+			slog.Warn("Failed to refresh product status", logger.KeySymbol, symbol, "error", err)
+			continue
+		}
+		bot.mu.Lock()
+		bot.productCache[symbol] = product
+		bot.productCheckedAt[symbol] = now
+		bot.mu.Unlock()
+	}
+
+	bot.mu.Lock()
+	defer bot.mu.Unlock()
+	tradeable := make([]string, 0, len(symbols))
+	for _, symbol := range symbols {
+		product, ok := bot.productCache[symbol]
+		if !ok {
+			continue // Never loaded; the entry loop skips it too
+		}
+		if ok, reason := delta.ProductTradeable(product, now, bot.cfg.MinTimeToSettlement); !ok {
+			if _, was := bot.untradeableSymbols[symbol]; !was {
+				slog.Warn("Product not tradeable, excluded", logger.KeySymbol, symbol, "reason", reason)
+			}
+			bot.untradeableSymbols[symbol] = reason
+			continue
+		}
+		if _, was := bot.untradeableSymbols[symbol]; was {
+			slog.Info("Product tradeable again, trading resumed", logger.KeySymbol, symbol)
+			delete(bot.untradeableSymbols, symbol)
+		}
+		tradeable = append(tradeable, symbol)
+	}
+	return tradeable
+}
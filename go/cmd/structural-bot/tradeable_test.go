@@ -0,0 +1,100 @@
+package main
+
+import (
+	"net/http"
+	"reflect"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/kasyap/delta-go/go/pkg/delta"
+)
+
+func TestInitialize_CachesInactiveProductUntilItRecovers(t *testing.T) {
+	var nopeState atomic.Value
+	nopeState.Store("expired")
+	bot := newInitTestBot(t, func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case strings.HasSuffix(r.URL.Path, "/products/BTCUSD"):
+			w.Write([]byte(`{"success":true,"result":{"id":27,"symbol":"BTCUSD","state":"live","trading_status":"operational"}}`))
+		case strings.HasSuffix(r.URL.Path, "/products/NOPEUSD"):
+			w.Write([]byte(`{"success":true,"result":{"id":28,"symbol":"NOPEUSD","state":"` + nopeState.Load().(string) + `"}}`))
+		case strings.Contains(r.URL.Path, "/history/candles"):
+			w.Write([]byte(`{"success":true,"result":[]}`))
+		default:
+			w.Write([]byte(`{"success":true,"result":{}}`))
+		}
+	})
+
+	if err := bot.Initialize(); err != nil {
+		t.Fatalf("Initialize() error = %v", err)
+	}
+	if _, ok := bot.productCache["BTCUSD"]; !ok {
+		t.Error("live product not loaded")
+	}
+	if _, ok := bot.productCache["NOPEUSD"]; !ok {
+		t.Fatal("inactive product not cached for the status refresh")
+	}
+	if reason := bot.untradeableSymbols["NOPEUSD"]; reason != "product state is expired" {
+		t.Errorf("NOPEUSD untradeable reason = %q", reason)
+	}
+	if bot.currentProduct == nil || bot.currentProduct.Symbol != "BTCUSD" {
+		t.Errorf("current product = %+v, want the live BTCUSD", bot.currentProduct)
+	}
+
+	now := bot.now()
+	symbols := []string{"BTCUSD", "NOPEUSD"}
+	if got := bot.tradeableProducts(symbols, now); !reflect.DeepEqual(got, []string{"BTCUSD"}) {
+		t.Errorf("tradeable = %v, want [BTCUSD]", got)
+	}
+
+	// Once the status refresh sees it live again, it trades
+	nopeState.Store("live")
+	if got := bot.tradeableProducts(symbols, now.Add(productStatusTTL)); !reflect.DeepEqual(got, symbols) {
+		t.Errorf("tradeable after recovery = %v, want %v", got, symbols)
+	}
+}
+
+func TestTradeableProducts_ExcludesWithReason(t *testing.T) {
+	now := time.Date(2024, 6, 28, 11, 30, 0, 0, time.UTC)
+	refreshed := 0
+	bot := newInitTestBot(t, func(w http.ResponseWriter, r *http.Request) {
+		if !strings.HasSuffix(r.URL.Path, "/products/BTCUSD") {
+			t.Errorf("unexpected request %s %s", r.Method, r.URL.Path)
+			return
+		}
+		refreshed++
+		w.Write([]byte(`{"success":true,"result":{"id":27,"symbol":"BTCUSD","state":"live","trading_status":"disrupted_cancel_only"}}`))
+	})
+	bot.cfg.MinTimeToSettlement = time.Hour
+
+	// BTCUSD's cached status is stale and is re-fetched; NOPEUSD is a future settling at noon
+	bot.productCache["BTCUSD"] = &delta.Product{ID: 27, Symbol: "BTCUSD", State: "live"}
+	bot.productCheckedAt["BTCUSD"] = now.Add(-productStatusTTL)
+	bot.productCache["NOPEUSD"] = &delta.Product{ID: 28, Symbol: "NOPEUSD", State: "live", SettlementTime: "2024-06-28T12:00:00Z"}
+	bot.productCheckedAt["NOPEUSD"] = now
+
+	if got := bot.tradeableProducts([]string{"BTCUSD", "NOPEUSD"}, now); len(got) != 0 {
+		t.Errorf("tradeable = %v, want none", got)
+	}
+	if refreshed != 1 {
+		t.Errorf("product refreshes = %d, want 1 (only the stale BTCUSD)", refreshed)
+	}
+	want := map[string]string{
+		"BTCUSD":  "trading status is disrupted_cancel_only",
+		"NOPEUSD": "settles in 30m0s at 2024-06-28T12:00:00Z",
+	}
+	if !reflect.DeepEqual(bot.untradeableSymbols, want) {
+		t.Errorf("untradeable reasons = %v, want %v", bot.untradeableSymbols, want)
+	}
+
+	// Further from settlement the future is tradeable again
+	bot.cfg.MinTimeToSettlement = 10 * time.Minute
+	if got := bot.tradeableProducts([]string{"NOPEUSD"}, now); !reflect.DeepEqual(got, []string{"NOPEUSD"}) {
+		t.Errorf("tradeable = %v, want [NOPEUSD]", got)
+	}
+	if _, ok := bot.untradeableSymbols["NOPEUSD"]; ok {
+		t.Error("NOPEUSD still marked untradeable")
+	}
+}
@@ -0,0 +1,45 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"log/slog"
+	"testing"
+
+	"github.com/kasyap/delta-go/go/config"
+	"github.com/kasyap/delta-go/go/pkg/delta"
+	"github.com/kasyap/delta-go/go/pkg/strategy"
+)
+
+func TestExecuteScalpEntry_LogsTradeAttributes(t *testing.T) {
+	var buf bytes.Buffer
+	prev := slog.Default()
+	slog.SetDefault(slog.New(slog.NewJSONHandler(&buf, nil)))
+	defer slog.SetDefault(prev)
+
+	bot := NewStructuralBot(&config.Config{
+		Symbols:         []string{"BTCUSD"},
+		CandleInterval:  "5m",
+		ScalperEnabled:  true,
+		MinRewardRisk:   1.5,
+		APIRateLimitRPS: 8,
+	})
+	// 0.5:1 is rejected before any API call
+	signal := strategy.Signal{Action: strategy.ActionBuy, Side: "buy", Price: 100, StopLoss: 98, TakeProfit: 101}
+	bot.executeScalpEntry(signal, &delta.Product{ID: 27, Symbol: "BTCUSD"}, "BTCUSD")
+
+	var rec map[string]interface{}
+	if err := json.Unmarshal(buf.Bytes(), &rec); err != nil {
+		t.Fatalf("record %q is not JSON: %v", buf.String(), err)
+	}
+	want := map[string]interface{}{
+		"symbol":   "BTCUSD",
+		"strategy": "fee_aware_scalper",
+		"action":   string(strategy.ActionBuy),
+	}
+	for k, v := range want {
+		if rec[k] != v {
+			t.Errorf("%s = %v, want %v (record %s)", k, rec[k], v, buf.String())
+		}
+	}
+}
@@ -0,0 +1,72 @@
+package main
+
+import (
+	"sort"
+	"sync"
+	"time"
+)
+
+// DataWatchdog is a dead-man's switch for market data. It tracks the last update time
+// per channel (e.g. "ticker:BTCUSD") and reports the feed as stale once any tracked
+// channel has been silent for longer than the timeout.
+type DataWatchdog struct {
+	mu       sync.Mutex
+	timeout  time.Duration
+	lastSeen map[string]time.Time
+	stale    bool
+}
+
+// NewDataWatchdog creates a watchdog; a non-positive timeout disables it
+func NewDataWatchdog(timeout time.Duration) *DataWatchdog {
+	return &DataWatchdog{
+		timeout:  timeout,
+		lastSeen: make(map[string]time.Time),
+	}
+}
+
+// Touch records fresh data on a channel, registering it if new
+func (w *DataWatchdog) Touch(channel string, now time.Time) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.lastSeen[channel] = now
+}
+
+// Reset marks every tracked channel as fresh (used on connect/reconnect)
+func (w *DataWatchdog) Reset(now time.Time) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	for ch := range w.lastSeen {
+		w.lastSeen[ch] = now
+	}
+	w.stale = false
+}
+
+// Check evaluates staleness at now. It returns the stale channels (sorted) and whether
+// the stale state changed since the previous check.
+func (w *DataWatchdog) Check(now time.Time) (staleChannels []string, changed bool) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if w.timeout <= 0 {
+		return nil, false
+	}
+
+	for ch, seen := range w.lastSeen {
+		if now.Sub(seen) > w.timeout {
+			staleChannels = append(staleChannels, ch)
+		}
+	}
+	sort.Strings(staleChannels)
+
+	isStale := len(staleChannels) > 0
+	changed = isStale != w.stale
+	w.stale = isStale
+	return staleChannels, changed
+}
+
+// IsStale reports the state from the most recent Check
+func (w *DataWatchdog) IsStale() bool {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return w.stale
+}
@@ -0,0 +1,115 @@
+package main
+
+import (
+	"testing"
+	"time"
+
+	"github.com/kasyap/delta-go/go/config"
+	"github.com/kasyap/delta-go/go/pkg/delta"
+)
+
+func TestDataWatchdog_TripsAndRecovers(t *testing.T) {
+	w := NewDataWatchdog(60 * time.Second)
+	t0 := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	w.Touch("ticker:BTCUSD", t0)
+	w.Touch("candle:BTCUSD", t0)
+
+	if stale, changed := w.Check(t0.Add(59 * time.Second)); len(stale) != 0 || changed {
+		t.Fatalf("expected fresh before timeout, got stale=%v changed=%v", stale, changed)
+	}
+
+	// Ticker keeps flowing, candle feed goes silent
+	w.Touch("ticker:BTCUSD", t0.Add(50*time.Second))
+	stale, changed := w.Check(t0.Add(61 * time.Second))
+	if !changed || len(stale) != 1 || stale[0] != "candle:BTCUSD" {
+		t.Fatalf("expected candle channel to trip, got stale=%v changed=%v", stale, changed)
+	}
+	if !w.IsStale() {
+		t.Fatal("expected IsStale after trip")
+	}
+	if _, changed := w.Check(t0.Add(62 * time.Second)); changed {
+		t.Error("expected no transition while still stale")
+	}
+
+	// Fresh candle clears the condition
+	w.Touch("candle:BTCUSD", t0.Add(63*time.Second))
+	if stale, changed := w.Check(t0.Add(64 * time.Second)); len(stale) != 0 || !changed {
+		t.Fatalf("expected recovery, got stale=%v changed=%v", stale, changed)
+	}
+}
+
+func TestDataWatchdog_ResetOnReconnect(t *testing.T) {
+	w := NewDataWatchdog(30 * time.Second)
+	t0 := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	w.Touch("ticker:ETHUSD", t0)
+
+	w.Check(t0.Add(time.Minute))
+	if !w.IsStale() {
+		t.Fatal("expected stale after a minute of silence")
+	}
+
+	w.Reset(t0.Add(2 * time.Minute))
+	if w.IsStale() {
+		t.Fatal("expected reset to clear stale state")
+	}
+	if stale, _ := w.Check(t0.Add(2*time.Minute + 10*time.Second)); len(stale) != 0 {
+		t.Errorf("expected fresh after reset, got %v", stale)
+	}
+}
+
+func TestDataWatchdog_DisabledWithZeroTimeout(t *testing.T) {
+	w := NewDataWatchdog(0)
+	w.Touch("ticker:BTCUSD", time.Unix(0, 0))
+	if stale, changed := w.Check(time.Unix(0, 0).Add(24 * time.Hour)); stale != nil || changed {
+		t.Errorf("disabled watchdog reported stale=%v changed=%v", stale, changed)
+	}
+}
+
+func TestCheckStaleData_PausesEntries(t *testing.T) {
+	bot := NewStructuralBot(&config.Config{
+		Symbols:          []string{"BTCUSD"},
+		StaleDataTimeout: 10 * time.Second,
+		APIRateLimitRPS:  8,
+	})
+	t0 := time.Now()
+	bot.watchdog.Touch(tickerChannel("BTCUSD"), t0)
+
+	bot.checkStaleData(t0.Add(11 * time.Second))
+	if !bot.watchdog.IsStale() {
+		t.Fatal("expected bot to pause entries on stale data")
+	}
+
+	bot.handleWSReconnect()
+	if bot.watchdog.IsStale() {
+		t.Error("expected reconnect to reset the watchdog")
+	}
+}
+
+func TestCheckStaleData_FlattenRecordsClosesAndKeepsFailures(t *testing.T) {
+	x := newFakeExchange(delta.Product{ID: 27, Symbol: "BTCUSD", ContractValue: "0.001", TickSize: "0.5"})
+	x.setPosition(27, 10)
+	bot := newFakeExchangeBot(t, x, &config.Config{
+		Symbols:            []string{"BTCUSD"},
+		StaleDataTimeout:   10 * time.Second,
+		FlattenOnStaleData: true,
+	})
+	bot.scalpPositions["BTCUSD"] = &ScalpPosition{Symbol: "BTCUSD", Side: "buy", Size: 10, EntryPrice: 50000, OrderID: 1}
+	bot.lastTickers["BTCUSD"] = &delta.Ticker{Symbol: "BTCUSD", MarkPrice: 49800}
+	t0 := time.Now()
+	bot.watchdog.Touch(tickerChannel("BTCUSD"), t0)
+
+	x.setRejectOrders(true)
+	bot.checkStaleData(t0.Add(11 * time.Second))
+	if _, ok := bot.scalpPositions["BTCUSD"]; !ok || x.position(27) != 10 {
+		t.Fatalf("failed flatten: tracked = %v, position = %d; want the open position still tracked", ok, x.position(27))
+	}
+
+	x.setRejectOrders(false)
+	bot.flattenAll("retry")
+	if _, ok := bot.scalpPositions["BTCUSD"]; ok || x.position(27) != 0 {
+		t.Errorf("after flatten: tracked = %v, position = %d; want flat and forgotten", ok, x.position(27))
+	}
+	if n := consecutiveLosses(bot); n != 1 {
+		t.Errorf("consecutive losses = %d, want the losing flatten recorded", n)
+	}
+}
@@ -1,6 +1,7 @@
 package config
 
 import (
+	"fmt"
 	"os"
 	"strconv"
 	"strings"
@@ -16,6 +17,11 @@ type Config struct {
 	WebSocketURL    string
 	IsTestnet       bool
 	APIRateLimitRPS int
+	SubAccountID    string // Sent with every request when set
+
+	// Named accounts for running strategies on separate (sub-)accounts; see ForAccount
+	Accounts map[string]Account
+	Account  string // Account the bot trades on (empty = DELTA_API_KEY/DELTA_API_SECRET)
 
 	// Trading
 	Symbol         string   // Primary/single symbol (backward compatible)
@@ -24,35 +30,168 @@ type Config struct {
 	MaxPositionPct float64 // Max % of wallet to use per position
 	MultiAssetMode bool    // Enable multi-asset signal selection
 
+	// Minimum 24h notional volume (USD) for a symbol to be a multi-asset candidate (0 = off)
+	MinSymbolVolumeUSD float64
+
+	// Stop entering a dated contract this long before it settles
+	MinTimeToSettlement time.Duration
+
+	// Pyramiding
+	MaxPyramidEntries int     // Max add-on units per winning position (0 = disabled)
+	PyramidStepPct    float64 // Min favorable move (%) from the last unit before adding
+
 	// Strategy Selection
 	ScalperEnabled    bool // Enable fee-free scalper strategy
 	BasisTradeEnabled bool // Enable basis trade monitoring
+	BreakoutEnabled   bool // Enable high-vol breakout stop entries
 
 	// Scalper Settings
 	ScalpImbalanceThreshold float64
 	ScalpPersistenceCount   int
 	ScalpTargetBps          float64
 	ScalpMaxLossBps         float64
+	ScalpMaxSpreadPctile    float64                  // Skip entries while the spread ranks above this percentile of its recent range (0 = off)
+	ScalpFeeWindows         map[string]time.Duration // Per-symbol fee window overrides
+	ScalpBreakevenAfter     float64                  // Fraction of the fee window after which a profitable scalp's stop moves to entry (0 = off)
 
 	// Basis Trade Settings
 	BasisEntryThreshold float64 // Annualized basis % to enter
 	BasisExitThreshold  float64 // Annualized basis % to exit
 	BasisMaxLeverage    int
+	BasisPriceStopPct   float64 // Adverse price move % that closes a funding position (0 = off)
+	BasisPriceTargetPct float64 // Favorable price move % that closes a funding position (0 = off)
+
+	// Dated future hedging each perp's funding position, keyed by perp symbol (unlisted = unhedged)
+	BasisHedgeSymbols     map[string]string
+	BasisHedgeFillTimeout int // Seconds each hedge leg may take to fill before the entry is unwound
+
+	// Grid Trading
+	GridMaxInventory int // Stop placing same-side grid levels past this many net filled plus resting contracts per symbol (0 = unlimited)
 
 	// Risk Management
-	MaxDrawdownPct    float64
-	StopLossPct       float64
-	TakeProfitPct     float64
-	RiskPerTradePct   float64
-	DailyLossLimitPct float64
+	MaxDrawdownPct       float64
+	StopLossPct          float64
+	TakeProfitPct        float64
+	MinRewardRisk        float64 // Skip entries whose target/stop distance ratio is below this (0 = off)
+	RiskPerTradePct      float64
+	DailyLossLimitPct    float64
+	MinEquityFloor       float64       // Halt new entries while equity is below this many dollars (0 = off)
+	MaxConsecutiveLosses int           // Pause trading for the day after this many losing trades in a row (0 = off)
+	StopCooldown         time.Duration // Block new entries on a symbol this long after a stop-loss exit (0 = off)
+	AllowReversal        bool          // Flip straight into the opposite side on an opposite signal; false closes and waits
+	ReversalCooldown     time.Duration // With AllowReversal off, block new entries this long after a signal close
+	ExitPriceSource      string        // "mark" or "last": price stops and price exits are checked against
+	BracketRounding      string        // "conservative" (stop away from, target toward entry) or "nearest"
+
+	// Confidence sizing: scale size from ConfidenceSizeFloor of the budget at MinConfidence up to
+	// the full budget at confidence 1.0
+	ConfidenceSizing    string  // "off", "linear" or "quadratic"
+	MinConfidence       float64 // Confidence at which the floor applies
+	ConfidenceSizeFloor float64 // Fraction of the budget used at or below MinConfidence
+
+	// Loss streak sizing: each consecutive loss scales risk per trade by LossStreakFactor
+	// (anti-martingale) or by 1/LossStreakFactor (martingale), bounded by LossStreakFloor
+	// or 1/LossStreakFloor; a win restores full size
+	LossStreakSizing string  // "off", "anti-martingale" or "martingale"
+	LossStreakFactor float64 // Per-loss multiplier, in (0, 1)
+	LossStreakFloor  float64 // Smallest anti-martingale multiplier; its inverse caps martingale
+
+	// Daily profit lock: once daily P&L reaches DailyProfitTargetPct, stop new entries or scale
+	// size by ProfitLockSizeMult for the rest of the day
+	DailyProfitTargetPct float64 // Daily P&L % that engages the lock (0 = off)
+	ProfitLockMode       string  // "stop" or "reduce"
+	ProfitLockSizeMult   float64 // Fraction of the risk budget used in reduce mode
+
+	// Dynamic leverage: starting from Leverage at the equity peak, step down by
+	// LeverageStepPerBand for every LeverageDrawdownBandPct of drawdown, never below MinLeverage
+	LeverageDrawdownBandPct float64 // Band width in % drawdown (0 = fixed leverage)
+	LeverageStepPerBand     int
+	MinLeverage             int
+
+	// Signal confidence gate: entries below the floor are skipped
+	SignalConfidenceFloor       float64            // Default floor for every strategy
+	SignalConfidenceHighVolBump float64            // Added to the floor in the high volatility regime
+	StrategyConfidenceFloors    map[string]float64 // Per-strategy overrides, e.g. "grid_trading": 0.75
+
+	// Regime alignment policy, keyed by regime name (unlisted regimes allow both sides at the
+	// floor above)
+	RegimeAllowedSides     map[string]string  // "buy", "sell", "both" or "none", e.g. "bear": "sell"
+	RegimeConfidenceFloors map[string]float64 // Minimum floor in a regime, e.g. "bear": 0.7
+	FlattenOnRegimeChange  bool               // Close positions that conflict with a newly confirmed regime
+	RegimeConfirmations    int                // Consecutive detections before a regime change is confirmed
+
+	// Execution
+	MaxSlippageBps           float64 // Max tolerated adverse fill slippage vs intended price (0 = unchecked)
+	AbortOnExcessiveSlippage bool    // Immediately flatten a fill that exceeds MaxSlippageBps
+
+	// Cancel unfilled limit orders resting longer than this, keyed by strategy
+	// (scalp, pyramid, funding, grid); unlisted strategies stay good-til-cancelled
+	OrderMaxAge map[string]time.Duration
+
+	// Cap order placement attempts per strategy over a rolling minute (0 = off)
+	MaxOrdersPerMinute int
+
+	// Market data watchdog
+	StaleDataTimeout   time.Duration // Pause entries when a ticker/candle feed is silent this long (0 = off)
+	FlattenOnStaleData bool          // Also close open positions when market data goes stale
+
+	// WebSocket reconnection: jittered exponential backoff from WSReconnectBackoff up to
+	// WSReconnectMaxBackoff; after MaxReconnectAttempts failures in a row the bot alerts and stops
+	WSReconnectBackoff    time.Duration
+	WSReconnectMaxBackoff time.Duration
+	MaxReconnectAttempts  int // 0 = retry forever
+
+	// Entry freeze around funding times and scheduled events, when spreads blow out
+	EventFreezeWindow    time.Duration // Block new entries this long either side of each (0 = off)
+	EventFreezeFunding   bool          // Freeze around the 00:00/08:00/16:00 UTC funding times
+	EventFreezeTimes     []time.Time   // Scheduled high-impact events, parsed from EventFreezeSpec at startup
+	EventFreezeSpec      string        // Raw EVENT_FREEZE_TIMES (comma-separated RFC3339)
+	FlattenOnEventFreeze bool          // Close open positions as a freeze starts instead of holding them
+
+	// Alerts
+	AlertWebhookURL  string // POST JSON alerts here (empty = off)
+	TelegramBotToken string
+	TelegramChatID   string
+
+	// Runtime control HTTP server, e.g. ":8090" (empty = off)
+	StatusAddr string
+
+	// Shadow mode: evaluate a candidate strategy config on the live market data and track its
+	// simulated fills without placing orders. Candidate overrides of 0 keep the live value.
+	ShadowEnabled                 bool
+	ShadowScalpImbalanceThreshold float64
+	ShadowScalpTargetBps          float64
+	ShadowScalpMaxLossBps         float64
+	ShadowBasisEntryThreshold     float64
+	ShadowBasisExitThreshold      float64
+	ShadowFeeBps                  float64 // Fee charged per simulated fill
 
 	// Intervals
-	CandleInterval    string        // "1m", "5m", "15m", etc.
-	RegimeCheckPeriod time.Duration // How often to check market regime
+	CandleInterval       string        // "1m", "5m", "15m", etc.
+	RegimeCandleInterval string        // Higher timeframe used for regime detection
+	RegimeCheckPeriod    time.Duration // How often to check market regime
+	WaitForCandleClose   bool          // Only act once per closed bar, ignoring the forming one
+	CandleHistoryBars    int           // Bars of CandleInterval history loaded at startup
+	CandlePageLimit      int           // Max candles Delta returns per history request
+
+	// HMM regime service (empty endpoint = no regime detection)
+	HMMEndpoint     string
+	HMMAuthMode     string // "gce", "bearer", "apikey" or "none"
+	HMMAuthToken    string // Bearer token or API key for the bearer/apikey modes
+	HMMAPIKeyHeader string // Header used in apikey mode
 
 	// Logging
-	LogPath  string
-	LogLevel string
+	LogPath      string
+	LogLevel     string
+	PerfLogPath  string // JSONL equity snapshot log, persisted across restarts (empty = off)
+	PerfLogMaxMB int    // Rotate the perf log once it exceeds this size
+}
+
+// Account holds the credentials of one Delta (sub-)account
+type Account struct {
+	APIKey       string
+	APISecret    string
+	SubAccountID string
 }
 
 // LoadConfig loads configuration from environment variables
@@ -62,41 +201,153 @@ func LoadConfig() *Config {
 		APISecret:       getEnv("DELTA_API_SECRET", ""),
 		IsTestnet:       getEnvBool("DELTA_TESTNET", true),
 		APIRateLimitRPS: getEnvInt("DELTA_API_RATE_LIMIT_RPS", 8),
+		SubAccountID:    getEnv("DELTA_SUBACCOUNT_ID", ""),
+		Accounts:        loadAccounts(getEnv("DELTA_ACCOUNTS", "")),
+		Account:         getEnv("DELTA_ACCOUNT", ""),
 		Symbol:          getEnv("DELTA_SYMBOL", "BTCUSD"),
 		Symbols:         parseSymbols(getEnv("DELTA_SYMBOLS", "BTCUSD,ETHUSD,SOLUSD")),
 		Leverage:        getEnvInt("DELTA_LEVERAGE", 10),
 		MaxPositionPct:  getEnvFloat("DELTA_MAX_POSITION_PCT", 10.0),
 		MultiAssetMode:  getEnvBool("MULTI_ASSET_MODE", true),
 
+		MinSymbolVolumeUSD: getEnvFloat("MIN_SYMBOL_VOLUME_USD", 0),
+
+		MinTimeToSettlement: time.Duration(getEnvInt("MIN_TIME_TO_SETTLEMENT_MINUTES", 60)) * time.Minute,
+
+		// Pyramiding
+		MaxPyramidEntries: getEnvInt("MAX_PYRAMID_ENTRIES", 0),
+		PyramidStepPct:    getEnvFloat("PYRAMID_STEP_PCT", 0.5),
+
 		// Strategy settings
 		ScalperEnabled:    getEnvBool("SCALPER_ENABLED", true),
 		BasisTradeEnabled: getEnvBool("BASIS_TRADE_ENABLED", false), // Disabled by default - requires spot hedge for profitability
+		BreakoutEnabled:   getEnvBool("BREAKOUT_ENABLED", false),
 
 		// Scalper settings
 		ScalpImbalanceThreshold: getEnvFloat("SCALP_IMBALANCE_THRESHOLD", 0.5),
 		ScalpPersistenceCount:   getEnvInt("SCALP_PERSISTENCE_COUNT", 5),
-		ScalpTargetBps:          getEnvFloat("SCALP_TARGET_BPS", 20.0),
+		ScalpTargetBps:          getEnvFloat("SCALP_TARGET_BPS", 30.0),
 		ScalpMaxLossBps:         getEnvFloat("SCALP_MAX_LOSS_BPS", 15.0),
+		ScalpMaxSpreadPctile:    getEnvFloat("SCALP_MAX_SPREAD_PERCENTILE", 90),
+		ScalpFeeWindows:         parseDurationMap(getEnv("SCALP_FEE_WINDOWS", "")),
+		ScalpBreakevenAfter:     getEnvFloat("SCALP_BREAKEVEN_AFTER", 0),
 
 		// Basis trade settings
 		BasisEntryThreshold: getEnvFloat("BASIS_ENTRY_THRESHOLD", 0.15),
 		BasisExitThreshold:  getEnvFloat("BASIS_EXIT_THRESHOLD", 0.05),
 		BasisMaxLeverage:    getEnvInt("BASIS_MAX_LEVERAGE", 3),
+		BasisPriceStopPct:   getEnvFloat("BASIS_PRICE_STOP_PCT", 0),
+		BasisPriceTargetPct: getEnvFloat("BASIS_PRICE_TARGET_PCT", 0),
+
+		BasisHedgeSymbols:     parseStringMap(getEnv("BASIS_HEDGE_SYMBOLS", "")),
+		BasisHedgeFillTimeout: getEnvInt("BASIS_HEDGE_FILL_TIMEOUT_SECONDS", 10),
+
+		// Grid Trading
+		GridMaxInventory: getEnvInt("GRID_MAX_INVENTORY", 0),
 
 		// Risk defaults
-		MaxDrawdownPct:    getEnvFloat("MAX_DRAWDOWN_PCT", 10.0),
-		StopLossPct:       getEnvFloat("STOP_LOSS_PCT", 2.0),
-		TakeProfitPct:     getEnvFloat("TAKE_PROFIT_PCT", 4.0),
-		RiskPerTradePct:   getEnvFloat("RISK_PER_TRADE_PCT", 1.0),
-		DailyLossLimitPct: getEnvFloat("DAILY_LOSS_LIMIT_PCT", -5.0),
+		MaxDrawdownPct:       getEnvFloat("MAX_DRAWDOWN_PCT", 10.0),
+		StopLossPct:          getEnvFloat("STOP_LOSS_PCT", 2.0),
+		TakeProfitPct:        getEnvFloat("TAKE_PROFIT_PCT", 4.0),
+		MinRewardRisk:        getEnvFloat("MIN_REWARD_RISK", 1.5),
+		RiskPerTradePct:      getEnvFloat("RISK_PER_TRADE_PCT", 1.0),
+		DailyLossLimitPct:    getEnvFloat("DAILY_LOSS_LIMIT_PCT", -5.0),
+		MinEquityFloor:       getEnvFloat("MIN_EQUITY_FLOOR", 0),
+		MaxConsecutiveLosses: getEnvInt("MAX_CONSECUTIVE_LOSSES", 0),
+		StopCooldown:         time.Duration(getEnvInt("STOP_COOLDOWN_MINUTES", 0)) * time.Minute,
+		AllowReversal:        getEnvBool("ALLOW_REVERSAL", true),
+		ReversalCooldown:     time.Duration(getEnvInt("REVERSAL_COOLDOWN_MINUTES", 0)) * time.Minute,
+		ExitPriceSource:      getEnv("EXIT_PRICE_SOURCE", "mark"),
+		BracketRounding:      getEnv("BRACKET_ROUNDING", "conservative"),
+
+		// Confidence sizing
+		ConfidenceSizing:    getEnv("CONFIDENCE_SIZING", "off"),
+		MinConfidence:       getEnvFloat("MIN_CONFIDENCE", 0.5),
+		ConfidenceSizeFloor: getEnvFloat("CONFIDENCE_SIZE_FLOOR", 0.25),
+
+		// Loss streak sizing
+		LossStreakSizing: getEnv("LOSS_STREAK_SIZING", "off"),
+		LossStreakFactor: getEnvFloat("LOSS_STREAK_FACTOR", 0.75),
+		LossStreakFloor:  getEnvFloat("LOSS_STREAK_FLOOR", 0.25),
+
+		// Daily profit lock
+		DailyProfitTargetPct: getEnvFloat("DAILY_PROFIT_TARGET_PCT", 0),
+		ProfitLockMode:       getEnv("PROFIT_LOCK_MODE", "stop"),
+		ProfitLockSizeMult:   getEnvFloat("PROFIT_LOCK_SIZE_MULT", 0.5),
+
+		// Dynamic leverage
+		LeverageDrawdownBandPct: getEnvFloat("LEVERAGE_DRAWDOWN_BAND_PCT", 0),
+		LeverageStepPerBand:     getEnvInt("LEVERAGE_STEP_PER_BAND", 2),
+		MinLeverage:             getEnvInt("MIN_LEVERAGE", 1),
+
+		// Signal confidence gate
+		SignalConfidenceFloor:       getEnvFloat("SIGNAL_CONFIDENCE_FLOOR", 0.5),
+		SignalConfidenceHighVolBump: getEnvFloat("SIGNAL_CONFIDENCE_HIGH_VOL_BUMP", 0.1),
+		StrategyConfidenceFloors:    parseFloatMap(getEnv("STRATEGY_CONFIDENCE_FLOORS", "")),
+
+		// Regime alignment policy
+		RegimeAllowedSides:     parseRegimeSides(getEnv("REGIME_ALLOWED_SIDES", "")),
+		RegimeConfidenceFloors: parseFloatMap(getEnv("REGIME_CONFIDENCE_FLOORS", "")),
+		FlattenOnRegimeChange:  getEnvBool("FLATTEN_ON_REGIME_CHANGE", false),
+		RegimeConfirmations:    getEnvInt("REGIME_CONFIRMATIONS", 3),
+
+		// Execution
+		MaxSlippageBps:           getEnvFloat("MAX_SLIPPAGE_BPS", 50.0),
+		AbortOnExcessiveSlippage: getEnvBool("ABORT_ON_EXCESSIVE_SLIPPAGE", false),
+		OrderMaxAge:              parseDurationMap(getEnv("ORDER_MAX_AGE", "scalp=1m,pyramid=1m,breakout=5m")),
+		MaxOrdersPerMinute:       getEnvInt("MAX_ORDERS_PER_MINUTE", 30),
+
+		// Market data watchdog
+		StaleDataTimeout:   time.Duration(getEnvInt("STALE_DATA_TIMEOUT_SECONDS", 60)) * time.Second,
+		FlattenOnStaleData: getEnvBool("FLATTEN_ON_STALE_DATA", false),
+
+		// WebSocket reconnection
+		WSReconnectBackoff:    time.Duration(getEnvInt("WS_RECONNECT_BACKOFF_SECONDS", 1)) * time.Second,
+		WSReconnectMaxBackoff: time.Duration(getEnvInt("WS_RECONNECT_MAX_BACKOFF_SECONDS", 30)) * time.Second,
+		MaxReconnectAttempts:  getEnvInt("WS_MAX_RECONNECT_ATTEMPTS", 0),
+
+		// Event freeze
+		EventFreezeWindow:    time.Duration(getEnvInt("EVENT_FREEZE_MINUTES", 0)) * time.Minute,
+		EventFreezeFunding:   getEnvBool("EVENT_FREEZE_FUNDING", true),
+		EventFreezeSpec:      getEnv("EVENT_FREEZE_TIMES", ""),
+		FlattenOnEventFreeze: getEnvBool("FLATTEN_ON_EVENT_FREEZE", false),
+
+		// Alerts
+		AlertWebhookURL:  getEnv("ALERT_WEBHOOK_URL", ""),
+		TelegramBotToken: getEnv("TELEGRAM_BOT_TOKEN", ""),
+		TelegramChatID:   getEnv("TELEGRAM_CHAT_ID", ""),
+
+		// Runtime control
+		StatusAddr: getEnv("STATUS_ADDR", ""),
+
+		// Shadow mode
+		ShadowEnabled:                 getEnvBool("SHADOW_ENABLED", false),
+		ShadowScalpImbalanceThreshold: getEnvFloat("SHADOW_SCALP_IMBALANCE_THRESHOLD", 0),
+		ShadowScalpTargetBps:          getEnvFloat("SHADOW_SCALP_TARGET_BPS", 0),
+		ShadowScalpMaxLossBps:         getEnvFloat("SHADOW_SCALP_MAX_LOSS_BPS", 0),
+		ShadowBasisEntryThreshold:     getEnvFloat("SHADOW_BASIS_ENTRY_THRESHOLD", 0),
+		ShadowBasisExitThreshold:      getEnvFloat("SHADOW_BASIS_EXIT_THRESHOLD", 0),
+		ShadowFeeBps:                  getEnvFloat("SHADOW_FEE_BPS", 5),
 
 		// Intervals
-		CandleInterval:    getEnv("CANDLE_INTERVAL", "5m"),
-		RegimeCheckPeriod: time.Duration(getEnvInt("REGIME_CHECK_SECONDS", 300)) * time.Second,
+		CandleInterval:       getEnv("CANDLE_INTERVAL", "5m"),
+		RegimeCandleInterval: getEnv("REGIME_CANDLE_INTERVAL", "1h"),
+		RegimeCheckPeriod:    time.Duration(getEnvInt("REGIME_CHECK_SECONDS", 300)) * time.Second,
+		WaitForCandleClose:   getEnvBool("WAIT_FOR_CANDLE_CLOSE", false),
+		CandleHistoryBars:    getEnvInt("CANDLE_HISTORY_BARS", 200),
+		CandlePageLimit:      getEnvInt("CANDLE_PAGE_LIMIT", 2000),
+
+		// HMM regime service
+		HMMEndpoint:     getEnv("HMM_ENDPOINT", ""),
+		HMMAuthMode:     getEnv("HMM_AUTH_MODE", "gce"),
+		HMMAuthToken:    getEnv("HMM_AUTH_TOKEN", ""),
+		HMMAPIKeyHeader: getEnv("HMM_API_KEY_HEADER", "X-API-Key"),
 
 		// Logging
-		LogPath:  getEnv("LOG_PATH", "bot.log"),
-		LogLevel: getEnv("LOG_LEVEL", "INFO"),
+		LogPath:      getEnv("LOG_PATH", "bot.log"),
+		LogLevel:     getEnv("LOG_LEVEL", "INFO"),
+		PerfLogPath:  getEnv("PERF_LOG_PATH", ""),
+		PerfLogMaxMB: getEnvInt("PERF_LOG_MAX_MB", 50),
 	}
 
 	// Set URLs based on testnet flag
@@ -112,6 +363,38 @@ func LoadConfig() *Config {
 	return cfg
 }
 
+// ForAccount returns a copy of the config using the named account's credentials
+func (c *Config) ForAccount(name string) (*Config, error) {
+	acct, ok := c.Accounts[name]
+	if !ok {
+		return nil, fmt.Errorf("unknown account %q", name)
+	}
+	if acct.APIKey == "" || acct.APISecret == "" {
+		return nil, fmt.Errorf("account %q is missing an API key or secret", name)
+	}
+	out := *c
+	out.APIKey = acct.APIKey
+	out.APISecret = acct.APISecret
+	out.SubAccountID = acct.SubAccountID
+	out.Account = name
+	return &out, nil
+}
+
+// loadAccounts reads DELTA_<NAME>_API_KEY, DELTA_<NAME>_API_SECRET and
+// DELTA_<NAME>_SUBACCOUNT_ID for each comma-separated account name
+func loadAccounts(names string) map[string]Account {
+	accounts := make(map[string]Account)
+	for _, name := range parseSymbols(names) {
+		prefix := "DELTA_" + strings.ToUpper(name) + "_"
+		accounts[name] = Account{
+			APIKey:       getEnv(prefix+"API_KEY", ""),
+			APISecret:    getEnv(prefix+"API_SECRET", ""),
+			SubAccountID: getEnv(prefix+"SUBACCOUNT_ID", ""),
+		}
+	}
+	return accounts
+}
+
 func getEnv(key, defaultVal string) string {
 	if val := os.Getenv(key); val != "" {
 		return val
@@ -146,6 +429,67 @@ func getEnvBool(key string, defaultVal bool) bool {
 	return defaultVal
 }
 
+// parseDurationMap parses "SYM=dur,SYM=dur" (e.g. "SOLUSD=10m,ETHUSD=20m"), skipping invalid entries
+func parseDurationMap(s string) map[string]time.Duration {
+	result := make(map[string]time.Duration)
+	for _, pair := range strings.Split(s, ",") {
+		key, val, ok := strings.Cut(strings.TrimSpace(pair), "=")
+		if !ok {
+			continue
+		}
+		d, err := time.ParseDuration(strings.TrimSpace(val))
+		if err != nil || d <= 0 {
+			continue
+		}
+		result[strings.TrimSpace(key)] = d
+	}
+	return result
+}
+
+// parseFloatMap parses "key=val,key=val" (e.g. "grid_trading=0.75"), skipping invalid entries
+func parseFloatMap(s string) map[string]float64 {
+	result := make(map[string]float64)
+	for _, pair := range strings.Split(s, ",") {
+		key, val, ok := strings.Cut(strings.TrimSpace(pair), "=")
+		if !ok {
+			continue
+		}
+		f, err := strconv.ParseFloat(strings.TrimSpace(val), 64)
+		if err != nil {
+			continue
+		}
+		result[strings.TrimSpace(key)] = f
+	}
+	return result
+}
+
+// parseStringMap parses "key=val,key=val" (e.g. "BTCUSD=BTCUSD_270625"), skipping invalid entries
+func parseStringMap(s string) map[string]string {
+	result := make(map[string]string)
+	for _, pair := range strings.Split(s, ",") {
+		key, val, ok := strings.Cut(strings.TrimSpace(pair), "=")
+		key, val = strings.TrimSpace(key), strings.TrimSpace(val)
+		if !ok || key == "" || val == "" {
+			continue
+		}
+		result[key] = val
+	}
+	return result
+}
+
+// parseRegimeSides parses "regime=sides,..." (e.g. "bear=sell,high_volatility=none"),
+// skipping entries whose sides are not buy, sell, both or none
+func parseRegimeSides(s string) map[string]string {
+	result := make(map[string]string)
+	for regime, sides := range parseStringMap(s) {
+		switch sides = strings.ToLower(sides); sides {
+		case "buy", "sell", "both", "none":
+			result[regime] = sides
+		}
+	}
+	return result
+}
+
 // parseSymbols splits comma-separated symbols into a slice
 func parseSymbols(s string) []string {
 	symbols := []string{}
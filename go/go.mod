@@ -3,6 +3,6 @@ module github.com/kasyap/delta-go/go
 go 1.22.0
 
 require (
-	github.com/gorilla/websocket v1.5.3 // indirect
-	gopkg.in/natefinch/lumberjack.v2 v2.2.1 // indirect
+	github.com/gorilla/websocket v1.5.3
+	gopkg.in/natefinch/lumberjack.v2 v2.2.1
 )
@@ -0,0 +1,199 @@
+// Package alert delivers push notifications (webhook, Telegram) for events that need
+// a human: circuit-breaker trips, order rejections, a feed that will not reconnect.
+package alert
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/kasyap/delta-go/go/config"
+)
+
+// Alert levels, matching the console log levels
+const (
+	LevelInfo  = "INFO"
+	LevelWarn  = "WARN"
+	LevelError = "ERROR"
+)
+
+// defaultQueueSize bounds pending alerts; further alerts are dropped until the queue drains
+const defaultQueueSize = 64
+
+// Alerter sends a notification. Implementations must not block the caller.
+type Alerter interface {
+	Alert(level, message string)
+}
+
+// Nop discards all alerts
+type Nop struct{}
+
+// Alert implements Alerter
+func (Nop) Alert(level, message string) {}
+
+// Multi fans an alert out to several alerters
+type Multi []Alerter
+
+// Alert implements Alerter
+func (m Multi) Alert(level, message string) {
+	for _, a := range m {
+		a.Alert(level, message)
+	}
+}
+
+// Close implements Close for each alerter
+func (m Multi) Close() {
+	for _, a := range m {
+		Close(a)
+	}
+}
+
+// Close stops a and waits for its queued alerts to be delivered, if it queues them
+func Close(a Alerter) {
+	if c, ok := a.(interface{ Close() }); ok {
+		c.Close()
+	}
+}
+
+// New builds the alerter from config: webhook and/or Telegram, or Nop if neither is set
+func New(cfg *config.Config) Alerter {
+	var alerters Multi
+	if cfg.AlertWebhookURL != "" {
+		alerters = append(alerters, NewWebhookAlerter(cfg.AlertWebhookURL))
+	}
+	if cfg.TelegramBotToken != "" && cfg.TelegramChatID != "" {
+		alerters = append(alerters, NewTelegramAlerter(cfg.TelegramBotToken, cfg.TelegramChatID))
+	}
+	switch len(alerters) {
+	case 0:
+		return Nop{}
+	case 1:
+		return alerters[0]
+	}
+	return alerters
+}
+
+type message struct {
+	level string
+	text  string
+	ts    time.Time
+}
+
+// dispatcher delivers messages on a single background goroutine from a bounded queue
+type dispatcher struct {
+	name   string
+	send   func(message) error
+	queue  chan message
+	done   chan struct{}
+	mu     sync.Mutex
+	closed bool
+}
+
+func newDispatcher(name string, send func(message) error) *dispatcher {
+	d := &dispatcher{
+		name:  name,
+		send:  send,
+		queue: make(chan message, defaultQueueSize),
+		done:  make(chan struct{}),
+	}
+	go d.run()
+	return d
+}
+
+func (d *dispatcher) run() {
+	defer close(d.done)
+	for msg := range d.queue {
+		if err := d.send(msg); err != nil {
+			log.Printf("Failed to send %s alert: %v", d.name, err)
+		}
+	}
+}
+
+// Alert queues a message, dropping it if the queue is full or the alerter is closed
+func (d *dispatcher) Alert(level, text string) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if d.closed {
+		return
+	}
+	select {
+	case d.queue <- message{level: level, text: text, ts: time.Now()}:
+	default:
+		log.Printf("%s alert queue full, dropping: [%s] %s", d.name, level, text)
+	}
+}
+
+// Close stops accepting alerts and waits for queued ones to be delivered
+func (d *dispatcher) Close() {
+	d.mu.Lock()
+	if !d.closed {
+		d.closed = true
+		close(d.queue)
+	}
+	d.mu.Unlock()
+	<-d.done
+}
+
+func postJSON(client *http.Client, url string, body interface{}) error {
+	data, err := json.Marshal(body)
+	if err != nil {
+		return err
+	}
+	resp, err := client.Post(url, "application/json", bytes.NewReader(data))
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("status %d", resp.StatusCode)
+	}
+	return nil
+}
+
+// WebhookPayload is the JSON body POSTed by WebhookAlerter
+type WebhookPayload struct {
+	Level     string    `json:"level"`
+	Message   string    `json:"message"`
+	Timestamp time.Time `json:"timestamp"`
+}
+
+// WebhookAlerter POSTs each alert as JSON to a URL (Slack/Discord-style relays, custom endpoints)
+type WebhookAlerter struct {
+	*dispatcher
+}
+
+// NewWebhookAlerter creates a webhook alerter
+func NewWebhookAlerter(url string) *WebhookAlerter {
+	client := &http.Client{Timeout: 10 * time.Second}
+	return &WebhookAlerter{newDispatcher("webhook", func(m message) error {
+		return postJSON(client, url, WebhookPayload{Level: m.level, Message: m.text, Timestamp: m.ts})
+	})}
+}
+
+// telegramAPIURL is the Telegram Bot API base
+const telegramAPIURL = "https://api.telegram.org"
+
+// TelegramAlerter sends alerts to a chat via the Telegram Bot API
+type TelegramAlerter struct {
+	*dispatcher
+}
+
+// NewTelegramAlerter creates a Telegram alerter for the given bot token and chat
+func NewTelegramAlerter(token, chatID string) *TelegramAlerter {
+	return newTelegramAlerter(telegramAPIURL, token, chatID)
+}
+
+func newTelegramAlerter(baseURL, token, chatID string) *TelegramAlerter {
+	client := &http.Client{Timeout: 10 * time.Second}
+	url := fmt.Sprintf("%s/bot%s/sendMessage", baseURL, token)
+	return &TelegramAlerter{newDispatcher("telegram", func(m message) error {
+		return postJSON(client, url, map[string]string{
+			"chat_id": chatID,
+			"text":    fmt.Sprintf("[%s] %s", m.level, m.text),
+		})
+	})}
+}
@@ -0,0 +1,118 @@
+package alert
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/kasyap/delta-go/go/config"
+)
+
+func TestWebhookAlerter_PostsJSON(t *testing.T) {
+	received := make(chan WebhookPayload, 1)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			t.Errorf("method = %s, want POST", r.Method)
+		}
+		if ct := r.Header.Get("Content-Type"); ct != "application/json" {
+			t.Errorf("content-type = %q", ct)
+		}
+		var p WebhookPayload
+		if err := json.NewDecoder(r.Body).Decode(&p); err != nil {
+			t.Errorf("decode: %v", err)
+		}
+		received <- p
+	}))
+	defer srv.Close()
+
+	a := NewWebhookAlerter(srv.URL)
+	defer a.Close()
+
+	start := time.Now()
+	a.Alert(LevelError, "circuit breaker triggered")
+
+	select {
+	case p := <-received:
+		if p.Level != LevelError || p.Message != "circuit breaker triggered" {
+			t.Errorf("payload = %+v", p)
+		}
+		if p.Timestamp.Before(start.Add(-time.Second)) {
+			t.Errorf("unexpected timestamp %v", p.Timestamp)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("webhook was not called")
+	}
+}
+
+func TestTelegramAlerter_PostsSendMessage(t *testing.T) {
+	received := make(chan map[string]string, 1)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/botTOKEN/sendMessage" {
+			t.Errorf("path = %s", r.URL.Path)
+		}
+		var body map[string]string
+		json.NewDecoder(r.Body).Decode(&body)
+		received <- body
+	}))
+	defer srv.Close()
+
+	a := newTelegramAlerter(srv.URL, "TOKEN", "42")
+	a.Alert(LevelWarn, "order rejected")
+	a.Close()
+
+	body := <-received
+	if body["chat_id"] != "42" || body["text"] != "[WARN] order rejected" {
+		t.Errorf("body = %v", body)
+	}
+}
+
+func TestDispatcher_DoesNotBlockWhenQueueFull(t *testing.T) {
+	release := make(chan struct{})
+	d := newDispatcher("test", func(message) error {
+		<-release
+		return nil
+	})
+
+	done := make(chan struct{})
+	go func() {
+		for i := 0; i < defaultQueueSize*2; i++ {
+			d.Alert(LevelInfo, "spam")
+		}
+		close(done)
+	}()
+
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("Alert blocked on a full queue")
+	}
+	close(release)
+	d.Close()
+	d.Alert(LevelInfo, "after close") // must not panic
+}
+
+func TestNew_NopWhenUnconfigured(t *testing.T) {
+	if _, ok := New(&config.Config{}).(Nop); !ok {
+		t.Error("expected Nop alerter with no webhook or Telegram config")
+	}
+}
+
+func TestClose_FlushesEveryAlerter(t *testing.T) {
+	received := make(chan string, 2)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		time.Sleep(20 * time.Millisecond)
+		received <- r.URL.Path
+	}))
+	defer srv.Close()
+
+	a := Multi{NewWebhookAlerter(srv.URL + "/a"), Nop{}, NewWebhookAlerter(srv.URL + "/b")}
+	a.Alert(LevelError, "stopping")
+	Close(a)
+
+	if len(received) != 2 {
+		t.Errorf("delivered %d alerts before Close returned, want 2", len(received))
+	}
+	Close(Nop{}) // must not panic
+}
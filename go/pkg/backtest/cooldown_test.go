@@ -0,0 +1,53 @@
+package backtest
+
+import (
+	"testing"
+	"time"
+
+	"github.com/kasyap/delta-go/go/pkg/delta"
+	"github.com/kasyap/delta-go/go/pkg/strategy"
+)
+
+func TestEngine_StopCooldownBlocksReentry(t *testing.T) {
+	base := time.Date(2024, 1, 1, 1, 0, 0, 0, time.UTC).Unix()
+	candles := []delta.Candle{
+		{Time: base, Open: 50000, High: 50050, Low: 49950, Close: 50000},
+		{Time: base + 300, Open: 50000, High: 50050, Low: 49500, Close: 49600}, // Stopped out
+	}
+	for i := 2; i < 8; i++ {
+		candles = append(candles, delta.Candle{Time: base + int64(i*300), Open: 49600, High: 49700, Low: 49550, Close: 49600})
+	}
+	// Buy with a stop on bar 0, then a fresh buy signal on every later bar
+	signals := map[int]strategy.Signal{
+		0: {Action: strategy.ActionBuy, Side: "buy", StopLoss: 49700},
+	}
+	for i := 1; i < 7; i++ {
+		signals[i] = strategy.Signal{Action: strategy.ActionBuy, Side: "buy"}
+	}
+
+	tests := []struct {
+		name      string
+		cooldown  time.Duration
+		reentryAt int64
+	}{
+		{"no cooldown re-enters next bar", 0, base + 600},
+		{"15m cooldown waits three bars", 15 * time.Minute, base + 1200},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e := newTestEngine(candles, signals)
+			e.config.StopCooldown = tt.cooldown
+
+			res, err := e.runLoaded()
+			if err != nil {
+				t.Fatalf("run: %v", err)
+			}
+			if len(res.Trades) != 2 || res.Trades[0].Reason != "stop_loss" {
+				t.Fatalf("expected a stop-out then one re-entry, got %+v", res.Trades)
+			}
+			if got := res.Trades[1].EntryTime.Unix(); got != tt.reentryAt {
+				t.Errorf("re-entered at %d, want %d", got, tt.reentryAt)
+			}
+		})
+	}
+}
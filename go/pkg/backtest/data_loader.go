@@ -1,6 +1,7 @@
 package backtest
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"net/http"
@@ -12,22 +13,154 @@ import (
 	"github.com/kasyap/delta-go/go/pkg/delta"
 )
 
+const binanceKlinesURL = "https://fapi.binance.com/fapi/v1/klines"
+
 // DataLoader handles fetching and caching historical data
 type DataLoader struct {
-	client   *delta.Client
-	cacheDir string
+	client     *delta.Client
+	cacheDir   string
+	binanceURL string
+
+	// Gap handling (see SetGapHandling)
+	fillGaps   bool
+	maxGapBars int
 }
 
 // NewDataLoader creates a data loader with caching
 func NewDataLoader(client *delta.Client, cacheDir string) *DataLoader {
 	return &DataLoader{
-		client:   client,
-		cacheDir: cacheDir,
+		client:     client,
+		cacheDir:   cacheDir,
+		binanceURL: binanceKlinesURL,
+	}
+}
+
+// SetGapHandling configures what LoadCandles does with missing bars. A run of more than
+// maxGapBars consecutive missing bars fails the load (0 = no limit); with fill set, the
+// remaining gaps are forward-filled with flat zero-volume bars at the previous close.
+func (d *DataLoader) SetGapHandling(fill bool, maxGapBars int) {
+	d.fillGaps = fill
+	d.maxGapBars = maxGapBars
+}
+
+// LoadCandles fetches candles for the given range, using cache if available, then checks
+// them for missing bars according to SetGapHandling.
+// Cancelling ctx aborts the fetch between requests; nothing is cached for an aborted fetch.
+func (d *DataLoader) LoadCandles(ctx context.Context, symbol, resolution string, start, end time.Time) ([]delta.Candle, error) {
+	if _, err := delta.ParseResolution(resolution); err != nil {
+		return nil, err
+	}
+	candles, err := d.loadCandles(ctx, symbol, resolution, start, end)
+	if err != nil {
+		return nil, err
+	}
+	return d.checkGaps(symbol, resolution, candles)
+}
+
+// checkGaps logs gap statistics and applies the gap policy
+func (d *DataLoader) checkGaps(symbol, resolution string, candles []delta.Candle) ([]delta.Candle, error) {
+	gaps, err := d.ValidateCandles(candles, resolution)
+	if err != nil {
+		return nil, err
+	}
+	if len(gaps) == 0 {
+		return candles, nil
+	}
+
+	step, _ := delta.ParseResolution(resolution)
+	runs, longest := gapRuns(gaps, step)
+	fmt.Printf("  Warning: %s has %d missing %s bars in %d gaps (longest %d bars, first at %s)\n",
+		symbol, len(gaps), resolution, runs, longest, gaps[0].UTC().Format(time.RFC3339))
+
+	if d.maxGapBars > 0 && longest > d.maxGapBars {
+		return nil, fmt.Errorf("%s has a gap of %d %s bars, above the limit of %d", symbol, longest, resolution, d.maxGapBars)
+	}
+	if d.fillGaps {
+		return fillCandleGaps(candles, step), nil
+	}
+	return candles, nil
+}
+
+// ValidateCandles returns the open time of every bar missing between the first and last
+// candle at the given resolution. Candles must be sorted by time.
+func (d *DataLoader) ValidateCandles(candles []delta.Candle, resolution string) (gaps []time.Time, err error) {
+	step, err := delta.ParseResolution(resolution)
+	if err != nil {
+		return nil, err
+	}
+	stepSecs := int64(step / time.Second)
+
+	for i := 1; i < len(candles); i++ {
+		prev, curr := candles[i-1].Time, candles[i].Time
+		if curr <= prev {
+			return nil, fmt.Errorf("candles out of order at %d: %d after %d", i, curr, prev)
+		}
+		for t := prev + stepSecs; t < curr; t += stepSecs {
+			gaps = append(gaps, time.Unix(t, 0))
+		}
+	}
+	return gaps, nil
+}
+
+// CandleCoverage returns the fraction of the bars expected in [start, end) at the given
+// resolution that are present in candles
+func CandleCoverage(candles []delta.Candle, resolution string, start, end time.Time) float64 {
+	step, err := delta.ParseResolution(resolution)
+	if err != nil || !end.After(start) {
+		return 0
+	}
+	expected := int(end.Sub(start) / step)
+	if expected == 0 {
+		return 0
+	}
+
+	present := 0
+	for _, c := range candles {
+		if t := time.Unix(c.Time, 0); !t.Before(start) && t.Before(end) {
+			present++
+		}
+	}
+	if present > expected {
+		return 1
+	}
+	return float64(present) / float64(expected)
+}
+
+// gapRuns counts runs of consecutive missing bars and the longest run
+func gapRuns(gaps []time.Time, step time.Duration) (runs, longest int) {
+	run := 0
+	for i, g := range gaps {
+		if i > 0 && g.Sub(gaps[i-1]) == step {
+			run++
+		} else {
+			runs++
+			run = 1
+		}
+		if run > longest {
+			longest = run
+		}
+	}
+	return runs, longest
+}
+
+// fillCandleGaps inserts flat zero-volume bars at the previous close for every missing bar
+func fillCandleGaps(candles []delta.Candle, step time.Duration) []delta.Candle {
+	stepSecs := int64(step / time.Second)
+	filled := make([]delta.Candle, 0, len(candles))
+	for i, c := range candles {
+		if i > 0 {
+			prev := candles[i-1]
+			for t := prev.Time + stepSecs; t < c.Time; t += stepSecs {
+				filled = append(filled, delta.Candle{Time: t, Open: prev.Close, High: prev.Close, Low: prev.Close, Close: prev.Close})
+			}
+		}
+		filled = append(filled, c)
 	}
+	return filled
 }
 
-// LoadCandles fetches candles for the given range, using cache if available
-func (d *DataLoader) LoadCandles(symbol, resolution string, start, end time.Time) ([]delta.Candle, error) {
+// loadCandles fetches candles from the cache, Delta or the Binance fallback
+func (d *DataLoader) loadCandles(ctx context.Context, symbol, resolution string, start, end time.Time) ([]delta.Candle, error) {
 	// Try cache first
 	cached, err := d.loadFromCache(symbol, resolution, start, end)
 	if err == nil && len(cached) > 0 {
@@ -35,16 +168,23 @@ func (d *DataLoader) LoadCandles(symbol, resolution string, start, end time.Time
 	}
 
 	// Try fetching from Delta
-	allCandles, err := d.fetchCandlesInChunks(symbol, resolution, start, end)
-	if err == nil && len(allCandles) > 0 {
-		// Save to cache
-		d.saveToCache(symbol, resolution, start, end, allCandles)
-		return allCandles, nil
+	if d.client != nil {
+		allCandles, err := d.fetchCandlesInChunks(ctx, symbol, resolution, start, end)
+		if err == nil && len(allCandles) > 0 {
+			// Save to cache
+			if err := d.saveToCache(symbol, resolution, start, end, allCandles); err != nil {
+				fmt.Printf("Warning: failed to cache data: %v\n", err)
+			}
+			return allCandles, nil
+		}
+	}
+	if err := ctx.Err(); err != nil {
+		return nil, err
 	}
 
 	// Fallback to Binance
 	fmt.Printf("  Delta API failed or inaccessible, trying Binance fallback for %s...\n", symbol)
-	allCandles, err = d.fetchFromBinance(symbol, resolution, start, end)
+	allCandles, err := d.fetchFromBinance(ctx, symbol, resolution, start, end)
 	if err != nil {
 		return nil, fmt.Errorf("both Delta and Binance fetching failed for %s: %w", symbol, err)
 	}
@@ -58,11 +198,14 @@ func (d *DataLoader) LoadCandles(symbol, resolution string, start, end time.Time
 }
 
 // fetchCandlesInChunks fetches data in chunks to avoid API limits
-func (d *DataLoader) fetchCandlesInChunks(symbol, resolution string, start, end time.Time) ([]delta.Candle, error) {
+func (d *DataLoader) fetchCandlesInChunks(ctx context.Context, symbol, resolution string, start, end time.Time) ([]delta.Candle, error) {
 	var allCandles []delta.Candle
 
 	// Determine chunk size based on resolution
-	chunkDuration := getChunkDuration(resolution)
+	chunkDuration, err := getChunkDuration(resolution)
+	if err != nil {
+		return nil, err
+	}
 	current := start
 
 	for current.Before(end) {
@@ -81,46 +224,54 @@ func (d *DataLoader) fetchCandlesInChunks(symbol, resolution string, start, end
 		current = chunkEnd
 
 		// Rate limiting delay (100ms between requests)
-		time.Sleep(100 * time.Millisecond)
+		if err := sleepContext(ctx, 100*time.Millisecond); err != nil {
+			return nil, err
+		}
 	}
 
-	// Sort by time
-	sortCandles(allCandles)
-
-	return allCandles, nil
+	// Chunk boundaries overlap, so the bar at each boundary can arrive twice
+	return delta.SortCandles(allCandles), nil
 }
 
-// sortCandles sorts candles by timestamp
-func sortCandles(candles []delta.Candle) {
-	// Simple bubble sort (data is usually mostly sorted)
-	for i := 0; i < len(candles)-1; i++ {
-		for j := i + 1; j < len(candles); j++ {
-			if candles[i].Time > candles[j].Time {
-				candles[i], candles[j] = candles[j], candles[i]
-			}
-		}
+// sleepContext sleeps for d or until ctx is cancelled
+func sleepContext(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
 	}
 }
 
 // fetchFromBinance fetches candles from Binance Futures public API
-func (d *DataLoader) fetchFromBinance(symbol, resolution string, start, end time.Time) ([]delta.Candle, error) {
+func (d *DataLoader) fetchFromBinance(ctx context.Context, symbol, resolution string, start, end time.Time) ([]delta.Candle, error) {
 	var allCandles []delta.Candle
 
 	// Map symbol and resolution
-	binanceSymbol := mapToBinanceSymbol(symbol)
-	binanceInterval := mapToBinanceInterval(resolution)
-
-	baseURL := "https://fapi.binance.com/fapi/v1/klines"
+	binanceSymbol := delta.DefaultSymbols.ToBinance(symbol)
+	binanceInterval, err := mapToBinanceInterval(resolution)
+	if err != nil {
+		return nil, err
+	}
 
 	current := start
 	client := &http.Client{Timeout: 10 * time.Second}
 
 	for current.Before(end) {
 		url := fmt.Sprintf("%s?symbol=%s&interval=%s&startTime=%d&endTime=%d&limit=1500",
-			baseURL, binanceSymbol, binanceInterval, current.UnixMilli(), end.UnixMilli())
+			d.binanceURL, binanceSymbol, binanceInterval, current.UnixMilli(), end.UnixMilli())
 
-		resp, err := client.Get(url)
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+		if err != nil {
+			return nil, err
+		}
+		resp, err := client.Do(req)
 		if err != nil {
+			if ctxErr := ctx.Err(); ctxErr != nil {
+				return nil, ctxErr
+			}
 			return nil, err
 		}
 
@@ -164,57 +315,47 @@ func (d *DataLoader) fetchFromBinance(symbol, resolution string, start, end time
 		current = time.UnixMilli(lastTime).Add(time.Minute) // Add a buffer to skip the last candle
 
 		// Avoid spamming
-		time.Sleep(100 * time.Millisecond)
+		if err := sleepContext(ctx, 100*time.Millisecond); err != nil {
+			return nil, err
+		}
 	}
 
 	return allCandles, nil
 }
 
-func mapToBinanceSymbol(symbol string) string {
-	switch symbol {
-	case "BTCUSD", "BTCINR":
-		return "BTCUSDT"
-	case "ETHUSD", "ETHINR":
-		return "ETHUSDT"
-	case "SOLUSD", "SOLINR":
-		return "SOLUSDT"
-	default:
-		return symbol
+// mapToBinanceInterval returns the Binance kline interval for a resolution. Binance uses the
+// same names except for weeks, and has no fixed 30-day interval.
+func mapToBinanceInterval(resolution string) (string, error) {
+	name, err := delta.NormalizeResolution(resolution)
+	if err != nil {
+		return "", err
 	}
-}
-
-func mapToBinanceInterval(resolution string) string {
-	switch resolution {
-	case "1m":
-		return "1m"
-	case "5m":
-		return "5m"
-	case "15m":
-		return "15m"
-	case "1h":
-		return "1h"
-	case "4h":
-		return "4h"
-	case "1d":
-		return "1d"
-	default:
-		return "5m"
+	switch name {
+	case "7d":
+		return "1w", nil
+	case "30d":
+		return "", fmt.Errorf("resolution %s has no Binance equivalent", name)
 	}
+	return name, nil
 }
 
 // getChunkDuration returns optimal chunk size for API calls
-func getChunkDuration(resolution string) time.Duration {
-	switch resolution {
-	case "1m":
-		return 24 * time.Hour // 1440 candles per day
-	case "5m":
-		return 7 * 24 * time.Hour // 2016 candles per week
-	case "15m":
-		return 14 * 24 * time.Hour
-	case "1h":
-		return 30 * 24 * time.Hour
+func getChunkDuration(resolution string) (time.Duration, error) {
+	step, err := delta.ParseResolution(resolution)
+	if err != nil {
+		return 0, err
+	}
+	switch step {
+	case time.Minute:
+		return 24 * time.Hour, nil // 1440 candles per day
+	case 5 * time.Minute:
+		return 7 * 24 * time.Hour, nil // 2016 candles per week
+	case 15 * time.Minute:
+		return 14 * 24 * time.Hour, nil
+	case time.Hour:
+		return 30 * 24 * time.Hour, nil
 	default:
-		return 7 * 24 * time.Hour
+		return step * 1000, nil
 	}
 }
 
@@ -256,15 +397,44 @@ func (d *DataLoader) saveToCache(symbol, resolution string, start, end time.Time
 		return err
 	}
 
-	return os.WriteFile(path, data, 0644)
+	return writeFileAtomic(path, data)
+}
+
+// writeFileAtomic writes to a temp file in the same directory and renames it into place,
+// so an interrupted write never leaves a truncated cache file behind
+func writeFileAtomic(path string, data []byte) error {
+	tmp, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".tmp-*")
+	if err != nil {
+		return err
+	}
+	tmpPath := tmp.Name()
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		os.Remove(tmpPath)
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		os.Remove(tmpPath)
+		return err
+	}
+	if err := os.Chmod(tmpPath, 0644); err != nil {
+		os.Remove(tmpPath)
+		return err
+	}
+	if err := os.Rename(tmpPath, path); err != nil {
+		os.Remove(tmpPath)
+		return err
+	}
+	return nil
 }
 
 // LoadMultiSymbol loads candles for multiple symbols
-func (d *DataLoader) LoadMultiSymbol(symbols []string, resolution string, start, end time.Time) (map[string][]delta.Candle, error) {
+func (d *DataLoader) LoadMultiSymbol(ctx context.Context, symbols []string, resolution string, start, end time.Time) (map[string][]delta.Candle, error) {
 	result := make(map[string][]delta.Candle)
 
 	for _, symbol := range symbols {
-		candles, err := d.LoadCandles(symbol, resolution, start, end)
+		candles, err := d.LoadCandles(ctx, symbol, resolution, start, end)
 		if err != nil {
 			return nil, fmt.Errorf("failed to load %s: %w", symbol, err)
 		}
@@ -0,0 +1,218 @@
+package backtest
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/kasyap/delta-go/go/config"
+	"github.com/kasyap/delta-go/go/pkg/delta"
+)
+
+// klinesPage returns n one-minute Binance klines starting at start
+func klinesPage(start time.Time, n int) [][]interface{} {
+	page := make([][]interface{}, n)
+	for i := range page {
+		ot := start.Add(time.Duration(i) * time.Minute).UnixMilli()
+		page[i] = []interface{}{float64(ot), "100", "101", "99", "100.5", "10"}
+	}
+	return page
+}
+
+func TestLoadCandlesCancelledMidFetchLeavesNoCache(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	end := start.Add(24 * time.Hour)
+
+	requests := 0
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		ms, _ := strconv.ParseInt(r.URL.Query().Get("startTime"), 10, 64)
+		json.NewEncoder(w).Encode(klinesPage(time.UnixMilli(ms), 60))
+		// Simulate Ctrl-C after the first page has been served
+		cancel()
+	}))
+	defer srv.Close()
+
+	cacheDir := t.TempDir()
+	loader := NewDataLoader(nil, cacheDir)
+	loader.binanceURL = srv.URL
+
+	candles, err := loader.LoadCandles(ctx, "BTCUSD", "1m", start, end)
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("expected context.Canceled, got err=%v (%d candles)", err, len(candles))
+	}
+	if requests != 1 {
+		t.Errorf("expected fetch to stop after 1 request, got %d", requests)
+	}
+
+	entries, err := os.ReadDir(cacheDir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 0 {
+		t.Errorf("expected no cache files after cancel, found %d (first: %s)", len(entries), entries[0].Name())
+	}
+}
+
+func TestLoadCandlesCachesCompleteFetch(t *testing.T) {
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	end := start.Add(time.Hour)
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ms, _ := strconv.ParseInt(r.URL.Query().Get("startTime"), 10, 64)
+		from := time.UnixMilli(ms)
+		if !from.Before(end) {
+			w.Write([]byte("[]"))
+			return
+		}
+		json.NewEncoder(w).Encode(klinesPage(from, int(end.Sub(from)/time.Minute)))
+	}))
+	defer srv.Close()
+
+	cacheDir := t.TempDir()
+	loader := NewDataLoader(nil, cacheDir)
+	loader.binanceURL = srv.URL
+
+	candles, err := loader.LoadCandles(context.Background(), "BTCUSD", "1m", start, end)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(candles) != 60 {
+		t.Fatalf("expected 60 candles, got %d", len(candles))
+	}
+
+	// Only the final cache file should exist, no leftover temp files
+	entries, err := os.ReadDir(cacheDir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 1 {
+		t.Fatalf("expected exactly 1 cache file, found %d", len(entries))
+	}
+	if want := loader.cacheFilePath("BTCUSD", "1m", start, end); filepath.Join(cacheDir, entries[0].Name()) != want {
+		t.Errorf("cache file = %s, want %s", entries[0].Name(), want)
+	}
+
+	cached, err := loader.loadFromCache("BTCUSD", "1m", start, end)
+	if err != nil || len(cached) != 60 {
+		t.Errorf("cache reload: %d candles, err=%v", len(cached), err)
+	}
+}
+
+// gappedCandles returns ten 5m candles with bars 3 and 6 missing
+func gappedCandles() []delta.Candle {
+	base := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC).Unix()
+	var candles []delta.Candle
+	for i := 0; i < 10; i++ {
+		if i == 3 || i == 6 {
+			continue
+		}
+		p := 100 + float64(i)
+		candles = append(candles, delta.Candle{Time: base + int64(i*300), Open: p, High: p, Low: p, Close: p, Volume: 1})
+	}
+	return candles
+}
+
+func TestValidateCandlesFindsInteriorGaps(t *testing.T) {
+	d := NewDataLoader(nil, t.TempDir())
+	candles := gappedCandles()
+
+	gaps, err := d.ValidateCandles(candles, "5m")
+	if err != nil {
+		t.Fatalf("ValidateCandles: %v", err)
+	}
+	base := time.Unix(candles[0].Time, 0)
+	want := []time.Time{base.Add(15 * time.Minute), base.Add(30 * time.Minute)}
+	if len(gaps) != len(want) {
+		t.Fatalf("gaps = %v, want %v", gaps, want)
+	}
+	for i := range want {
+		if !gaps[i].Equal(want[i]) {
+			t.Errorf("gap %d = %v, want %v", i, gaps[i], want[i])
+		}
+	}
+
+	if _, err := d.ValidateCandles(candles, "7m"); err == nil {
+		t.Error("expected error for unknown resolution")
+	}
+}
+
+func TestCheckGapsFillAndLimit(t *testing.T) {
+	d := NewDataLoader(nil, t.TempDir())
+
+	d.SetGapHandling(true, 0)
+	filled, err := d.checkGaps("BTCUSD", "5m", gappedCandles())
+	if err != nil {
+		t.Fatalf("checkGaps: %v", err)
+	}
+	if len(filled) != 10 {
+		t.Fatalf("filled %d candles, want 10", len(filled))
+	}
+	if c := filled[3]; c.Close != 102 || c.Open != 102 || c.Volume != 0 {
+		t.Errorf("filled bar = %+v, want flat at previous close 102", c)
+	}
+	if gaps, _ := d.ValidateCandles(filled, "5m"); len(gaps) != 0 {
+		t.Errorf("gaps remain after fill: %v", gaps)
+	}
+
+	// Missing bars 3 and 4 in a row exceed a one-bar limit
+	candles := gappedCandles()
+	candles = append(candles[:3], candles[4:]...)
+	d.SetGapHandling(true, 1)
+	if _, err := d.checkGaps("BTCUSD", "5m", candles); err == nil {
+		t.Error("expected error for a gap above the limit")
+	}
+}
+
+func TestLoadCandlesRejectsUnknownResolution(t *testing.T) {
+	loader := NewDataLoader(nil, t.TempDir())
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	if _, err := loader.LoadCandles(context.Background(), "BTCUSD", "7m", start, start.Add(time.Hour)); err == nil {
+		t.Fatal("expected an error for an unsupported resolution")
+	}
+	if _, err := mapToBinanceInterval("30d"); err == nil {
+		t.Error("30d has no Binance interval and should fail")
+	}
+	if got, err := mapToBinanceInterval("7d"); err != nil || got != "1w" {
+		t.Errorf("mapToBinanceInterval(7d) = %q, %v; want 1w", got, err)
+	}
+}
+
+func TestLoadCandlesDedupesChunkBoundaries(t *testing.T) {
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	end := start.Add(48 * time.Hour)
+
+	// Each request returns both of its endpoints, so consecutive day chunks share a bar
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		from, _ := strconv.ParseInt(r.URL.Query().Get("start"), 10, 64)
+		to, _ := strconv.ParseInt(r.URL.Query().Get("end"), 10, 64)
+		var page []map[string]any
+		for ts := from; ts <= to; ts += 60 {
+			page = append(page, map[string]any{"time": ts, "open": 100, "high": 101, "low": 99, "close": 100, "volume": 1})
+		}
+		json.NewEncoder(w).Encode(map[string]any{"success": true, "result": page})
+	}))
+	defer srv.Close()
+
+	client := delta.NewClient(&config.Config{BaseURL: srv.URL, APIRateLimitRPS: 100, CandlePageLimit: 2000})
+	defer client.Close()
+	loader := NewDataLoader(client, t.TempDir())
+
+	candles, err := loader.LoadCandles(context.Background(), "BTCUSD", "1m", start, end)
+	if err != nil {
+		t.Fatalf("LoadCandles() error = %v", err)
+	}
+	if want := 2*24*60 + 1; len(candles) != want {
+		t.Errorf("loaded %d candles, want %d with no repeated boundary bars", len(candles), want)
+	}
+}
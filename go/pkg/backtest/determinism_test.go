@@ -0,0 +1,131 @@
+package backtest
+
+import (
+	"bytes"
+	"encoding/json"
+	"math"
+	"testing"
+	"time"
+
+	"github.com/kasyap/delta-go/go/pkg/delta"
+	"github.com/kasyap/delta-go/go/pkg/features"
+	"github.com/kasyap/delta-go/go/pkg/strategy"
+)
+
+// cyclingStrategy opens long, closes, opens short and closes on a fixed bar cycle
+type cyclingStrategy struct{}
+
+func (cyclingStrategy) Name() string { return "cycling" }
+
+func (cyclingStrategy) UpdateParams(params map[string]interface{}) {}
+
+func (cyclingStrategy) Analyze(f features.MarketFeatures, candles []delta.Candle) strategy.Signal {
+	switch len(candles) % 20 {
+	case 5:
+		return strategy.Signal{Action: strategy.ActionBuy, Side: "buy", Confidence: 0.8}
+	case 15:
+		return strategy.Signal{Action: strategy.ActionSell, Side: "sell", Confidence: 0.8}
+	case 10, 19:
+		return strategy.Signal{Action: strategy.ActionClose}
+	}
+	return strategy.Signal{Action: strategy.ActionNone}
+}
+
+// wavyCandles oscillates around base so the cycle sees both winners and losers
+func wavyCandles(start time.Time, n int, base float64) []delta.Candle {
+	candles := make([]delta.Candle, n)
+	for i := range candles {
+		p := base * (1 + 0.01*math.Sin(float64(i)/7))
+		candles[i] = delta.Candle{
+			Time: start.Unix() + int64(i*300), Open: p, High: p * 1.002, Low: p * 0.998, Close: p * 1.0005, Volume: 10,
+		}
+	}
+	return candles
+}
+
+// newDeterminismEngine builds a two-symbol run with funding, tiered fees, slippage and
+// latency, all of which depend on the order fills are applied in
+func newDeterminismEngine(seed int64) *Engine {
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	const bars = 600
+	end := start.Add(bars * 5 * time.Minute)
+
+	cfg := DefaultConfig()
+	cfg.Symbols = []string{"BTCUSD", "ETHUSD"}
+	cfg.StartTime, cfg.EndTime = start, end
+	cfg.Seed = seed
+	cfg.FeeTiers = []FeeTier{{MinVolume: 5000, MakerFeeBps: 1, TakerFeeBps: 3}}
+
+	e := NewEngine(cfg, nil)
+	e.progress = nil
+	e.RegisterStrategy(cyclingStrategy{})
+	e.candles["BTCUSD"] = wavyCandles(start, bars, 50000)
+	e.candles["ETHUSD"] = wavyCandles(start, bars, 3000)
+	for _, symbol := range cfg.Symbols {
+		e.fundingRates[symbol] = e.fundingFetcher.generateSyntheticRates(symbol, start, end)
+	}
+	return e
+}
+
+func TestEngine_DeterministicRuns(t *testing.T) {
+	run := func() []byte {
+		t.Helper()
+		res, err := newDeterminismEngine(42).runLoaded()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(res.Trades) == 0 {
+			t.Fatal("run produced no trades")
+		}
+		out, err := json.Marshal(res)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return out
+	}
+
+	first := run()
+	for i := 0; i < 5; i++ {
+		if again := run(); !bytes.Equal(first, again) {
+			t.Fatalf("run %d differs from the first: %d vs %d bytes", i+2, len(again), len(first))
+		}
+	}
+}
+
+func TestSyntheticFundingRates_Seeded(t *testing.T) {
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	end := start.Add(30 * 24 * time.Hour)
+	rates := func(seed int64, from time.Time) []FundingRate {
+		f := NewFundingFetcher(t.TempDir())
+		f.SetSeed(seed)
+		return f.generateSyntheticRates("BTCUSD", from, end)
+	}
+
+	a, b, other := rates(7, start), rates(7, start), rates(8, start)
+	distinct := map[float64]bool{}
+	differs := false
+	for i := range a {
+		if a[i] != b[i] {
+			t.Fatalf("rate %d differs for the same seed: %+v vs %+v", i, a[i], b[i])
+		}
+		if a[i].Rate != other[i].Rate {
+			differs = true
+		}
+		if a[i].Rate < 0.00015*0.5 || a[i].Rate > 0.00015*1.5 {
+			t.Errorf("rate %d = %g outside +/-50%% of the BTC base", i, a[i].Rate)
+		}
+		distinct[a[i].Rate] = true
+	}
+	if !differs {
+		t.Error("different seeds gave identical rates")
+	}
+	if len(distinct) < len(a)/2 {
+		t.Errorf("only %d distinct rates in %d", len(distinct), len(a))
+	}
+
+	// A later window reproduces the overlapping rates
+	later := rates(7, start.Add(10*24*time.Hour))
+	if later[0] != a[30] {
+		t.Errorf("overlapping window rate = %+v, want %+v", later[0], a[30])
+	}
+}
@@ -1,7 +1,10 @@
 package backtest
 
 import (
+	"context"
 	"fmt"
+	"math"
+	"sort"
 	"time"
 
 	"github.com/kasyap/delta-go/go/pkg/delta"
@@ -19,23 +22,37 @@ type Engine struct {
 	featuresEngine *features.Engine
 	strategyMgr    *strategy.Manager
 	slippage       SlippageModel
+	clock          *strategy.SimClock // Bar time, read by strategies with holding-period logic
 
 	// State
 	equity        float64
 	peakEquity    float64
-	positions     map[string]*Position
+	positions     map[string][]*Position // Open units per symbol, oldest first
+	nextPosID     int64
 	trades        []Trade
 	equityCurve   []EquityPoint
 	pendingOrders map[string]PendingOrder
 	prevTimestamp time.Time
+	lastTimestamp time.Time // Final bar; open positions are closed here
 	lastPrice     map[string]float64
+	lastStopLoss  map[string]time.Time // Bar of each symbol's last stop-loss exit
+	lastReversal  map[string]time.Time // Bar of each symbol's last close on an opposite signal
+	history       int                  // Candles handed to strategies per bar, see Manager.RequiredHistory
 
 	// Margin tracking
 	usedMargin float64 // Total margin currently in use
 
+	tradedVolume float64 // Cumulative filled notional, selects the fee tier
+
 	// Data
 	candles      map[string][]delta.Candle
 	fundingRates map[string][]FundingRate
+
+	// Event hooks for custom analytics
+	hooks    []EventHook
+	progress ProgressFunc // Simulation progress reporting (nil = silent)
+
+	warnedCV map[string]bool // Symbols already warned about a contract value fallback
 }
 
 // PendingOrder represents a signal to execute on the next bar
@@ -43,34 +60,62 @@ type PendingOrder struct {
 	Signal     strategy.Signal
 	SignalTime time.Time
 	Symbol     string
+	Context    map[string]float64 // Entry context, with RecordTradeContext
 }
 
 // NewEngine creates a new backtesting engine
 func NewEngine(config Config, client *delta.Client) *Engine {
+	dataLoader := NewDataLoader(client, config.DataCacheDir)
+	dataLoader.SetGapHandling(config.FillGaps, config.MaxGapBars)
+	fundingFetcher := NewFundingFetcher(config.DataCacheDir)
+	fundingFetcher.SetSeed(config.Seed)
+
 	return &Engine{
 		config:         config,
-		dataLoader:     NewDataLoader(client, config.DataCacheDir),
-		fundingFetcher: NewFundingFetcher(config.DataCacheDir),
+		dataLoader:     dataLoader,
+		fundingFetcher: fundingFetcher,
 		featuresEngine: features.NewEngine(),
 		strategyMgr:    strategy.NewManager(),
-		slippage:       config.SlippageModel,
+		clock:          strategy.NewSimClock(config.StartTime),
+		slippage:       cappedSlippage(config),
 		equity:         config.InitialCapital,
 		peakEquity:     config.InitialCapital,
-		positions:      make(map[string]*Position),
+		positions:      make(map[string][]*Position),
 		pendingOrders:  make(map[string]PendingOrder),
 		lastPrice:      make(map[string]float64),
+		lastStopLoss:   make(map[string]time.Time),
+		lastReversal:   make(map[string]time.Time),
 		candles:        make(map[string][]delta.Candle),
 		fundingRates:   make(map[string][]FundingRate),
+		warnedCV:       make(map[string]bool),
+		progress:       printProgress,
 	}
 }
 
+// FeaturesEngine returns the engine that computes per-bar features. Strategies that read
+// OBI history (e.g. the scalper) must be built with it to see the replayed order book.
+func (e *Engine) FeaturesEngine() *features.Engine {
+	return e.featuresEngine
+}
+
 // RegisterStrategy adds a strategy to the backtest
 func (e *Engine) RegisterStrategy(s strategy.Strategy) {
 	e.strategyMgr.RegisterStrategy(s)
 }
 
+// UpdateStrategyParams applies parameter overrides to the registered strategies. Keys several
+// strategies read (e.g. "enabled") need the strategy's name as a prefix: "grid_trading.enabled".
+func (e *Engine) UpdateStrategyParams(params map[string]interface{}) {
+	e.strategyMgr.UpdateParams(params)
+}
+
 // Run executes the backtest and returns results
 func (e *Engine) Run() (*Result, error) {
+	return e.RunContext(context.Background())
+}
+
+// RunContext executes the backtest; cancelling ctx aborts data loading
+func (e *Engine) RunContext(ctx context.Context) (*Result, error) {
 	fmt.Printf("=== Starting Backtest ===\n")
 	fmt.Printf("Period: %s to %s\n", e.config.StartTime.Format("2006-01-02"), e.config.EndTime.Format("2006-01-02"))
 	fmt.Printf("Symbols: %v\n", e.config.Symbols)
@@ -78,11 +123,15 @@ func (e *Engine) Run() (*Result, error) {
 	fmt.Println()
 
 	// Load data
-	if err := e.loadData(); err != nil {
+	if err := e.loadData(ctx); err != nil {
 		return nil, fmt.Errorf("failed to load data: %w", err)
 	}
 
-	// Run simulation
+	return e.runLoaded()
+}
+
+// runLoaded simulates over already-loaded data and computes metrics
+func (e *Engine) runLoaded() (*Result, error) {
 	if err := e.simulate(); err != nil {
 		return nil, fmt.Errorf("simulation failed: %w", err)
 	}
@@ -104,14 +153,14 @@ type Result struct {
 }
 
 // loadData fetches all historical data needed for backtest
-func (e *Engine) loadData() error {
+func (e *Engine) loadData(ctx context.Context) error {
 	fmt.Println("Loading historical data...")
 
 	// Load candles for each symbol
 	for _, symbol := range e.config.Symbols {
 		fmt.Printf("  Loading %s candles...\n", symbol)
 		candles, err := e.dataLoader.LoadCandles(
-			symbol, e.config.Resolution,
+			ctx, symbol, e.config.Resolution,
 			e.config.StartTime, e.config.EndTime,
 		)
 		if err != nil {
@@ -151,19 +200,21 @@ func (e *Engine) simulate() error {
 	}
 
 	fmt.Printf("Processing %d time steps...\n", len(timestamps))
+	e.lastTimestamp = timestamps[len(timestamps)-1]
+	e.strategyMgr.SetClock(e.clock)
+	e.history = e.strategyMgr.RequiredHistory()
 
 	// Process each timestamp
 	for i, ts := range timestamps {
+		e.clock.Set(ts)
 		if err := e.processTimestamp(ts); err != nil {
 			return fmt.Errorf("error at %v: %w", ts, err)
 		}
 		e.prevTimestamp = ts
 
 		// Progress update every 10%
-		if i%(len(timestamps)/10+1) == 0 {
-			progress := float64(i) / float64(len(timestamps)) * 100
-			fmt.Printf("  Progress: %.0f%% | Equity: $%.2f | Trades: %d\n",
-				progress, e.equity, len(e.trades))
+		if e.progress != nil && i%(len(timestamps)/10+1) == 0 {
+			e.progress(float64(i)/float64(len(timestamps))*100, e.equity, len(e.trades))
 		}
 	}
 
@@ -192,19 +243,22 @@ func (e *Engine) getUniqueTimestamps() []time.Time {
 	for ts := range timeSet {
 		times = append(times, time.Unix(ts, 0))
 	}
-
-	// Sort
-	for i := 0; i < len(times)-1; i++ {
-		for j := i + 1; j < len(times); j++ {
-			if times[i].After(times[j]) {
-				times[i], times[j] = times[j], times[i]
-			}
-		}
-	}
+	sort.Slice(times, func(i, j int) bool { return times[i].Before(times[j]) })
 
 	return times
 }
 
+// sortedSymbols returns the keys of a per-symbol map in order. Fills, exits and funding
+// are applied in this order so equity, fee tiers and trade IDs don't depend on map order.
+func sortedSymbols[V any](m map[string]V) []string {
+	symbols := make([]string, 0, len(m))
+	for symbol := range m {
+		symbols = append(symbols, symbol)
+	}
+	sort.Strings(symbols)
+	return symbols
+}
+
 // processTimestamp handles all events at a single timestamp
 func (e *Engine) processTimestamp(ts time.Time) error {
 	// 1. FIRST: Process funding payments for positions that were open BEFORE this bar
@@ -214,9 +268,17 @@ func (e *Engine) processTimestamp(ts time.Time) error {
 	}
 
 	// 2. Execute pending orders from previous bar at THIS bar's open
+	if e.config.StrictNoLookahead {
+		if err := e.checkNoLookahead(ts); err != nil {
+			return err
+		}
+	}
 	e.executePendingOrders(ts)
+	if e.config.FlattenOnEventFreeze && e.config.EventFreeze.Active(ts) {
+		e.flattenForEventFreeze(ts)
+	}
 
-	// 3. Check stop-loss and take-profit for open positions
+	// 3. Check liquidation, stop-loss and take-profit for open positions
 	e.checkExits(ts)
 
 	// 4. Generate signals for each symbol (will be executed NEXT bar)
@@ -229,43 +291,101 @@ func (e *Engine) processTimestamp(ts time.Time) error {
 		// Store last price for equity curve
 		e.lastPrice[symbol] = candle.Close
 
-		// Get signal from Strategy Manager
-		candles := e.getRecentCandles(symbol, ts, 200)
-		mf := e.buildMarketFeatures(symbol, candle, candles, ts)
+		// Get signal from Strategy Manager. In strict mode the features come from the last
+		// closed bar rather than this bar's full OHLC.
+		candles := e.getRecentCandles(symbol, ts, e.history)
+		featureCandle := candle
+		if e.config.StrictNoLookahead {
+			if len(candles) == 0 {
+				continue
+			}
+			featureCandle = &candles[len(candles)-1]
+		}
+		mf := e.buildMarketFeatures(symbol, featureCandle, candles, ts)
 		signal := e.strategyMgr.GetSignal(mf, candles)
 
 		// Queue signal for execution on NEXT bar
 		if signal.Action != strategy.ActionNone {
-			e.pendingOrders[symbol] = PendingOrder{
+			order := PendingOrder{
 				Signal:     signal,
 				SignalTime: ts,
 				Symbol:     symbol,
 			}
+			if e.config.RecordTradeContext {
+				order.Context = tradeContext(mf, candles, signal)
+			}
+			e.pendingOrders[symbol] = order
 		}
 	}
 
-	// 5. Update equity curve
+	// 5. On the final bar, close anything still open so its accrued funding and price P&L
+	// are realized into trades
+	if ts.Equal(e.lastTimestamp) {
+		e.closeOpenPositions(ts)
+	}
+
+	// 6. Update equity curve
 	e.updateEquityCurve(ts)
 
 	return nil
 }
 
-// executePendingOrders executes queued orders at the current bar's open
+// checkNoLookahead fails when a queued order is due to fill at or before its signal bar
+func (e *Engine) checkNoLookahead(ts time.Time) error {
+	for _, symbol := range sortedSymbols(e.pendingOrders) {
+		order := e.pendingOrders[symbol]
+		if !ts.After(order.SignalTime) {
+			return fmt.Errorf("lookahead: %s order signalled at %v would fill at %v", symbol, order.SignalTime, ts)
+		}
+	}
+	return nil
+}
+
+// executePendingOrders executes queued orders at the current bar's open, delayed by latency
 func (e *Engine) executePendingOrders(ts time.Time) {
-	for symbol, order := range e.pendingOrders {
+	for _, symbol := range sortedSymbols(e.pendingOrders) {
+		order := e.pendingOrders[symbol]
 		candle := e.getCandleAt(symbol, ts)
 		if candle == nil {
 			continue // Keep order pending if no candle
 		}
 
-		// Execute at THIS bar's open (not close!)
-		e.processSignalAtPrice(symbol, order.Signal, candle, ts, candle.Open)
+		// Execute at THIS bar's open (not close!), or later in the bar with latency
+		fillPrice, arrived := e.latencyFillPrice(order, candle, ts)
+		if !arrived {
+			continue // Still in flight at this bar's close
+		}
+		e.processSignalAtPrice(symbol, order.Signal, order.Context, candle, ts, fillPrice)
 
 		// Remove from pending
 		delete(e.pendingOrders, symbol)
 	}
 }
 
+// latencyFillPrice models LatencyMs for an order signalled at the close of the bar opened at
+// order.SignalTime. The order reaches the exchange LatencyMs after that close. Candles carry no
+// intra-bar path, so price is assumed to move linearly from open to close: arriving a fraction
+// f into the bar fills at Open + f*(Close-Open). An order still in flight at the bar's close is
+// not filled (arrived=false) and is retried on the next bar. Zero latency or an unknown
+// resolution fills at the open.
+func (e *Engine) latencyFillPrice(order PendingOrder, candle *delta.Candle, ts time.Time) (price float64, arrived bool) {
+	barDur, err := delta.ParseResolution(e.config.Resolution)
+	if e.config.LatencyMs <= 0 || err != nil {
+		return candle.Open, true
+	}
+
+	arrival := order.SignalTime.Add(barDur + time.Duration(e.config.LatencyMs)*time.Millisecond)
+	if !arrival.Before(ts.Add(barDur)) {
+		return 0, false
+	}
+
+	fraction := float64(arrival.Sub(ts)) / float64(barDur)
+	if fraction <= 0 { // Arrived before this bar opened (data gap)
+		return candle.Open, true
+	}
+	return candle.Open + fraction*(candle.Close-candle.Open), true
+}
+
 // shouldProcessFunding checks if we crossed a funding boundary since last timestamp
 func (e *Engine) shouldProcessFunding(ts time.Time) bool {
 	if e.prevTimestamp.IsZero() {
@@ -276,21 +396,17 @@ func (e *Engine) shouldProcessFunding(ts time.Time) bool {
 
 // crossedFundingBoundary checks if we crossed 00:00, 08:00, or 16:00 UTC
 func crossedFundingBoundary(prev, curr time.Time) bool {
-	prevU := prev.UTC()
-	currU := curr.UTC()
-
-	fundingHours := []int{0, 8, 16}
-	for _, h := range fundingHours {
-		fundingTime := time.Date(currU.Year(), currU.Month(), currU.Day(), h, 0, 0, 0, time.UTC)
-		if prevU.Before(fundingTime) && (currU.Equal(fundingTime) || currU.After(fundingTime)) {
-			return true
-		}
-		// Handle day boundary for 00:00
-		if h == 0 && prevU.Day() != currU.Day() {
-			return true
-		}
+	return len(fundingBoundaries(prev, curr)) > 0
+}
+
+// fundingBoundaries returns every funding time in (prev, curr], oldest first.
+// A gap in the data can span more than one boundary and each one settles separately.
+func fundingBoundaries(prev, curr time.Time) []time.Time {
+	var boundaries []time.Time
+	for b := NextFundingTime(prev); !b.After(curr.UTC()); b = NextFundingTime(b) {
+		boundaries = append(boundaries, b)
 	}
-	return false
+	return boundaries
 }
 
 func oppositeSide(side string) string {
@@ -300,111 +416,172 @@ func oppositeSide(side string) string {
 	return "buy"
 }
 
-// processFunding applies funding payments to open positions
+// fundingRateTolerance absorbs exchange timestamps that land a few ms after the boundary
+const fundingRateTolerance = time.Minute
+
+// processFunding applies funding payments to open positions for each boundary
+// crossed since the previous bar
 func (e *Engine) processFunding(ts time.Time) {
-	for symbol, pos := range e.positions {
-		rate := GetFundingAtTime(e.fundingRates[symbol], ts)
-		if rate == 0 {
+	boundaries := fundingBoundaries(e.prevTimestamp, ts)
+	for _, symbol := range sortedSymbols(e.positions) {
+		units := e.positions[symbol]
+		contractValue, err := delta.ParseContractValue(e.getProduct(symbol))
+		if err != nil {
 			continue
 		}
-
-		// Get notional value for funding calculation
-		product := e.getProduct(symbol)
-		contracts := int(pos.Size)
-		// Use mark price (current candle close) for notional calculation
+		// Mark at settlement is approximated by the open of the first bar at/after it
 		candle := e.getCandleAt(symbol, ts)
-		markPrice := pos.EntryPrice // Fallback to entry price
-		if candle != nil {
-			markPrice = candle.Close
-		}
-		notional, err := delta.ContractsToNotional(contracts, markPrice, product)
-		if err != nil || notional <= 0 {
-			continue
-		}
 
-		// Calculate funding payment based on notional value
-		payment := notional * rate
-
-		// Funding mechanics:
-		// Positive rate: longs pay shorts
-		// Negative rate: shorts pay longs
-		if pos.Side == "buy" {
-			// Long pays when rate is positive (payment > 0 means we lose)
-			pos.FundingPaid += payment
-			e.equity -= payment
-		} else {
-			// Short receives when rate is positive (payment > 0 means we earn)
-			pos.FundingPaid -= payment // Negative FundingPaid = we earned
-			e.equity += payment
+		for _, pos := range units {
+			markPrice := pos.EntryPrice // Fallback to entry price
+			if candle != nil {
+				markPrice = candle.Open
+			}
+			notional := pos.Size * markPrice * contractValue
+			if notional <= 0 {
+				continue
+			}
+
+			for _, boundary := range boundaries {
+				// Settle with the rate published for this boundary, not whatever is latest at ts
+				rate := GetFundingAtTime(e.fundingRates[symbol], boundary.Add(fundingRateTolerance))
+				if rate == 0 {
+					continue
+				}
+
+				// Calculate funding payment based on notional value
+				payment := notional * rate
+
+				// Funding mechanics:
+				// Positive rate: longs pay shorts
+				// Negative rate: shorts pay longs
+				if pos.Side == "buy" {
+					// Long pays when rate is positive (payment > 0 means we lose)
+					pos.FundingPaid += payment
+					e.equity -= payment
+				} else {
+					// Short receives when rate is positive (payment > 0 means we earn)
+					pos.FundingPaid -= payment // Negative FundingPaid = we earned
+					e.equity += payment
+				}
+			}
 		}
 	}
 }
 
-// checkExits checks stop-loss and take-profit for all positions
+// checkExits checks liquidation, stop-loss and take-profit for every open unit independently
 func (e *Engine) checkExits(ts time.Time) {
-	for symbol, pos := range e.positions {
+	for _, symbol := range sortedSymbols(e.positions) {
+		units := e.positions[symbol]
 		candle := e.getCandleAt(symbol, ts)
 		if candle == nil {
 			continue
 		}
 
-		var exitPrice float64
-		var exitReason string
-
-		if pos.Side == "buy" {
-			// Long position
-			if candle.Low <= pos.StopLoss && pos.StopLoss > 0 {
-				exitPrice = pos.StopLoss
-				exitReason = "stop_loss"
-			} else if candle.High >= pos.TakeProfit && pos.TakeProfit > 0 {
-				exitPrice = pos.TakeProfit
-				exitReason = "take_profit"
+		// Copy: closing a unit removes it from e.positions[symbol]
+		for _, pos := range append([]*Position(nil), units...) {
+			var exitPrice float64
+			var exitReason string
+
+			// The exchange liquidates once the loss eats the margin down to maintenance
+			if liq := liquidationPrice(pos, e.getProduct(symbol)); liquidationHit(pos, liq, candle) {
+				e.closeUnit(pos, liq, ts, "liquidation", candle)
+				continue
 			}
-		} else {
-			// Short position
-			if candle.High >= pos.StopLoss && pos.StopLoss > 0 {
-				exitPrice = pos.StopLoss
-				exitReason = "stop_loss"
-			} else if candle.Low <= pos.TakeProfit && pos.TakeProfit > 0 {
-				exitPrice = pos.TakeProfit
-				exitReason = "take_profit"
+
+			if pos.Side == "buy" {
+				// Long position
+				if candle.Low <= pos.StopLoss && pos.StopLoss > 0 {
+					exitPrice = pos.StopLoss
+					exitReason = "stop_loss"
+				} else if candle.High >= pos.TakeProfit && pos.TakeProfit > 0 {
+					exitPrice = pos.TakeProfit
+					exitReason = "take_profit"
+				}
+			} else {
+				// Short position
+				if candle.High >= pos.StopLoss && pos.StopLoss > 0 {
+					exitPrice = pos.StopLoss
+					exitReason = "stop_loss"
+				} else if candle.Low <= pos.TakeProfit && pos.TakeProfit > 0 {
+					exitPrice = pos.TakeProfit
+					exitReason = "take_profit"
+				}
 			}
-		}
 
-		if exitReason != "" {
-			e.closePosition(symbol, exitPrice, ts, exitReason)
+			if exitReason != "" {
+				e.closeUnit(pos, exitPrice, ts, exitReason, candle)
+			}
 		}
 	}
 }
 
 // processSignalAtPrice handles a trading signal at a specific fill price
-func (e *Engine) processSignalAtPrice(symbol string, signal strategy.Signal, candle *delta.Candle, ts time.Time, fillPrice float64) {
+func (e *Engine) processSignalAtPrice(symbol string, signal strategy.Signal, context map[string]float64, candle *delta.Candle, ts time.Time, fillPrice float64) {
 	// Check if we have an existing position
-	existingPos := e.positions[symbol]
+	units := e.positions[symbol]
+	frozen := e.config.EventFreeze.Active(ts)
 
 	switch signal.Action {
 	case strategy.ActionBuy, strategy.ActionSell:
-		if existingPos != nil {
-			// Already have a position - check if same direction
-			if (signal.Action == strategy.ActionBuy && existingPos.Side == "buy") ||
-				(signal.Action == strategy.ActionSell && existingPos.Side == "sell") {
-				return // Same direction, ignore
+		if len(units) == 0 && (frozen || e.inStopCooldown(symbol, ts) || e.inReversalCooldown(symbol, ts)) {
+			return
+		}
+		if len(units) > 0 {
+			// Already have a position - same direction adds a unit while under the cap
+			if (signal.Action == strategy.ActionBuy && units[0].Side == "buy") ||
+				(signal.Action == strategy.ActionSell && units[0].Side == "sell") {
+				if !frozen && len(units) < e.config.MaxUnitsPerSymbol {
+					e.openPositionAtPrice(symbol, signal, context, candle, ts, fillPrice)
+				}
+				return
 			}
 			// Opposite direction - close first
 			e.closePositionAtPrice(symbol, fillPrice, ts, "signal_reversal", candle)
+			if !e.config.AllowReversal {
+				e.lastReversal[symbol] = ts
+				return
+			}
+			if frozen {
+				return // Closing is allowed in a freeze, entering the other side is not
+			}
 		}
 		// Open new position
-		e.openPositionAtPrice(symbol, signal, candle, ts, fillPrice)
+		e.openPositionAtPrice(symbol, signal, context, candle, ts, fillPrice)
 
 	case strategy.ActionClose:
-		if existingPos != nil {
+		if len(units) > 0 {
 			e.closePositionAtPrice(symbol, fillPrice, ts, "signal_close", candle)
 		}
 	}
 }
 
+// flattenForEventFreeze closes every open unit at this bar's open
+func (e *Engine) flattenForEventFreeze(ts time.Time) {
+	for _, symbol := range sortedSymbols(e.positions) {
+		candle := e.getCandleAt(symbol, ts)
+		if candle == nil {
+			continue
+		}
+		e.closePositionAtPrice(symbol, candle.Open, ts, "event_freeze", candle)
+	}
+}
+
+// inStopCooldown reports whether symbol was stopped out less than StopCooldown before ts
+func (e *Engine) inStopCooldown(symbol string, ts time.Time) bool {
+	stoppedAt, ok := e.lastStopLoss[symbol]
+	return ok && e.config.StopCooldown > 0 && ts.Sub(stoppedAt) < e.config.StopCooldown
+}
+
+// inReversalCooldown reports whether symbol was closed on an opposite signal, with reversals
+// disabled, on this bar or less than ReversalCooldown before ts
+func (e *Engine) inReversalCooldown(symbol string, ts time.Time) bool {
+	closedAt, ok := e.lastReversal[symbol]
+	return ok && (ts.Equal(closedAt) || ts.Sub(closedAt) < e.config.ReversalCooldown)
+}
+
 // openPositionAtPrice opens a new position at a specific fill price
-func (e *Engine) openPositionAtPrice(symbol string, signal strategy.Signal, candle *delta.Candle, ts time.Time, fillPrice float64) {
+func (e *Engine) openPositionAtPrice(symbol string, signal strategy.Signal, context map[string]float64, candle *delta.Candle, ts time.Time, fillPrice float64) {
 	// 1. Calculate position size in contracts based on equity and risk
 	contracts := e.calculatePositionSize(symbol, fillPrice, signal.StopLoss)
 	if contracts <= 0 {
@@ -424,18 +601,22 @@ func (e *Engine) openPositionAtPrice(symbol string, signal strategy.Signal, cand
 		return // Not enough margin
 	}
 
-	// 4. Calculate slippage based on ACTUAL size (use notional for slippage model)
-	slippageAmt := e.slippage.Calculate(signal.Side, notional, *candle, 0)
-	actualEntryPrice := ApplySlippage(fillPrice, slippageAmt, signal.Side)
+	// 4. Cross the spread, then apply slippage based on ACTUAL size (use notional for slippage model)
+	halfSpread := e.halfSpread(fillPrice)
+	actualEntryPrice, slippageAmt := e.slippedPrice(symbol, fillPrice, halfSpread, e.slippage.Calculate(signal.Side, notional, *candle, 0), signal.Side)
 
-	// 5. Calculate fee based on notional
-	fee := CalculateFee(actualEntryPrice, notional, 1.0, e.config.TakerFeeBps)
+	// 5. Calculate fee based on notional, at the volume tier reached before this fill
+	_, takerBps := e.config.feeRates(e.tradedVolume)
+	fee := CalculateFee(actualEntryPrice, notional, 1.0, takerBps)
+	e.tradedVolume += notional
 
 	// 6. Reserve margin
 	e.usedMargin += requiredMargin
 
 	// Store size as contracts (int converted to float64 for Position struct compatibility)
+	e.nextPosID++
 	pos := &Position{
+		ID:            e.nextPosID,
 		Symbol:        symbol,
 		Side:          signal.Side,
 		Size:          float64(contracts), // Store contracts as Size
@@ -446,23 +627,47 @@ func (e *Engine) openPositionAtPrice(symbol string, signal strategy.Signal, cand
 		InitialMargin: requiredMargin,
 		EntryFee:      fee,
 		EntrySlip:     slippageAmt,
+		EntrySpread:   halfSpread * (notional / fillPrice),
+		Context:       context,
 	}
 
-	e.positions[symbol] = pos
+	e.positions[symbol] = append(e.positions[symbol], pos)
 	e.equity -= fee
+	e.emitPositionOpen(pos)
 }
 
-// closePosition closes an existing position (used by checkExits)
-func (e *Engine) closePosition(symbol string, exitPrice float64, ts time.Time, reason string) {
-	candle := e.getCandleAt(symbol, ts)
-	e.closePositionAtPrice(symbol, exitPrice, ts, reason, candle)
+// closeOpenPositions closes every open position at the bar close (or last known price)
+func (e *Engine) closeOpenPositions(ts time.Time) {
+	for _, symbol := range sortedSymbols(e.positions) {
+		candle := e.getCandleAt(symbol, ts)
+		exitPrice := 0.0
+		if candle != nil {
+			exitPrice = candle.Close
+		} else if last, ok := e.lastPrice[symbol]; ok {
+			exitPrice = last
+		}
+		for _, pos := range append([]*Position(nil), e.positions[symbol]...) {
+			price := exitPrice
+			if price == 0 {
+				price = pos.EntryPrice
+			}
+			e.closeUnit(pos, price, ts, "end_of_backtest", candle)
+		}
+	}
 }
 
-// closePositionAtPrice closes an existing position at a specific fill price
+// closePositionAtPrice closes every unit open on symbol at a specific fill price
 func (e *Engine) closePositionAtPrice(symbol string, exitPrice float64, ts time.Time, reason string, candle *delta.Candle) {
-	pos := e.positions[symbol]
-	if pos == nil {
-		return
+	for _, pos := range append([]*Position(nil), e.positions[symbol]...) {
+		e.closeUnit(pos, exitPrice, ts, reason, candle)
+	}
+}
+
+// closeUnit closes one open unit at a specific fill price and records its trade
+func (e *Engine) closeUnit(pos *Position, exitPrice float64, ts time.Time, reason string, candle *delta.Candle) {
+	symbol := pos.Symbol
+	if reason == "stop_loss" {
+		e.lastStopLoss[symbol] = ts
 	}
 
 	// Release margin
@@ -481,39 +686,41 @@ func (e *Engine) closePositionAtPrice(symbol string, exitPrice float64, ts time.
 	// Convert to notional for slippage and fee calculations
 	entryNotional, _ := delta.ContractsToNotional(contracts, pos.EntryPrice, product)
 
+	halfSpread := e.halfSpread(exitPrice)
 	slippageAmt := 0.0
 	if candle != nil && entryNotional > 0 {
 		slippageAmt = e.slippage.Calculate(exitSide, entryNotional, *candle, 0)
 	}
-	actualExitPrice := ApplySlippage(exitPrice, slippageAmt, exitSide)
+	actualExitPrice, slippageAmt := e.slippedPrice(symbol, exitPrice, halfSpread, slippageAmt, exitSide)
 
 	// Calculate exit notional and fee
 	exitNotional, _ := delta.ContractsToNotional(contracts, actualExitPrice, product)
-	exitFee := CalculateFee(actualExitPrice, exitNotional, 1.0, e.config.TakerFeeBps)
+	_, takerBps := e.config.feeRates(e.tradedVolume)
+	exitFee := CalculateFee(actualExitPrice, exitNotional, 1.0, takerBps)
+	e.tradedVolume += exitNotional
 
-	// Calculate P&L based on notional difference
-	// For linear futures: PnL = contracts * contractValue * (exitPrice - entryPrice) * direction
+	// Gross P&L in USD: contracts * contractValue * (exitPrice - entryPrice), negated for shorts
 	cv, _ := delta.ParseContractValue(product)
-	priceDiff := actualExitPrice - pos.EntryPrice
-
-	// For a long: +price = +profit, for short: +price = -profit
-	multiplier := 1.0
-	if pos.Side == "sell" {
-		multiplier = -1.0
-	}
-
-	// Gross P&L in USD
-	grossPnL := float64(contracts) * cv * priceDiff * multiplier
+	grossPnL := delta.ComputeUnrealizedPnL(pos.Side, contracts, pos.EntryPrice, actualExitPrice, product)
 
 	// Calculate slippage cost in dollars
 	entrySlipCost := pos.EntrySlip * (entryNotional / pos.EntryPrice)
 	exitSlipCost := slippageAmt * (exitNotional / actualExitPrice)
+	exitSpreadCost := halfSpread * float64(contracts) * cv
 
 	// NetPnL includes ALL costs: exit fee, slippage costs
 	// Note: EntryFee was deducted from equity at entry time
 	// FundingPaid was also applied to equity in processFunding()
 	netPnL := grossPnL - exitFee - entrySlipCost - exitSlipCost
 
+	// Excursions over the bars held, also expressed in R against the initial stop
+	mae, mfe := priceExcursion(pos.Side, pos.EntryPrice, actualExitPrice, pos.EntryTime, ts, e.candles[symbol])
+	maeR, mfeR := 0.0, 0.0
+	if riskDist := absFloat(pos.EntryPrice - pos.StopLoss); pos.StopLoss > 0 && riskDist > 0 {
+		maeR = mae / riskDist
+		mfeR = mfe / riskDist
+	}
+
 	// Record trade
 	trade := Trade{
 		ID:            fmt.Sprintf("%s-%d", symbol, len(e.trades)),
@@ -530,18 +737,71 @@ func (e *Engine) closePositionAtPrice(symbol string, exitPrice float64, ts time.
 		ExitSlip:      slippageAmt,
 		EntrySlipCost: entrySlipCost,
 		ExitSlipCost:  exitSlipCost,
-		FundingPaid:   pos.FundingPaid,
-		GrossPnL:      grossPnL,
-		NetPnL:        netPnL,
-		Reason:        reason,
+
+		EntrySpreadCost: pos.EntrySpread,
+		ExitSpreadCost:  exitSpreadCost,
+		FundingPaid:     pos.FundingPaid,
+		GrossPnL:        grossPnL,
+		NetPnL:          netPnL,
+		Reason:          reason,
+
+		MaxAdverseExcursion:   mae,
+		MaxFavorableExcursion: mfe,
+		MAER:                  maeR,
+		MFER:                  mfeR,
+
+		Context: pos.Context,
 	}
 	e.trades = append(e.trades, trade)
+	e.emitTrade(trade)
 
 	// Update equity
 	e.equity += netPnL
 
-	// Remove position
-	delete(e.positions, symbol)
+	// Remove the unit
+	e.removeUnit(pos)
+}
+
+// removeUnit drops pos from its symbol's open units
+func (e *Engine) removeUnit(pos *Position) {
+	units := e.positions[pos.Symbol]
+	for i, u := range units {
+		if u.ID == pos.ID {
+			units = append(units[:i:i], units[i+1:]...)
+			break
+		}
+	}
+	if len(units) == 0 {
+		delete(e.positions, pos.Symbol)
+		return
+	}
+	e.positions[pos.Symbol] = units
+}
+
+// halfSpread returns the half bid/ask spread in price units around mid
+func (e *Engine) halfSpread(mid float64) float64 {
+	return mid * e.config.AssumedSpreadBps / 2 / 10000
+}
+
+// slippedPrice crosses the spread and applies slippage to price, clamping the fill to at
+// least one tick. It returns the fill and the slippage actually applied after clamping.
+func (e *Engine) slippedPrice(symbol string, price, halfSpread, slippage float64, side string) (float64, float64) {
+	tick, _ := delta.ParseTickSize(e.getProduct(symbol))
+	quoted := ApplySlippage(price, halfSpread, side)
+	fill := ClampFillPrice(ApplySlippage(quoted, slippage, side), price, tick)
+	applied := fill - quoted
+	if side != "buy" {
+		applied = -applied
+	}
+	return fill, math.Max(applied, 0)
+}
+
+// cappedSlippage returns the configured slippage model limited to MaxSlippageBps
+func cappedSlippage(config Config) SlippageModel {
+	if config.SlippageModel == nil || config.MaxSlippageBps <= 0 {
+		return config.SlippageModel
+	}
+	return &CappedSlippage{Base: config.SlippageModel, MaxBps: config.MaxSlippageBps}
 }
 
 // calculateRequiredMargin calculates initial margin for a position
@@ -624,7 +884,8 @@ func (e *Engine) updateEquityCurve(ts time.Time) {
 	// Calculate mark-to-market equity
 	totalEquity := e.equity
 
-	for symbol, pos := range e.positions {
+	for _, symbol := range sortedSymbols(e.positions) {
+		units := e.positions[symbol]
 		candle := e.getCandleAt(symbol, ts)
 		var markPrice float64
 		if candle != nil {
@@ -633,19 +894,26 @@ func (e *Engine) updateEquityCurve(ts time.Time) {
 		} else if lastPrice, ok := e.lastPrice[symbol]; ok {
 			// Use last known price if no candle at this timestamp
 			markPrice = lastPrice
-		} else {
-			// Fallback to entry price if no price history
-			markPrice = pos.EntryPrice
 		}
 
 		// Get contract value from product
 		product := e.getProduct(symbol)
-		cv, err := delta.ParseContractValue(product)
-		if err != nil {
-			cv = 0.001 // Default to BTC contract value
+		if _, err := delta.ParseContractValue(product); err != nil {
+			if !e.warnedCV[symbol] {
+				fmt.Printf("Warning: %v for %s, defaulting contract value to 0.001\n", err, symbol)
+				e.warnedCV[symbol] = true
+			}
+			product = &delta.Product{Symbol: symbol, ContractValue: "0.001"} // Default to BTC contract value
 		}
 
-		totalEquity += pos.UnrealizedPnL(markPrice, cv)
+		for _, pos := range units {
+			price := markPrice
+			if price == 0 {
+				// Fallback to entry price if no price history
+				price = pos.EntryPrice
+			}
+			totalEquity += pos.UnrealizedPnL(price, product)
+		}
 	}
 
 	// Update peak
@@ -659,11 +927,13 @@ func (e *Engine) updateEquityCurve(ts time.Time) {
 		drawdown = (e.peakEquity - totalEquity) / e.peakEquity
 	}
 
-	e.equityCurve = append(e.equityCurve, EquityPoint{
+	point := EquityPoint{
 		Timestamp: ts,
 		Equity:    totalEquity,
 		Drawdown:  drawdown,
-	})
+	}
+	e.equityCurve = append(e.equityCurve, point)
+	e.emitEquityPoint(point)
 }
 
 // Helper methods
@@ -714,8 +984,13 @@ func (e *Engine) buildMarketFeatures(symbol string, candle *delta.Candle, candle
 		ticker.FundingRate = GetFundingAtTime(e.fundingRates[symbol], ts)
 	}
 
+	var orderbook *delta.Orderbook
+	if e.config.OrderbookSource != nil {
+		orderbook = e.config.OrderbookSource.Snapshot(symbol, *candle, ts)
+	}
+
 	// Use features engine
-	return e.featuresEngine.ComputeFeaturesWithFunding(nil, ticker, candles)
+	return e.featuresEngine.ComputeFeaturesWithFunding(orderbook, ticker, candles)
 }
 
 func absFloat(x float64) float64 {
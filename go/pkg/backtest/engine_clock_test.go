@@ -0,0 +1,45 @@
+package backtest
+
+import (
+	"testing"
+	"time"
+
+	"github.com/kasyap/delta-go/go/pkg/delta"
+	"github.com/kasyap/delta-go/go/pkg/features"
+	"github.com/kasyap/delta-go/go/pkg/strategy"
+)
+
+// clockedStrategy records the strategy clock's time on every Analyze call
+type clockedStrategy struct {
+	clock strategy.Clock
+	seen  []time.Time
+}
+
+func (s *clockedStrategy) Name() string                               { return "clocked" }
+func (s *clockedStrategy) UpdateParams(params map[string]interface{}) {}
+func (s *clockedStrategy) SetClock(c strategy.Clock)                  { s.clock = c }
+
+func (s *clockedStrategy) Analyze(f features.MarketFeatures, candles []delta.Candle) strategy.Signal {
+	s.seen = append(s.seen, s.clock.Now())
+	return strategy.Signal{Action: strategy.ActionNone}
+}
+
+func TestEngine_AdvancesStrategyClock(t *testing.T) {
+	candles, _ := dipThenRallyCandles()
+	e := newTestEngine(candles, nil)
+	clocked := &clockedStrategy{clock: strategy.RealClock{}}
+	e.strategyMgr = strategy.NewManager()
+	e.RegisterStrategy(clocked)
+
+	if _, err := e.runLoaded(); err != nil {
+		t.Fatalf("run: %v", err)
+	}
+	if len(clocked.seen) != len(candles) {
+		t.Fatalf("analyzed %d bars, want %d", len(clocked.seen), len(candles))
+	}
+	for i, got := range clocked.seen {
+		if want := time.Unix(candles[i].Time, 0); !got.Equal(want) {
+			t.Errorf("bar %d: clock = %v, want bar time %v", i, got, want)
+		}
+	}
+}
@@ -0,0 +1,59 @@
+package backtest
+
+import (
+	"time"
+
+	"github.com/kasyap/delta-go/go/pkg/delta"
+	"github.com/kasyap/delta-go/go/pkg/features"
+	"github.com/kasyap/delta-go/go/pkg/strategy"
+)
+
+// scriptedStrategy emits a pre-programmed signal on the Nth call to Analyze
+type scriptedStrategy struct {
+	calls   int
+	signals map[int]strategy.Signal
+}
+
+func (s *scriptedStrategy) Name() string { return "scripted" }
+
+func (s *scriptedStrategy) UpdateParams(params map[string]interface{}) {}
+
+func (s *scriptedStrategy) Analyze(f features.MarketFeatures, candles []delta.Candle) strategy.Signal {
+	sig, ok := s.signals[s.calls]
+	s.calls++
+	if !ok {
+		return strategy.Signal{Action: strategy.ActionNone}
+	}
+	return sig
+}
+
+// newTestEngine builds an engine over in-memory BTCUSD candles with zero slippage and no funding
+func newTestEngine(candles []delta.Candle, signals map[int]strategy.Signal) *Engine {
+	cfg := DefaultConfig()
+	cfg.Symbols = []string{"BTCUSD"}
+	cfg.SimulateFunding = false
+	cfg.SlippageModel = NewFixedSlippage(0)
+	cfg.LatencyMs = 0
+
+	e := NewEngine(cfg, nil)
+	e.candles["BTCUSD"] = candles
+	e.RegisterStrategy(&scriptedStrategy{signals: signals})
+	return e
+}
+
+// dipThenRallyCandles: buy signal on bar 0 fills at bar 1 open (50000), the trade dips
+// to 49500, rallies to 51000 and is closed at bar 3 open (50800)
+func dipThenRallyCandles() ([]delta.Candle, map[int]strategy.Signal) {
+	base := time.Date(2024, 1, 1, 1, 0, 0, 0, time.UTC).Unix()
+	candles := []delta.Candle{
+		{Time: base, Open: 50000, High: 50050, Low: 49950, Close: 50000},
+		{Time: base + 300, Open: 50000, High: 50100, Low: 49500, Close: 49900},
+		{Time: base + 600, Open: 49900, High: 51000, Low: 49900, Close: 50800},
+		{Time: base + 900, Open: 50800, High: 52000, Low: 48000, Close: 50900},
+	}
+	signals := map[int]strategy.Signal{
+		0: {Action: strategy.ActionBuy, Side: "buy"},
+		2: {Action: strategy.ActionClose},
+	}
+	return candles, signals
+}
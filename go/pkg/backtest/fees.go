@@ -0,0 +1,22 @@
+package backtest
+
+// FeeTier is a volume discount level: once cumulative traded notional reaches MinVolume,
+// fills pay the tier's rates instead of Config.MakerFeeBps/TakerFeeBps
+type FeeTier struct {
+	MinVolume   float64 // Traded notional in USD, entries and exits both count
+	MakerFeeBps float64
+	TakerFeeBps float64
+}
+
+// feeRates returns the maker and taker bps in effect after volume USD of traded notional:
+// those of the highest tier reached, or the base rates below every tier
+func (c Config) feeRates(volume float64) (maker, taker float64) {
+	maker, taker = c.MakerFeeBps, c.TakerFeeBps
+	reached := -1.0
+	for _, t := range c.FeeTiers {
+		if volume >= t.MinVolume && t.MinVolume > reached {
+			maker, taker, reached = t.MakerFeeBps, t.TakerFeeBps, t.MinVolume
+		}
+	}
+	return maker, taker
+}
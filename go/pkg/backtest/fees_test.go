@@ -0,0 +1,77 @@
+package backtest
+
+import (
+	"math"
+	"testing"
+
+	"github.com/kasyap/delta-go/go/pkg/delta"
+	"github.com/kasyap/delta-go/go/pkg/strategy"
+)
+
+func TestEngine_FeeTierLowersLaterFees(t *testing.T) {
+	signals := map[int]strategy.Signal{
+		1:  {Action: strategy.ActionBuy, Side: "buy"},
+		4:  {Action: strategy.ActionClose},
+		8:  {Action: strategy.ActionBuy, Side: "buy"},
+		11: {Action: strategy.ActionClose},
+	}
+	product := delta.MockProduct("BTCUSD")
+	bps := func(fee, price, size float64) float64 {
+		notional, _ := delta.ContractsToNotional(int(size), price, product)
+		return fee / notional * 10000
+	}
+
+	// Flat fees first, to size the tier so the first round trip reaches it exactly
+	flat := newTestEngine(trendCandles(16), signals)
+	if err := flat.simulate(); err != nil {
+		t.Fatalf("simulate: %v", err)
+	}
+	if len(flat.trades) != 2 {
+		t.Fatalf("got %d trades, want 2", len(flat.trades))
+	}
+	first := flat.trades[0]
+	entryNotional, _ := delta.ContractsToNotional(int(first.Size), first.EntryPrice, product)
+	exitNotional, _ := delta.ContractsToNotional(int(first.Size), first.ExitPrice, product)
+
+	e := newTestEngine(trendCandles(16), signals)
+	e.config.FeeTiers = []FeeTier{
+		{MinVolume: 1e12, MakerFeeBps: 0, TakerFeeBps: 0}, // Never reached
+		{MinVolume: entryNotional + exitNotional, MakerFeeBps: 1, TakerFeeBps: 3},
+	}
+	if err := e.simulate(); err != nil {
+		t.Fatalf("simulate: %v", err)
+	}
+	if len(e.trades) != 2 {
+		t.Fatalf("got %d trades, want 2", len(e.trades))
+	}
+
+	want := []struct{ entry, exit float64 }{{5, 5}, {3, 3}}
+	for i, tr := range e.trades {
+		entry := bps(tr.EntryFee, tr.EntryPrice, tr.Size)
+		exit := bps(tr.ExitFee, tr.ExitPrice, tr.Size)
+		if math.Abs(entry-want[i].entry) > 1e-9 || math.Abs(exit-want[i].exit) > 1e-9 {
+			t.Errorf("trade %d fees = %.4f/%.4f bps, want %.0f/%.0f", i, entry, exit, want[i].entry, want[i].exit)
+		}
+	}
+	if e.trades[1].EntryFee >= flat.trades[1].EntryFee {
+		t.Errorf("second entry fee %.6f not below the flat-fee run's %.6f", e.trades[1].EntryFee, flat.trades[1].EntryFee)
+	}
+}
+
+func TestConfig_FeeRates(t *testing.T) {
+	cfg := Config{MakerFeeBps: 2, TakerFeeBps: 5, FeeTiers: []FeeTier{
+		{MinVolume: 5e6, MakerFeeBps: 0, TakerFeeBps: 2},
+		{MinVolume: 1e6, MakerFeeBps: 1, TakerFeeBps: 4},
+	}}
+	cases := []struct{ volume, maker, taker float64 }{
+		{0, 2, 5},
+		{999999, 2, 5},
+		{1e6, 1, 4},
+		{7e6, 0, 2},
+	}
+	for _, c := range cases {
+		if maker, taker := cfg.feeRates(c.volume); maker != c.maker || taker != c.taker {
+			t.Errorf("feeRates(%.0f) = %v/%v, want %v/%v", c.volume, maker, taker, c.maker, c.taker)
+		}
+	}
+}
@@ -0,0 +1,65 @@
+package backtest
+
+import (
+	"testing"
+	"time"
+
+	"github.com/kasyap/delta-go/go/pkg/delta"
+	"github.com/kasyap/delta-go/go/pkg/risk"
+	"github.com/kasyap/delta-go/go/pkg/strategy"
+)
+
+// fundingCandles returns flat 5m bars from 07:40 to 08:30 UTC, around the 08:00 funding
+func fundingCandles() []delta.Candle {
+	base := time.Date(2024, 1, 1, 7, 40, 0, 0, time.UTC).Unix()
+	var candles []delta.Candle
+	for i := 0; i <= 10; i++ {
+		candles = append(candles, delta.Candle{Time: base + int64(i*300), Open: 50000, High: 50010, Low: 49990, Close: 50000})
+	}
+	return candles
+}
+
+func TestEngine_EventFreezeBlocksEntriesAroundFunding(t *testing.T) {
+	tests := []struct {
+		name      string
+		signalBar int // Fills on the next bar's open
+		wantEntry bool
+	}{
+		{"fill at 07:45, outside window", 0, true},
+		{"fill at 07:55, before funding", 2, false},
+		{"fill at 08:10, window edge", 5, false},
+		{"fill at 08:15, after window", 6, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e := newTestEngine(fundingCandles(), map[int]strategy.Signal{
+				tt.signalBar: {Action: strategy.ActionBuy, Side: "buy"},
+			})
+			e.config.EventFreeze = risk.EventFreeze{Window: 10 * time.Minute, Funding: true}
+			if err := e.simulate(); err != nil {
+				t.Fatal(err)
+			}
+			if got := len(e.trades) == 1; got != tt.wantEntry {
+				t.Errorf("entered = %v (%d trades), want %v", got, len(e.trades), tt.wantEntry)
+			}
+		})
+	}
+}
+
+func TestEngine_EventFreezeFlattens(t *testing.T) {
+	e := newTestEngine(fundingCandles(), map[int]strategy.Signal{0: {Action: strategy.ActionBuy, Side: "buy"}})
+	e.config.EventFreeze = risk.EventFreeze{Window: 10 * time.Minute, Funding: true}
+	e.config.FlattenOnEventFreeze = true
+	if err := e.simulate(); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(e.trades) != 1 {
+		t.Fatalf("got %d trades, want 1", len(e.trades))
+	}
+	want := time.Date(2024, 1, 1, 7, 50, 0, 0, time.UTC)
+	if tr := e.trades[0]; tr.Reason != "event_freeze" || !tr.ExitTime.Equal(want) {
+		t.Errorf("exit = %s at %v, want event_freeze at %v", tr.Reason, tr.ExitTime, want)
+	}
+}
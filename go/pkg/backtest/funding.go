@@ -1,8 +1,10 @@
 package backtest
 
 import (
+	"encoding/binary"
 	"encoding/json"
 	"fmt"
+	"hash/fnv"
 	"io"
 	"net/http"
 	"os"
@@ -10,12 +12,15 @@ import (
 	"sort"
 	"strconv"
 	"time"
+
+	"github.com/kasyap/delta-go/go/pkg/delta"
 )
 
 // FundingFetcher fetches historical funding rates from external sources
 type FundingFetcher struct {
 	cacheDir   string
 	httpClient *http.Client
+	seed       int64 // Synthetic rate variance, see SetSeed
 }
 
 // NewFundingFetcher creates a funding rate fetcher
@@ -28,6 +33,12 @@ func NewFundingFetcher(cacheDir string) *FundingFetcher {
 	}
 }
 
+// SetSeed sets the seed behind the variance of synthetic funding rates. Each rate is derived
+// from the seed, symbol and funding time alone, so overlapping ranges agree.
+func (f *FundingFetcher) SetSeed(seed int64) {
+	f.seed = seed
+}
+
 // FetchFundingRates fetches historical funding rates for a symbol
 // It tries multiple sources: Coinglass, Binance (as proxy for market funding)
 func (f *FundingFetcher) FetchFundingRates(symbol string, start, end time.Time) ([]FundingRate, error) {
@@ -38,7 +49,7 @@ func (f *FundingFetcher) FetchFundingRates(symbol string, start, end time.Time)
 	}
 
 	// Map symbol to external symbol format
-	externalSymbol := mapToExternalSymbol(symbol)
+	externalSymbol := delta.DefaultSymbols.ToBinance(symbol)
 
 	// Try Binance funding rates (free, no API key required)
 	rates, err := f.fetchFromBinance(externalSymbol, start, end)
@@ -69,20 +80,6 @@ func sortFundingRates(rates []FundingRate) {
 	})
 }
 
-// mapToExternalSymbol converts Delta symbols to exchange symbols
-func mapToExternalSymbol(symbol string) string {
-	switch symbol {
-	case "BTCUSD", "BTCINR":
-		return "BTCUSDT"
-	case "ETHUSD", "ETHINR":
-		return "ETHUSDT"
-	case "SOLUSD", "SOLINR":
-		return "SOLUSDT"
-	default:
-		return symbol
-	}
-}
-
 // fetchFromBinance fetches funding rates from Binance Futures API
 func (f *FundingFetcher) fetchFromBinance(symbol string, start, end time.Time) ([]FundingRate, error) {
 	var allRates []FundingRate
@@ -202,19 +199,18 @@ func (f *FundingFetcher) generateSyntheticRates(symbol string, start, end time.T
 
 	// Base rate varies by asset (BTC tends to have higher funding)
 	baseRate := 0.0001 // 0.01% per 8h (typical)
-	switch symbol {
-	case "BTCUSD", "BTCINR":
+	switch delta.DefaultSymbols.Underlying(symbol) {
+	case "BTC":
 		baseRate = 0.00015
-	case "ETHUSD", "ETHINR":
+	case "ETH":
 		baseRate = 0.0001
-	case "SOLUSD", "SOLINR":
+	case "SOL":
 		baseRate = 0.00008
 	}
 
 	for current.Before(end) {
-		// Add some variance (+/- 50% of base rate)
-		// Using deterministic variance based on timestamp for reproducibility
-		variance := (float64(current.Unix()%100) - 50) / 100 * baseRate * 0.5
+		// Add some variance (+/- 50% of base rate), seeded for reproducibility
+		variance := syntheticNoise(f.seed, symbol, current) * baseRate * 0.5
 		rate := baseRate + variance
 
 		rates = append(rates, FundingRate{
@@ -229,6 +225,17 @@ func (f *FundingFetcher) generateSyntheticRates(symbol string, start, end time.T
 	return rates
 }
 
+// syntheticNoise maps (seed, symbol, t) to a value in [-1, 1)
+func syntheticNoise(seed int64, symbol string, t time.Time) float64 {
+	h := fnv.New64a()
+	var buf [16]byte
+	binary.LittleEndian.PutUint64(buf[:8], uint64(seed))
+	binary.LittleEndian.PutUint64(buf[8:], uint64(t.Unix()))
+	h.Write(buf[:])
+	h.Write([]byte(symbol))
+	return float64(h.Sum64()>>11)/(1<<52) - 1
+}
+
 // Cache methods
 func (f *FundingFetcher) cacheFilePath(symbol string, start, end time.Time) string {
 	filename := fmt.Sprintf("funding_%s_%s_%s.json",
@@ -264,7 +271,7 @@ func (f *FundingFetcher) saveToCache(symbol string, start, end time.Time, rates
 		return err
 	}
 
-	return os.WriteFile(path, data, 0644)
+	return writeFileAtomic(path, data)
 }
 
 // GetFundingAtTime finds the applicable funding rate at a given time
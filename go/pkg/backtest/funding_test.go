@@ -1,8 +1,13 @@
 package backtest
 
 import (
+	"math"
 	"testing"
 	"time"
+
+	"github.com/kasyap/delta-go/go/pkg/delta"
+	"github.com/kasyap/delta-go/go/pkg/features"
+	"github.com/kasyap/delta-go/go/pkg/strategy"
 )
 
 func TestIsFundingTime(t *testing.T) {
@@ -76,21 +81,145 @@ func TestGetFundingAtTime(t *testing.T) {
 	}
 }
 
-func TestMapToExternalSymbol(t *testing.T) {
+func TestEngine_ShortCarryEarnsFundingAndClosesAtEnd(t *testing.T) {
+	base := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	candles := make([]delta.Candle, 13) // 00:00 - 12:00 hourly, crosses the 08:00 funding
+	for i := range candles {
+		candles[i] = delta.Candle{Time: base.Add(time.Duration(i) * time.Hour).Unix(), Open: 50000, High: 50000, Low: 50000, Close: 50000}
+	}
+	e := newTestEngine(candles, map[int]strategy.Signal{
+		0: {Action: strategy.ActionSell, Side: "sell"},
+	})
+	e.config.SimulateFunding = true
+	e.fundingRates["BTCUSD"] = []FundingRate{{Timestamp: base, Symbol: "BTCUSD", Rate: 0.001}}
+
+	if err := e.simulate(); err != nil {
+		t.Fatalf("simulate() error = %v", err)
+	}
+
+	if len(e.positions) != 0 {
+		t.Fatalf("expected open positions to be closed at the final bar, got %d", len(e.positions))
+	}
+	if len(e.trades) != 1 || e.trades[0].Reason != "end_of_backtest" {
+		t.Fatalf("expected one end_of_backtest trade, got %+v", e.trades)
+	}
+
+	tr := e.trades[0]
+	notional := tr.Size * 0.001 * 50000 // BTCUSD contract value 0.001
+	metrics := NewMetricsCalculator(e.config).Calculate(e.trades, e.equityCurve)
+	if math.Abs(metrics.FundingPnL-notional*0.001) > 1e-9 || metrics.FundingPnL <= 0 {
+		t.Errorf("FundingPnL = %.6f, want %.6f", metrics.FundingPnL, notional*0.001)
+	}
+	if metrics.PricePnL != 0 {
+		t.Errorf("PricePnL = %.6f, want 0 on a flat market", metrics.PricePnL)
+	}
+}
+
+func TestFundingBoundaries(t *testing.T) {
+	day := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
 	tests := []struct {
-		input    string
-		expected string
+		name       string
+		prev, curr time.Time
+		want       []time.Time
 	}{
-		{"BTCUSD", "BTCUSDT"},
-		{"ETHUSD", "ETHUSDT"},
-		{"SOLUSD", "SOLUSDT"},
-		{"UNKNOWN", "UNKNOWN"},
+		{"no crossing", day.Add(time.Hour), day.Add(2 * time.Hour), nil},
+		{"lands on boundary", day.Add(7 * time.Hour), day.Add(8 * time.Hour), []time.Time{day.Add(8 * time.Hour)}},
+		{"starts on boundary", day.Add(8 * time.Hour), day.Add(9 * time.Hour), nil},
+		{"gap spans two", day.Add(7 * time.Hour), day.Add(17 * time.Hour), []time.Time{day.Add(8 * time.Hour), day.Add(16 * time.Hour)}},
+		{"midnight", day.Add(23 * time.Hour), day.Add(25 * time.Hour), []time.Time{day.Add(24 * time.Hour)}},
 	}
 
 	for _, tt := range tests {
-		result := mapToExternalSymbol(tt.input)
-		if result != tt.expected {
-			t.Errorf("mapToExternalSymbol(%s) = %s, expected %s", tt.input, result, tt.expected)
+		got := fundingBoundaries(tt.prev, tt.curr)
+		if len(got) != len(tt.want) {
+			t.Errorf("%s: got %v, want %v", tt.name, got, tt.want)
+			continue
+		}
+		for i := range got {
+			if !got[i].Equal(tt.want[i]) {
+				t.Errorf("%s: boundary %d = %v, want %v", tt.name, i, got[i], tt.want[i])
+			}
+		}
+		if crossedFundingBoundary(tt.prev, tt.curr) != (len(tt.want) > 0) {
+			t.Errorf("%s: crossedFundingBoundary disagrees with fundingBoundaries", tt.name)
+		}
+	}
+}
+
+func TestEngine_FundingSignAndRatePerBoundary(t *testing.T) {
+	base := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	// Hourly bars from 01:00 to 01:00 the next day with a data gap from 07:00 to 17:00,
+	// so a single step crosses both the 08:00 and 16:00 boundaries
+	var candles []delta.Candle
+	for h := 1; h <= 25; h++ {
+		if h > 6 && h < 17 {
+			continue
+		}
+		candles = append(candles, delta.Candle{Time: base.Add(time.Duration(h) * time.Hour).Unix(), Open: 50000, High: 50000, Low: 50000, Close: 50000})
+	}
+	// Exchange timestamps trail the boundary by a few ms; the 00:00 rate predates entry
+	late := 5 * time.Millisecond
+	rates := []FundingRate{
+		{Timestamp: base, Symbol: "BTCUSD", Rate: 0.01},
+		{Timestamp: base.Add(8*time.Hour + late), Symbol: "BTCUSD", Rate: 0.001},
+		{Timestamp: base.Add(16*time.Hour + late), Symbol: "BTCUSD", Rate: -0.0005},
+		{Timestamp: base.Add(24*time.Hour + late), Symbol: "BTCUSD", Rate: 0.002},
+	}
+	const netRate = 0.001 - 0.0005 + 0.002
+
+	for _, side := range []string{"buy", "sell"} {
+		action := strategy.ActionBuy
+		if side == "sell" {
+			action = strategy.ActionSell
+		}
+		e := newTestEngine(candles, map[int]strategy.Signal{0: {Action: action, Side: side}})
+		e.config.SimulateFunding = true
+		e.fundingRates["BTCUSD"] = rates
+
+		if err := e.simulate(); err != nil {
+			t.Fatalf("%s: simulate() error = %v", side, err)
+		}
+		if len(e.trades) != 1 {
+			t.Fatalf("%s: expected one trade, got %d", side, len(e.trades))
 		}
+
+		tr := e.trades[0]
+		notional := tr.Size * 0.001 * 50000 // BTCUSD contract value 0.001
+		want := notional * netRate          // long pays on net positive funding
+		if side == "sell" {
+			want = -want // short receives
+		}
+		if math.Abs(tr.FundingPaid-want) > 1e-9 {
+			t.Errorf("%s: FundingPaid = %.6f, want %.6f", side, tr.FundingPaid, want)
+		}
+	}
+}
+
+// The -strategy all run registers a StrategySelector; its funding strategy must hold
+// positions on bar time, not the wall clock
+func TestEngine_StrategySelectorUsesBarClock(t *testing.T) {
+	cfg := DefaultConfig()
+	cfg.Symbols = []string{"BTCUSD"}
+	cfg.SimulateFunding = false
+	e := NewEngine(cfg, nil)
+	e.progress = nil
+	e.candles["BTCUSD"] = trendCandles(20)
+
+	funding := strategy.NewFundingArbitrageStrategy(strategy.DefaultFundingArbitrageConfig())
+	e.RegisterStrategy(strategy.NewStrategySelector(
+		strategy.NewFeeAwareScalper(strategy.DefaultScalperConfig(), e.FeaturesEngine()),
+		funding,
+		strategy.NewGridTradingStrategy(strategy.DefaultGridConfig(), "BTCUSD"),
+		nil,
+	))
+	if _, err := e.runLoaded(); err != nil {
+		t.Fatalf("runLoaded() error = %v", err)
+	}
+
+	funding.RecordEntry("BTCUSD", "sell", 0.20, 0)
+	e.clock.Advance(25 * time.Hour)
+	sig := funding.Analyze(features.MarketFeatures{Symbol: "BTCUSD", BasisAnnualized: 0.20}, nil)
+	if sig.Action != strategy.ActionClose {
+		t.Errorf("expected max-holding close after 25h of bar time, got %v (%s)", sig.Action, sig.Reason)
 	}
 }
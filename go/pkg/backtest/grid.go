@@ -0,0 +1,257 @@
+package backtest
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/kasyap/delta-go/go/pkg/delta"
+	"github.com/kasyap/delta-go/go/pkg/strategy"
+)
+
+// GridStats is the grid-specific P&L of a resting-order grid simulation
+type GridStats struct {
+	Levels        []float64
+	Fills         int
+	RoundTrips    int
+	RealizedPnL   float64 // Completed round trips, net of maker fees
+	OpenUnits     int     // Levels still holding inventory at the end
+	UnrealizedPnL float64 // Open units marked at the final close, net of their entry fees
+	TotalFees     float64 // Maker fees on every fill, including open units
+}
+
+// GridResult is a grid simulation's standard metrics plus grid stats
+type GridResult struct {
+	Result
+	Grid GridStats
+}
+
+// gridOrder is a resting limit order at one grid level. Opening orders start a unit of
+// inventory; closing orders (counter orders) flatten the unit opened at entryPrice.
+type gridOrder struct {
+	level      int
+	side       string
+	size       float64
+	closes     bool
+	entryPrice float64
+	entryTime  time.Time
+	entryFee   float64
+}
+
+// SimulateGrid replays a neutral grid on one symbol's candles with resting limit orders.
+// Levels come from strategy.GridTradingStrategy centered on the first close; the level
+// nearest the center is left empty, buys rest below it and sells above. Orders fill at their
+// level price without slippage as FillLimitOrder decides for config.GridTimeInForce: GTC
+// orders fill in full when the bar's low (buys) or high (sells) reaches them and pay the
+// maker fee; IOC and FOK orders are re-sent every bar, fill what the bar allows, pay the
+// taker fee, and any unfilled quantity waits for the next bar. Filled quantity gets a
+// counter order one level away from the next bar: a buy at level i is closed by a sell at
+// i+1, a sell at i by a buy at i-1, and each close re-places the opening order. Funding is
+// not simulated.
+func SimulateGrid(config Config, symbol string, candles []delta.Candle, grid strategy.GridConfig) GridResult {
+	var res GridResult
+	if len(candles) < 2 || grid.GridLevels < 2 {
+		return res
+	}
+
+	product := delta.MockProduct(symbol)
+	if p, ok := config.Products[symbol]; ok {
+		product = p
+	}
+	cv, err := delta.ParseContractValue(product)
+	if err != nil {
+		fmt.Printf("Warning: %v for %s, defaulting contract value to 0.001\n", err, symbol)
+		cv = 0.001
+	}
+	size := float64(grid.PositionSizePerLevel)
+	if size <= 0 {
+		size = 1
+	}
+
+	center := candles[0].Close
+	levels := strategy.NewGridTradingStrategy(grid, symbol).CalculateLevels(center)
+	gap := 0
+	for i, l := range levels {
+		res.Grid.Levels = append(res.Grid.Levels, l.Price)
+		if math.Abs(l.Price-center) < math.Abs(levels[gap].Price-center) {
+			gap = i
+		}
+	}
+
+	var orders []gridOrder
+	for i := range levels {
+		switch {
+		case i < gap:
+			orders = append(orders, gridOrder{level: i, side: "buy", size: size})
+		case i > gap:
+			orders = append(orders, gridOrder{level: i, side: "sell", size: size})
+		}
+	}
+
+	tif := config.GridTimeInForce
+	taker := tif == TimeInForceIOC || tif == TimeInForceFOK
+	volume := 0.0 // Traded notional so far, selects the fee tier
+	fee := func(price, qty float64) float64 {
+		makerBps, feeBps := config.feeRates(volume)
+		if !taker {
+			feeBps = makerBps
+		}
+		notional := qty * cv * price
+		volume += notional
+		return CalculateFee(price, notional, 1.0, feeBps)
+	}
+
+	peak := config.InitialCapital
+	var equityCurve []EquityPoint
+	for _, c := range candles[1:] {
+		ts := time.Unix(c.Time, 0)
+		var resting, placed []gridOrder
+		for _, o := range orders {
+			price := levels[o.level].Price
+			qty := FillLimitOrder(tif, o.side, price, o.size, c)
+			if qty <= 0 {
+				resting = append(resting, o)
+				continue
+			}
+
+			// The unfilled remainder keeps its share of the entry fee and waits
+			entryFee := o.entryFee
+			if rest := o.size - qty; rest > 0 {
+				remainder := o
+				remainder.size = rest
+				remainder.entryFee = o.entryFee * rest / o.size
+				resting = append(resting, remainder)
+				entryFee -= remainder.entryFee
+			}
+
+			fillFee := fee(price, qty)
+			res.Grid.Fills++
+			res.Grid.TotalFees += fillFee
+
+			if o.closes {
+				// Closing side is opposite the unit's side
+				unitSide := "buy"
+				if o.side == "buy" {
+					unitSide = "sell"
+				}
+				gross := qty * cv * (price - o.entryPrice)
+				if unitSide == "sell" {
+					gross = -gross
+				}
+				net := gross - entryFee - fillFee
+				res.Grid.RoundTrips++
+				res.Grid.RealizedPnL += net
+				res.Trades = append(res.Trades, Trade{
+					ID:         fmt.Sprintf("%s-grid-%d", symbol, len(res.Trades)),
+					Symbol:     symbol,
+					Side:       unitSide,
+					Size:       qty,
+					EntryPrice: o.entryPrice,
+					EntryTime:  o.entryTime,
+					EntryFee:   entryFee,
+					ExitPrice:  price,
+					ExitTime:   ts,
+					ExitFee:    fillFee,
+					GrossPnL:   gross,
+					NetPnL:     net,
+					Reason:     "grid",
+				})
+
+				// Re-place the opening order the unit came from
+				openLevel := o.level - 1
+				if unitSide == "sell" {
+					openLevel = o.level + 1
+				}
+				placed = append(placed, gridOrder{level: openLevel, side: unitSide, size: qty})
+				continue
+			}
+
+			closeLevel, closeSide := o.level+1, "sell"
+			if o.side == "sell" {
+				closeLevel, closeSide = o.level-1, "buy"
+			}
+			if closeLevel < 0 || closeLevel >= len(levels) {
+				continue
+			}
+			placed = append(placed, gridOrder{
+				level:      closeLevel,
+				side:       closeSide,
+				size:       qty,
+				closes:     true,
+				entryPrice: price,
+				entryTime:  ts,
+				entryFee:   fillFee,
+			})
+		}
+		// Counter orders rest from the next bar, since the order of prices within a bar is unknown
+		orders = append(resting, placed...)
+
+		equity := config.InitialCapital + res.Grid.RealizedPnL + openUnitPnL(orders, cv, c.Close)
+		if equity > peak {
+			peak = equity
+		}
+		drawdown := 0.0
+		if peak > 0 {
+			drawdown = (peak - equity) / peak
+		}
+		equityCurve = append(equityCurve, EquityPoint{Timestamp: ts, Equity: equity, Drawdown: drawdown})
+	}
+
+	for _, o := range orders {
+		if o.closes {
+			res.Grid.OpenUnits++
+		}
+	}
+	res.Grid.UnrealizedPnL = openUnitPnL(orders, cv, candles[len(candles)-1].Close)
+
+	res.Metrics = NewMetricsCalculator(config).Calculate(res.Trades, equityCurve)
+	return res
+}
+
+// openUnitPnL marks the units held by resting closing orders at price, net of entry fees
+func openUnitPnL(orders []gridOrder, cv, price float64) float64 {
+	pnl := 0.0
+	for _, o := range orders {
+		if !o.closes {
+			continue
+		}
+		diff := price - o.entryPrice
+		if o.side == "buy" { // Short unit, closed by a buy
+			diff = -diff
+		}
+		pnl += o.size*cv*diff - o.entryFee
+	}
+	return pnl
+}
+
+// RunGridContext loads data if needed and runs SimulateGrid on one of the configured symbols
+func (e *Engine) RunGridContext(ctx context.Context, symbol string, grid strategy.GridConfig) (*GridResult, error) {
+	if len(e.candles) == 0 {
+		if err := e.loadData(ctx); err != nil {
+			return nil, fmt.Errorf("failed to load data: %w", err)
+		}
+	}
+	candles := e.candles[symbol]
+	if len(candles) < 2 {
+		return nil, fmt.Errorf("not enough %s candles for a grid simulation: %d", symbol, len(candles))
+	}
+
+	res := SimulateGrid(e.config, symbol, candles, grid)
+	return &res, nil
+}
+
+// FormatReport renders the standard metrics followed by grid stats
+func (r *GridResult) FormatReport() string {
+	report := r.Metrics.FormatReport()
+	report += "\n"
+	report += "GRID\n"
+	report += formatLine("  Levels", formatInt(len(r.Grid.Levels)))
+	report += formatLine("  Fills", formatInt(r.Grid.Fills))
+	report += formatLine("  Round Trips", formatInt(r.Grid.RoundTrips))
+	report += formatLine("  Realized P&L", formatMoney(r.Grid.RealizedPnL))
+	report += formatLine("  Open Units", formatInt(r.Grid.OpenUnits))
+	report += formatLine("  Unrealized P&L", formatMoney(r.Grid.UnrealizedPnL))
+	report += formatLine("  Grid Fees", formatMoney(r.Grid.TotalFees))
+	return report
+}
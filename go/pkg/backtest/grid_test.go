@@ -0,0 +1,78 @@
+package backtest
+
+import (
+	"math"
+	"testing"
+
+	"github.com/kasyap/delta-go/go/pkg/delta"
+	"github.com/kasyap/delta-go/go/pkg/strategy"
+)
+
+// sawtoothCandles starts at 100 and alternates a dip through 99 with a rally through 100
+func sawtoothCandles(cycles int) []delta.Candle {
+	candles := []delta.Candle{{Time: 0, Open: 100, High: 100, Low: 100, Close: 100}}
+	for i := 0; i < cycles; i++ {
+		ts := int64(len(candles)) * 300
+		candles = append(candles,
+			delta.Candle{Time: ts, Open: 99.7, High: 99.8, Low: 98.5, Close: 98.7},
+			delta.Candle{Time: ts + 300, Open: 99.3, High: 100.5, Low: 99.2, Close: 100.4},
+		)
+	}
+	return candles
+}
+
+func TestSimulateGrid_SawtoothRoundTrips(t *testing.T) {
+	config := Config{
+		InitialCapital: 1000,
+		MakerFeeBps:    2,
+		Products: map[string]*delta.Product{
+			"BTCUSD": {Symbol: "BTCUSD", ContractValue: "1", TickSize: "0.5"},
+		},
+	}
+	grid := strategy.GridConfig{GridLevels: 5, GridRangePct: 2, PositionSizePerLevel: 1}
+	const cycles = 6
+
+	res := SimulateGrid(config, "BTCUSD", sawtoothCandles(cycles), grid)
+
+	// Levels 98, 99, [100], 101, 102: each dip buys 99, each rally sells it at 100
+	if res.Grid.RoundTrips != cycles {
+		t.Fatalf("round trips = %d, want %d", res.Grid.RoundTrips, cycles)
+	}
+	if res.Grid.Fills != 2*cycles {
+		t.Errorf("fills = %d, want %d", res.Grid.Fills, 2*cycles)
+	}
+	if res.Grid.OpenUnits != 0 {
+		t.Errorf("open units = %d, want 0", res.Grid.OpenUnits)
+	}
+
+	perTrip := 1.0 - (99+100)*2.0/10000
+	if want := perTrip * cycles; math.Abs(res.Grid.RealizedPnL-want) > 1e-9 {
+		t.Errorf("realized P&L = %.6f, want %.6f", res.Grid.RealizedPnL, want)
+	}
+	if len(res.Trades) != cycles || res.Trades[0].Side != "buy" || res.Trades[0].EntryPrice != 99 || res.Trades[0].ExitPrice != 100 {
+		t.Errorf("unexpected trades: %+v", res.Trades)
+	}
+	if want := 1000 + perTrip*cycles; math.Abs(res.Metrics.FinalEquity-want) > 1e-9 {
+		t.Errorf("final equity = %.6f, want %.6f", res.Metrics.FinalEquity, want)
+	}
+}
+
+func TestSimulateGrid_OpenUnitMarkedToMarket(t *testing.T) {
+	config := Config{
+		InitialCapital: 1000,
+		Products: map[string]*delta.Product{
+			"BTCUSD": {Symbol: "BTCUSD", ContractValue: "1", TickSize: "0.5"},
+		},
+	}
+	grid := strategy.GridConfig{GridLevels: 5, GridRangePct: 2, PositionSizePerLevel: 1}
+
+	// One dip with no rally leaves the 99 unit open
+	res := SimulateGrid(config, "BTCUSD", sawtoothCandles(1)[:2], grid)
+
+	if res.Grid.RoundTrips != 0 || res.Grid.OpenUnits != 1 {
+		t.Fatalf("round trips = %d, open units = %d; want 0, 1", res.Grid.RoundTrips, res.Grid.OpenUnits)
+	}
+	if want := 98.7 - 99; math.Abs(res.Grid.UnrealizedPnL-want) > 1e-9 {
+		t.Errorf("unrealized P&L = %.4f, want %.4f", res.Grid.UnrealizedPnL, want)
+	}
+}
@@ -0,0 +1,43 @@
+package backtest
+
+import (
+	"testing"
+
+	"github.com/kasyap/delta-go/go/pkg/delta"
+	"github.com/kasyap/delta-go/go/pkg/features"
+	"github.com/kasyap/delta-go/go/pkg/strategy"
+)
+
+// longHistoryStrategy declares a warmup beyond strategy.DefaultHistory and records the
+// longest candle window it was handed
+type longHistoryStrategy struct {
+	required, longest int
+}
+
+func (s *longHistoryStrategy) Name() string                               { return "long-history" }
+func (s *longHistoryStrategy) UpdateParams(params map[string]interface{}) {}
+func (s *longHistoryStrategy) RequiredHistory() int                       { return s.required }
+
+func (s *longHistoryStrategy) Analyze(f features.MarketFeatures, candles []delta.Candle) strategy.Signal {
+	if len(candles) > s.longest {
+		s.longest = len(candles)
+	}
+	return strategy.Signal{Action: strategy.ActionNone}
+}
+
+func TestEngine_SuppliesRequiredHistory(t *testing.T) {
+	e := newTestEngine(trendCandles(350), nil)
+	long := &longHistoryStrategy{required: 300}
+	e.RegisterStrategy(long)
+	e.strategyMgr.SetDefaultStrategy(long.Name())
+
+	if got := e.strategyMgr.RequiredHistory(); got != 300 {
+		t.Fatalf("RequiredHistory() = %d, want 300", got)
+	}
+	if err := e.simulate(); err != nil {
+		t.Fatalf("simulate: %v", err)
+	}
+	if long.longest != 300 {
+		t.Errorf("strategy saw at most %d candles, want 300", long.longest)
+	}
+}
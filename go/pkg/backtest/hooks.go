@@ -0,0 +1,144 @@
+package backtest
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/kasyap/delta-go/go/pkg/delta"
+)
+
+// EventHook receives engine events for custom analytics without modifying the engine.
+// Hooks are invoked synchronously from the simulation loop.
+type EventHook interface {
+	OnTrade(trade Trade)
+	OnEquityPoint(point EquityPoint)
+	OnPositionOpen(pos *Position)
+}
+
+// AddHook registers an event hook with the engine
+func (e *Engine) AddHook(h EventHook) {
+	e.hooks = append(e.hooks, h)
+}
+
+// ProgressFunc receives simulation progress: percent of bars processed, realized equity and
+// trades closed so far. It is called from the simulation loop about every 10%.
+type ProgressFunc func(pct float64, equity float64, trades int)
+
+// printProgress is the default ProgressFunc
+func printProgress(pct, equity float64, trades int) {
+	fmt.Printf("  Progress: %.0f%% | Equity: $%.2f | Trades: %d\n", pct, equity, trades)
+}
+
+// SetProgressFunc replaces the default progress printout; nil silences it
+func (e *Engine) SetProgressFunc(f ProgressFunc) {
+	e.progress = f
+}
+
+// Candles returns the loaded candles for a symbol (useful as a hook price source)
+func (e *Engine) Candles(symbol string) []delta.Candle {
+	return e.candles[symbol]
+}
+
+func (e *Engine) emitTrade(t Trade) {
+	for _, h := range e.hooks {
+		h.OnTrade(t)
+	}
+}
+
+func (e *Engine) emitEquityPoint(p EquityPoint) {
+	for _, h := range e.hooks {
+		h.OnEquityPoint(p)
+	}
+}
+
+func (e *Engine) emitPositionOpen(pos *Position) {
+	for _, h := range e.hooks {
+		h.OnPositionOpen(pos)
+	}
+}
+
+// ---------------------- MAE/MFE Recorder ----------------------
+
+// TradeExcursion holds the maximum adverse/favorable price excursion of a trade
+type TradeExcursion struct {
+	TradeID string
+	Symbol  string
+	Side    string
+	MAE     float64 // Max adverse move from entry in price units (>= 0)
+	MFE     float64 // Max favorable move from entry in price units (>= 0)
+	NetPnL  float64
+}
+
+// MAEMFERecorder is an EventHook that computes maximum adverse/favorable excursion per trade
+type MAEMFERecorder struct {
+	mu         sync.Mutex
+	candles    func(symbol string) []delta.Candle
+	excursions []TradeExcursion
+}
+
+// NewMAEMFERecorder creates a recorder that reads held-bar prices from the candle source
+// (typically Engine.Candles)
+func NewMAEMFERecorder(candles func(symbol string) []delta.Candle) *MAEMFERecorder {
+	return &MAEMFERecorder{candles: candles}
+}
+
+func (r *MAEMFERecorder) OnPositionOpen(pos *Position) {}
+
+func (r *MAEMFERecorder) OnEquityPoint(point EquityPoint) {}
+
+func (r *MAEMFERecorder) OnTrade(t Trade) {
+	mae, mfe := priceExcursion(t.Side, t.EntryPrice, t.ExitPrice, t.EntryTime, t.ExitTime, r.candles(t.Symbol))
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.excursions = append(r.excursions, TradeExcursion{
+		TradeID: t.ID,
+		Symbol:  t.Symbol,
+		Side:    t.Side,
+		MAE:     mae,
+		MFE:     mfe,
+		NetPnL:  t.NetPnL,
+	})
+}
+
+// Excursions returns the recorded per-trade excursions
+func (r *MAEMFERecorder) Excursions() []TradeExcursion {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	result := make([]TradeExcursion, len(r.excursions))
+	copy(result, r.excursions)
+	return result
+}
+
+// priceExcursion returns the maximum adverse and favorable moves from the entry price over
+// the bars held, located by binary search in the time-sorted candles. Bars from the entry
+// bar up to (but excluding) the exit bar contribute their full range; the exit bar
+// contributes only the exit price since the position was gone for the rest of that bar.
+func priceExcursion(side string, entryPrice, exitPrice float64, entryTime, exitTime time.Time, candles []delta.Candle) (mae, mfe float64) {
+	entryTs, exitTs := entryTime.Unix(), exitTime.Unix()
+	first := sort.Search(len(candles), func(i int) bool { return candles[i].Time >= entryTs })
+	last := sort.Search(len(candles), func(i int) bool { return candles[i].Time >= exitTs })
+
+	high, low := max(entryPrice, exitPrice), min(entryPrice, exitPrice)
+	for _, c := range candles[first:max(first, last)] {
+		high = max(high, c.High)
+		low = min(low, c.Low)
+	}
+
+	if side == "sell" {
+		mae = high - entryPrice
+		mfe = entryPrice - low
+	} else {
+		mae = entryPrice - low
+		mfe = high - entryPrice
+	}
+	if mae < 0 {
+		mae = 0
+	}
+	if mfe < 0 {
+		mfe = 0
+	}
+	return mae, mfe
+}
@@ -0,0 +1,80 @@
+package backtest
+
+import (
+	"testing"
+	"time"
+
+	"github.com/kasyap/delta-go/go/pkg/delta"
+)
+
+type countingHook struct {
+	trades, points, opens int
+}
+
+func (h *countingHook) OnTrade(Trade)             { h.trades++ }
+func (h *countingHook) OnEquityPoint(EquityPoint) { h.points++ }
+func (h *countingHook) OnPositionOpen(*Position)  { h.opens++ }
+
+func TestEngine_HooksInvoked(t *testing.T) {
+	candles, signals := dipThenRallyCandles()
+	e := newTestEngine(candles, signals)
+	hook := &countingHook{}
+	e.AddHook(hook)
+
+	if err := e.simulate(); err != nil {
+		t.Fatalf("simulate() error = %v", err)
+	}
+
+	if hook.opens != 1 || hook.trades != 1 {
+		t.Errorf("expected 1 open and 1 trade, got opens=%d trades=%d", hook.opens, hook.trades)
+	}
+	if hook.points != len(candles) {
+		t.Errorf("expected %d equity points, got %d", len(candles), hook.points)
+	}
+}
+
+func TestMAEMFERecorder(t *testing.T) {
+	candles, signals := dipThenRallyCandles()
+	e := newTestEngine(candles, signals)
+	rec := NewMAEMFERecorder(e.Candles)
+	e.AddHook(rec)
+
+	if err := e.simulate(); err != nil {
+		t.Fatalf("simulate() error = %v", err)
+	}
+
+	ex := rec.Excursions()
+	if len(ex) != 1 {
+		t.Fatalf("expected 1 excursion, got %d", len(ex))
+	}
+	// Exit bar's extreme range (48000-52000) must not count
+	if ex[0].MAE != 500 {
+		t.Errorf("MAE = %.2f, want 500", ex[0].MAE)
+	}
+	if ex[0].MFE != 1000 {
+		t.Errorf("MFE = %.2f, want 1000", ex[0].MFE)
+	}
+}
+
+func TestPriceExcursion_MeasuresFromEntryOverHeldBars(t *testing.T) {
+	base := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	candles := []delta.Candle{
+		{Time: base.Unix(), High: 40000, Low: 30000}, // Before entry
+		{Time: base.Add(5 * time.Minute).Unix(), High: 50400, Low: 49800},
+		{Time: base.Add(10 * time.Minute).Unix(), High: 50600, Low: 49900},
+		{Time: base.Add(15 * time.Minute).Unix(), High: 52000, Low: 49000}, // Exit bar
+		{Time: base.Add(20 * time.Minute).Unix(), High: 60000, Low: 45000}, // After exit
+	}
+
+	// Long from 50000 stopped at 49500 inside the exit bar
+	mae, mfe := priceExcursion("buy", 50000, 49500, base.Add(5*time.Minute), base.Add(15*time.Minute), candles)
+	if mae != 500 || mfe != 600 {
+		t.Errorf("long MAE, MFE = %.0f, %.0f; want 500, 600", mae, mfe)
+	}
+
+	// Short entered and exited within one bar: only entry and exit prices count
+	mae, mfe = priceExcursion("sell", 50000, 49900, base.Add(15*time.Minute), base.Add(15*time.Minute), candles)
+	if mae != 0 || mfe != 100 {
+		t.Errorf("same-bar short MAE, MFE = %.0f, %.0f; want 0, 100", mae, mfe)
+	}
+}
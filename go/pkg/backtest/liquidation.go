@@ -0,0 +1,46 @@
+package backtest
+
+import (
+	"github.com/kasyap/delta-go/go/pkg/delta"
+)
+
+// liquidationPrice is the price at which a unit's margin, less its unrealized loss, falls to
+// the product's maintenance margin. Each unit is margined in isolation. Returns 0 when the
+// product has no usable maintenance margin or contract value, or the price would be <= 0.
+func liquidationPrice(pos *Position, product *delta.Product) float64 {
+	mmPct, err := delta.ParseMaintenanceMargin(product)
+	if err != nil || mmPct <= 0 {
+		return 0
+	}
+	cv, err := delta.ParseContractValue(product)
+	if err != nil || cv <= 0 || pos.Size <= 0 {
+		return 0
+	}
+
+	units := pos.Size * cv
+	maintenance := units * pos.EntryPrice * mmPct / 100
+	distance := (pos.InitialMargin - maintenance) / units
+	if distance < 0 {
+		distance = 0
+	}
+
+	if pos.Side == "sell" {
+		return pos.EntryPrice + distance
+	}
+	if liq := pos.EntryPrice - distance; liq > 0 {
+		return liq
+	}
+	return 0
+}
+
+// liquidationHit reports whether the bar reaches the unit's liquidation price before its
+// stop: a stop at or inside the liquidation price fills first
+func liquidationHit(pos *Position, liq float64, c *delta.Candle) bool {
+	if liq <= 0 {
+		return false
+	}
+	if pos.Side == "sell" {
+		return c.High >= liq && (pos.StopLoss <= 0 || pos.StopLoss > liq)
+	}
+	return c.Low <= liq && (pos.StopLoss <= 0 || pos.StopLoss < liq)
+}
@@ -0,0 +1,86 @@
+package backtest
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"github.com/kasyap/delta-go/go/pkg/delta"
+	"github.com/kasyap/delta-go/go/pkg/strategy"
+)
+
+// liquidationCandles: a long entered at 50000 with a 5% stop, then a 2% flush that stays
+// well above the stop
+func liquidationCandles() ([]delta.Candle, map[int]strategy.Signal) {
+	base := time.Date(2024, 1, 1, 1, 0, 0, 0, time.UTC).Unix()
+	candles := []delta.Candle{
+		{Time: base, Open: 50000, High: 50050, Low: 49950, Close: 50000},
+		{Time: base + 300, Open: 50000, High: 50100, Low: 49900, Close: 50000},
+		{Time: base + 600, Open: 50000, High: 50000, Low: 49000, Close: 49500},
+		{Time: base + 900, Open: 49500, High: 50500, Low: 49400, Close: 50400},
+	}
+	signals := map[int]strategy.Signal{
+		0: {Action: strategy.ActionBuy, Side: "buy", StopLoss: 47500},
+	}
+	return candles, signals
+}
+
+func TestEngine_LiquidatesOverLeveragedPositionBeforeStop(t *testing.T) {
+	candles, signals := liquidationCandles()
+	e := newTestEngine(candles, signals)
+	e.config.Leverage = 100 // 1% initial margin against BTCUSD's 0.5% maintenance margin
+	if err := e.simulate(); err != nil {
+		t.Fatalf("simulate() error = %v", err)
+	}
+
+	if len(e.trades) != 1 {
+		t.Fatalf("trades = %d, want 1", len(e.trades))
+	}
+	tr := e.trades[0]
+	if tr.Reason != "liquidation" {
+		t.Fatalf("exit reason = %q, want liquidation", tr.Reason)
+	}
+	// Margin 1% of notional, maintenance 0.5%: liquidated 0.5% below entry, far above the stop
+	if want := tr.EntryPrice * 0.995; math.Abs(tr.ExitPrice-want) > 1 {
+		t.Errorf("exit price = %.2f, want liquidation price %.2f", tr.ExitPrice, want)
+	}
+	if tr.ExitTime.Unix() != candles[2].Time {
+		t.Errorf("liquidated at %v, want the flush bar", tr.ExitTime)
+	}
+	if tr.GrossPnL >= 0 {
+		t.Errorf("gross PnL = %.2f, want a loss", tr.GrossPnL)
+	}
+	if e.usedMargin > 1e-9 {
+		t.Errorf("used margin = %.4f after liquidation, want released", e.usedMargin)
+	}
+}
+
+func TestEngine_StopFillsBeforeLiquidationAtModerateLeverage(t *testing.T) {
+	candles, signals := liquidationCandles()
+	signals[0] = strategy.Signal{Action: strategy.ActionBuy, Side: "buy", StopLoss: 49200}
+	e := newTestEngine(candles, signals)
+	e.config.Leverage = 10 // Liquidation 9.5% away, beyond the stop
+	if err := e.simulate(); err != nil {
+		t.Fatalf("simulate() error = %v", err)
+	}
+
+	if len(e.trades) != 1 || e.trades[0].Reason != "stop_loss" {
+		t.Fatalf("trades = %+v, want one stop_loss exit", e.trades)
+	}
+}
+
+func TestLiquidationPrice(t *testing.T) {
+	product := &delta.Product{ContractValue: "0.001", MaintenanceMargin: "0.5"}
+	long := &Position{Side: "buy", Size: 1000, EntryPrice: 50000, InitialMargin: 5000} // 10x on 50000 notional
+	short := &Position{Side: "sell", Size: 1000, EntryPrice: 50000, InitialMargin: 5000}
+
+	if got := liquidationPrice(long, product); math.Abs(got-45250) > 1e-6 {
+		t.Errorf("long liquidation = %.2f, want 45250", got)
+	}
+	if got := liquidationPrice(short, product); math.Abs(got-54750) > 1e-6 {
+		t.Errorf("short liquidation = %.2f, want 54750", got)
+	}
+	if got := liquidationPrice(long, &delta.Product{ContractValue: "0.001"}); got != 0 {
+		t.Errorf("liquidation without maintenance margin = %.2f, want 0 (off)", got)
+	}
+}
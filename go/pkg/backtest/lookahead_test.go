@@ -0,0 +1,66 @@
+package backtest
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/kasyap/delta-go/go/pkg/delta"
+	"github.com/kasyap/delta-go/go/pkg/features"
+	"github.com/kasyap/delta-go/go/pkg/strategy"
+)
+
+// priceRecorder records the spot price each Analyze call sees
+type priceRecorder struct {
+	seen []float64
+}
+
+func (s *priceRecorder) Name() string                               { return "price_recorder" }
+func (s *priceRecorder) UpdateParams(params map[string]interface{}) {}
+
+func (s *priceRecorder) Analyze(f features.MarketFeatures, candles []delta.Candle) strategy.Signal {
+	s.seen = append(s.seen, f.SpotPrice)
+	return strategy.Signal{Action: strategy.ActionNone}
+}
+
+func TestEngine_StrictNoLookaheadUsesLastClosedBar(t *testing.T) {
+	candles, _ := dipThenRallyCandles() // Closes: 50000, 49900, 50800, 50900
+
+	run := func(strict bool) []float64 {
+		e := newTestEngine(candles, nil)
+		e.config.StrictNoLookahead = strict
+		rec := &priceRecorder{}
+		e.strategyMgr = strategy.NewManager()
+		e.RegisterStrategy(rec)
+		if err := e.simulate(); err != nil {
+			t.Fatalf("simulate(strict=%v) error = %v", strict, err)
+		}
+		return rec.seen
+	}
+
+	if got, want := run(false), []float64{50000, 49900, 50800, 50900}; !reflect.DeepEqual(got, want) {
+		t.Errorf("default features = %v, want each bar's own close %v", got, want)
+	}
+	// The first bar has nothing closed before it; later bars see the previous close
+	if got, want := run(true), []float64{50000, 49900, 50800}; !reflect.DeepEqual(got, want) {
+		t.Errorf("strict features = %v, want previous closes %v", got, want)
+	}
+}
+
+func TestEngine_StrictNoLookaheadRejectsSameBarFill(t *testing.T) {
+	candles, signals := dipThenRallyCandles()
+	e := newTestEngine(candles, signals)
+	e.config.StrictNoLookahead = true
+	if err := e.simulate(); err != nil {
+		t.Fatalf("strict run with next-bar fills failed: %v", err)
+	}
+
+	ts := time.Unix(candles[1].Time, 0)
+	e.pendingOrders["BTCUSD"] = PendingOrder{Symbol: "BTCUSD", SignalTime: ts}
+	if err := e.checkNoLookahead(ts); err == nil {
+		t.Error("order filling on its own signal bar was not rejected")
+	}
+	if err := e.checkNoLookahead(ts.Add(5 * time.Minute)); err != nil {
+		t.Errorf("next-bar fill rejected: %v", err)
+	}
+}
@@ -27,6 +27,9 @@ type Metrics struct {
 	SharpeRatio    float64 // Risk-free rate assumed 0 for crypto
 	SortinoRatio   float64 // Downside deviation only
 	CalmarRatio    float64 // Return / MaxDrawdown
+	RecoveryFactor float64 // Net profit / largest peak-to-trough loss in dollars
+	AvgDrawdown    float64 // Mean depth of drawdown episodes, as decimal
+	UlcerIndex     float64 // RMS of the drawdown at every equity point, as decimal
 
 	// Trading statistics
 	TotalTrades    int
@@ -41,17 +44,78 @@ type Metrics struct {
 	AvgHoldingTime time.Duration
 	TradesPerDay   float64
 
+	// Excursions (as decimal fraction of entry price, 0.01 = 1%)
+	AvgMAE      float64
+	AvgMFE      float64
+	MAEWinRatio float64 // Avg MAE of winners / avg MAE of losers (low = stops can be tighter)
+
+	// P&L attribution
+	PricePnL   float64 // Sum of gross price P&L across trades
+	FundingPnL float64 // Net funding earned (positive) or paid (negative)
+
 	// Cost breakdown
-	TotalFees     float64
-	TotalSlippage float64
-	TotalFunding  float64
-	TotalCosts    float64
-	CostPct       float64 // Costs as % of gross profits
+	TotalFees       float64
+	TotalSlippage   float64
+	TotalSpreadCost float64 // Half-spread paid on each fill; already inside PricePnL
+	TotalFunding    float64
+	TotalCosts      float64
+	CostPct         float64 // Costs as % of gross profits
 
 	// Equity curve
 	EquityCurve []EquityPoint
 }
 
+// DownsampleEquity reduces the equity curve to at most maxPoints for plotting. It keeps the
+// first and last points and the min and max equity point of each bucket in between, so
+// drawdown troughs and peaks survive. maxPoints below 4 is treated as 4; a non-positive
+// value or a curve that already fits returns a copy of the full curve.
+func (m *Metrics) DownsampleEquity(maxPoints int) []EquityPoint {
+	curve := m.EquityCurve
+	if maxPoints <= 0 || len(curve) <= maxPoints {
+		result := make([]EquityPoint, len(curve))
+		copy(result, curve)
+		return result
+	}
+	if maxPoints < 4 {
+		maxPoints = 4
+	}
+
+	inner := curve[1 : len(curve)-1]
+	buckets := (maxPoints - 2) / 2
+	result := make([]EquityPoint, 0, maxPoints)
+	result = append(result, curve[0])
+
+	for b := 0; b < buckets; b++ {
+		start := b * len(inner) / buckets
+		end := (b + 1) * len(inner) / buckets
+		if start >= end {
+			continue
+		}
+
+		minIdx, maxIdx := start, start
+		for i := start + 1; i < end; i++ {
+			if inner[i].Equity < inner[minIdx].Equity {
+				minIdx = i
+			}
+			if inner[i].Equity > inner[maxIdx].Equity {
+				maxIdx = i
+			}
+		}
+
+		// Emit in time order
+		switch {
+		case minIdx == maxIdx:
+			result = append(result, inner[minIdx])
+		case minIdx < maxIdx:
+			result = append(result, inner[minIdx], inner[maxIdx])
+		default:
+			result = append(result, inner[maxIdx], inner[minIdx])
+		}
+	}
+
+	return append(result, curve[len(curve)-1])
+}
+
 // MetricsCalculator computes performance metrics from trades
 type MetricsCalculator struct {
 	config       Config
@@ -95,6 +159,7 @@ func (mc *MetricsCalculator) Calculate(trades []Trade, equityCurve []EquityPoint
 	m.SharpeRatio = mc.computeSharpe()
 	m.SortinoRatio = mc.computeSortino()
 	m.CalmarRatio = mc.computeCalmar(m.AnnualizedReturn, m.MaxDrawdown)
+	mc.computeDrawdownStats(&m)
 
 	// Trading stats
 	mc.computeTradingStats(&m)
@@ -284,6 +349,46 @@ func (mc *MetricsCalculator) computeCalmar(annualizedReturn, maxDrawdown float64
 	return annualizedReturn / maxDrawdown
 }
 
+// computeDrawdownStats derives recovery factor, average drawdown and Ulcer index from the
+// equity curve. An episode runs from a peak until equity makes a new high.
+func (mc *MetricsCalculator) computeDrawdownStats(m *Metrics) {
+	if len(mc.equityCurve) == 0 {
+		return
+	}
+
+	peak := mc.equityCurve[0].Equity
+	maxLoss, episodeDepth, depthSum, sqSum := 0.0, 0.0, 0.0, 0.0
+	episodes := 0
+	for _, point := range mc.equityCurve {
+		if point.Equity >= peak {
+			if episodeDepth > 0 {
+				depthSum += episodeDepth
+				episodes++
+				episodeDepth = 0
+			}
+			peak = point.Equity
+			continue
+		}
+
+		maxLoss = math.Max(maxLoss, peak-point.Equity)
+		dd := (peak - point.Equity) / peak
+		episodeDepth = math.Max(episodeDepth, dd)
+		sqSum += dd * dd
+	}
+	if episodeDepth > 0 { // Still under water at the end
+		depthSum += episodeDepth
+		episodes++
+	}
+
+	if episodes > 0 {
+		m.AvgDrawdown = depthSum / float64(episodes)
+	}
+	m.UlcerIndex = math.Sqrt(sqSum / float64(len(mc.equityCurve)))
+	if maxLoss > 0 {
+		m.RecoveryFactor = (m.FinalEquity - m.InitialCapital) / maxLoss
+	}
+}
+
 func (mc *MetricsCalculator) computeTradingStats(m *Metrics) {
 	if len(mc.trades) == 0 {
 		return
@@ -294,10 +399,24 @@ func (mc *MetricsCalculator) computeTradingStats(m *Metrics) {
 	var grossProfit, grossLoss float64
 	var totalWin, totalLoss float64
 	var holdingSum time.Duration
+	var maeSum, mfeSum, winMAESum, lossMAESum float64
 
 	for _, t := range mc.trades {
 		holdingSum += t.ExitTime.Sub(t.EntryTime)
 
+		maePct, mfePct := 0.0, 0.0
+		if t.EntryPrice > 0 {
+			maePct = t.MaxAdverseExcursion / t.EntryPrice
+			mfePct = t.MaxFavorableExcursion / t.EntryPrice
+		}
+		maeSum += maePct
+		mfeSum += mfePct
+		if t.NetPnL > 0 {
+			winMAESum += maePct
+		} else {
+			lossMAESum += maePct
+		}
+
 		if t.NetPnL > 0 {
 			m.WinningTrades++
 			grossProfit += t.NetPnL
@@ -318,6 +437,14 @@ func (mc *MetricsCalculator) computeTradingStats(m *Metrics) {
 	if m.TotalTrades > 0 {
 		m.WinRate = float64(m.WinningTrades) / float64(m.TotalTrades)
 		m.AvgHoldingTime = holdingSum / time.Duration(m.TotalTrades)
+		m.AvgMAE = maeSum / float64(m.TotalTrades)
+		m.AvgMFE = mfeSum / float64(m.TotalTrades)
+	}
+
+	if m.WinningTrades > 0 && m.LosingTrades > 0 && lossMAESum > 0 {
+		avgWinMAE := winMAESum / float64(m.WinningTrades)
+		avgLossMAE := lossMAESum / float64(m.LosingTrades)
+		m.MAEWinRatio = avgWinMAE / avgLossMAE
 	}
 
 	if m.WinningTrades > 0 {
@@ -342,9 +469,12 @@ func (mc *MetricsCalculator) computeCosts(m *Metrics) {
 		m.TotalFees += t.EntryFee + t.ExitFee
 		// Use slippage COSTS (in dollars), not slippage price deltas
 		m.TotalSlippage += t.EntrySlipCost + t.ExitSlipCost
+		m.TotalSpreadCost += t.EntrySpreadCost + t.ExitSpreadCost
 		m.TotalFunding += t.FundingPaid
+		m.PricePnL += t.GrossPnL
 	}
-	m.TotalCosts = m.TotalFees + m.TotalSlippage + m.TotalFunding
+	m.FundingPnL = -m.TotalFunding
+	m.TotalCosts = m.TotalFees + m.TotalSlippage + m.TotalSpreadCost + m.TotalFunding
 
 	// Gross profit (before costs)
 	grossProfit := 0.0
@@ -382,6 +512,9 @@ func formatMetricsReport(m *Metrics) string {
 	report += formatLine("  Sharpe Ratio", formatFloat(m.SharpeRatio))
 	report += formatLine("  Sortino Ratio", formatFloat(m.SortinoRatio))
 	report += formatLine("  Calmar Ratio", formatFloat(m.CalmarRatio))
+	report += formatLine("  Recovery Factor", formatFloat(m.RecoveryFactor))
+	report += formatLine("  Avg Drawdown", pct(m.AvgDrawdown))
+	report += formatLine("  Ulcer Index", pct(m.UlcerIndex))
 	report += "\n"
 
 	report += "TRADING STATS\n"
@@ -391,11 +524,19 @@ func formatMetricsReport(m *Metrics) string {
 	report += formatLine("  Avg Win", formatMoney(m.AvgWin))
 	report += formatLine("  Avg Loss", formatMoney(m.AvgLoss))
 	report += formatLine("  Trades/Day", formatFloat(m.TradesPerDay))
+	report += formatLine("  Avg MAE", pct(m.AvgMAE))
+	report += formatLine("  Avg MFE", pct(m.AvgMFE))
+	report += "\n"
+
+	report += "PNL ATTRIBUTION\n"
+	report += formatLine("  Price P&L", formatMoney(m.PricePnL))
+	report += formatLine("  Funding P&L", formatMoney(m.FundingPnL))
 	report += "\n"
 
 	report += "COSTS BREAKDOWN\n"
 	report += formatLine("  Total Fees", formatMoney(m.TotalFees))
 	report += formatLine("  Total Slippage", formatMoney(m.TotalSlippage))
+	report += formatLine("  Total Spread", formatMoney(m.TotalSpreadCost))
 	report += formatLine("  Total Funding", formatMoney(m.TotalFunding))
 	report += formatLine("  Total Costs", formatMoney(m.TotalCosts))
 
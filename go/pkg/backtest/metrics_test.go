@@ -1,8 +1,11 @@
 package backtest
 
 import (
+	"math"
 	"testing"
 	"time"
+
+	"github.com/kasyap/delta-go/go/pkg/strategy"
 )
 
 func TestMetricsCalculator_TotalReturn(t *testing.T) {
@@ -49,6 +52,36 @@ func TestMetricsCalculator_MaxDrawdown(t *testing.T) {
 	}
 }
 
+func TestMetricsCalculator_DrawdownStats(t *testing.T) {
+	config := DefaultConfig()
+	config.InitialCapital = 1000
+
+	mc := NewMetricsCalculator(config)
+
+	// Two episodes: 1000 -> 800 -> 1000 (20%), then 1250 -> 1000 -> 1100 (20%, unrecovered)
+	now := time.Now()
+	equity := []float64{1000, 800, 1000, 1250, 1000, 1100}
+	equityCurve := make([]EquityPoint, len(equity))
+	for i, e := range equity {
+		equityCurve[i] = EquityPoint{Timestamp: now.Add(time.Duration(i) * 24 * time.Hour), Equity: e}
+	}
+
+	metrics := mc.Calculate(nil, equityCurve)
+
+	// Drawdowns per point: 0, 0.2, 0, 0, 0.2, 0.12
+	wantUlcer := math.Sqrt((0.04 + 0.04 + 0.0144) / 6)
+	if absMetrics(metrics.UlcerIndex-wantUlcer) > 1e-9 {
+		t.Errorf("Ulcer index = %.6f, want %.6f", metrics.UlcerIndex, wantUlcer)
+	}
+	if absMetrics(metrics.AvgDrawdown-0.2) > 1e-9 {
+		t.Errorf("avg drawdown = %.4f, want 0.2", metrics.AvgDrawdown)
+	}
+	// Net profit 100 over the largest dollar loss, 1250 -> 1000
+	if absMetrics(metrics.RecoveryFactor-100.0/250.0) > 1e-9 {
+		t.Errorf("recovery factor = %.4f, want 0.4", metrics.RecoveryFactor)
+	}
+}
+
 func TestMetricsCalculator_WinRate(t *testing.T) {
 	config := DefaultConfig()
 	mc := NewMetricsCalculator(config)
@@ -133,3 +166,90 @@ func absMetrics(x float64) float64 {
 	}
 	return x
 }
+
+func TestEngine_TradeRecordsExcursions(t *testing.T) {
+	candles, signals := dipThenRallyCandles()
+	signals[0] = strategy.Signal{Action: strategy.ActionBuy, Side: "buy", StopLoss: 49000}
+	e := newTestEngine(candles, signals)
+
+	if err := e.simulate(); err != nil {
+		t.Fatalf("simulate() error = %v", err)
+	}
+	if len(e.trades) != 1 {
+		t.Fatalf("expected 1 trade, got %d", len(e.trades))
+	}
+
+	tr := e.trades[0]
+	if tr.NetPnL <= 0 {
+		t.Fatalf("expected a winning trade, got NetPnL %.4f", tr.NetPnL)
+	}
+	if tr.MaxAdverseExcursion != 500 {
+		t.Errorf("MAE = %.2f, want 500 (dip before winning)", tr.MaxAdverseExcursion)
+	}
+	if absMetrics(tr.MAER-0.5) > 1e-9 {
+		t.Errorf("MAE in R = %.4f, want 0.5 (500 / 1000 stop distance)", tr.MAER)
+	}
+}
+
+func TestMetricsCalculator_Excursions(t *testing.T) {
+	mc := NewMetricsCalculator(DefaultConfig())
+
+	trades := []Trade{
+		{EntryPrice: 100, NetPnL: 5, MaxAdverseExcursion: 1, MaxFavorableExcursion: 6},
+		{EntryPrice: 100, NetPnL: -3, MaxAdverseExcursion: 4, MaxFavorableExcursion: 2},
+	}
+	equityCurve := []EquityPoint{{Timestamp: time.Now(), Equity: 1002}}
+
+	m := mc.Calculate(trades, equityCurve)
+
+	if absMetrics(m.AvgMAE-0.025) > 1e-9 {
+		t.Errorf("AvgMAE = %.4f, want 0.025", m.AvgMAE)
+	}
+	if absMetrics(m.AvgMFE-0.04) > 1e-9 {
+		t.Errorf("AvgMFE = %.4f, want 0.04", m.AvgMFE)
+	}
+	if absMetrics(m.MAEWinRatio-0.25) > 1e-9 {
+		t.Errorf("MAEWinRatio = %.4f, want 0.25", m.MAEWinRatio)
+	}
+}
+
+func TestMetrics_DownsampleEquityKeepsExtremes(t *testing.T) {
+	base := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	curve := make([]EquityPoint, 10000)
+	for i := range curve {
+		// Gentle oscillation with one deep trough and one sharp peak
+		eq := 1000 + float64(i%50)
+		switch i {
+		case 3217:
+			eq = 400
+		case 8841:
+			eq = 2500
+		}
+		curve[i] = EquityPoint{Timestamp: base.Add(time.Duration(i) * time.Minute), Equity: eq}
+	}
+	m := Metrics{EquityCurve: curve}
+
+	points := m.DownsampleEquity(500)
+	if len(points) > 500 {
+		t.Fatalf("expected at most 500 points, got %d", len(points))
+	}
+	if points[0] != curve[0] || points[len(points)-1] != curve[len(curve)-1] {
+		t.Error("expected first and last points to be kept")
+	}
+
+	var sawMin, sawMax bool
+	for i, p := range points {
+		if i > 0 && !p.Timestamp.After(points[i-1].Timestamp) {
+			t.Fatalf("points not in time order at %d", i)
+		}
+		sawMin = sawMin || p.Equity == 400
+		sawMax = sawMax || p.Equity == 2500
+	}
+	if !sawMin || !sawMax {
+		t.Errorf("global extremes lost: min kept=%v max kept=%v", sawMin, sawMax)
+	}
+
+	if full := m.DownsampleEquity(0); len(full) != len(curve) {
+		t.Errorf("expected full curve for maxPoints=0, got %d points", len(full))
+	}
+}
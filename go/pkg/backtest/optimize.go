@@ -0,0 +1,267 @@
+package backtest
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math"
+	"os"
+	"runtime"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// Objective selects the metric used to rank parameter combinations
+type Objective string
+
+const (
+	ObjectiveSharpe Objective = "sharpe"
+	ObjectiveCalmar Objective = "calmar"
+	ObjectiveReturn Objective = "return"
+)
+
+// ParseObjective validates an objective name
+func ParseObjective(s string) (Objective, error) {
+	switch o := Objective(strings.ToLower(s)); o {
+	case ObjectiveSharpe, ObjectiveCalmar, ObjectiveReturn:
+		return o, nil
+	}
+	return "", fmt.Errorf("unknown objective %q (want sharpe, calmar or return)", s)
+}
+
+// Score extracts the objective value from metrics
+func (o Objective) Score(m Metrics) float64 {
+	switch o {
+	case ObjectiveCalmar:
+		return m.CalmarRatio
+	case ObjectiveReturn:
+		return m.TotalReturn
+	default:
+		return m.SharpeRatio
+	}
+}
+
+// ParamGrid maps strategy parameter names to the values to sweep
+type ParamGrid map[string][]interface{}
+
+// LoadParamGrid reads a JSON parameter grid, e.g. {"imbalance_threshold": [0.3, 0.5]}, with
+// keys routed as in Engine.UpdateStrategyParams
+func LoadParamGrid(path string) (ParamGrid, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read param grid: %v", err)
+	}
+	var grid ParamGrid
+	if err := json.Unmarshal(data, &grid); err != nil {
+		return nil, fmt.Errorf("failed to parse param grid: %v", err)
+	}
+	for name, values := range grid {
+		if len(values) == 0 {
+			return nil, fmt.Errorf("param %q has no values", name)
+		}
+	}
+	return grid, nil
+}
+
+// Combinations expands the grid into the cartesian product of parameter values.
+// Parameters are iterated in name order so the output is deterministic.
+func (g ParamGrid) Combinations() []map[string]interface{} {
+	names := make([]string, 0, len(g))
+	for name := range g {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	combos := []map[string]interface{}{{}}
+	for _, name := range names {
+		next := make([]map[string]interface{}, 0, len(combos)*len(g[name]))
+		for _, base := range combos {
+			for _, v := range g[name] {
+				combo := make(map[string]interface{}, len(base)+1)
+				for k, bv := range base {
+					combo[k] = bv
+				}
+				combo[name] = v
+				next = append(next, combo)
+			}
+		}
+		combos = next
+	}
+	return combos
+}
+
+// OptimizeConfig controls a parameter sweep
+type OptimizeConfig struct {
+	Objective Objective
+	TopN      int // Number of best combinations to report
+	Workers   int // Parallel backtests (0 = NumCPU)
+}
+
+// OptimizeResult is the outcome of one parameter combination
+type OptimizeResult struct {
+	Params  map[string]interface{}
+	Metrics Metrics
+	Score   float64
+}
+
+// OptimizeReport holds the ranked sweep results
+type OptimizeReport struct {
+	Objective    Objective
+	Combinations int
+	Failed       int
+	Top          []OptimizeResult
+}
+
+// Optimizer runs a backtest per parameter combination and ranks the results
+type Optimizer struct {
+	baseConfig    Config
+	optConfig     OptimizeConfig
+	engineFactory func(Config) *Engine
+}
+
+// NewOptimizer creates a grid-search optimizer; the factory builds a fresh engine with
+// strategies registered for each combination
+func NewOptimizer(baseConfig Config, optConfig OptimizeConfig, factory func(Config) *Engine) *Optimizer {
+	return &Optimizer{
+		baseConfig:    baseConfig,
+		optConfig:     optConfig,
+		engineFactory: factory,
+	}
+}
+
+// Run loads data once, backtests every combination on a worker pool and returns the
+// top-N by objective
+func (o *Optimizer) Run(grid ParamGrid) (*OptimizeReport, error) {
+	return o.RunContext(context.Background(), grid)
+}
+
+// RunContext is Run with cancellation; pending combinations are skipped once ctx is done
+func (o *Optimizer) RunContext(ctx context.Context, grid ParamGrid) (*OptimizeReport, error) {
+	if len(grid) == 0 {
+		return nil, fmt.Errorf("empty parameter grid")
+	}
+	combos := grid.Combinations()
+
+	// Load data once and share it read-only across all combinations
+	loader := o.engineFactory(o.baseConfig)
+	if len(loader.candles) == 0 {
+		if err := loader.loadData(ctx); err != nil {
+			return nil, fmt.Errorf("failed to load data: %w", err)
+		}
+	}
+
+	workers := o.optConfig.Workers
+	if workers <= 0 {
+		workers = runtime.NumCPU()
+	}
+
+	fmt.Printf("=== Parameter Optimization ===\n")
+	fmt.Printf("Combinations: %d | Workers: %d | Objective: %s\n\n", len(combos), workers, o.optConfig.Objective)
+
+	jobs := make(chan int)
+	results := make([]*OptimizeResult, len(combos))
+	var wg sync.WaitGroup
+
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range jobs {
+				engine := o.engineFactory(o.baseConfig)
+				engine.candles = loader.candles
+				engine.fundingRates = loader.fundingRates
+				engine.UpdateStrategyParams(combos[i])
+				engine.SetProgressFunc(nil) // Parallel runs would interleave their progress lines
+
+				res, err := engine.runLoaded()
+				if err != nil {
+					fmt.Printf("  Combination %v failed: %v\n", combos[i], err)
+					continue
+				}
+				results[i] = &OptimizeResult{
+					Params:  combos[i],
+					Metrics: res.Metrics,
+					Score:   o.optConfig.Objective.Score(res.Metrics),
+				}
+			}
+		}()
+	}
+feed:
+	for i := range combos {
+		select {
+		case <-ctx.Done():
+			break feed
+		case jobs <- i:
+		}
+	}
+	close(jobs)
+	wg.Wait()
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	report := &OptimizeReport{
+		Objective:    o.optConfig.Objective,
+		Combinations: len(combos),
+	}
+	ranked := make([]OptimizeResult, 0, len(combos))
+	for _, r := range results {
+		if r == nil {
+			report.Failed++
+			continue
+		}
+		if math.IsNaN(r.Score) {
+			r.Score = math.Inf(-1)
+		}
+		ranked = append(ranked, *r)
+	}
+	sort.SliceStable(ranked, func(i, j int) bool {
+		return ranked[i].Score > ranked[j].Score
+	})
+
+	topN := o.optConfig.TopN
+	if topN <= 0 || topN > len(ranked) {
+		topN = len(ranked)
+	}
+	report.Top = ranked[:topN]
+	return report, nil
+}
+
+// FormatReport renders the ranked results with an overfitting warning
+func (r *OptimizeReport) FormatReport() string {
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "\n=== Optimization Results (top %d of %d by %s) ===\n", len(r.Top), r.Combinations, r.Objective)
+	if r.Failed > 0 {
+		fmt.Fprintf(&sb, "Failed combinations: %d\n", r.Failed)
+	}
+	for i, res := range r.Top {
+		fmt.Fprintf(&sb, "%2d. score=%.4f | Return: %.2f%% | Sharpe: %.2f | Calmar: %.2f | MaxDD: %.2f%% | Trades: %d | %s\n",
+			i+1, res.Score,
+			res.Metrics.TotalReturn*100,
+			res.Metrics.SharpeRatio,
+			res.Metrics.CalmarRatio,
+			res.Metrics.MaxDrawdown*100,
+			res.Metrics.TotalTrades,
+			formatParams(res.Params))
+	}
+	sb.WriteString(`
+WARNING: These results are in-sample. Picking the best of many combinations on the
+same data overstates expected performance. Validate the chosen parameters with
+-walkforward or on a held-out period before trading them.
+`)
+	return sb.String()
+}
+
+func formatParams(params map[string]interface{}) string {
+	names := make([]string, 0, len(params))
+	for name := range params {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	parts := make([]string, len(names))
+	for i, name := range names {
+		parts[i] = fmt.Sprintf("%s=%v", name, params[name])
+	}
+	return strings.Join(parts, " ")
+}
@@ -0,0 +1,76 @@
+package backtest
+
+import (
+	"testing"
+
+	"github.com/kasyap/delta-go/go/pkg/delta"
+	"github.com/kasyap/delta-go/go/pkg/features"
+	"github.com/kasyap/delta-go/go/pkg/strategy"
+)
+
+// holdStrategy buys on the first bar and closes after "hold_bars" bars
+type holdStrategy struct {
+	calls    int
+	holdBars int
+}
+
+func (s *holdStrategy) Name() string { return "hold" }
+
+func (s *holdStrategy) UpdateParams(params map[string]interface{}) {
+	if v, ok := params["hold_bars"].(float64); ok {
+		s.holdBars = int(v)
+	}
+}
+
+func (s *holdStrategy) Analyze(f features.MarketFeatures, candles []delta.Candle) strategy.Signal {
+	defer func() { s.calls++ }()
+	switch s.calls {
+	case 0:
+		return strategy.Signal{Action: strategy.ActionBuy, Side: "buy"}
+	case s.holdBars:
+		return strategy.Signal{Action: strategy.ActionClose}
+	}
+	return strategy.Signal{Action: strategy.ActionNone}
+}
+
+func TestParamGrid_Combinations(t *testing.T) {
+	grid := ParamGrid{"a": {1.0, 2.0}, "b": {"x", "y", "z"}}
+	combos := grid.Combinations()
+	if len(combos) != 6 {
+		t.Fatalf("expected 6 combinations, got %d", len(combos))
+	}
+	if combos[0]["a"] != 1.0 || combos[0]["b"] != "x" || combos[5]["a"] != 2.0 || combos[5]["b"] != "z" {
+		t.Errorf("unexpected combination order: %v", combos)
+	}
+}
+
+func TestOptimizer_RanksByObjective(t *testing.T) {
+	candles, _ := dipThenRallyCandles()
+	factory := func(cfg Config) *Engine {
+		e := NewEngine(cfg, nil)
+		e.candles["BTCUSD"] = candles
+		e.RegisterStrategy(&holdStrategy{})
+		return e
+	}
+	cfg := DefaultConfig()
+	cfg.Symbols = []string{"BTCUSD"}
+	cfg.SimulateFunding = false
+	cfg.SlippageModel = NewFixedSlippage(0)
+
+	// hold_bars=1 exits at 49900 (loss), hold_bars=2 exits at 50800 (profit)
+	opt := NewOptimizer(cfg, OptimizeConfig{Objective: ObjectiveReturn, TopN: 2, Workers: 2}, factory)
+	report, err := opt.Run(ParamGrid{"hold_bars": {1.0, 2.0}})
+	if err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+
+	if report.Combinations != 2 || len(report.Top) != 2 {
+		t.Fatalf("expected 2 ranked combinations, got %d of %d", len(report.Top), report.Combinations)
+	}
+	if report.Top[0].Params["hold_bars"] != 2.0 {
+		t.Errorf("best combination = %v, want hold_bars=2", report.Top[0].Params)
+	}
+	if report.Top[0].Score <= 0 || report.Top[1].Score >= 0 {
+		t.Errorf("unexpected scores: best=%.4f worst=%.4f", report.Top[0].Score, report.Top[1].Score)
+	}
+}
@@ -0,0 +1,99 @@
+package backtest
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/kasyap/delta-go/go/pkg/delta"
+	"github.com/kasyap/delta-go/go/pkg/strategy"
+)
+
+func TestSyntheticOrderbook_Snapshot(t *testing.T) {
+	ob := NewSyntheticOrderbook(4).Snapshot("BTCUSD", delta.Candle{High: 50000, Low: 49800, Close: 50000, Volume: 2000}, time.Time{})
+
+	if ob.Buy[0].Price != "49990" || ob.Sell[0].Price != "50010" {
+		t.Errorf("book = %s/%s, want 49990/50010 (4 bps around the close)", ob.Buy[0].Price, ob.Sell[0].Price)
+	}
+	// Close at the high: all depth on the bid
+	if ob.Buy[0].Size != 2000 || ob.Sell[0].Size != 0 {
+		t.Errorf("sizes = %d/%d, want 2000/0", ob.Buy[0].Size, ob.Sell[0].Size)
+	}
+}
+
+func TestRecordedOrderbook_NoLookahead(t *testing.T) {
+	base := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	path := filepath.Join(t.TempDir(), "books.jsonl")
+	data := `{"time":"2024-01-01T00:10:00Z","symbol":"BTCUSD","buy":[{"price":"50010","size":5}],"sell":[{"price":"50011","size":5}]}
+{"time":"2024-01-01T00:05:00Z","symbol":"BTCUSD","buy":[{"price":"50005","size":5}],"sell":[{"price":"50006","size":5}]}
+`
+	if err := os.WriteFile(path, []byte(data), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	r, err := LoadRecordedOrderbook(path)
+	if err != nil {
+		t.Fatalf("LoadRecordedOrderbook() error = %v", err)
+	}
+	if ob := r.Snapshot("BTCUSD", delta.Candle{}, base); ob != nil {
+		t.Errorf("expected no book before the first snapshot, got %+v", ob)
+	}
+	if ob := r.Snapshot("BTCUSD", delta.Candle{}, base.Add(7*time.Minute)); ob == nil || ob.Buy[0].Price != "50005" {
+		t.Errorf("expected the 00:05 book at 00:07, got %+v", ob)
+	}
+	if ob := r.Snapshot("BTCUSD", delta.Candle{}, base.Add(10*time.Minute)); ob == nil || ob.Buy[0].Price != "50010" {
+		t.Errorf("expected the 00:10 book at 00:10, got %+v", ob)
+	}
+}
+
+// risingBidHeavyCandles closes every bar near its high while grinding up with enough
+// variance for the scalper's volatility filter
+func risingBidHeavyCandles(n int) []delta.Candle {
+	base := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC).Unix()
+	candles := make([]delta.Candle, n)
+	prev := 50000.0
+	for i := range candles {
+		close := 50000 * (1 + 0.001*float64(i) + 0.0005*float64(i%2))
+		candles[i] = delta.Candle{
+			Time:   base + int64(i)*300,
+			Open:   prev,
+			High:   close * 1.0002,
+			Low:    close * 0.998,
+			Close:  close,
+			Volume: 1000,
+		}
+		prev = close
+	}
+	return candles
+}
+
+func TestEngine_SyntheticOrderbookEnablesScalper(t *testing.T) {
+	run := func(source OrderbookSource) []Trade {
+		cfg := DefaultConfig()
+		cfg.Symbols = []string{"BTCUSD"}
+		cfg.SimulateFunding = false
+		cfg.SlippageModel = NewFixedSlippage(0)
+		cfg.OrderbookSource = source
+
+		e := NewEngine(cfg, nil)
+		e.candles["BTCUSD"] = risingBidHeavyCandles(40)
+		e.RegisterStrategy(strategy.NewFeeAwareScalper(strategy.DefaultScalperConfig(), e.FeaturesEngine()))
+		if err := e.simulate(); err != nil {
+			t.Fatalf("simulate() error = %v", err)
+		}
+		return e.trades
+	}
+
+	if trades := run(nil); len(trades) != 0 {
+		t.Fatalf("without an orderbook the scalper should stay idle, got %d trades", len(trades))
+	}
+
+	trades := run(NewSyntheticOrderbook(4))
+	if len(trades) == 0 {
+		t.Fatal("expected a scalper entry from persistent synthetic bid imbalance")
+	}
+	if trades[0].Side != "buy" {
+		t.Errorf("first trade side = %s, want buy", trades[0].Side)
+	}
+}
@@ -0,0 +1,26 @@
+package backtest
+
+import "testing"
+
+func TestEngine_ProgressFuncCalledWithIncreasingPercent(t *testing.T) {
+	e := newTestEngine(sawtoothCandles(100), nil)
+	var pcts []float64
+	e.SetProgressFunc(func(pct, equity float64, trades int) {
+		pcts = append(pcts, pct)
+	})
+	if err := e.simulate(); err != nil {
+		t.Fatalf("simulate() error = %v", err)
+	}
+
+	if len(pcts) < 5 {
+		t.Fatalf("progress calls = %v, want about one per 10%%", pcts)
+	}
+	for i := 1; i < len(pcts); i++ {
+		if pcts[i] <= pcts[i-1] {
+			t.Fatalf("progress not increasing: %v", pcts)
+		}
+	}
+	if pcts[0] != 0 || pcts[len(pcts)-1] >= 100 {
+		t.Errorf("progress range = %.0f..%.0f, want 0 up to below 100", pcts[0], pcts[len(pcts)-1])
+	}
+}
@@ -0,0 +1,176 @@
+package backtest
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"math"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/kasyap/delta-go/go/pkg/delta"
+)
+
+// LiveFill is one executed live order, as exported one JSON object per line.
+// IntendedPrice is the price the strategy wanted (signal or top of book at send time);
+// BarHigh/BarLow describe the candle the fill happened in and feed the slippage model.
+type LiveFill struct {
+	Time          time.Time `json:"time"`
+	Symbol        string    `json:"symbol"`
+	Side          string    `json:"side"`
+	Size          float64   `json:"size"` // contracts
+	IntendedPrice float64   `json:"intended_price"`
+	FillPrice     float64   `json:"fill_price"`
+	Fee           float64   `json:"fee"`
+	Maker         bool      `json:"maker"`
+	BarHigh       float64   `json:"bar_high,omitempty"`
+	BarLow        float64   `json:"bar_low,omitempty"`
+}
+
+// CostError summarizes live-minus-model error for one cost category, in dollars.
+// Positive errors mean the backtest underestimated the cost.
+type CostError struct {
+	TotalLive    float64
+	TotalModel   float64
+	MeanError    float64
+	MedianError  float64
+	MeanAbsError float64
+}
+
+// ReconcileReport compares realized live costs with what the backtest would have charged
+type ReconcileReport struct {
+	Fills    int
+	Fees     CostError
+	Slippage CostError
+}
+
+// LoadLiveFills reads a JSONL file of live fills. Blank lines are ignored.
+func LoadLiveFills(path string) ([]LiveFill, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open live fills: %w", err)
+	}
+	defer f.Close()
+
+	var fills []LiveFill
+	scanner := bufio.NewScanner(f)
+	line := 0
+	for scanner.Scan() {
+		line++
+		text := strings.TrimSpace(scanner.Text())
+		if text == "" {
+			continue
+		}
+		var fill LiveFill
+		if err := json.Unmarshal([]byte(text), &fill); err != nil {
+			return nil, fmt.Errorf("invalid live fill on line %d: %w", line, err)
+		}
+		fills = append(fills, fill)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read live fills: %w", err)
+	}
+	return fills, nil
+}
+
+// Reconcile prices every live fill with the config's fee and slippage models and reports
+// the error per cost category. Fills with a non-positive size or price are skipped.
+func Reconcile(config Config, fills []LiveFill) ReconcileReport {
+	slippage := cappedSlippage(config)
+	if slippage == nil {
+		slippage = NewFixedSlippage(0)
+	}
+
+	var feeErrs, slipErrs []float64
+	var report ReconcileReport
+	for _, fill := range fills {
+		if fill.Size <= 0 || fill.IntendedPrice <= 0 || fill.FillPrice <= 0 {
+			continue
+		}
+
+		product := delta.MockProduct(fill.Symbol)
+		if p, ok := config.Products[fill.Symbol]; ok {
+			product = p
+		}
+		contractValue, err := delta.ParseContractValue(product)
+		if err != nil {
+			continue
+		}
+		notional := fill.Size * contractValue * fill.FillPrice
+
+		// Fees: same formula as the engine, at the maker or taker rate the fill actually paid
+		feeBps := config.TakerFeeBps
+		if fill.Maker {
+			feeBps = config.MakerFeeBps
+		}
+		modelFee := CalculateFee(fill.FillPrice, notional, 1.0, feeBps)
+
+		// Slippage: adverse move from intended price, in dollars
+		liveSlip := (fill.FillPrice - fill.IntendedPrice) * fill.Size * contractValue
+		if fill.Side == "sell" {
+			liveSlip = -liveSlip
+		}
+		candle := delta.Candle{High: fill.BarHigh, Low: fill.BarLow}
+		if candle.High <= 0 || candle.Low <= 0 {
+			candle.High, candle.Low = fill.IntendedPrice, fill.IntendedPrice
+		}
+		modelSlip := slippage.Calculate(fill.Side, notional, candle, 0) * fill.Size * contractValue
+
+		report.Fills++
+		report.Fees.TotalLive += fill.Fee
+		report.Fees.TotalModel += modelFee
+		report.Slippage.TotalLive += liveSlip
+		report.Slippage.TotalModel += modelSlip
+		feeErrs = append(feeErrs, fill.Fee-modelFee)
+		slipErrs = append(slipErrs, liveSlip-modelSlip)
+	}
+
+	report.Fees.summarize(feeErrs)
+	report.Slippage.summarize(slipErrs)
+	return report
+}
+
+func (c *CostError) summarize(errs []float64) {
+	if len(errs) == 0 {
+		return
+	}
+	sum, absSum := 0.0, 0.0
+	for _, e := range errs {
+		sum += e
+		absSum += math.Abs(e)
+	}
+	c.MeanError = sum / float64(len(errs))
+	c.MeanAbsError = absSum / float64(len(errs))
+
+	sorted := append([]float64(nil), errs...)
+	sort.Float64s(sorted)
+	mid := len(sorted) / 2
+	if len(sorted)%2 == 0 {
+		c.MedianError = (sorted[mid-1] + sorted[mid]) / 2
+	} else {
+		c.MedianError = sorted[mid]
+	}
+}
+
+// FormatReport creates a human-readable reconciliation report
+func (r *ReconcileReport) FormatReport() string {
+	report := "===== COST RECONCILIATION =====\n"
+	report += formatLine("Live Fills", formatInt(r.Fills))
+	report += "\n"
+	report += formatCostError("FEES", r.Fees)
+	report += "\n"
+	report += formatCostError("SLIPPAGE", r.Slippage)
+	return report
+}
+
+func formatCostError(title string, c CostError) string {
+	s := title + "\n"
+	s += formatLine("  Live Total", formatMoney(c.TotalLive))
+	s += formatLine("  Model Total", formatMoney(c.TotalModel))
+	s += formatLine("  Mean Error", formatMoney(c.MeanError))
+	s += formatLine("  Median Error", formatMoney(c.MedianError))
+	s += formatLine("  Mean Abs Error", formatMoney(c.MeanAbsError))
+	return s
+}
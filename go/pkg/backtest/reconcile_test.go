@@ -0,0 +1,81 @@
+package backtest
+
+import (
+	"math"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func reconcileConfig() Config {
+	cfg := DefaultConfig()
+	cfg.MakerFeeBps = 2
+	cfg.TakerFeeBps = 5
+	cfg.SlippageModel = NewFixedSlippage(2)
+	return cfg
+}
+
+func TestReconcile_ErrorStats(t *testing.T) {
+	// BTCUSD contract value is 0.001, so 100 contracts at 50000 is $5000 notional
+	fills := []LiveFill{
+		// Taker buy that paid more fee and slipped 20 vs the modelled 10
+		{Symbol: "BTCUSD", Side: "buy", Size: 100, IntendedPrice: 50000, FillPrice: 50020, Fee: 3.0},
+		// Maker sell that matched the model almost exactly
+		{Symbol: "BTCUSD", Side: "sell", Size: 100, IntendedPrice: 50000, FillPrice: 49990, Fee: 1.0, Maker: true},
+		// Taker buy with no slippage at all, model charged 10 per contract-unit
+		{Symbol: "BTCUSD", Side: "buy", Size: 200, IntendedPrice: 50000, FillPrice: 50000, Fee: 5.0},
+		// Skipped: no intended price
+		{Symbol: "BTCUSD", Side: "buy", Size: 100, FillPrice: 50000, Fee: 2.5},
+	}
+
+	report := Reconcile(reconcileConfig(), fills)
+	if report.Fills != 3 {
+		t.Fatalf("Fills = %d, want 3", report.Fills)
+	}
+
+	near := func(name string, got, want float64) {
+		t.Helper()
+		if math.Abs(got-want) > 1e-9 {
+			t.Errorf("%s = %.6f, want %.6f", name, got, want)
+		}
+	}
+
+	// Fee errors: 3.0-2.501, 1.0-0.9998, 5.0-5.0
+	near("Fees.TotalLive", report.Fees.TotalLive, 9.0)
+	near("Fees.TotalModel", report.Fees.TotalModel, 2.501+0.9998+5.0)
+	near("Fees.MeanError", report.Fees.MeanError, (0.499+0.0002)/3)
+	near("Fees.MedianError", report.Fees.MedianError, 0.0002)
+
+	// Slippage errors: 2.0-1.0, 1.0-1.0, 0-2.0
+	near("Slippage.TotalLive", report.Slippage.TotalLive, 3.0)
+	near("Slippage.TotalModel", report.Slippage.TotalModel, 4.0)
+	near("Slippage.MeanError", report.Slippage.MeanError, -1.0/3)
+	near("Slippage.MedianError", report.Slippage.MedianError, 0)
+	near("Slippage.MeanAbsError", report.Slippage.MeanAbsError, 1.0)
+}
+
+func TestLoadLiveFills(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "fills.jsonl")
+	data := `{"symbol":"BTCUSD","side":"buy","size":10,"intended_price":50000,"fill_price":50005,"fee":0.25}
+
+{"symbol":"ETHUSD","side":"sell","size":5,"intended_price":3000,"fill_price":2999,"fee":0.1,"maker":true}
+`
+	if err := os.WriteFile(path, []byte(data), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	fills, err := LoadLiveFills(path)
+	if err != nil {
+		t.Fatalf("LoadLiveFills() error = %v", err)
+	}
+	if len(fills) != 2 || fills[1].Symbol != "ETHUSD" || !fills[1].Maker {
+		t.Errorf("unexpected fills: %+v", fills)
+	}
+
+	if err := os.WriteFile(path, []byte("{not json}\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := LoadLiveFills(path); err == nil {
+		t.Error("expected error for malformed line")
+	}
+}
@@ -0,0 +1,53 @@
+package backtest
+
+import (
+	"testing"
+	"time"
+
+	"github.com/kasyap/delta-go/go/pkg/delta"
+	"github.com/kasyap/delta-go/go/pkg/strategy"
+)
+
+func TestEngine_ReversalDisabledClosesOnly(t *testing.T) {
+	base := time.Date(2024, 1, 1, 1, 0, 0, 0, time.UTC).Unix()
+	var candles []delta.Candle
+	for i := 0; i < 6; i++ {
+		candles = append(candles, delta.Candle{Time: base + int64(i*300), Open: 50000, High: 50050, Low: 49950, Close: 50000})
+	}
+	// Long on bar 0, then sell signals on bars 1 and 2; fills happen on the next bar's open
+	signals := map[int]strategy.Signal{
+		0: {Action: strategy.ActionBuy, Side: "buy"},
+		1: {Action: strategy.ActionSell, Side: "sell"},
+		2: {Action: strategy.ActionSell, Side: "sell"},
+	}
+
+	tests := []struct {
+		name    string
+		allow   bool
+		shortAt int64
+	}{
+		{"reversal flips on the same bar", true, base + 600},
+		{"no reversal waits for the next signal", false, base + 900},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e := newTestEngine(candles, signals)
+			e.config.AllowReversal = tt.allow
+
+			res, err := e.runLoaded()
+			if err != nil {
+				t.Fatalf("run: %v", err)
+			}
+			if len(res.Trades) != 2 || res.Trades[0].Reason != "signal_reversal" {
+				t.Fatalf("expected the long closed on the sell signal then a short, got %+v", res.Trades)
+			}
+			long, short := res.Trades[0], res.Trades[1]
+			if short.Side != "sell" || short.EntryTime.Unix() != tt.shortAt {
+				t.Errorf("short opened at %d, want %d", short.EntryTime.Unix(), tt.shortAt)
+			}
+			if !tt.allow && !short.EntryTime.After(long.ExitTime) {
+				t.Error("short opened on the same bar the long was closed")
+			}
+		})
+	}
+}
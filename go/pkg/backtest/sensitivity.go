@@ -0,0 +1,95 @@
+package backtest
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// RunFeeSensitivity re-runs the simulation with maker/taker fees, slippage and the assumed
+// spread scaled by each multiplier (e.g. 0.5, 1, 2) to show how much of the edge survives
+// higher costs. Data is loaded once and shared by every run; strategies implementing
+// strategy.Resetter are reset between runs. Failed runs are logged and omitted from the result.
+func (e *Engine) RunFeeSensitivity(feeMultipliers []float64) map[float64]Metrics {
+	results, err := e.RunFeeSensitivityContext(context.Background(), feeMultipliers)
+	if err != nil {
+		fmt.Printf("Warning: fee sensitivity failed: %v\n", err)
+	}
+	return results
+}
+
+// RunFeeSensitivityContext is RunFeeSensitivity with cancellable data loading
+func (e *Engine) RunFeeSensitivityContext(ctx context.Context, feeMultipliers []float64) (map[float64]Metrics, error) {
+	results := make(map[float64]Metrics, len(feeMultipliers))
+	if len(e.candles) == 0 {
+		if err := e.loadData(ctx); err != nil {
+			return results, fmt.Errorf("failed to load data: %w", err)
+		}
+	}
+
+	for _, mult := range feeMultipliers {
+		if err := ctx.Err(); err != nil {
+			return results, err
+		}
+		fmt.Printf("\n--- Cost multiplier %.2fx ---\n", mult)
+
+		e.strategyMgr.Reset()
+		e.featuresEngine.Reset()
+		res, err := e.withCostMultiplier(mult).runLoaded()
+		if err != nil {
+			fmt.Printf("Warning: run at %.2fx costs failed: %v\n", mult, err)
+			continue
+		}
+		results[mult] = res.Metrics
+	}
+	return results, nil
+}
+
+// withCostMultiplier returns a fresh engine over the same data and strategies with fees,
+// slippage and spread scaled. Hooks are not copied so analytics only see the base run.
+func (e *Engine) withCostMultiplier(mult float64) *Engine {
+	cfg := e.config
+	cfg.MakerFeeBps *= mult
+	cfg.TakerFeeBps *= mult
+	cfg.FeeTiers = make([]FeeTier, len(e.config.FeeTiers))
+	for i, t := range e.config.FeeTiers {
+		cfg.FeeTiers[i] = FeeTier{MinVolume: t.MinVolume, MakerFeeBps: t.MakerFeeBps * mult, TakerFeeBps: t.TakerFeeBps * mult}
+	}
+	cfg.AssumedSpreadBps *= mult
+	cfg.MaxSlippageBps *= mult
+	if cfg.SlippageModel != nil {
+		cfg.SlippageModel = &ScaledSlippage{Base: cfg.SlippageModel, Multiplier: mult}
+	}
+
+	run := NewEngine(cfg, nil)
+	run.strategyMgr = e.strategyMgr
+	run.featuresEngine = e.featuresEngine // Strategies hold this engine for OBI history
+	run.candles = e.candles
+	run.fundingRates = e.fundingRates
+	return run
+}
+
+// FormatFeeSensitivity renders fee sensitivity results ordered by multiplier
+func FormatFeeSensitivity(results map[float64]Metrics) string {
+	mults := make([]float64, 0, len(results))
+	for m := range results {
+		mults = append(mults, m)
+	}
+	sort.Float64s(mults)
+
+	var sb strings.Builder
+	sb.WriteString("\n=== Fee/Slippage Sensitivity ===\n")
+	fmt.Fprintf(&sb, "%-8s %10s %10s %10s %12s %8s\n", "Costs", "Return", "Sharpe", "MaxDD", "Total Costs", "Trades")
+	for _, m := range mults {
+		r := results[m]
+		fmt.Fprintf(&sb, "%-8s %9.2f%% %10.2f %9.2f%% %12s %8d\n",
+			fmt.Sprintf("%.2fx", m),
+			r.TotalReturn*100,
+			r.SharpeRatio,
+			r.MaxDrawdown*100,
+			formatMoney(r.TotalCosts),
+			r.TotalTrades)
+	}
+	return sb.String()
+}
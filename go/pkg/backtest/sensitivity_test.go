@@ -0,0 +1,51 @@
+package backtest
+
+import (
+	"strings"
+	"testing"
+)
+
+func (s *holdStrategy) Reset() { s.calls = 0 }
+
+func TestRunFeeSensitivity_HigherCostsReduceReturn(t *testing.T) {
+	candles, _ := dipThenRallyCandles()
+	cfg := DefaultConfig()
+	cfg.Symbols = []string{"BTCUSD"}
+	cfg.SimulateFunding = false
+	cfg.SlippageModel = NewFixedSlippage(2)
+
+	e := NewEngine(cfg, nil)
+	e.candles["BTCUSD"] = candles
+	// Profitable: buys at 50000, exits at 50800
+	e.RegisterStrategy(&holdStrategy{holdBars: 2})
+
+	mults := []float64{0.5, 1, 2, 4}
+	results := e.RunFeeSensitivity(mults)
+	if len(results) != len(mults) {
+		t.Fatalf("expected %d results, got %d", len(mults), len(results))
+	}
+
+	if results[0.5].TotalReturn <= 0 {
+		t.Fatalf("expected a profitable strategy at 0.5x costs, got return %.4f", results[0.5].TotalReturn)
+	}
+	for i := 1; i < len(mults); i++ {
+		lo, hi := results[mults[i-1]], results[mults[i]]
+		if hi.TotalTrades != lo.TotalTrades || hi.TotalTrades == 0 {
+			t.Errorf("trade count changed across runs: %d at %.1fx vs %d at %.1fx (strategy state leaked?)",
+				lo.TotalTrades, mults[i-1], hi.TotalTrades, mults[i])
+		}
+		if hi.TotalReturn >= lo.TotalReturn {
+			t.Errorf("return at %.1fx (%.6f) should be below %.1fx (%.6f)",
+				mults[i], hi.TotalReturn, mults[i-1], lo.TotalReturn)
+		}
+		if hi.TotalCosts <= lo.TotalCosts {
+			t.Errorf("costs at %.1fx (%.4f) should exceed %.1fx (%.4f)",
+				mults[i], hi.TotalCosts, mults[i-1], lo.TotalCosts)
+		}
+	}
+
+	report := FormatFeeSensitivity(results)
+	if !strings.Contains(report, "0.50x") || !strings.Contains(report, "4.00x") {
+		t.Errorf("report missing multipliers:\n%s", report)
+	}
+}
@@ -12,6 +12,36 @@ type SlippageModel interface {
 	Calculate(side string, size float64, candle delta.Candle, volatility float64) float64
 }
 
+// ---------------------- Scaled Slippage ----------------------
+
+// ScaledSlippage multiplies another model's slippage, for cost sensitivity runs
+type ScaledSlippage struct {
+	Base       SlippageModel
+	Multiplier float64
+}
+
+func (s *ScaledSlippage) Calculate(side string, size float64, candle delta.Candle, volatility float64) float64 {
+	return s.Base.Calculate(side, size, candle, volatility) * s.Multiplier
+}
+
+// ---------------------- Capped Slippage ----------------------
+
+// CappedSlippage limits another model's slippage to MaxBps of the bar mid, so models
+// like VolumeImpactSlippage can't explode on large participation
+type CappedSlippage struct {
+	Base   SlippageModel
+	MaxBps float64
+}
+
+func (s *CappedSlippage) Calculate(side string, size float64, candle delta.Candle, volatility float64) float64 {
+	slip := s.Base.Calculate(side, size, candle, volatility)
+	if math.IsNaN(slip) || slip < 0 {
+		return 0
+	}
+	mid := (candle.High + candle.Low) / 2
+	return math.Min(slip, mid*(s.MaxBps/10000))
+}
+
 // ---------------------- Fixed Slippage ----------------------
 
 // FixedSlippage applies a constant slippage in basis points
@@ -124,6 +154,18 @@ func ApplySlippage(price, slippage float64, side string) float64 {
 	return price - slippage // Sells fill lower
 }
 
+// ClampFillPrice keeps a slipped fill price usable: non-finite prices fall back to ref and
+// anything below one tick (e.g. a sell slipped past zero) is raised to the tick
+func ClampFillPrice(price, ref, tick float64) float64 {
+	if math.IsNaN(price) || math.IsInf(price, 0) {
+		price = ref
+	}
+	if tick <= 0 {
+		tick = math.SmallestNonzeroFloat64
+	}
+	return math.Max(price, tick)
+}
+
 // CalculateFee computes trading fee
 // Note: size is the NOTIONAL VALUE in dollars, not contract count
 func CalculateFee(price float64, size float64, contractValue, feeBps float64) float64 {
@@ -1,9 +1,12 @@
 package backtest
 
 import (
+	"math"
 	"testing"
+	"time"
 
 	"github.com/kasyap/delta-go/go/pkg/delta"
+	"github.com/kasyap/delta-go/go/pkg/strategy"
 )
 
 func TestFixedSlippage(t *testing.T) {
@@ -48,6 +51,65 @@ func TestVolatilitySlippage(t *testing.T) {
 	}
 }
 
+func TestCappedSlippage_ExtremeParticipation(t *testing.T) {
+	candle := delta.Candle{High: 100, Low: 100, Close: 100, Volume: 10}
+	impact := NewVolumeImpactSlippage(1, 0.1)
+
+	// 1000x the bar's volume: sqrt impact is 0.1 * sqrt(1000) * 100 ≈ 316, three times the price
+	raw := impact.Calculate("sell", 10_000, candle, 0)
+	if raw <= candle.Close {
+		t.Fatalf("expected raw impact to exceed the price, got %.2f", raw)
+	}
+
+	capped := (&CappedSlippage{Base: impact, MaxBps: 100}).Calculate("sell", 10_000, candle, 0)
+	if math.Abs(capped-1) > 1e-9 {
+		t.Errorf("capped slippage = %.4f, want 1 (100 bps of 100)", capped)
+	}
+
+	// Small orders stay under the cap and pass through unchanged
+	small := impact.Calculate("buy", 0.001, candle, 0)
+	if got := (&CappedSlippage{Base: impact, MaxBps: 100}).Calculate("buy", 0.001, candle, 0); got != small {
+		t.Errorf("uncapped slippage changed: got %.6f, want %.6f", got, small)
+	}
+}
+
+func TestClampFillPrice(t *testing.T) {
+	raw := ApplySlippage(0.05, 0.2, "sell")
+	if raw >= 0 {
+		t.Fatalf("expected unclamped sell to go negative, got %.4f", raw)
+	}
+	if got := ClampFillPrice(raw, 0.05, 0.0001); got != 0.0001 {
+		t.Errorf("negative sell clamped to %.6f, want one tick", got)
+	}
+	if got := ClampFillPrice(math.NaN(), 0.05, 0.0001); got != 0.05 {
+		t.Errorf("NaN fill = %v, want reference price", got)
+	}
+	if got := ClampFillPrice(0.06, 0.05, 0.0001); got != 0.06 {
+		t.Errorf("valid fill changed to %v", got)
+	}
+}
+
+func TestEngine_MaxSlippageCapsEntry(t *testing.T) {
+	candles, signals := dipThenRallyCandles()
+	e := newTestEngine(candles, signals)
+	e.slippage = cappedSlippage(Config{
+		SlippageModel:  NewFixedSlippage(500),
+		MaxSlippageBps: 10,
+	})
+
+	res, err := e.runLoaded()
+	if err != nil {
+		t.Fatalf("run: %v", err)
+	}
+	if len(res.Trades) == 0 {
+		t.Fatal("expected a trade")
+	}
+	// Bar 1 mid is 49800; 10 bps of it is the most a buy can pay over the 50000 open
+	if got, want := res.Trades[0].EntryPrice, 50000+49800*0.001; math.Abs(got-want) > 1e-6 {
+		t.Errorf("entry price = %.2f, want %.2f", got, want)
+	}
+}
+
 func TestApplySlippage(t *testing.T) {
 	price := 50000.0
 	slippage := 10.0
@@ -83,6 +145,7 @@ func TestPositionUnrealizedPnL(t *testing.T) {
 	// Size = contracts (e.g., 10 contracts)
 	// ContractValue = 0.001 (for BTCUSD, 1 contract = 0.001 BTC)
 	// P&L = contracts * contractValue * (currentPrice - entryPrice) * direction
+	btc := &delta.Product{Symbol: "BTCUSD", ContractValue: "0.001"}
 	pos := &Position{
 		Side:       "buy",
 		Size:       10.0, // 10 contracts
@@ -91,7 +154,7 @@ func TestPositionUnrealizedPnL(t *testing.T) {
 
 	// Price goes up by 500 (1%)
 	// P&L = 10 * 0.001 * (50500 - 50000) * 1 = 10 * 0.001 * 500 = 5.0
-	pnl := pos.UnrealizedPnL(50500, 0.001)
+	pnl := pos.UnrealizedPnL(50500, btc)
 
 	expected := 5.0
 	if abs(pnl-expected) > 0.01 {
@@ -107,11 +170,17 @@ func TestPositionUnrealizedPnL(t *testing.T) {
 
 	// Price goes down by 500 - short profits
 	// P&L = 10 * 0.001 * (49500 - 50000) * -1 = 10 * 0.001 * 500 = 5.0
-	shortPnl := shortPos.UnrealizedPnL(49500, 0.001)
+	shortPnl := shortPos.UnrealizedPnL(49500, btc)
 	expected = 5.0
 	if abs(shortPnl-expected) > 0.01 {
 		t.Errorf("Short position should profit when price drops, expected %.4f got %.4f", expected, shortPnl)
 	}
+
+	// The live bot marks the exchange's signed position the same way
+	live := delta.Position{Size: -10, EntryPrice: "50000"}
+	if got := live.UnrealizedPnLAt(49500, btc); got != shortPnl {
+		t.Errorf("live short PnL %.4f differs from backtest %.4f", got, shortPnl)
+	}
 }
 
 func abs(x float64) float64 {
@@ -120,3 +189,70 @@ func abs(x float64) float64 {
 	}
 	return x
 }
+
+func TestEngine_SpreadCostRoundTrip(t *testing.T) {
+	base := time.Date(2024, 1, 1, 1, 0, 0, 0, time.UTC)
+	candles := make([]delta.Candle, 4)
+	for i := range candles {
+		candles[i] = delta.Candle{Time: base.Add(time.Duration(i) * 5 * time.Minute).Unix(), Open: 50000, High: 50000, Low: 50000, Close: 50000}
+	}
+	e := newTestEngine(candles, map[int]strategy.Signal{
+		0: {Action: strategy.ActionBuy, Side: "buy"},
+		1: {Action: strategy.ActionClose},
+	})
+	e.config.AssumedSpreadBps = 10
+
+	if err := e.simulate(); err != nil {
+		t.Fatalf("simulate() error = %v", err)
+	}
+	if len(e.trades) != 1 {
+		t.Fatalf("expected one trade, got %d", len(e.trades))
+	}
+
+	tr := e.trades[0]
+	// Buy pays the ask, the closing sell receives the bid
+	if tr.EntryPrice != 50025 || tr.ExitPrice != 49975 {
+		t.Errorf("fills = %.2f/%.2f, want 50025/49975", tr.EntryPrice, tr.ExitPrice)
+	}
+
+	notional := tr.Size * 0.001 * 50000 // BTCUSD contract value 0.001
+	want := notional * 10 / 10000
+	metrics := NewMetricsCalculator(e.config).Calculate(e.trades, e.equityCurve)
+	if math.Abs(metrics.TotalSpreadCost-want) > 1e-9 {
+		t.Errorf("TotalSpreadCost = %.6f, want one full spread %.6f", metrics.TotalSpreadCost, want)
+	}
+	if math.Abs(tr.GrossPnL+want) > 1e-9 {
+		t.Errorf("GrossPnL = %.6f, want -%.6f on a flat market", tr.GrossPnL, want)
+	}
+}
+
+func TestEngine_LatencyShiftsFillPrice(t *testing.T) {
+	// Buy on bar 0; bar 1 opens at 50000 and closes at 49900, bar 2 runs 49900 -> 50800
+	tests := []struct {
+		name      string
+		latencyMs int
+		want      float64
+	}{
+		{"no latency fills at open", 0, 50000},
+		{"1m into a 5m bar", 60_000, 50000 - 0.2*100},
+		{"longer than a bar fills on the next", 360_000, 49900 + 0.2*900},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			candles, signals := dipThenRallyCandles()
+			e := newTestEngine(candles, signals)
+			e.config.LatencyMs = tt.latencyMs
+
+			res, err := e.runLoaded()
+			if err != nil {
+				t.Fatalf("run: %v", err)
+			}
+			if len(res.Trades) == 0 {
+				t.Fatal("expected a trade")
+			}
+			if got := res.Trades[0].EntryPrice; math.Abs(got-tt.want) > 1e-6 {
+				t.Errorf("entry price = %.2f, want %.2f", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,53 @@
+package backtest
+
+import (
+	"math"
+
+	"github.com/kasyap/delta-go/go/pkg/delta"
+)
+
+// Time in force of simulated limit orders, as in delta.OrderRequest.TimeInForce
+const (
+	TimeInForceGTC = "gtc" // Rest until filled
+	TimeInForceIOC = "ioc" // Fill what is immediately crossable, cancel the rest
+	TimeInForceFOK = "fok" // Fill in full immediately or not at all
+)
+
+// crossableFraction is the share of the bar's range at or through a limit price: the part
+// of [Low, High] at or below the limit for a buy, at or above it for a sell. Candles carry
+// no book depth, so this stands in for the share of an order that is immediately executable.
+func crossableFraction(side string, limit float64, c delta.Candle) float64 {
+	if c.High <= c.Low {
+		if (side == "buy" && c.Low <= limit) || (side == "sell" && c.High >= limit) {
+			return 1
+		}
+		return 0
+	}
+	frac := (limit - c.Low) / (c.High - c.Low)
+	if side == "sell" {
+		frac = (c.High - limit) / (c.High - c.Low)
+	}
+	return math.Max(0, math.Min(1, frac))
+}
+
+// FillLimitOrder returns how many of size contracts a limit order fills on bar c. GTC
+// fills in full once the bar touches the limit; IOC fills the crossable fraction of size,
+// rounded down to whole contracts; FOK fills in full only when the whole bar trades at or
+// through the limit, otherwise nothing.
+func FillLimitOrder(tif, side string, limit, size float64, c delta.Candle) float64 {
+	frac := crossableFraction(side, limit, c)
+	switch tif {
+	case TimeInForceIOC:
+		return math.Floor(size*frac + 1e-9)
+	case TimeInForceFOK:
+		if frac >= 1 {
+			return size
+		}
+		return 0
+	default:
+		if frac > 0 || (side == "buy" && c.Low == limit) || (side == "sell" && c.High == limit) {
+			return size
+		}
+		return 0
+	}
+}
@@ -0,0 +1,69 @@
+package backtest
+
+import (
+	"testing"
+
+	"github.com/kasyap/delta-go/go/pkg/delta"
+	"github.com/kasyap/delta-go/go/pkg/strategy"
+)
+
+func TestFillLimitOrder_PartiallyCrossableBar(t *testing.T) {
+	// Limits at 100 and 102 leave a quarter of the 99-103 range crossable
+	bar := delta.Candle{Open: 101, High: 103, Low: 99, Close: 101}
+	tests := []struct {
+		tif, side string
+		limit     float64
+		want      float64
+	}{
+		{TimeInForceGTC, "buy", 100, 8},
+		{TimeInForceIOC, "buy", 100, 2},
+		{TimeInForceFOK, "buy", 100, 0},
+		{TimeInForceGTC, "sell", 102, 8},
+		{TimeInForceIOC, "sell", 102, 2},
+		{TimeInForceFOK, "sell", 102, 0},
+		{TimeInForceFOK, "buy", 103, 8},  // Whole bar at or below the limit
+		{TimeInForceGTC, "buy", 98, 0},   // Never reached
+		{TimeInForceGTC, "sell", 103, 8}, // Touched at the high
+		{TimeInForceIOC, "buy", 99.5, 1}, // 1.0 contracts crossable
+		{TimeInForceIOC, "buy", 99.2, 0}, // 0.4 rounds down to nothing
+	}
+	for _, tt := range tests {
+		if got := FillLimitOrder(tt.tif, tt.side, tt.limit, 8, bar); got != tt.want {
+			t.Errorf("FillLimitOrder(%s %s @ %.1f) = %.0f, want %.0f", tt.tif, tt.side, tt.limit, got, tt.want)
+		}
+	}
+}
+
+func TestSimulateGrid_TimeInForce(t *testing.T) {
+	grid := strategy.GridConfig{GridLevels: 5, GridRangePct: 2, PositionSizePerLevel: 4}
+	config := Config{
+		InitialCapital: 1000,
+		MakerFeeBps:    2,
+		TakerFeeBps:    5,
+		Products: map[string]*delta.Product{
+			"BTCUSD": {Symbol: "BTCUSD", ContractValue: "1", TickSize: "0.5"},
+		},
+	}
+	// The dip bar (98.5-99.8) has 0.5/1.3 of its range at or below the 99 buy level
+	candles := sawtoothCandles(1)[:2]
+
+	config.GridTimeInForce = TimeInForceGTC
+	if res := SimulateGrid(config, "BTCUSD", candles, grid); res.Grid.Fills != 1 || res.Grid.TotalFees != 4*99*2.0/10000 {
+		t.Errorf("GTC: fills = %d, fees = %.4f; want the full 4 contracts at the maker fee", res.Grid.Fills, res.Grid.TotalFees)
+	}
+
+	config.GridTimeInForce = TimeInForceIOC
+	res := SimulateGrid(config, "BTCUSD", candles, grid)
+	if res.Grid.Fills != 1 || res.Grid.OpenUnits != 1 || res.Grid.TotalFees != 99*5.0/10000 {
+		t.Errorf("IOC: fills = %d, open units = %d, fees = %.4f; want 1 contract at the taker fee",
+			res.Grid.Fills, res.Grid.OpenUnits, res.Grid.TotalFees)
+	}
+	if want := 98.7 - 99 - 99*5.0/10000; res.Grid.UnrealizedPnL-want > 1e-9 || want-res.Grid.UnrealizedPnL > 1e-9 {
+		t.Errorf("IOC: unrealized P&L = %.4f, want %.4f for the 1 filled contract", res.Grid.UnrealizedPnL, want)
+	}
+
+	config.GridTimeInForce = TimeInForceFOK
+	if res := SimulateGrid(config, "BTCUSD", candles, grid); res.Grid.Fills != 0 || res.Grid.OpenUnits != 0 {
+		t.Errorf("FOK: fills = %d, open units = %d; want nothing filled", res.Grid.Fills, res.Grid.OpenUnits)
+	}
+}
@@ -0,0 +1,53 @@
+package backtest
+
+import (
+	"github.com/kasyap/delta-go/go/pkg/delta"
+	"github.com/kasyap/delta-go/go/pkg/features"
+	"github.com/kasyap/delta-go/go/pkg/strategy"
+)
+
+// Indicator periods recorded in trade context
+const (
+	contextRSIPeriod  = 14
+	contextATRPeriod  = 14
+	contextFastEMA    = 20
+	contextSlowEMA    = 50
+	contextRegimeFlag = "regime_"
+)
+
+// tradeContext snapshots the indicators and features behind an entry signal, from the bars
+// the strategy saw. Indicators without enough history are left out. The HMM regime is
+// recorded as a "regime_<name>" = 1 flag.
+func tradeContext(f features.MarketFeatures, candles []delta.Candle, signal strategy.Signal) map[string]float64 {
+	ctx := map[string]float64{
+		"confidence":       signal.Confidence,
+		"price":            f.SpotPrice,
+		"spread_bps":       f.SpreadBps,
+		"spread_pctile":    f.SpreadPercentile,
+		"imbalance":        f.Imbalance,
+		"hist_vol":         f.HistoricalVol,
+		"basis_annualized": f.BasisAnnualized,
+	}
+	if f.HMMRegime != "" {
+		ctx[contextRegimeFlag+string(f.HMMRegime)] = 1
+		ctx["regime_confidence"] = f.HMMConfidence
+	}
+
+	ti := strategy.NewIndicators()
+	series := strategy.ExtractSeries(candles)
+	if len(series.Closes) > contextRSIPeriod {
+		ctx["rsi_14"] = ti.RSILast(series.Closes, contextRSIPeriod)
+	}
+	if atr := ti.ATRLast(series.Highs, series.Lows, series.Closes, contextATRPeriod); atr > 0 {
+		ctx["atr_14"] = atr
+	}
+	if f.SpotPrice > 0 {
+		if ema := ti.EMALast(series.Closes, contextFastEMA); ema > 0 {
+			ctx["ema20_dist_pct"] = (f.SpotPrice - ema) / ema * 100
+		}
+		if ema := ti.EMALast(series.Closes, contextSlowEMA); ema > 0 {
+			ctx["ema50_dist_pct"] = (f.SpotPrice - ema) / ema * 100
+		}
+	}
+	return ctx
+}
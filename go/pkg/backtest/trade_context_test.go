@@ -0,0 +1,63 @@
+package backtest
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/kasyap/delta-go/go/pkg/delta"
+	"github.com/kasyap/delta-go/go/pkg/strategy"
+)
+
+// trendCandles returns n rising 5m bars, enough history for every context indicator
+func trendCandles(n int) []delta.Candle {
+	base := time.Date(2024, 1, 1, 1, 0, 0, 0, time.UTC).Unix()
+	candles := make([]delta.Candle, n)
+	for i := range candles {
+		p := 50000 + float64(i)*10
+		candles[i] = delta.Candle{Time: base + int64(i*300), Open: p, High: p + 20, Low: p - 20, Close: p + 5, Volume: 1}
+	}
+	return candles
+}
+
+func TestEngine_RecordsTradeContext(t *testing.T) {
+	signals := map[int]strategy.Signal{60: {Action: strategy.ActionBuy, Side: "buy", Confidence: 0.8}}
+
+	e := newTestEngine(trendCandles(70), signals)
+	e.config.RecordTradeContext = true
+	if err := e.simulate(); err != nil {
+		t.Fatal(err)
+	}
+	if len(e.trades) != 1 {
+		t.Fatalf("got %d trades, want 1", len(e.trades))
+	}
+
+	ctx := e.trades[0].Context
+	for _, key := range []string{"confidence", "price", "rsi_14", "atr_14", "ema20_dist_pct", "ema50_dist_pct", "hist_vol"} {
+		if _, ok := ctx[key]; !ok {
+			t.Errorf("context missing %q: %v", key, ctx)
+		}
+	}
+	if ctx["confidence"] != 0.8 || ctx["rsi_14"] <= 50 || ctx["ema20_dist_pct"] <= 0 {
+		t.Errorf("context does not reflect the uptrend signal: %v", ctx)
+	}
+
+	out, err := json.Marshal(e.trades[0])
+	if err != nil || !strings.Contains(string(out), `"rsi_14"`) {
+		t.Errorf("JSON export missing context: %s (%v)", out, err)
+	}
+}
+
+func TestEngine_TradeContextOffByDefault(t *testing.T) {
+	e := newTestEngine(trendCandles(70), map[int]strategy.Signal{60: {Action: strategy.ActionBuy, Side: "buy"}})
+	if err := e.simulate(); err != nil {
+		t.Fatal(err)
+	}
+	if len(e.trades) != 1 || e.trades[0].Context != nil {
+		t.Fatalf("trades = %d, context = %v, want one trade without context", len(e.trades), e.trades[0].Context)
+	}
+	if out, _ := json.Marshal(e.trades[0]); strings.Contains(string(out), "Context") {
+		t.Errorf("empty context should be omitted from JSON: %s", out)
+	}
+}
@@ -6,6 +6,7 @@ import (
 	"time"
 
 	"github.com/kasyap/delta-go/go/pkg/delta"
+	"github.com/kasyap/delta-go/go/pkg/risk"
 )
 
 // Config defines backtesting parameters
@@ -27,15 +28,67 @@ type Config struct {
 	TakerFeeBps   float64 // Delta: 5 bps (0.05%)
 	SlippageModel SlippageModel
 
-	// Latency simulation
+	// Volume discounts: the tier reached by cumulative traded notional replaces the rates
+	// above for later fills (empty = flat fees)
+	FeeTiers []FeeTier
+
+	// Ceiling on any model's slippage, in bps of the bar mid (0 = uncapped)
+	MaxSlippageBps float64
+
+	// Bid/ask spread paid on every fill: buys pay mid + half, sells receive mid - half.
+	// Candles only carry trade prices, so this is an assumption (0 = fill at mid).
+	AssumedSpreadBps float64
+
+	// Optional L2 source for order book features (nil = no book, OBI strategies stay idle)
+	OrderbookSource OrderbookSource
+
+	// Block new entries on a symbol this long after a stop-loss exit (0 = off)
+	StopCooldown time.Duration
+
+	// An opposite signal reverses the position when AllowReversal is set; otherwise it only
+	// closes it and new entries on the symbol wait ReversalCooldown (0 = from the next bar)
+	AllowReversal    bool
+	ReversalCooldown time.Duration
+
+	// Units that may be open on a symbol at once: a same-direction signal adds a unit with
+	// its own bracket until the cap is reached (0 or 1 = a single position per symbol)
+	MaxUnitsPerSymbol int
+
+	// Entry freeze around funding boundaries and scheduled events (zero Window = off);
+	// open positions are held through it unless FlattenOnEventFreeze is set
+	EventFreeze          risk.EventFreeze
+	FlattenOnEventFreeze bool
+
+	// Time in force of the grid simulation's limit orders: TimeInForceGTC (default),
+	// TimeInForceIOC or TimeInForceFOK; see FillLimitOrder
+	GridTimeInForce string
+
+	// Debug guard against lookahead: strategies see only bars closed before the signal bar,
+	// and the run fails if an order would fill at or before the bar it was signalled on
+	StrictNoLookahead bool
+
+	// Attach the indicators, features and regime behind each entry to its Trade.Context
+	// (off by default: it costs an indicator pass per signal)
+	RecordTradeContext bool
+
+	// Latency simulation: signal-to-exchange delay, applied to next-bar fills (see latencyFillPrice)
 	LatencyMs int // Typical: 50-100ms
 
 	// Funding simulation
 	SimulateFunding bool
 
+	// Seed for the synthetic funding variance used when no historical rates are available.
+	// Runs are deterministic: the same config, seed and data give identical trades and metrics.
+	Seed int64
+
 	// Data caching
 	DataCacheDir string
 
+	// Missing bars: runs longer than MaxGapBars fail the load (0 = no limit);
+	// FillGaps forward-fills the rest with flat bars at the previous close
+	FillGaps   bool
+	MaxGapBars int
+
 	// Product metadata for contract value conversions
 	Products map[string]*delta.Product
 }
@@ -57,6 +110,7 @@ func DefaultConfig() Config {
 		TakerFeeBps:     5.0, // 0.05%
 		SlippageModel:   NewVolatilitySlippage(1.5, 0.5),
 		LatencyMs:       50,
+		AllowReversal:   true,
 		SimulateFunding: true,
 		DataCacheDir:    ".backtest_cache",
 		Products:        products,
@@ -65,6 +119,7 @@ func DefaultConfig() Config {
 
 // Position represents an open position during backtesting
 type Position struct {
+	ID         int64 // Unique per engine run; several units can be open on one symbol
 	Symbol     string
 	Side       string // "buy" or "sell"
 	Size       float64
@@ -79,19 +134,16 @@ type Position struct {
 	// Accumulated costs
 	EntryFee    float64
 	EntrySlip   float64
+	EntrySpread float64 // Half-spread cost paid at entry, in dollars
 	FundingPaid float64
+
+	Context map[string]float64 // Entry signal context when RecordTradeContext is set
 }
 
-// UnrealizedPnL calculates unrealized P&L at given price
-// Size is now contract count, contractValue is from Product.ContractValue
-func (p *Position) UnrealizedPnL(currentPrice float64, contractValue float64) float64 {
-	multiplier := 1.0
-	if p.Side == "sell" {
-		multiplier = -1.0
-	}
-	// P&L = contracts * contractValue * (currentPrice - entryPrice) * direction
-	priceDiff := currentPrice - p.EntryPrice
-	return p.Size * contractValue * priceDiff * multiplier
+// UnrealizedPnL calculates unrealized P&L at given price with delta.ComputeUnrealizedPnL,
+// the same formula the live bot uses. Size is a contract count.
+func (p *Position) UnrealizedPnL(currentPrice float64, product *delta.Product) float64 {
+	return delta.ComputeUnrealizedPnL(p.Side, int(p.Size), p.EntryPrice, currentPrice, product)
 }
 
 // Trade represents a completed trade with all costs
@@ -117,6 +169,10 @@ type Trade struct {
 	EntrySlipCost float64
 	ExitSlipCost  float64
 
+	// Half-spread costs in dollars. Already reflected in EntryPrice/ExitPrice and GrossPnL.
+	EntrySpreadCost float64
+	ExitSpreadCost  float64
+
 	// Funding (for perpetuals)
 	FundingPaid float64
 
@@ -124,8 +180,17 @@ type Trade struct {
 	GrossPnL float64
 	NetPnL   float64 // After all costs: fees, slippage costs, funding
 
+	// Excursions while held, in price units and in R (multiples of initial stop distance)
+	MaxAdverseExcursion   float64
+	MaxFavorableExcursion float64
+	MAER                  float64 // 0 when the trade had no stop-loss
+	MFER                  float64
+
 	// Exit reason
-	Reason string // "stop_loss", "take_profit", "signal", "timeout"
+	Reason string // "stop_loss", "take_profit", "liquidation", "signal", "timeout"
+
+	// Indicators, features and regime at the entry signal (Config.RecordTradeContext)
+	Context map[string]float64 `json:",omitempty"`
 }
 
 // FundingRate represents a funding payment event
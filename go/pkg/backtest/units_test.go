@@ -0,0 +1,72 @@
+package backtest
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"github.com/kasyap/delta-go/go/pkg/delta"
+	"github.com/kasyap/delta-go/go/pkg/strategy"
+)
+
+// twoUnitCandles: the first buy fills at 50000 and takes profit at 50500 on bar 2; the
+// second fills at 50200 on bar 2 and takes profit at 51500 on bar 3
+func twoUnitCandles() ([]delta.Candle, map[int]strategy.Signal) {
+	base := time.Date(2024, 1, 1, 1, 0, 0, 0, time.UTC).Unix()
+	candles := []delta.Candle{
+		{Time: base, Open: 50000, High: 50000, Low: 50000, Close: 50000},
+		{Time: base + 300, Open: 50000, High: 50100, Low: 49900, Close: 50100},
+		{Time: base + 600, Open: 50200, High: 50600, Low: 50150, Close: 50550},
+		{Time: base + 900, Open: 50550, High: 51600, Low: 50500, Close: 51550},
+		{Time: base + 1200, Open: 51550, High: 51550, Low: 51550, Close: 51550},
+	}
+	signals := map[int]strategy.Signal{
+		0: {Action: strategy.ActionBuy, Side: "buy", StopLoss: 49000, TakeProfit: 50500},
+		1: {Action: strategy.ActionBuy, Side: "buy", StopLoss: 49000, TakeProfit: 51500},
+	}
+	return candles, signals
+}
+
+func TestEngine_IndependentUnitsPerSymbol(t *testing.T) {
+	candles, signals := twoUnitCandles()
+	e := newTestEngine(candles, signals)
+	e.config.MaxUnitsPerSymbol = 2
+	e.config.TakerFeeBps = 0
+	if err := e.simulate(); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(e.trades) != 2 {
+		t.Fatalf("got %d trades, want 2", len(e.trades))
+	}
+	first, second := e.trades[0], e.trades[1]
+	if first.EntryPrice != 50000 || first.ExitPrice != 50500 || first.Reason != "take_profit" {
+		t.Errorf("first unit = %.0f -> %.0f (%s), want 50000 -> 50500 take_profit", first.EntryPrice, first.ExitPrice, first.Reason)
+	}
+	if second.EntryPrice != 50200 || second.ExitPrice != 51500 || second.Reason != "take_profit" {
+		t.Errorf("second unit = %.0f -> %.0f (%s), want 50200 -> 51500 take_profit", second.EntryPrice, second.ExitPrice, second.Reason)
+	}
+
+	cv, _ := delta.ParseContractValue(e.getProduct("BTCUSD"))
+	wantPnL := first.Size*cv*500 + second.Size*cv*1300
+	if got := first.NetPnL + second.NetPnL; math.Abs(got-wantPnL) > 1e-9 {
+		t.Errorf("aggregate P&L = %.6f, want %.6f", got, wantPnL)
+	}
+	if got := e.equity - e.config.InitialCapital; math.Abs(got-wantPnL) > 1e-9 {
+		t.Errorf("equity change = %.6f, want %.6f", got, wantPnL)
+	}
+	if len(e.positions) != 0 || e.usedMargin > 1e-9 {
+		t.Errorf("positions=%d usedMargin=%.6f after both exits, want none", len(e.positions), e.usedMargin)
+	}
+}
+
+func TestEngine_SingleUnitIgnoresSameSideSignal(t *testing.T) {
+	candles, signals := twoUnitCandles()
+	e := newTestEngine(candles, signals)
+	if err := e.simulate(); err != nil {
+		t.Fatal(err)
+	}
+	if len(e.trades) != 1 || e.trades[0].ExitPrice != 50500 {
+		t.Fatalf("trades = %+v, want only the first unit", e.trades)
+	}
+}
@@ -1,6 +1,7 @@
 package backtest
 
 import (
+	"context"
 	"fmt"
 	"time"
 )
@@ -10,6 +11,16 @@ type WalkForwardConfig struct {
 	TrainingPeriod time.Duration // e.g., 6 months
 	TestingPeriod  time.Duration // e.g., 1 month
 	Anchored       bool          // If true, training window expands from start
+
+	// Stability inputs: the first WarmupWindows windows and windows with fewer than
+	// MinTradesPerWindow trades are left out; WeightByTrades weights the rest by trade count
+	WarmupWindows      int
+	MinTradesPerWindow int
+	WeightByTrades     bool
+
+	// Skip test windows where any symbol has less than this fraction of the expected
+	// candles, e.g. 0.9 (0 = off). Gap-filled bars count as present.
+	MinCoverage float64
 }
 
 // DefaultWalkForwardConfig returns sensible defaults
@@ -18,6 +29,8 @@ func DefaultWalkForwardConfig() WalkForwardConfig {
 		TrainingPeriod: 180 * 24 * time.Hour, // 6 months
 		TestingPeriod:  30 * 24 * time.Hour,  // 1 month
 		Anchored:       false,                // Rolling window
+
+		MinTradesPerWindow: 5,
 	}
 }
 
@@ -30,11 +43,21 @@ type WindowResult struct {
 	TestMetrics Metrics
 }
 
+// SkippedWindow is a test window left out for insufficient candle coverage
+type SkippedWindow struct {
+	TestStart time.Time
+	TestEnd   time.Time
+	Symbol    string  // First symbol below MinCoverage
+	Coverage  float64 // Its fraction of expected candles
+}
+
 // WalkForwardResult contains combined walk-forward analysis results
 type WalkForwardResult struct {
 	Windows   []WindowResult
+	Skipped   []SkippedWindow
 	Combined  Metrics // Combined OOS metrics
 	Stability float64 // Consistency score (0-1)
+	Excluded  int     // Warmup or too-few-trades windows left out of Stability
 	Summary   string
 }
 
@@ -56,6 +79,11 @@ func NewWalkForwardAnalyzer(baseConfig Config, wfConfig WalkForwardConfig, facto
 
 // Run performs walk-forward analysis
 func (wf *WalkForwardAnalyzer) Run() (*WalkForwardResult, error) {
+	return wf.RunContext(context.Background())
+}
+
+// RunContext performs walk-forward analysis, stopping early if ctx is cancelled
+func (wf *WalkForwardAnalyzer) RunContext(ctx context.Context) (*WalkForwardResult, error) {
 	fmt.Println("=== Walk-Forward Analysis ===")
 	fmt.Printf("Training Period: %d days\n", int(wf.wfConfig.TrainingPeriod.Hours()/24))
 	fmt.Printf("Testing Period: %d days\n", int(wf.wfConfig.TestingPeriod.Hours()/24))
@@ -89,11 +117,21 @@ func (wf *WalkForwardAnalyzer) Run() (*WalkForwardResult, error) {
 		testConfig.EndTime = window.testEnd
 
 		engine := wf.engineFactory(testConfig)
-		res, err := engine.Run()
+		res, skipped, err := wf.runWindow(ctx, engine)
+		if ctxErr := ctx.Err(); ctxErr != nil {
+			return nil, ctxErr
+		}
 		if err != nil {
 			fmt.Printf("  Error: %v\n", err)
 			continue
 		}
+		if skipped != nil {
+			skipped.TestStart, skipped.TestEnd = window.testStart, window.testEnd
+			result.Skipped = append(result.Skipped, *skipped)
+			fmt.Printf("  Skipped: %s has %.0f%% candle coverage (min %.0f%%)\n",
+				skipped.Symbol, skipped.Coverage*100, wf.wfConfig.MinCoverage*100)
+			continue
+		}
 
 		windowResult := WindowResult{
 			TrainStart:  window.trainStart,
@@ -119,7 +157,9 @@ func (wf *WalkForwardAnalyzer) Run() (*WalkForwardResult, error) {
 	result.Combined = mc.Calculate(allTrades, allEquity)
 
 	// Calculate stability score
-	result.Stability = wf.calculateStability(result.Windows)
+	scored := wf.stabilityWindows(result.Windows)
+	result.Excluded = len(result.Windows) - len(scored)
+	result.Stability = wf.calculateStability(scored)
 
 	// Generate summary
 	result.Summary = wf.generateSummary(result)
@@ -127,6 +167,27 @@ func (wf *WalkForwardAnalyzer) Run() (*WalkForwardResult, error) {
 	return result, nil
 }
 
+// runWindow runs the engine over its test window. With MinCoverage set the data is loaded
+// first and the window is skipped, without simulating, if any symbol is too sparse.
+func (wf *WalkForwardAnalyzer) runWindow(ctx context.Context, engine *Engine) (*Result, *SkippedWindow, error) {
+	if wf.wfConfig.MinCoverage <= 0 {
+		res, err := engine.RunContext(ctx)
+		return res, nil, err
+	}
+
+	if err := engine.loadData(ctx); err != nil {
+		return nil, nil, fmt.Errorf("failed to load data: %w", err)
+	}
+	for _, symbol := range engine.config.Symbols {
+		coverage := CandleCoverage(engine.candles[symbol], engine.config.Resolution, engine.config.StartTime, engine.config.EndTime)
+		if coverage < wf.wfConfig.MinCoverage {
+			return nil, &SkippedWindow{Symbol: symbol, Coverage: coverage}, nil
+		}
+	}
+	res, err := engine.runLoaded()
+	return res, nil, err
+}
+
 type window struct {
 	trainStart time.Time
 	trainEnd   time.Time
@@ -195,38 +256,60 @@ func (wf *WalkForwardAnalyzer) generateWindows() []window {
 	return windows
 }
 
+// stabilityWindows drops the warmup windows and windows with too few trades to be meaningful
+func (wf *WalkForwardAnalyzer) stabilityWindows(windows []WindowResult) []WindowResult {
+	var kept []WindowResult
+	for i, w := range windows {
+		if i < wf.wfConfig.WarmupWindows || w.TestMetrics.TotalTrades < wf.wfConfig.MinTradesPerWindow {
+			continue
+		}
+		kept = append(kept, w)
+	}
+	return kept
+}
+
 // calculateStability computes consistency across windows
 func (wf *WalkForwardAnalyzer) calculateStability(windows []WindowResult) float64 {
 	if len(windows) < 2 {
 		return 0
 	}
 
-	// Calculate what percentage of windows are profitable
-	profitableCount := 0
-	var sharpes []float64
+	// Each window counts once, or by its trade count with WeightByTrades
+	weight := func(w WindowResult) float64 {
+		if wf.wfConfig.WeightByTrades {
+			return float64(w.TestMetrics.TotalTrades)
+		}
+		return 1
+	}
 
+	// Calculate what (weighted) share of windows are profitable
+	profitableWeight, totalWeight := 0.0, 0.0
 	for _, w := range windows {
 		if w.TestMetrics.TotalReturn > 0 {
-			profitableCount++
+			profitableWeight += weight(w)
 		}
-		sharpes = append(sharpes, w.TestMetrics.SharpeRatio)
+		totalWeight += weight(w)
+	}
+	if totalWeight == 0 {
+		return 0
 	}
 
-	profitability := float64(profitableCount) / float64(len(windows))
+	profitability := profitableWeight / totalWeight
 
 	// Calculate Sharpe consistency (inverse of coefficient of variation)
-	if len(sharpes) > 1 {
+	if len(windows) > 1 {
 		mean := 0.0
-		for _, s := range sharpes {
-			mean += s
+		for _, w := range windows {
+			mean += weight(w) * w.TestMetrics.SharpeRatio
 		}
-		mean /= float64(len(sharpes))
+		mean /= totalWeight
 
 		variance := 0.0
-		for _, s := range sharpes {
-			variance += (s - mean) * (s - mean)
+		for _, w := range windows {
+			s := w.TestMetrics.SharpeRatio
+			variance += weight(w) * (s - mean) * (s - mean)
 		}
-		variance /= float64(len(sharpes))
+		variance /= totalWeight
 
 		stdDev := 0.0
 		if variance > 0 {
@@ -265,6 +348,8 @@ func (wf *WalkForwardAnalyzer) generateSummary(result *WalkForwardResult) string
 	summary := fmt.Sprintf(`
 === Walk-Forward Summary ===
 Windows: %d total, %d profitable (%.0f%%)
+Excluded from stability: %d (warmup or < %d trades)
+Skipped for coverage: %d (< %.0f%% of expected candles)
 Combined OOS Return: %.2f%%
 Combined Sharpe: %.2f
 Max Drawdown: %.2f%%
@@ -278,6 +363,10 @@ Interpretation:
 		len(result.Windows),
 		profitableWindows,
 		float64(profitableWindows)/float64(len(result.Windows))*100,
+		result.Excluded,
+		wf.wfConfig.MinTradesPerWindow,
+		len(result.Skipped),
+		wf.wfConfig.MinCoverage*100,
 		result.Combined.TotalReturn*100,
 		result.Combined.SharpeRatio,
 		result.Combined.MaxDrawdown*100,
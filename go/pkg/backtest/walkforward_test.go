@@ -0,0 +1,117 @@
+package backtest
+
+import (
+	"math"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/kasyap/delta-go/go/pkg/delta"
+)
+
+func wfWindow(trades int, ret, sharpe float64) WindowResult {
+	return WindowResult{TestMetrics: Metrics{TotalTrades: trades, TotalReturn: ret, SharpeRatio: sharpe}}
+}
+
+func TestWalkForward_StabilityExcludesThinWindows(t *testing.T) {
+	windows := []WindowResult{
+		wfWindow(12, -0.02, -0.5),
+		wfWindow(1, 0.30, 6.0), // One lucky trade
+		wfWindow(10, -0.01, -0.4),
+	}
+
+	cfg := DefaultWalkForwardConfig()
+	cfg.MinTradesPerWindow = 0
+	unfiltered := NewWalkForwardAnalyzer(DefaultConfig(), cfg, nil)
+	if got := unfiltered.calculateStability(unfiltered.stabilityWindows(windows)); got <= 1.0/3-1e-9 {
+		t.Fatalf("unfiltered stability = %.3f, expected the lucky window to count as profitable", got)
+	}
+
+	cfg.MinTradesPerWindow = 5
+	wf := NewWalkForwardAnalyzer(DefaultConfig(), cfg, nil)
+	scored := wf.stabilityWindows(windows)
+	if len(scored) != 2 {
+		t.Fatalf("scored %d windows, want 2", len(scored))
+	}
+
+	// Only the two losing windows remain: profitability 0, Sharpe CV = 0.05/0.45
+	want := (0 + 1/(1+0.05/0.45)) / 2
+	if got := wf.calculateStability(scored); math.Abs(got-want) > 1e-6 {
+		t.Errorf("stability = %.4f, want %.4f", got, want)
+	}
+}
+
+func TestWalkForward_WarmupAndTradeWeighting(t *testing.T) {
+	windows := []WindowResult{
+		wfWindow(50, -0.10, -1.0), // Warmup
+		wfWindow(30, 0.05, 1.0),
+		wfWindow(10, -0.02, 1.0),
+	}
+
+	cfg := DefaultWalkForwardConfig()
+	cfg.WarmupWindows = 1
+	cfg.WeightByTrades = true
+	wf := NewWalkForwardAnalyzer(DefaultConfig(), cfg, nil)
+
+	scored := wf.stabilityWindows(windows)
+	if len(scored) != 2 {
+		t.Fatalf("scored %d windows, want 2 after warmup", len(scored))
+	}
+	// 30 of 40 weighted trades are in the profitable window; equal Sharpes are fully consistent
+	if got, want := wf.calculateStability(scored), (0.75+1)/2; math.Abs(got-want) > 1e-9 {
+		t.Errorf("stability = %.4f, want %.4f", got, want)
+	}
+}
+
+func TestWalkForward_SkipsSparseWindows(t *testing.T) {
+	day := 24 * time.Hour
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	cfg := DefaultConfig()
+	cfg.Symbols = []string{"BTCUSD"}
+	cfg.Resolution = "1h"
+	cfg.SimulateFunding = false
+	cfg.StartTime = start
+	cfg.EndTime = start.Add(5 * day)
+	cfg.DataCacheDir = t.TempDir()
+
+	wfCfg := DefaultWalkForwardConfig()
+	wfCfg.TrainingPeriod = 2 * day
+	wfCfg.TestingPeriod = day
+	wfCfg.MinCoverage = 0.9
+
+	// Seed the cache for each test window; the feed dies six hours into day 4
+	loader := NewDataLoader(nil, cfg.DataCacheDir)
+	for _, from := range []time.Time{start.Add(2 * day), start.Add(3 * day), start.Add(4 * day)} {
+		bars := 24
+		if from.Equal(start.Add(3 * day)) {
+			bars = 6
+		}
+		var candles []delta.Candle
+		for i := 0; i < bars; i++ {
+			candles = append(candles, delta.Candle{Time: from.Add(time.Duration(i) * time.Hour).Unix(), Open: 100, High: 101, Low: 99, Close: 100, Volume: 1})
+		}
+		if err := loader.saveToCache("BTCUSD", "1h", from, from.Add(day), candles); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	wf := NewWalkForwardAnalyzer(cfg, wfCfg, func(c Config) *Engine { return NewEngine(c, nil) })
+	result, err := wf.Run()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(result.Windows) != 2 {
+		t.Errorf("ran %d windows, want 2", len(result.Windows))
+	}
+	if len(result.Skipped) != 1 {
+		t.Fatalf("skipped %d windows, want 1", len(result.Skipped))
+	}
+	s := result.Skipped[0]
+	if !s.TestStart.Equal(start.Add(3*day)) || s.Symbol != "BTCUSD" || s.Coverage != 0.25 {
+		t.Errorf("skipped window = %+v, want day 4 at 25%% coverage", s)
+	}
+	if !strings.Contains(result.Summary, "Skipped for coverage: 1") {
+		t.Errorf("summary does not report the skipped window:\n%s", result.Summary)
+	}
+}
@@ -5,6 +5,7 @@ import (
 	"crypto/sha256"
 	"encoding/hex"
 	"fmt"
+	"net/http"
 	"strconv"
 	"time"
 )
@@ -28,12 +29,16 @@ func GenerateTimestamp() string {
 	return strconv.FormatInt(time.Now().Unix(), 10)
 }
 
+// SubAccountHeader carries the sub-account a request acts on
+const SubAccountHeader = "subaccount-id"
+
 // AuthHeaders represents the authentication headers required by Delta Exchange
 type AuthHeaders struct {
-	APIKey    string
-	Signature string
-	Timestamp string
-	UserAgent string
+	APIKey       string
+	Signature    string
+	Timestamp    string
+	UserAgent    string
+	SubAccountID string // Empty for the main account
 }
 
 // NewAuthHeaders generates authentication headers for a request
@@ -49,6 +54,17 @@ func NewAuthHeaders(apiKey, apiSecret, method, path, queryString, body string) *
 	}
 }
 
+// Apply sets the auth headers on req, including the sub-account header when set
+func (a *AuthHeaders) Apply(req *http.Request) {
+	req.Header.Set("api-key", a.APIKey)
+	req.Header.Set("signature", a.Signature)
+	req.Header.Set("timestamp", a.Timestamp)
+	req.Header.Set("User-Agent", a.UserAgent)
+	if a.SubAccountID != "" {
+		req.Header.Set(SubAccountHeader, a.SubAccountID)
+	}
+}
+
 // Validate checks if the timestamp is within acceptable range (5 seconds)
 func (a *AuthHeaders) Validate() error {
 	ts, err := strconv.ParseInt(a.Timestamp, 10, 64)
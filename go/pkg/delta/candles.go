@@ -4,13 +4,51 @@ import (
 	"encoding/json"
 	"fmt"
 	"net/url"
+	"sort"
 	"strconv"
+	"strings"
 	"time"
 )
 
-// GetCandles fetches historical OHLC candles
+// defaultCandlePageLimit is Delta's per-request cap on /history/candles
+const defaultCandlePageLimit = 2000
+
+// GetCandles fetches historical OHLC candles, oldest first
 // resolution: "1m", "5m", "15m", "30m", "1h", "2h", "4h", "6h", "1d", "7d", "30d"
+// Ranges longer than the per-request cap (CandlePageLimit) are fetched page by page and
+// merged, with bars repeated across page boundaries kept once.
 func (c *Client) GetCandles(symbol string, resolution string, start, end time.Time) ([]Candle, error) {
+	limit := c.cfg.CandlePageLimit
+	if limit <= 0 {
+		limit = defaultCandlePageLimit
+	}
+	span := time.Duration(0)
+	if step, err := ParseResolution(resolution); err == nil {
+		span = step * time.Duration(limit)
+	}
+
+	var all []Candle
+	for from := start; ; {
+		to := end
+		if span > 0 && from.Add(span).Before(end) {
+			to = from.Add(span)
+		}
+		page, err := c.getCandlePage(symbol, resolution, from, to)
+		if err != nil {
+			return nil, err
+		}
+		all = append(all, page...)
+		if !to.Before(end) {
+			break
+		}
+		from = to
+	}
+
+	return SortCandles(all), nil
+}
+
+// getCandlePage fetches one /history/candles request
+func (c *Client) getCandlePage(symbol, resolution string, start, end time.Time) ([]Candle, error) {
 	query := url.Values{}
 	query.Set("symbol", symbol)
 	query.Set("resolution", resolution)
@@ -30,44 +68,145 @@ func (c *Client) GetCandles(symbol string, resolution string, start, end time.Ti
 	return candles, nil
 }
 
-// GetRecentCandles fetches recent candles (last N)
+// SortCandles orders candles oldest first and drops repeated timestamps, keeping the last
+// copy seen
+func SortCandles(candles []Candle) []Candle {
+	sort.SliceStable(candles, func(i, j int) bool { return candles[i].Time < candles[j].Time })
+	out := candles[:0]
+	for _, cd := range candles {
+		if n := len(out); n > 0 && out[n-1].Time == cd.Time {
+			out[n-1] = cd
+			continue
+		}
+		out = append(out, cd)
+	}
+	return out
+}
+
+// GetRecentCandles fetches the last count candles, paging as needed
 func (c *Client) GetRecentCandles(symbol string, resolution string, count int) ([]Candle, error) {
 	// Calculate time range based on resolution and count
-	duration := resolutionToDuration(resolution) * time.Duration(count)
+	step, err := ParseResolution(resolution)
+	if err != nil {
+		return nil, err
+	}
 	end := time.Now()
-	start := end.Add(-duration)
+	start := end.Add(-step * time.Duration(count))
+
+	candles, err := c.GetCandles(symbol, resolution, start, end)
+	if err != nil {
+		return nil, err
+	}
+	if len(candles) > count {
+		candles = candles[len(candles)-count:]
+	}
+	return candles, nil
+}
 
-	return c.GetCandles(symbol, resolution, start, end)
+// resolutions lists the candle resolutions Delta serves, by canonical name
+var resolutions = []struct {
+	name string
+	step time.Duration
+}{
+	{"1m", time.Minute},
+	{"5m", 5 * time.Minute},
+	{"15m", 15 * time.Minute},
+	{"30m", 30 * time.Minute},
+	{"1h", time.Hour},
+	{"2h", 2 * time.Hour},
+	{"4h", 4 * time.Hour},
+	{"6h", 6 * time.Hour},
+	{"1d", 24 * time.Hour},
+	{"7d", 7 * 24 * time.Hour},
+	{"30d", 30 * 24 * time.Hour},
 }
 
-// resolutionToDuration converts resolution string to duration
-func resolutionToDuration(resolution string) time.Duration {
-	switch resolution {
-	case "1m":
-		return time.Minute
-	case "5m":
-		return 5 * time.Minute
-	case "15m":
-		return 15 * time.Minute
-	case "30m":
-		return 30 * time.Minute
-	case "1h":
-		return time.Hour
-	case "2h":
-		return 2 * time.Hour
-	case "4h":
-		return 4 * time.Hour
-	case "6h":
-		return 6 * time.Hour
-	case "1d":
-		return 24 * time.Hour
-	case "7d":
-		return 7 * 24 * time.Hour
-	case "30d":
-		return 30 * 24 * time.Hour
+// ParseResolution converts a resolution string ("5m", "1h", "1d") to its duration. It is the
+// single source of truth for supported resolutions: anything NormalizeResolution rejects is
+// an error rather than a default.
+func ParseResolution(resolution string) (time.Duration, error) {
+	name, err := NormalizeResolution(resolution)
+	if err != nil {
+		return 0, err
+	}
+	for _, r := range resolutions {
+		if r.name == name {
+			return r.step, nil
+		}
+	}
+	return 0, fmt.Errorf("unsupported resolution %q", resolution)
+}
+
+// NormalizeResolution returns the canonical name of a supported resolution. Case and
+// surrounding space are ignored, and equivalent spellings map to the canonical one
+// ("60m" and "1H" are "1h", "24h" is "1d").
+func NormalizeResolution(resolution string) (string, error) {
+	s := strings.ToLower(strings.TrimSpace(resolution))
+	if len(s) < 2 {
+		return "", fmt.Errorf("unsupported resolution %q", resolution)
+	}
+
+	n, err := strconv.Atoi(s[:len(s)-1])
+	if err != nil || n <= 0 {
+		return "", fmt.Errorf("unsupported resolution %q", resolution)
+	}
+	var unit time.Duration
+	switch s[len(s)-1] {
+	case 'm':
+		unit = time.Minute
+	case 'h':
+		unit = time.Hour
+	case 'd':
+		unit = 24 * time.Hour
 	default:
-		return time.Minute
+		return "", fmt.Errorf("unsupported resolution %q", resolution)
+	}
+
+	step := time.Duration(n) * unit
+	for _, r := range resolutions {
+		if r.step == step {
+			return r.name, nil
+		}
+	}
+	return "", fmt.Errorf("unsupported resolution %q", resolution)
+}
+
+// AggregateCandles rolls lower-timeframe candles up into the target resolution
+// Input must be sorted by time; an unsupported resolution yields nil. Buckets are aligned to
+// multiples of the target duration; the trailing bucket may be incomplete (the currently
+// forming candle).
+func AggregateCandles(candles []Candle, resolution string) []Candle {
+	step, err := ParseResolution(resolution)
+	if err != nil || len(candles) == 0 {
+		return nil
+	}
+	bucketSecs := int64(step / time.Second)
+
+	var result []Candle
+	for _, c := range candles {
+		bucket := c.Time - c.Time%bucketSecs
+		if n := len(result); n > 0 && result[n-1].Time == bucket {
+			agg := &result[n-1]
+			if c.High > agg.High {
+				agg.High = c.High
+			}
+			if c.Low < agg.Low {
+				agg.Low = c.Low
+			}
+			agg.Close = c.Close
+			agg.Volume += c.Volume
+			continue
+		}
+		result = append(result, Candle{
+			Time:   bucket,
+			Open:   c.Open,
+			High:   c.High,
+			Low:    c.Low,
+			Close:  c.Close,
+			Volume: c.Volume,
+		})
 	}
+	return result
 }
 
 // CandlesToHMMInput converts candles to format suitable for HMM processing
@@ -1,7 +1,12 @@
 package delta
 
 import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
 	"testing"
+	"time"
 )
 
 func TestCandleHistory(t *testing.T) {
@@ -19,3 +24,129 @@ func TestCandleHistory(t *testing.T) {
 		t.Error("Candle time mismatch")
 	}
 }
+
+func TestAggregateCandles(t *testing.T) {
+	// 12 five-minute candles starting on the hour roll up into one 1h candle
+	var candles []Candle
+	for i := 0; i < 13; i++ {
+		candles = append(candles, Candle{
+			Time:   3600 + int64(i)*300,
+			Open:   100 + float64(i),
+			High:   110 + float64(i),
+			Low:    90 - float64(i),
+			Close:  101 + float64(i),
+			Volume: 1,
+		})
+	}
+
+	agg := AggregateCandles(candles, "1h")
+	if len(agg) != 2 {
+		t.Fatalf("expected 2 hourly candles, got %d", len(agg))
+	}
+
+	h := agg[0]
+	if h.Time != 3600 || h.Open != 100 || h.High != 121 || h.Low != 79 || h.Close != 112 || h.Volume != 12 {
+		t.Errorf("unexpected first hourly candle: %+v", h)
+	}
+	if agg[1].Time != 7200 || agg[1].Open != 112 {
+		t.Errorf("unexpected trailing candle: %+v", agg[1])
+	}
+}
+
+func TestParseResolution(t *testing.T) {
+	want := map[string]time.Duration{
+		"1m":  time.Minute,
+		"5m":  5 * time.Minute,
+		"15m": 15 * time.Minute,
+		"30m": 30 * time.Minute,
+		"1h":  time.Hour,
+		"2h":  2 * time.Hour,
+		"4h":  4 * time.Hour,
+		"6h":  6 * time.Hour,
+		"1d":  24 * time.Hour,
+		"7d":  7 * 24 * time.Hour,
+		"30d": 30 * 24 * time.Hour,
+	}
+	for res, step := range want {
+		got, err := ParseResolution(res)
+		if err != nil || got != step {
+			t.Errorf("ParseResolution(%q) = %v, %v; want %v", res, got, err, step)
+		}
+	}
+
+	for _, bad := range []string{"7m", "", "m", "5x", "-5m", "0h", "1w"} {
+		if _, err := ParseResolution(bad); err == nil {
+			t.Errorf("ParseResolution(%q) should fail", bad)
+		}
+	}
+}
+
+func TestNormalizeResolution(t *testing.T) {
+	for in, want := range map[string]string{"5m": "5m", " 1H ": "1h", "60m": "1h", "24h": "1d", "168h": "7d"} {
+		got, err := NormalizeResolution(in)
+		if err != nil || got != want {
+			t.Errorf("NormalizeResolution(%q) = %q, %v; want %q", in, got, err, want)
+		}
+	}
+}
+
+func TestGetCandles_Paginates(t *testing.T) {
+	var pages []int
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		start, _ := strconv.ParseInt(r.URL.Query().Get("start"), 10, 64)
+		end, _ := strconv.ParseInt(r.URL.Query().Get("end"), 10, 64)
+		// Newest first, repeating the previous page's last bar
+		var bars []string
+		for ts := end - 60; ts >= start; ts -= 60 {
+			bars = append(bars, fmt.Sprintf(`{"time":%d,"close":%d}`, ts, ts))
+		}
+		pages = append(pages, len(bars))
+		if start > 0 {
+			bars = append(bars, fmt.Sprintf(`{"time":%d,"close":%d}`, start-60, start-60))
+		}
+		fmt.Fprintf(w, `{"success":true,"result":[%s]}`, strings.Join(bars, ","))
+	})
+	c.cfg.CandlePageLimit = 3
+
+	candles, err := c.GetCandles("BTCUSD", "1m", time.Unix(0, 0), time.Unix(8*60, 0))
+	if err != nil {
+		t.Fatalf("GetCandles: %v", err)
+	}
+	if len(pages) != 3 || pages[0] != 3 || pages[1] != 3 || pages[2] != 2 {
+		t.Errorf("page sizes = %v, want [3 3 2]", pages)
+	}
+	if len(candles) != 8 {
+		t.Fatalf("got %d candles, want 8: %+v", len(candles), candles)
+	}
+	for i, cd := range candles {
+		if cd.Time != int64(i*60) {
+			t.Fatalf("candle %d at %d, want %d: %+v", i, cd.Time, i*60, candles)
+		}
+	}
+}
+
+func TestGetRecentCandles_HonorsCount(t *testing.T) {
+	requests := 0
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		start, _ := strconv.ParseInt(r.URL.Query().Get("start"), 10, 64)
+		end, _ := strconv.ParseInt(r.URL.Query().Get("end"), 10, 64)
+		var bars []string
+		for ts := start - start%60; ts <= end; ts += 60 {
+			bars = append(bars, fmt.Sprintf(`{"time":%d}`, ts))
+		}
+		fmt.Fprintf(w, `{"success":true,"result":[%s]}`, strings.Join(bars, ","))
+	})
+	c.cfg.CandlePageLimit = 4
+
+	candles, err := c.GetRecentCandles("BTCUSD", "1m", 10)
+	if err != nil {
+		t.Fatalf("GetRecentCandles: %v", err)
+	}
+	if requests != 3 {
+		t.Errorf("requests = %d, want 3 pages of up to 4", requests)
+	}
+	if len(candles) != 10 {
+		t.Errorf("got %d candles, want 10", len(candles))
+	}
+}
@@ -8,9 +8,11 @@ import (
 	"net/http"
 	"net/url"
 	"strconv"
+	"sync"
 	"time"
 
 	"github.com/kasyap/delta-go/go/config"
+	"github.com/kasyap/delta-go/go/pkg/alert"
 )
 
 // Client is the Delta Exchange API client
@@ -20,6 +22,10 @@ type Client struct {
 	baseURL       string
 	apiPathPrefix string
 	limiter       *time.Ticker
+	alerter       alert.Alerter
+
+	leverageMu sync.Mutex
+	leverage   map[int]int // Last leverage set per product ID, see SetLeverage
 }
 
 // NewClient creates a new Delta Exchange API client
@@ -49,10 +55,33 @@ func NewClient(cfg *config.Config) *Client {
 		httpClient: &http.Client{
 			Timeout: 30 * time.Second,
 		},
-		limiter: time.NewTicker(interval),
+		limiter:  time.NewTicker(interval),
+		alerter:  alert.Nop{},
+		leverage: make(map[int]int),
 	}
 }
 
+// NewClients creates one client per configured account, keyed by account name
+func NewClients(cfg *config.Config) (map[string]*Client, error) {
+	clients := make(map[string]*Client, len(cfg.Accounts))
+	for name := range cfg.Accounts {
+		acctCfg, err := cfg.ForAccount(name)
+		if err != nil {
+			for _, c := range clients {
+				c.Close()
+			}
+			return nil, err
+		}
+		clients[name] = NewClient(acctCfg)
+	}
+	return clients, nil
+}
+
+// SetAlerter installs the alerter notified when the exchange rejects an order
+func (c *Client) SetAlerter(a alert.Alerter) {
+	c.alerter = a
+}
+
 func (c *Client) Close() {
 	if c.limiter != nil {
 		c.limiter.Stop()
@@ -73,6 +102,34 @@ type APIError struct {
 	Message string `json:"message"`
 }
 
+func (e *APIError) Error() string {
+	return fmt.Sprintf("API error %s: %s", e.Code, e.Message)
+}
+
+// StatusError is a non-2xx HTTP response. API holds the structured error when the body has one.
+type StatusError struct {
+	StatusCode int
+	Body       string
+	API        *APIError
+}
+
+func (e *StatusError) Error() string {
+	if e.API != nil {
+		return fmt.Sprintf("http %d: %v", e.StatusCode, e.API)
+	}
+	return fmt.Sprintf("http %d: %s", e.StatusCode, e.Body)
+}
+
+func (e *StatusError) Unwrap() error {
+	if e.API == nil {
+		return nil
+	}
+	return e.API
+}
+
+// requestBackoff is the linear backoff step between attempts of a failed request
+var requestBackoff = time.Second
+
 // doRequest performs an authenticated HTTP request with proper retry logic
 func (c *Client) doRequest(method, path string, query url.Values, body interface{}) (*APIResponse, error) {
 	<-c.limiter.C // Rate limiting without locks
@@ -103,6 +160,7 @@ func (c *Client) doRequest(method, path string, query url.Values, body interface
 	var lastErr error
 	for attempt := 0; attempt < 3; attempt++ {
 		authHeaders := NewAuthHeaders(c.cfg.APIKey, c.cfg.APISecret, method, signaturePath, queryString, bodyStr)
+		authHeaders.SubAccountID = c.cfg.SubAccountID
 
 		req, err := http.NewRequest(method, fullURL, bytes.NewReader(bodyBytes))
 		if err != nil {
@@ -111,15 +169,15 @@ func (c *Client) doRequest(method, path string, query url.Values, body interface
 
 		req.Header.Set("Content-Type", "application/json")
 		req.Header.Set("Accept", "application/json")
-		req.Header.Set("api-key", authHeaders.APIKey)
-		req.Header.Set("signature", authHeaders.Signature)
-		req.Header.Set("timestamp", authHeaders.Timestamp)
-		req.Header.Set("User-Agent", authHeaders.UserAgent)
+		authHeaders.Apply(req)
 
 		resp, err := c.httpClient.Do(req)
 		if err != nil {
+			if !IsTransient(err) {
+				return nil, fmt.Errorf("request failed: %w", err)
+			}
 			lastErr = err
-			time.Sleep(time.Duration(attempt+1) * time.Second)
+			time.Sleep(time.Duration(attempt+1) * requestBackoff)
 			continue
 		}
 
@@ -131,7 +189,7 @@ func (c *Client) doRequest(method, path string, query url.Values, body interface
 
 		// Retry on rate limit or server errors
 		if resp.StatusCode == 429 || resp.StatusCode >= 500 {
-			lastErr = fmt.Errorf("http %d: %s", resp.StatusCode, string(respBody))
+			lastErr = &StatusError{StatusCode: resp.StatusCode, Body: string(respBody)}
 			if resp.StatusCode == 429 {
 				if ra := resp.Header.Get("Retry-After"); ra != "" {
 					if secs, err := strconv.Atoi(ra); err == nil && secs > 0 {
@@ -140,13 +198,18 @@ func (c *Client) doRequest(method, path string, query url.Values, body interface
 					}
 				}
 			}
-			time.Sleep(time.Duration(attempt+1) * time.Second)
+			time.Sleep(time.Duration(attempt+1) * requestBackoff)
 			continue
 		}
 
-		// Non-retryable HTTP errors
+		// Non-retryable HTTP errors - surface the structured API error when present
 		if resp.StatusCode < 200 || resp.StatusCode >= 300 {
-			return nil, fmt.Errorf("http %d: %s", resp.StatusCode, string(respBody))
+			statusErr := &StatusError{StatusCode: resp.StatusCode, Body: string(respBody)}
+			var errResp APIResponse
+			if err := json.Unmarshal(respBody, &errResp); err == nil && errResp.Error != nil {
+				statusErr.API = errResp.Error
+			}
+			return nil, statusErr
 		}
 
 		var apiResp APIResponse
@@ -156,7 +219,7 @@ func (c *Client) doRequest(method, path string, query url.Values, body interface
 
 		if !apiResp.Success {
 			if apiResp.Error != nil {
-				return nil, fmt.Errorf("API error %s: %s", apiResp.Error.Code, apiResp.Error.Message)
+				return nil, apiResp.Error
 			}
 			return nil, fmt.Errorf("API error: %s", string(respBody))
 		}
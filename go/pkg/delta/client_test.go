@@ -1,6 +1,10 @@
 package delta
 
 import (
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
 	"testing"
 
 	"github.com/kasyap/delta-go/go/config"
@@ -22,3 +26,105 @@ func TestNewClient_ExtractsPathPrefix(t *testing.T) {
 		t.Fatalf("apiPathPrefix mismatch: got=%q want=%q", c.apiPathPrefix, "/v2")
 	}
 }
+
+// newTestClient returns a Client pointed at a mock server running handler
+func newTestClient(t *testing.T, handler http.HandlerFunc) *Client {
+	t.Helper()
+	srv := httptest.NewServer(handler)
+	t.Cleanup(srv.Close)
+
+	c := NewClient(&config.Config{
+		BaseURL:         srv.URL + "/v2",
+		APIKey:          "k",
+		APISecret:       "s",
+		APIRateLimitRPS: 100,
+	})
+	t.Cleanup(c.Close)
+	return c
+}
+
+func TestDoRequest_SubAccountHeader(t *testing.T) {
+	var gotKey, gotSub string
+	var hasSub bool
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		gotKey = r.Header.Get("api-key")
+		gotSub = r.Header.Get(SubAccountHeader)
+		_, hasSub = r.Header[http.CanonicalHeaderKey(SubAccountHeader)]
+		w.Write([]byte(`{"success":true,"result":[]}`))
+	})
+
+	if _, err := c.doRequest(http.MethodGet, "/positions/margined", nil, nil); err != nil {
+		t.Fatalf("doRequest() error = %v", err)
+	}
+	if hasSub {
+		t.Errorf("sub-account header sent without a sub-account: %q", gotSub)
+	}
+
+	c.cfg.SubAccountID = "sub-42"
+	if _, err := c.doRequest(http.MethodGet, "/positions/margined", nil, nil); err != nil {
+		t.Fatalf("doRequest() error = %v", err)
+	}
+	if gotSub != "sub-42" || gotKey != "k" {
+		t.Errorf("headers: api-key=%q %s=%q, want k and sub-42", gotKey, SubAccountHeader, gotSub)
+	}
+}
+
+func TestNewClients_PerAccountCredentials(t *testing.T) {
+	cfg := &config.Config{
+		BaseURL:         "https://api.india.delta.exchange/v2",
+		APIKey:          "main",
+		APISecret:       "main-secret",
+		APIRateLimitRPS: 8,
+		Accounts: map[string]config.Account{
+			"scalper": {APIKey: "k1", APISecret: "s1", SubAccountID: "sub-1"},
+			"funding": {APIKey: "k2", APISecret: "s2"},
+		},
+	}
+
+	clients, err := NewClients(cfg)
+	if err != nil {
+		t.Fatalf("NewClients() error = %v", err)
+	}
+	for _, c := range clients {
+		defer c.Close()
+	}
+	if got := clients["scalper"].cfg; got.APIKey != "k1" || got.SubAccountID != "sub-1" {
+		t.Errorf("scalper client: key=%q sub=%q", got.APIKey, got.SubAccountID)
+	}
+	if got := clients["funding"].cfg; got.APIKey != "k2" || got.SubAccountID != "" {
+		t.Errorf("funding client: key=%q sub=%q", got.APIKey, got.SubAccountID)
+	}
+	if cfg.APIKey != "main" {
+		t.Errorf("base config mutated: APIKey=%q", cfg.APIKey)
+	}
+
+	cfg.Accounts["broken"] = config.Account{APIKey: "k3"}
+	if _, err := NewClients(cfg); err == nil {
+		t.Error("expected an error for an account without a secret")
+	}
+}
+
+func TestDoRequest_DoesNotRetryTLSFailures(t *testing.T) {
+	var conns atomic.Int32
+	srv := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"success":true,"result":[]}`))
+	}))
+	srv.Config.ConnState = func(_ net.Conn, state http.ConnState) {
+		if state == http.StateNew {
+			conns.Add(1)
+		}
+	}
+	srv.StartTLS()
+	t.Cleanup(srv.Close)
+
+	// The default transport doesn't trust the test server's certificate
+	c := NewClient(&config.Config{BaseURL: srv.URL + "/v2", APIKey: "k", APISecret: "s", APIRateLimitRPS: 100})
+	t.Cleanup(c.Close)
+
+	if _, err := c.doRequest(http.MethodGet, "/tickers", nil, nil); err == nil {
+		t.Fatal("doRequest() succeeded against an untrusted certificate")
+	}
+	if n := conns.Load(); n != 1 {
+		t.Errorf("connections = %d, want 1: a TLS failure is not worth retrying", n)
+	}
+}
@@ -2,10 +2,31 @@ package delta
 
 import (
 	"fmt"
+	"log"
 	"math"
 	"strconv"
+	"sync"
 )
 
+type productOverride struct {
+	tickSize      string
+	contractValue string
+}
+
+var (
+	overridesMu      sync.RWMutex
+	productOverrides = make(map[string]productOverride)
+	warnedDefaults   sync.Map // symbol -> struct{}, so the fallback warning is logged once
+)
+
+// RegisterProductOverride sets the tick size and contract value MockProduct returns for a
+// symbol, for backtests without live product metadata. Empty values keep the built-in default.
+func RegisterProductOverride(symbol, tickSize, contractValue string) {
+	overridesMu.Lock()
+	defer overridesMu.Unlock()
+	productOverrides[symbol] = productOverride{tickSize: tickSize, contractValue: contractValue}
+}
+
 // ParseContractValue parses the string contract value from Product to float64
 func ParseContractValue(p *Product) (float64, error) {
 	if p == nil {
@@ -21,6 +42,36 @@ func ParseContractValue(p *Product) (float64, error) {
 	return cv, nil
 }
 
+// ParseTickSize parses the string tick size from Product to float64
+func ParseTickSize(p *Product) (float64, error) {
+	if p == nil {
+		return 0, fmt.Errorf("product is nil")
+	}
+	if p.TickSize == "" {
+		return 0, fmt.Errorf("tick size is empty")
+	}
+	tick, err := strconv.ParseFloat(p.TickSize, 64)
+	if err != nil {
+		return 0, fmt.Errorf("failed to parse tick size '%s': %w", p.TickSize, err)
+	}
+	return tick, nil
+}
+
+// ParseMaintenanceMargin parses the product's maintenance margin, a percentage of notional
+func ParseMaintenanceMargin(p *Product) (float64, error) {
+	if p == nil {
+		return 0, fmt.Errorf("product is nil")
+	}
+	if p.MaintenanceMargin == "" {
+		return 0, fmt.Errorf("maintenance margin is empty")
+	}
+	mm, err := strconv.ParseFloat(p.MaintenanceMargin, 64)
+	if err != nil {
+		return 0, fmt.Errorf("failed to parse maintenance margin '%s': %w", p.MaintenanceMargin, err)
+	}
+	return mm, nil
+}
+
 // NotionalToContracts converts a notional USD amount to number of contracts
 // Formula: Contracts = Notional / (Price * ContractValue) for Linear Futures
 // Note: This implementation assumes Linear Futures (Inverse contracts would be different)
@@ -58,47 +109,78 @@ func ContractsToNotional(contracts int, price float64, product *Product) (float6
 }
 
 // MockProduct returns a Product with typical contract values for backtesting
-// without requiring live API calls. These values are based on Delta Exchange specs.
+// without requiring live API calls. Registered overrides take precedence over the
+// built-in Delta Exchange specs; unknown symbols fall back to a generic default.
 func MockProduct(symbol string) *Product {
+	p := defaultProduct(symbol)
+
+	overridesMu.RLock()
+	o, ok := productOverrides[symbol]
+	overridesMu.RUnlock()
+	if ok {
+		if o.tickSize != "" {
+			p.TickSize = o.tickSize
+		}
+		if o.contractValue != "" {
+			p.ContractValue = o.contractValue
+		}
+		return p
+	}
+
+	if p.ID == 0 {
+		if _, warned := warnedDefaults.LoadOrStore(symbol, struct{}{}); !warned {
+			log.Printf("Warning: no product metadata for %s - using default contract value %s and tick size %s (register an override)",
+				symbol, p.ContractValue, p.TickSize)
+		}
+	}
+	return p
+}
+
+// defaultProduct returns the built-in contract specs for a symbol
+func defaultProduct(symbol string) *Product {
 	// Default values based on Delta Exchange contract specifications
 	// https://docs.delta.exchange
 	switch symbol {
 	case "BTCUSD", "BTCINR":
 		return &Product{
-			ID:            27,
-			Symbol:        symbol,
-			ProductType:   "perpetual_futures",
-			ContractValue: "0.001", // 1 contract = 0.001 BTC
-			TickSize:      "0.5",
-			SettlingAsset: Asset{Symbol: "USDT"},
+			ID:                27,
+			Symbol:            symbol,
+			ProductType:       "perpetual_futures",
+			ContractValue:     "0.001", // 1 contract = 0.001 BTC
+			TickSize:          "0.5",
+			SettlingAsset:     Asset{Symbol: "USDT"},
+			MaintenanceMargin: "0.5", // % of notional
 		}
 	case "ETHUSD", "ETHINR":
 		return &Product{
-			ID:            139,
-			Symbol:        symbol,
-			ProductType:   "perpetual_futures",
-			ContractValue: "0.01", // 1 contract = 0.01 ETH
-			TickSize:      "0.05",
-			SettlingAsset: Asset{Symbol: "USDT"},
+			ID:                139,
+			Symbol:            symbol,
+			ProductType:       "perpetual_futures",
+			ContractValue:     "0.01", // 1 contract = 0.01 ETH
+			TickSize:          "0.05",
+			SettlingAsset:     Asset{Symbol: "USDT"},
+			MaintenanceMargin: "0.5",
 		}
 	case "SOLUSD", "SOLINR":
 		return &Product{
-			ID:            259,
-			Symbol:        symbol,
-			ProductType:   "perpetual_futures",
-			ContractValue: "0.1", // 1 contract = 0.1 SOL
-			TickSize:      "0.01",
-			SettlingAsset: Asset{Symbol: "USDT"},
+			ID:                259,
+			Symbol:            symbol,
+			ProductType:       "perpetual_futures",
+			ContractValue:     "0.1", // 1 contract = 0.1 SOL
+			TickSize:          "0.01",
+			SettlingAsset:     Asset{Symbol: "USDT"},
+			MaintenanceMargin: "1",
 		}
 	default:
 		// Generic default for unknown symbols
 		return &Product{
-			ID:            0,
-			Symbol:        symbol,
-			ProductType:   "perpetual_futures",
-			ContractValue: "0.001",
-			TickSize:      "0.01",
-			SettlingAsset: Asset{Symbol: "USDT"},
+			ID:                0,
+			Symbol:            symbol,
+			ProductType:       "perpetual_futures",
+			ContractValue:     "0.001",
+			TickSize:          "0.01",
+			SettlingAsset:     Asset{Symbol: "USDT"},
+			MaintenanceMargin: "1",
 		}
 	}
 }
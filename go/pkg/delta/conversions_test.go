@@ -0,0 +1,55 @@
+package delta
+
+import (
+	"bytes"
+	"log"
+	"strings"
+	"testing"
+)
+
+func TestRegisterProductOverride_ChangesNotional(t *testing.T) {
+	before, err := NotionalToContracts(1000, 0.5, MockProduct("XRPTEST"))
+	if err != nil {
+		t.Fatalf("NotionalToContracts() error = %v", err)
+	}
+
+	RegisterProductOverride("XRPTEST", "0.0001", "1")
+	p := MockProduct("XRPTEST")
+	if p.TickSize != "0.0001" || p.ContractValue != "1" {
+		t.Fatalf("override not applied: tick=%s cv=%s", p.TickSize, p.ContractValue)
+	}
+
+	after, err := NotionalToContracts(1000, 0.5, p)
+	if err != nil {
+		t.Fatalf("NotionalToContracts() error = %v", err)
+	}
+	if before != 2000000 || after != 2000 {
+		t.Errorf("contracts before/after override = %d/%d, want 2000000/2000", before, after)
+	}
+
+	// Partial override keeps the built-in tick size
+	RegisterProductOverride("ETHUSD", "", "0.1")
+	defer RegisterProductOverride("ETHUSD", "", "")
+	if eth := MockProduct("ETHUSD"); eth.TickSize != "0.05" || eth.ContractValue != "0.1" {
+		t.Errorf("partial override: tick=%s cv=%s", eth.TickSize, eth.ContractValue)
+	}
+}
+
+func TestMockProduct_WarnsOnFallbackDefault(t *testing.T) {
+	var buf bytes.Buffer
+	orig := log.Writer()
+	log.SetOutput(&buf)
+	defer log.SetOutput(orig)
+
+	MockProduct("DOGEMISSING")
+	MockProduct("DOGEMISSING")
+	MockProduct("BTCUSD")
+
+	out := buf.String()
+	if strings.Count(out, "DOGEMISSING") != 1 {
+		t.Errorf("expected exactly one fallback warning, got: %q", out)
+	}
+	if strings.Contains(out, "BTCUSD") {
+		t.Errorf("known symbol should not warn, got: %q", out)
+	}
+}
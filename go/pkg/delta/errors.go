@@ -0,0 +1,82 @@
+package delta
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net"
+	"net/http"
+	"strings"
+	"syscall"
+)
+
+// Error code fragments, matched like reject reasons on lowercased text with separators removed
+var (
+	authErrorFragments = []string{
+		"unauthorized", "invalidapikey", "apikeynotfound", "signature", "ipnotwhitelisted", "forbidden",
+	}
+	notFoundFragments = []string{
+		"notfound", "invalidproduct", "invalidcontract", "invalidsymbol", "nosuchproduct",
+	}
+)
+
+// IsTransient reports whether err is worth retrying later: rate limits, 5xx responses,
+// timeouts and refused or dropped connections. TLS, DNS and malformed-URL failures fail the
+// same way on every attempt and are not transient.
+func IsTransient(err error) bool {
+	if err == nil {
+		return false
+	}
+	var statusErr *StatusError
+	if errors.As(err, &statusErr) && (statusErr.StatusCode == http.StatusTooManyRequests || statusErr.StatusCode >= 500) {
+		return true
+	}
+	var apiErr *APIError
+	if errors.As(err, &apiErr) && ParseRejectReason(apiErr.Code, "") == RejectRateLimited {
+		return true
+	}
+	if errors.Is(err, context.DeadlineExceeded) || errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF) {
+		return true
+	}
+	if errors.Is(err, syscall.ECONNREFUSED) || errors.Is(err, syscall.ECONNRESET) || errors.Is(err, syscall.EPIPE) {
+		return true
+	}
+	var netErr net.Error
+	return errors.As(err, &netErr) && netErr.Timeout()
+}
+
+// IsAuthError reports whether err is a rejected credential, signature or IP: retrying will
+// not help until the configuration is fixed
+func IsAuthError(err error) bool {
+	var statusErr *StatusError
+	if errors.As(err, &statusErr) && (statusErr.StatusCode == http.StatusUnauthorized || statusErr.StatusCode == http.StatusForbidden) {
+		return true
+	}
+	return apiCodeMatches(err, authErrorFragments)
+}
+
+// IsNotFound reports whether err means the requested symbol, product or order does not exist
+func IsNotFound(err error) bool {
+	if errors.Is(err, ErrBracketNotFound) {
+		return true
+	}
+	var statusErr *StatusError
+	if errors.As(err, &statusErr) && statusErr.StatusCode == http.StatusNotFound {
+		return true
+	}
+	return apiCodeMatches(err, notFoundFragments)
+}
+
+func apiCodeMatches(err error, fragments []string) bool {
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) {
+		return false
+	}
+	code := normalizeRejectText(apiErr.Code)
+	for _, f := range fragments {
+		if strings.Contains(code, f) {
+			return true
+		}
+	}
+	return false
+}
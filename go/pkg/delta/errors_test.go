@@ -0,0 +1,73 @@
+package delta
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"syscall"
+	"testing"
+)
+
+func TestErrorClassification(t *testing.T) {
+	tests := []struct {
+		name                      string
+		err                       error
+		transient, auth, notFound bool
+	}{
+		{"nil", nil, false, false, false},
+		{"rate limited", &StatusError{StatusCode: 429, Body: "slow down"}, true, false, false},
+		{"server error", fmt.Errorf("request failed after retries: %w", &StatusError{StatusCode: 503}), true, false, false},
+		{"rate limit code", &APIError{Code: "rate_limit_exceeded"}, true, false, false},
+		{"timeout", fmt.Errorf("get ticker: %w", context.DeadlineExceeded), true, false, false},
+		{"connection refused", &url.Error{Op: "Post", URL: "https://api/orders", Err: &net.OpError{Op: "dial", Net: "tcp", Err: os.NewSyscallError("connect", syscall.ECONNREFUSED)}}, true, false, false},
+		{"dial timeout", &url.Error{Op: "Get", URL: "https://api/tickers", Err: &net.DNSError{Err: "i/o timeout", IsTimeout: true}}, true, false, false},
+		{"tls failure", &url.Error{Op: "Post", URL: "https://api/orders", Err: errors.New("tls: failed to verify certificate")}, false, false, false},
+		{"invalid url", &url.Error{Op: "parse", URL: "://api", Err: errors.New("missing protocol scheme")}, false, false, false},
+		{"unauthorized status", &StatusError{StatusCode: 401, API: &APIError{Code: "UnauthorizedApiAccess"}}, false, true, false},
+		{"bad signature", &APIError{Code: "SignatureExpired"}, false, true, false},
+		{"ip not whitelisted", &APIError{Code: "ip_not_whitelisted_for_api_key"}, false, true, false},
+		{"unknown symbol", &StatusError{StatusCode: 404, API: &APIError{Code: "not_found"}}, false, false, true},
+		{"invalid product", &APIError{Code: "invalid_product"}, false, false, true},
+		{"bracket gone", fmt.Errorf("edit bracket: %w", ErrBracketNotFound), false, false, true},
+		{"margin rejection", &StatusError{StatusCode: 400, API: &APIError{Code: "insufficient_margin"}}, false, false, false},
+		{"plain error", errors.New("boom"), false, false, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := IsTransient(tt.err); got != tt.transient {
+				t.Errorf("IsTransient() = %v, want %v", got, tt.transient)
+			}
+			if got := IsAuthError(tt.err); got != tt.auth {
+				t.Errorf("IsAuthError() = %v, want %v", got, tt.auth)
+			}
+			if got := IsNotFound(tt.err); got != tt.notFound {
+				t.Errorf("IsNotFound() = %v, want %v", got, tt.notFound)
+			}
+		})
+	}
+}
+
+func TestDoRequest_ReturnsStatusError(t *testing.T) {
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+		w.Write([]byte(`{"success":false,"error":{"code":"not_found","message":"no such product"}}`))
+	})
+
+	_, err := c.GetProductBySymbol("NOPEUSD")
+	var statusErr *StatusError
+	if !errors.As(err, &statusErr) || statusErr.StatusCode != http.StatusNotFound {
+		t.Fatalf("err = %v, want *StatusError 404", err)
+	}
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) || apiErr.Code != "not_found" {
+		t.Errorf("structured API error not unwrapped from %v", err)
+	}
+	if !IsNotFound(err) || IsTransient(err) {
+		t.Errorf("404 classified as transient=%v notFound=%v", IsTransient(err), IsNotFound(err))
+	}
+}
@@ -0,0 +1,111 @@
+package delta
+
+import (
+	"fmt"
+	"sync"
+	"time"
+)
+
+// OrderSweeper emulates good-til-time orders, which Delta does not offer: tracked orders
+// that are still open once older than their max age are cancelled by Sweep
+type OrderSweeper struct {
+	client *Client
+
+	mu     sync.Mutex
+	maxAge map[int64]time.Duration // Tracked order ID -> max resting age
+}
+
+// NewOrderSweeper creates a sweeper that cancels through client
+func NewOrderSweeper(client *Client) *OrderSweeper {
+	return &OrderSweeper{
+		client: client,
+		maxAge: make(map[int64]time.Duration),
+	}
+}
+
+// Track sets an expiry on a resting order: it is cancelled once open for longer than maxAge.
+// A non-positive maxAge leaves the order good-til-cancelled.
+func (s *OrderSweeper) Track(orderID int64, maxAge time.Duration) {
+	if maxAge <= 0 {
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.maxAge[orderID] = maxAge
+}
+
+// Untrack removes an order's expiry
+func (s *OrderSweeper) Untrack(orderID int64) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.maxAge, orderID)
+}
+
+// Tracked returns the number of orders with an expiry
+func (s *OrderSweeper) Tracked() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return len(s.maxAge)
+}
+
+// Sweep cancels tracked open orders older than their max age and returns them as they were
+// before the cancel, so callers can see how much was left unfilled. Tracked orders that are no
+// longer open (filled or cancelled elsewhere) are forgotten. Orders whose created_at cannot be
+// parsed are left alone.
+func (s *OrderSweeper) Sweep(now time.Time) ([]Order, error) {
+	s.mu.Lock()
+	tracked := make(map[int64]time.Duration, len(s.maxAge))
+	for id, age := range s.maxAge {
+		tracked[id] = age
+	}
+	s.mu.Unlock()
+	if len(tracked) == 0 {
+		return nil, nil
+	}
+
+	open, err := s.client.GetActiveOrders(0)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list open orders: %w", err)
+	}
+
+	var expired []Order
+	stillOpen := make(map[int64]bool, len(open))
+	var cancelErr error
+	for _, order := range open {
+		maxAge, ok := tracked[order.ID]
+		if !ok {
+			continue
+		}
+		stillOpen[order.ID] = true
+
+		created, err := ParseOrderTime(order.CreatedAt)
+		if err != nil || now.Sub(created) < maxAge {
+			continue
+		}
+		if err := s.client.CancelOrder(order.ID, order.ProductID); err != nil {
+			cancelErr = fmt.Errorf("failed to cancel expired order %d: %w", order.ID, err)
+			continue
+		}
+		delete(stillOpen, order.ID)
+		expired = append(expired, order)
+	}
+
+	s.mu.Lock()
+	for id := range tracked {
+		if !stillOpen[id] {
+			delete(s.maxAge, id)
+		}
+	}
+	s.mu.Unlock()
+
+	return expired, cancelErr
+}
+
+// ParseOrderTime parses an order's created_at, which Delta sends as an RFC 3339 timestamp
+func ParseOrderTime(s string) (time.Time, error) {
+	t, err := time.Parse(time.RFC3339Nano, s)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("failed to parse order time '%s': %w", s, err)
+	}
+	return t, nil
+}
@@ -0,0 +1,63 @@
+package delta
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+)
+
+func TestOrderSweeper_CancelsOrdersPastMaxAge(t *testing.T) {
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	openOrders := []Order{
+		{ID: 1, ProductID: 27, Size: 5, UnfilledSize: 5, State: "open", CreatedAt: now.Add(-10 * time.Minute).Format(time.RFC3339Nano)},
+		{ID: 2, ProductID: 27, Size: 5, UnfilledSize: 5, State: "open", CreatedAt: now.Add(-30 * time.Second).Format(time.RFC3339Nano)},
+		{ID: 3, ProductID: 27, Size: 5, UnfilledSize: 5, State: "open", CreatedAt: now.Add(-time.Hour).Format(time.RFC3339Nano)}, // Untracked
+	}
+
+	var cancelled []int64
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case http.MethodGet:
+			result, _ := json.Marshal(openOrders)
+			fmt.Fprintf(w, `{"success":true,"result":%s}`, result)
+		case http.MethodDelete:
+			var body struct {
+				ID int64 `json:"id"`
+			}
+			_ = json.NewDecoder(r.Body).Decode(&body)
+			cancelled = append(cancelled, body.ID)
+			w.Write([]byte(`{"success":true,"result":{}}`))
+		}
+	})
+
+	s := NewOrderSweeper(c)
+	s.Track(1, time.Minute)
+	s.Track(2, time.Minute)
+	s.Track(4, time.Minute) // Already filled: not in the open list
+	s.Track(5, 0)           // No expiry
+
+	expired, err := s.Sweep(now)
+	if err != nil {
+		t.Fatalf("Sweep() error = %v", err)
+	}
+	if len(expired) != 1 || expired[0].ID != 1 {
+		t.Fatalf("expired = %+v, want only order 1", expired)
+	}
+	if len(cancelled) != 1 || cancelled[0] != 1 {
+		t.Errorf("cancelled = %v, want [1]", cancelled)
+	}
+	if got := s.Tracked(); got != 1 {
+		t.Errorf("tracked after sweep = %d, want 1 (order 2 still resting)", got)
+	}
+
+	// Order 2 ages past its limit on a later sweep
+	expired, err = s.Sweep(now.Add(time.Minute))
+	if err != nil {
+		t.Fatalf("Sweep() error = %v", err)
+	}
+	if len(expired) != 1 || expired[0].ID != 2 {
+		t.Errorf("expired = %+v, want order 2", expired)
+	}
+}
@@ -0,0 +1,93 @@
+package delta
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// flexFloat decodes a number sent either as a JSON number or as a numeric string. Delta
+// quotes prices as strings on some endpoints and feeds and not on others. Null and "" are 0.
+type flexFloat float64
+
+func (f *flexFloat) UnmarshalJSON(data []byte) error {
+	s := strings.TrimSpace(string(data))
+	if s == "null" {
+		*f = 0
+		return nil
+	}
+	if unquoted, err := strconv.Unquote(s); err == nil {
+		s = strings.TrimSpace(unquoted)
+		if s == "" {
+			*f = 0
+			return nil
+		}
+	}
+	v, err := strconv.ParseFloat(s, 64)
+	if err != nil {
+		return fmt.Errorf("invalid number %s", data)
+	}
+	*f = flexFloat(v)
+	return nil
+}
+
+// UnmarshalJSON accepts price, volume and rate fields as strings or numbers
+func (t *Ticker) UnmarshalJSON(data []byte) error {
+	var raw struct {
+		Symbol      string    `json:"symbol"`
+		ProductID   int       `json:"product_id"`
+		Close       flexFloat `json:"close"`
+		High        flexFloat `json:"high"`
+		Low         flexFloat `json:"low"`
+		MarkPrice   flexFloat `json:"mark_price"`
+		Open        flexFloat `json:"open"`
+		Size        flexFloat `json:"size"`
+		Timestamp   int64     `json:"timestamp"`
+		Turnover    flexFloat `json:"turnover"`
+		Volume      flexFloat `json:"volume"`
+		FundingRate flexFloat `json:"funding_rate"`
+	}
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+	*t = Ticker{
+		Symbol:      raw.Symbol,
+		ProductID:   raw.ProductID,
+		Close:       float64(raw.Close),
+		High:        float64(raw.High),
+		Low:         float64(raw.Low),
+		MarkPrice:   float64(raw.MarkPrice),
+		Open:        float64(raw.Open),
+		Size:        float64(raw.Size),
+		Timestamp:   raw.Timestamp,
+		Turnover:    float64(raw.Turnover),
+		Volume:      float64(raw.Volume),
+		FundingRate: float64(raw.FundingRate),
+	}
+	return nil
+}
+
+// UnmarshalJSON accepts OHLCV fields as strings or numbers
+func (c *Candle) UnmarshalJSON(data []byte) error {
+	var raw struct {
+		Time   int64     `json:"time"`
+		Open   flexFloat `json:"open"`
+		High   flexFloat `json:"high"`
+		Low    flexFloat `json:"low"`
+		Close  flexFloat `json:"close"`
+		Volume flexFloat `json:"volume"`
+	}
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+	*c = Candle{
+		Time:   raw.Time,
+		Open:   float64(raw.Open),
+		High:   float64(raw.High),
+		Low:    float64(raw.Low),
+		Close:  float64(raw.Close),
+		Volume: float64(raw.Volume),
+	}
+	return nil
+}
@@ -0,0 +1,55 @@
+package delta
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestTicker_UnmarshalStringOrNumber(t *testing.T) {
+	shapes := map[string]string{
+		"strings": `{"symbol":"BTCUSD","product_id":27,"close":"50100.5","high":"50500","low":"49800",
+			"mark_price":"50101","open":"50000","size":"12","timestamp":1700000000000000,
+			"turnover":"1000000","volume":"20.5","funding_rate":"0.0001"}`,
+		"numbers": `{"symbol":"BTCUSD","product_id":27,"close":50100.5,"high":50500,"low":49800,
+			"mark_price":50101,"open":50000,"size":12,"timestamp":1700000000000000,
+			"turnover":1000000,"volume":20.5,"funding_rate":0.0001}`,
+	}
+	want := Ticker{
+		Symbol: "BTCUSD", ProductID: 27, Close: 50100.5, High: 50500, Low: 49800, MarkPrice: 50101,
+		Open: 50000, Size: 12, Timestamp: 1700000000000000, Turnover: 1000000, Volume: 20.5, FundingRate: 0.0001,
+	}
+	for name, body := range shapes {
+		var got Ticker
+		if err := json.Unmarshal([]byte(body), &got); err != nil {
+			t.Fatalf("%s: %v", name, err)
+		}
+		if got != want {
+			t.Errorf("%s: got %+v, want %+v", name, got, want)
+		}
+	}
+
+	var bad Ticker
+	if err := json.Unmarshal([]byte(`{"close":"n/a"}`), &bad); err == nil {
+		t.Error("non-numeric close decoded without error")
+	}
+	var empty Ticker
+	if err := json.Unmarshal([]byte(`{"mark_price":"","funding_rate":null}`), &empty); err != nil || empty.MarkPrice != 0 {
+		t.Errorf("empty fields = %+v, %v; want zero values and no error", empty, err)
+	}
+}
+
+func TestCandle_UnmarshalStringOrNumber(t *testing.T) {
+	want := Candle{Time: 1700000000, Open: 100, High: 110.5, Low: 95, Close: 105, Volume: 3.25}
+	for _, body := range []string{
+		`{"time":1700000000,"open":"100","high":"110.5","low":"95","close":"105","volume":"3.25"}`,
+		`{"time":1700000000,"open":100,"high":110.5,"low":95,"close":105,"volume":3.25}`,
+	} {
+		var got Candle
+		if err := json.Unmarshal([]byte(body), &got); err != nil {
+			t.Fatalf("%s: %v", body, err)
+		}
+		if got != want {
+			t.Errorf("%s: got %+v, want %+v", body, got, want)
+		}
+	}
+}
@@ -0,0 +1,65 @@
+package delta
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"sort"
+	"strconv"
+	"time"
+)
+
+// FundingRate is one funding observation for a perpetual
+type FundingRate struct {
+	Time   time.Time
+	Symbol string
+	Rate   float64 // 8-hourly rate, same units as Ticker.FundingRate
+}
+
+// fundingHistoryResolution is the bucket size requested for funding history;
+// Delta settles every 8h but publishes the accruing rate more often
+const fundingHistoryResolution = "1h"
+
+// GetFundingHistory fetches the funding rate history for a perpetual between start and end
+// Delta serves funding history as candles on the FUNDING:<symbol> series, with the rate in close
+func (c *Client) GetFundingHistory(symbol string, start, end time.Time) ([]FundingRate, error) {
+	query := url.Values{}
+	query.Set("symbol", "FUNDING:"+symbol)
+	query.Set("resolution", fundingHistoryResolution)
+	query.Set("start", strconv.FormatInt(start.Unix(), 10))
+	query.Set("end", strconv.FormatInt(end.Unix(), 10))
+
+	resp, err := c.Get("/history/candles", query)
+	if err != nil {
+		return nil, err
+	}
+
+	var candles []Candle
+	if err := json.Unmarshal(resp.Result, &candles); err != nil {
+		return nil, fmt.Errorf("failed to parse funding history: %v", err)
+	}
+
+	rates := make([]FundingRate, 0, len(candles))
+	for _, cd := range candles {
+		rates = append(rates, FundingRate{
+			Time:   time.Unix(cd.Time, 0).UTC(),
+			Symbol: symbol,
+			Rate:   cd.Close,
+		})
+	}
+	// Delta returns newest first; callers expect chronological order
+	sort.Slice(rates, func(i, j int) bool { return rates[i].Time.Before(rates[j].Time) })
+	return rates, nil
+}
+
+// AverageFundingRate is the mean rate over rates, 0 when empty
+func AverageFundingRate(rates []FundingRate) float64 {
+	if len(rates) == 0 {
+		return 0
+	}
+	sum := 0.0
+	for _, r := range rates {
+		sum += r.Rate
+	}
+	return sum / float64(len(rates))
+}
@@ -0,0 +1,43 @@
+package delta
+
+import (
+	"math"
+	"net/http"
+	"testing"
+	"time"
+)
+
+func TestGetFundingHistory(t *testing.T) {
+	var gotPath, gotSymbol, gotStart string
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		gotPath = r.URL.Path
+		gotSymbol = r.URL.Query().Get("symbol")
+		gotStart = r.URL.Query().Get("start")
+		w.Write([]byte(`{"success":true,"result":[
+			{"time":1700007200,"open":0.012,"high":0.012,"low":0.012,"close":0.012,"volume":0},
+			{"time":1700003600,"open":0.008,"high":0.008,"low":0.008,"close":0.008,"volume":0},
+			{"time":1700000000,"open":0.01,"high":0.01,"low":0.01,"close":0.01,"volume":0}
+		]}`))
+	})
+
+	start := time.Unix(1700000000, 0)
+	rates, err := c.GetFundingHistory("BTCUSD", start, start.Add(3*time.Hour))
+	if err != nil {
+		t.Fatalf("GetFundingHistory() error = %v", err)
+	}
+	if gotPath != "/v2/history/candles" || gotSymbol != "FUNDING:BTCUSD" || gotStart != "1700000000" {
+		t.Errorf("request = %s symbol=%s start=%s", gotPath, gotSymbol, gotStart)
+	}
+	if len(rates) != 3 {
+		t.Fatalf("got %d rates, want 3", len(rates))
+	}
+	if rates[0].Time.Unix() != 1700000000 || rates[0].Rate != 0.01 || rates[0].Symbol != "BTCUSD" {
+		t.Errorf("first rate = %+v, want oldest first", rates[0])
+	}
+	if rates[2].Rate != 0.012 {
+		t.Errorf("last rate = %v, want 0.012", rates[2].Rate)
+	}
+	if avg := AverageFundingRate(rates); math.Abs(avg-0.01) > 1e-12 {
+		t.Errorf("AverageFundingRate = %v, want 0.01", avg)
+	}
+}
@@ -0,0 +1,104 @@
+package delta
+
+import (
+	"fmt"
+	"log"
+)
+
+// HedgeLegError reports a two-leg entry that did not complete. Whatever had filled on
+// either leg was unwound with reduce-only market orders, unless UnwindErr is set.
+type HedgeLegError struct {
+	Leg       int // Leg that failed: 1 or 2
+	Err       error
+	Unwound   []*Order // Market orders that closed filled legs
+	UnwindErr error
+}
+
+func (e *HedgeLegError) Error() string {
+	msg := fmt.Sprintf("hedge leg %d failed: %v", e.Leg, e.Err)
+	if e.UnwindErr != nil {
+		msg += fmt.Sprintf(" (unwind failed: %v)", e.UnwindErr)
+	} else if len(e.Unwound) > 0 {
+		msg += fmt.Sprintf(" (unwound %d leg(s))", len(e.Unwound))
+	}
+	return msg
+}
+
+func (e *HedgeLegError) Unwrap() error {
+	return e.Err
+}
+
+// PlaceHedgedPair places first and waits up to fillTimeoutSeconds for it to fill, then does
+// the same for second. A leg counts once filled, not merely accepted. If either leg fails or
+// times out it is cancelled and every filled quantity on both legs is unwound at market, so a
+// failed entry never leaves one leg naked. Errors are *HedgeLegError.
+func (c *Client) PlaceHedgedPair(first, second *OrderRequest, fillTimeoutSeconds int) (*Order, *Order, error) {
+	firstOrder, firstFilled, err := c.placeLegAndWait(first, fillTimeoutSeconds)
+	if err != nil {
+		return nil, nil, c.unwindHedge(1, err, []*OrderRequest{first}, []int{firstFilled})
+	}
+
+	secondOrder, secondFilled, err := c.placeLegAndWait(second, fillTimeoutSeconds)
+	if err != nil {
+		return nil, nil, c.unwindHedge(2, err, []*OrderRequest{first, second}, []int{firstFilled, secondFilled})
+	}
+
+	return firstOrder, secondOrder, nil
+}
+
+// placeLegAndWait places one leg and waits for a full fill. On failure the order is
+// cancelled and the returned size is what filled before the cancel; when the outcome
+// cannot be verified the full size is assumed, which the reduce-only unwind caps.
+func (c *Client) placeLegAndWait(req *OrderRequest, fillTimeoutSeconds int) (*Order, int, error) {
+	order, err := c.PlaceOrder(req)
+	if err != nil {
+		return nil, 0, err
+	}
+	if ParseOrderState(order.State).IsFilled() {
+		return order, order.Size, nil
+	}
+
+	filledOrder, waitErr := c.WaitForOrderFill(order.ID, fillTimeoutSeconds)
+	if waitErr == nil && filledOrder != nil {
+		return filledOrder, filledOrder.Size, nil
+	}
+
+	final, _ := c.waitForCancelConfirmation(order.ID, req.ProductID)
+	if final == nil {
+		if waitErr == nil {
+			waitErr = fmt.Errorf("order %d not filled within %ds and its state is unknown", order.ID, fillTimeoutSeconds)
+		}
+		return nil, req.Size, waitErr
+	}
+	if ParseOrderState(final.State).IsFilled() {
+		return final, final.Size, nil
+	}
+	if waitErr == nil {
+		waitErr = fmt.Errorf("order %d not filled within %ds (%d/%d unfilled)", order.ID, fillTimeoutSeconds, final.UnfilledSize, final.Size)
+	}
+	return nil, final.Size - final.UnfilledSize, waitErr
+}
+
+// unwindHedge closes the filled quantity of each leg with a reduce-only market order
+func (c *Client) unwindHedge(failedLeg int, cause error, legs []*OrderRequest, filled []int) error {
+	hedgeErr := &HedgeLegError{Leg: failedLeg, Err: cause}
+	for i, leg := range legs {
+		if filled[i] <= 0 {
+			continue
+		}
+		log.Printf("WARNING: hedge leg %d failed - unwinding %d filled on leg %d (product %d)", failedLeg, filled[i], i+1, leg.ProductID)
+		order, err := c.PlaceOrder(&OrderRequest{
+			ProductID:  leg.ProductID,
+			Size:       filled[i],
+			Side:       oppositeSide(leg.Side),
+			OrderType:  "market_order",
+			ReduceOnly: true,
+		})
+		if err != nil {
+			hedgeErr.UnwindErr = fmt.Errorf("failed to unwind leg %d: %w", i+1, err)
+			continue
+		}
+		hedgeErr.Unwound = append(hedgeErr.Unwound, order)
+	}
+	return hedgeErr
+}
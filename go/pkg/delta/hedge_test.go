@@ -0,0 +1,72 @@
+package delta
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+)
+
+func TestPlaceHedgedPair_SecondLegFailureUnwindsFirst(t *testing.T) {
+	var placed []OrderRequest
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		var req OrderRequest
+		_ = json.NewDecoder(r.Body).Decode(&req)
+		placed = append(placed, req)
+		switch {
+		case req.ProductID == 101: // Futures hedge leg fills immediately
+			fmt.Fprintf(w, `{"success":true,"result":{"id":1,"size":%d,"unfilled_size":0,"side":%q,"state":"closed"}}`, req.Size, req.Side)
+		case req.ReduceOnly:
+			fmt.Fprintf(w, `{"success":true,"result":{"id":3,"size":%d,"unfilled_size":0,"side":%q,"state":"closed","reduce_only":true}}`, req.Size, req.Side)
+		default: // Perp leg is refused
+			w.WriteHeader(http.StatusBadRequest)
+			w.Write([]byte(`{"success":false,"error":{"code":"insufficient_margin","message":"not enough margin"}}`))
+		}
+	})
+
+	future := &OrderRequest{ProductID: 101, Size: 5, Side: "sell", OrderType: "market_order"}
+	perp := &OrderRequest{ProductID: 27, Size: 5, Side: "buy", OrderType: "limit_order", LimitPrice: "50000"}
+
+	_, _, err := c.PlaceHedgedPair(future, perp, 1)
+	var hedgeErr *HedgeLegError
+	if !errors.As(err, &hedgeErr) {
+		t.Fatalf("err = %v, want *HedgeLegError", err)
+	}
+	if hedgeErr.Leg != 2 || hedgeErr.UnwindErr != nil || len(hedgeErr.Unwound) != 1 {
+		t.Fatalf("hedge error = %+v, want leg 2 failure with one unwind", hedgeErr)
+	}
+	if RejectReasonOf(err) != RejectInsufficientMargin {
+		t.Errorf("cause not preserved: %v", err)
+	}
+
+	if len(placed) != 3 {
+		t.Fatalf("placed %d orders, want future, perp, rollback", len(placed))
+	}
+	rollback := placed[2]
+	if rollback.ProductID != 101 || rollback.Side != "buy" || rollback.Size != 5 ||
+		rollback.OrderType != "market_order" || !rollback.ReduceOnly {
+		t.Errorf("rollback = %+v, want reduce-only market buy 5 on the future", rollback)
+	}
+}
+
+func TestPlaceHedgedPair_BothLegsFilled(t *testing.T) {
+	var placed int
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		var req OrderRequest
+		_ = json.NewDecoder(r.Body).Decode(&req)
+		placed++
+		fmt.Fprintf(w, `{"success":true,"result":{"id":%d,"size":%d,"unfilled_size":0,"side":%q,"state":"closed"}}`, placed, req.Size, req.Side)
+	})
+
+	first, second, err := c.PlaceHedgedPair(
+		&OrderRequest{ProductID: 101, Size: 5, Side: "sell", OrderType: "market_order"},
+		&OrderRequest{ProductID: 27, Size: 5, Side: "buy", OrderType: "market_order"},
+		1)
+	if err != nil {
+		t.Fatalf("PlaceHedgedPair() error = %v", err)
+	}
+	if first.ID != 1 || second.ID != 2 || placed != 2 {
+		t.Errorf("legs = %d/%d after %d placements, want 1/2 with no rollback", first.ID, second.ID, placed)
+	}
+}
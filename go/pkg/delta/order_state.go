@@ -0,0 +1,54 @@
+package delta
+
+import "strings"
+
+// OrderState is the lifecycle state of an order as reported by Delta Exchange
+type OrderState string
+
+const (
+	OrderStateOpen            OrderState = "open"
+	OrderStatePending         OrderState = "pending"
+	OrderStatePartiallyFilled OrderState = "partially_filled"
+	OrderStateFilled          OrderState = "filled"
+	OrderStateClosed          OrderState = "closed" // Delta reports fully executed orders as closed
+	OrderStateCancelled       OrderState = "cancelled"
+	OrderStateRejected        OrderState = "rejected"
+	OrderStateUnknown         OrderState = "unknown"
+)
+
+// ParseOrderState maps an API state string to an OrderState. Unrecognised values map to
+// OrderStateUnknown, which is neither terminal nor cancellable.
+func ParseOrderState(s string) OrderState {
+	switch state := OrderState(strings.ToLower(strings.TrimSpace(s))); state {
+	case OrderStateOpen, OrderStatePending, OrderStatePartiallyFilled,
+		OrderStateFilled, OrderStateClosed, OrderStateCancelled, OrderStateRejected:
+		return state
+	case "canceled":
+		return OrderStateCancelled
+	default:
+		return OrderStateUnknown
+	}
+}
+
+// IsTerminal reports whether the order can no longer change
+func (s OrderState) IsTerminal() bool {
+	switch s {
+	case OrderStateFilled, OrderStateClosed, OrderStateCancelled, OrderStateRejected:
+		return true
+	}
+	return false
+}
+
+// IsFilled reports whether the order was fully executed
+func (s OrderState) IsFilled() bool {
+	return s == OrderStateFilled || s == OrderStateClosed
+}
+
+// IsCancellable reports whether the order is still working on the book
+func (s OrderState) IsCancellable() bool {
+	switch s {
+	case OrderStateOpen, OrderStatePending, OrderStatePartiallyFilled:
+		return true
+	}
+	return false
+}
@@ -0,0 +1,36 @@
+package delta
+
+import "testing"
+
+func TestParseOrderState(t *testing.T) {
+	tests := []struct {
+		input       string
+		want        OrderState
+		terminal    bool
+		filled      bool
+		cancellable bool
+	}{
+		{"open", OrderStateOpen, false, false, true},
+		{"pending", OrderStatePending, false, false, true},
+		{"partially_filled", OrderStatePartiallyFilled, false, false, true},
+		{"filled", OrderStateFilled, true, true, false},
+		{"closed", OrderStateClosed, true, true, false},
+		{"cancelled", OrderStateCancelled, true, false, false},
+		{"canceled", OrderStateCancelled, true, false, false},
+		{"rejected", OrderStateRejected, true, false, false},
+		{" Filled ", OrderStateFilled, true, true, false},
+		{"triggered_somehow", OrderStateUnknown, false, false, false},
+		{"", OrderStateUnknown, false, false, false},
+	}
+
+	for _, tt := range tests {
+		got := ParseOrderState(tt.input)
+		if got != tt.want {
+			t.Errorf("ParseOrderState(%q) = %q, want %q", tt.input, got, tt.want)
+		}
+		if got.IsTerminal() != tt.terminal || got.IsFilled() != tt.filled || got.IsCancellable() != tt.cancellable {
+			t.Errorf("%q: terminal=%v filled=%v cancellable=%v, want %v/%v/%v", tt.input,
+				got.IsTerminal(), got.IsFilled(), got.IsCancellable(), tt.terminal, tt.filled, tt.cancellable)
+		}
+	}
+}
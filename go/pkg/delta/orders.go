@@ -1,13 +1,18 @@
 package delta
 
 import (
+	"crypto/sha256"
+	"encoding/hex"
 	"encoding/json"
 	"errors"
 	"fmt"
+	"log"
 	"math"
 	"net/url"
 	"strconv"
 	"time"
+
+	"github.com/kasyap/delta-go/go/pkg/alert"
 )
 
 // GetProducts returns list of all products
@@ -55,10 +60,61 @@ func (c *Client) GetTicker(symbol string) (*Ticker, error) {
 	return &ticker, nil
 }
 
-// PlaceOrder places a new order
+// GenerateClientOrderID derives a deterministic client_order_id from the order's intent, so
+// retrying the same request after an ambiguous failure cannot create a second order.
+// candleTime is the signal's source candle and seq a per-symbol order sequence, so a later
+// order on the same candle with the same side and size still gets a fresh ID. tag
+// distinguishes orders placed for the same signal (e.g. "scalp", "grid:50100").
+// Delta limits client_order_id to 32 characters.
+func GenerateClientOrderID(symbol, side string, size int, candleTime time.Time, seq uint64, tag string) string {
+	sum := sha256.Sum256([]byte(fmt.Sprintf("%s|%s|%d|%d|%d|%s", symbol, side, size, candleTime.Unix(), seq, tag)))
+	return "dg" + hex.EncodeToString(sum[:])[:30]
+}
+
+// duplicateOrderCodes are the API error codes Delta returns for a reused client_order_id
+var duplicateOrderCodes = map[string]bool{
+	"duplicate_client_order_id": true,
+	"client_order_id_exists":    true,
+}
+
+// PlaceOrder places a new order. If the request carries a client_order_id the exchange has
+// already accepted (e.g. a retry after a timeout), the existing order is returned instead
+// while it is still working; a duplicate of a filled, cancelled or rejected order is an error.
+// When placement fails transiently the order is looked up the same way, in case it went in.
 func (c *Client) PlaceOrder(req *OrderRequest) (*Order, error) {
 	resp, err := c.Post("/orders", req)
 	if err != nil {
+		var apiErr *APIError
+		if errors.As(err, &apiErr) && req.ClientOrderID != "" && duplicateOrderCodes[apiErr.Code] {
+			existing, getErr := c.GetOrderByClientOrderID(req.ClientOrderID)
+			if getErr != nil {
+				return nil, fmt.Errorf("duplicate order %s but failed to fetch it: %v (place error: %w)", req.ClientOrderID, getErr, err)
+			}
+			if state := ParseOrderState(existing.State); state.IsTerminal() {
+				return nil, fmt.Errorf("duplicate order %s matches order %d that is already %s: %w",
+					req.ClientOrderID, existing.ID, state, err)
+			}
+			log.Printf("Order %s already placed (ID %d) - treating retry as success", req.ClientOrderID, existing.ID)
+			return existing, nil
+		}
+		if IsTransient(err) && req.ClientOrderID != "" {
+			// A request that timed out or dropped may still have been accepted, by this call:
+			// client order IDs aren't reused, so whatever state the order is in, it is ours
+			if existing, getErr := c.GetOrderByClientOrderID(req.ClientOrderID); getErr == nil {
+				log.Printf("Order %s accepted despite %v (ID %d) - treating as placed", req.ClientOrderID, err, existing.ID)
+				return existing, nil
+			}
+			return nil, err
+		}
+		if errors.As(err, &apiErr) {
+			c.alerter.Alert(alert.LevelWarn, fmt.Sprintf("Order rejected (product %d, %s %d): %v",
+				req.ProductID, req.Side, req.Size, apiErr))
+			return nil, &OrderRejectedError{
+				Reason: apiErr.Message,
+				Kind:   ParseRejectReason(apiErr.Code, apiErr.Message),
+				Err:    err,
+			}
+		}
 		return nil, err
 	}
 
@@ -66,6 +122,10 @@ func (c *Client) PlaceOrder(req *OrderRequest) (*Order, error) {
 	if err := json.Unmarshal(resp.Result, &order); err != nil {
 		return nil, fmt.Errorf("failed to parse order: %v", err)
 	}
+	if ParseOrderState(order.State) == OrderStateRejected {
+		c.alerter.Alert(alert.LevelWarn, fmt.Sprintf("Order %d rejected (product %d, %s %d)",
+			order.ID, req.ProductID, req.Side, req.Size))
+	}
 
 	return &order, nil
 }
@@ -92,6 +152,48 @@ func (c *Client) CancelAllOrders(productID int) error {
 	return err
 }
 
+// ErrBracketNotFound indicates the bracket order no longer exists on the exchange,
+// typically because the stop-loss or take-profit has already triggered
+var ErrBracketNotFound = errors.New("bracket order not found")
+
+// bracketNotFoundCodes are the API error codes Delta returns for a missing/closed order
+var bracketNotFoundCodes = map[string]bool{
+	"open_order_not_found": true,
+	"order_not_found":      true,
+	"no_position_found":    true,
+}
+
+// EditBracket amends the stop-loss and/or take-profit of an existing bracket order in place
+// using Delta's bracket edit endpoint (PUT /v2/orders/bracket). This avoids the unprotected
+// gap of cancel+replace. Pass an empty string to leave a leg unchanged.
+// Returns ErrBracketNotFound if the bracket has already triggered or been cancelled.
+func (c *Client) EditBracket(orderID int64, productID int, newStopLoss, newTakeProfit string) error {
+	if newStopLoss == "" && newTakeProfit == "" {
+		return fmt.Errorf("edit bracket %d: no stop-loss or take-profit provided", orderID)
+	}
+
+	body := map[string]interface{}{
+		"id":         orderID,
+		"product_id": productID,
+	}
+	if newStopLoss != "" {
+		body["bracket_stop_loss_price"] = newStopLoss
+	}
+	if newTakeProfit != "" {
+		body["bracket_take_profit_price"] = newTakeProfit
+	}
+
+	_, err := c.Put("/orders/bracket", body)
+	if err != nil {
+		var apiErr *APIError
+		if errors.As(err, &apiErr) && bracketNotFoundCodes[apiErr.Code] {
+			return fmt.Errorf("edit bracket %d: %w", orderID, ErrBracketNotFound)
+		}
+		return err
+	}
+	return nil
+}
+
 // GetActiveOrders returns all active orders
 func (c *Client) GetActiveOrders(productID int) ([]Order, error) {
 	query := url.Values{}
@@ -113,6 +215,28 @@ func (c *Client) GetActiveOrders(productID int) ([]Order, error) {
 	return orders, nil
 }
 
+// GetOrderHistory returns a product's closed and cancelled orders created since since,
+// newest first, including the stop-loss and take-profit orders a bracket triggered
+func (c *Client) GetOrderHistory(productID int, since time.Time) ([]Order, error) {
+	query := url.Values{}
+	query.Set("product_ids", fmt.Sprintf("%d", productID))
+	if !since.IsZero() {
+		query.Set("start_time", fmt.Sprintf("%d", since.UnixMicro()))
+	}
+
+	resp, err := c.Get("/orders/history", query)
+	if err != nil {
+		return nil, err
+	}
+
+	var orders []Order
+	if err := json.Unmarshal(resp.Result, &orders); err != nil {
+		return nil, fmt.Errorf("failed to parse order history: %v", err)
+	}
+
+	return orders, nil
+}
+
 // GetOrderByID returns an order by ID
 func (c *Client) GetOrderByID(orderID int64) (*Order, error) {
 	resp, err := c.Get(fmt.Sprintf("/orders/%d", orderID), nil)
@@ -128,15 +252,51 @@ func (c *Client) GetOrderByID(orderID int64) (*Order, error) {
 	return &order, nil
 }
 
-// SetLeverage sets leverage for a product using Delta v2 API
-// Correct endpoint: POST /v2/products/{product_id}/orders/leverage
+// GetOrderByClientOrderID returns an order by its client_order_id
+func (c *Client) GetOrderByClientOrderID(clientOrderID string) (*Order, error) {
+	resp, err := c.Get("/orders/client_order_id/"+url.PathEscape(clientOrderID), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var order Order
+	if err := json.Unmarshal(resp.Result, &order); err != nil {
+		return nil, fmt.Errorf("failed to parse order: %v", err)
+	}
+
+	return &order, nil
+}
+
+// SetLeverage sets leverage for a product, skipping the API call when this client last set
+// the same leverage on it. Use ForceSetLeverage if it may have been changed elsewhere.
 func (c *Client) SetLeverage(productID int, leverage int) error {
+	c.leverageMu.Lock()
+	current, ok := c.leverage[productID]
+	c.leverageMu.Unlock()
+	if ok && current == leverage {
+		return nil
+	}
+	return c.ForceSetLeverage(productID, leverage)
+}
+
+// ForceSetLeverage sets leverage for a product using Delta v2 API, whatever was set before.
+// Correct endpoint: POST /v2/products/{product_id}/orders/leverage
+func (c *Client) ForceSetLeverage(productID int, leverage int) error {
 	body := map[string]interface{}{
 		"leverage": fmt.Sprintf("%d", leverage), // Delta expects string
 	}
 
 	_, err := c.Post(fmt.Sprintf("/products/%d/orders/leverage", productID), body)
-	return err
+
+	c.leverageMu.Lock()
+	defer c.leverageMu.Unlock()
+	if err != nil {
+		// The exchange's leverage is unknown after a failure; set it again next time
+		delete(c.leverage, productID)
+		return err
+	}
+	c.leverage[productID] = leverage
+	return nil
 }
 
 // RoundToTickSize rounds a price to the nearest valid tick size
@@ -149,28 +309,69 @@ func RoundToTickSize(price float64, tickSize string) (string, error) {
 func RoundToTickSizeWithDirection(price float64, tickSize string, direction string) (string, error) {
 	tick, err := strconv.ParseFloat(tickSize, 64)
 	if err != nil || tick <= 0 {
-		return fmt.Sprintf("%.2f", price), nil
+		// Unknown tick: shortest exact representation rather than truncating sub-cent prices
+		return strconv.FormatFloat(price, 'f', -1, 64), nil
 	}
 
+	// Prices already on a tick (50000.3 / 0.1 = 500002.999...) must not step a whole tick
+	const eps = 1e-9
 	var rounded float64
 	switch direction {
 	case "down":
-		rounded = math.Floor(price/tick) * tick
+		rounded = math.Floor(price/tick+eps) * tick
 	case "up":
-		rounded = math.Ceil(price/tick) * tick
+		rounded = math.Ceil(price/tick-eps) * tick
 	default:
 		rounded = math.Round(price/tick) * tick
 	}
 
-	precision := 0
+	return strconv.FormatFloat(rounded, 'f', tickPrecision(tickSize), 64), nil
+}
+
+// Bracket rounding policies for RoundBracketPrices
+const (
+	BracketRoundingConservative = "conservative" // Stop away from entry, target toward entry
+	BracketRoundingNearest      = "nearest"      // Both legs to the nearest tick
+)
+
+// RoundBracketPrices rounds a bracket's stop-loss and take-profit to the tick for an entry on
+// side. The conservative policy (the default) never tightens the stop or stretches the target:
+// a long's stop and target both round down, a short's both round up.
+func RoundBracketPrices(side string, stopLoss, takeProfit float64, tickSize, policy string) (sl, tp string) {
+	if policy == BracketRoundingNearest {
+		sl, _ = RoundToTickSize(stopLoss, tickSize)
+		tp, _ = RoundToTickSize(takeProfit, tickSize)
+		return sl, tp
+	}
+
+	direction := "down"
+	if side == "sell" {
+		direction = "up"
+	}
+	sl, _ = RoundToTickSizeWithDirection(stopLoss, tickSize, direction)
+	tp, _ = RoundToTickSizeWithDirection(takeProfit, tickSize, direction)
+	return sl, tp
+}
+
+// tickPrecision returns the number of decimal places in a tick size string ("0.005" -> 3)
+func tickPrecision(tickSize string) int {
 	for i := len(tickSize) - 1; i >= 0; i-- {
 		if tickSize[i] == '.' {
-			precision = len(tickSize) - 1 - i
-			break
+			return len(tickSize) - 1 - i
 		}
 	}
+	return 0
+}
 
-	return strconv.FormatFloat(rounded, 'f', precision, 64), nil
+// FormatPrice rounds a price to the product's tick size and formats it with the tick's
+// decimal places, so sub-dollar and coarse-tick products are both sent correctly
+func FormatPrice(price float64, product *Product) string {
+	tickSize := ""
+	if product != nil {
+		tickSize = product.TickSize
+	}
+	s, _ := RoundToTickSize(price, tickSize)
+	return s
 }
 
 // PlaceLimitOrder places a limit order at the specified price
@@ -184,6 +385,57 @@ func (c *Client) PlaceLimitOrder(req *OrderRequest) (*Order, error) {
 	return c.PlaceOrder(req)
 }
 
+// Stop trigger types accepted by the stop_order_type field
+const (
+	StopOrderTypeStopLoss   = "stop_loss_order"
+	StopOrderTypeTakeProfit = "take_profit_order"
+)
+
+// PlaceStopEntryOrder places a stop entry that rests until price trades through triggerPrice,
+// e.g. a buy-stop above resistance for a breakout. With stopLimit the order becomes a limit
+// at req.LimitPrice once triggered; otherwise it executes at market. Prices are rounded to
+// the product's tick away from the market (up for buys, down for sells) so the trigger is
+// never moved inside the intended level. req.ProductSymbol is required for the tick lookup.
+func (c *Client) PlaceStopEntryOrder(req *OrderRequest, triggerPrice string, stopLimit bool) (*Order, error) {
+	if req.ProductSymbol == "" {
+		return nil, fmt.Errorf("product_symbol required for stop entry order")
+	}
+	trigger, err := strconv.ParseFloat(triggerPrice, 64)
+	if err != nil || trigger <= 0 {
+		return nil, fmt.Errorf("invalid trigger price %q", triggerPrice)
+	}
+
+	product, err := c.GetProductBySymbol(req.ProductSymbol)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get product: %w", err)
+	}
+
+	direction := "down"
+	if req.Side == "buy" {
+		direction = "up"
+	}
+
+	req.StopOrderType = StopOrderTypeStopLoss
+	req.StopPrice, _ = RoundToTickSizeWithDirection(trigger, product.TickSize, direction)
+
+	if stopLimit {
+		limit, err := strconv.ParseFloat(req.LimitPrice, 64)
+		if err != nil || limit <= 0 {
+			return nil, fmt.Errorf("stop-limit entry needs a limit price, got %q", req.LimitPrice)
+		}
+		req.OrderType = "limit_order"
+		req.LimitPrice, _ = RoundToTickSizeWithDirection(limit, product.TickSize, direction)
+	} else {
+		req.OrderType = "market_order"
+		req.LimitPrice = ""
+	}
+	if req.TimeInForce == "" {
+		req.TimeInForce = "gtc"
+	}
+
+	return c.PlaceOrder(req)
+}
+
 // PlaceAggressiveLimitOrder places a limit order at best bid/ask with optional offset
 // For buys: places at best ask to maximize fill probability
 // For sells: places at best bid to maximize fill probability
@@ -250,21 +502,23 @@ func (c *Client) WaitForOrderFill(orderID int64, timeoutSeconds int) (*Order, er
 		consecutiveErrors = 0
 		lastOrder = order
 
+		state := ParseOrderState(order.State)
+
 		// Check if order is filled - require explicit state check
-		if order.State == "filled" {
+		if state.IsFilled() {
 			return order, nil
 		}
 
 		// Check terminal failure states - do NOT fallback to market on rejection
-		if order.State == "cancelled" {
+		switch state {
+		case OrderStateCancelled:
 			return nil, fmt.Errorf("order %d was cancelled", orderID)
-		}
-		if order.State == "rejected" {
-			return nil, &OrderRejectedError{OrderID: orderID, Reason: "order rejected by exchange"}
+		case OrderStateRejected:
+			return nil, &OrderRejectedError{OrderID: orderID, Reason: "order rejected by exchange", Kind: RejectUnknown}
 		}
 
-		// Unknown terminal states
-		if order.State != "open" && order.State != "pending" && order.State != "partially_filled" {
+		// Unknown states
+		if !state.IsCancellable() {
 			return nil, fmt.Errorf("order %d in unexpected state: %s", orderID, order.State)
 		}
 
@@ -273,7 +527,7 @@ func (c *Client) WaitForOrderFill(orderID int64, timeoutSeconds int) (*Order, er
 
 	// Final check after deadline to catch fills at the last moment
 	order, err := c.GetOrderByID(orderID)
-	if err == nil && order.State == "filled" {
+	if err == nil && ParseOrderState(order.State).IsFilled() {
 		return order, nil
 	}
 	if err == nil {
@@ -281,24 +535,36 @@ func (c *Client) WaitForOrderFill(orderID int64, timeoutSeconds int) (*Order, er
 	}
 
 	// Return the last known order state (nil indicates timeout with no fill)
-	if lastOrder != nil && lastOrder.State == "filled" {
+	if lastOrder != nil && ParseOrderState(lastOrder.State).IsFilled() {
 		return lastOrder, nil
 	}
 	return nil, nil
 }
 
-// OrderRejectedError indicates an order was rejected by the exchange
+// OrderRejectedError indicates an order was rejected by the exchange.
+// OrderID is 0 when the order was refused at placement and never created.
 type OrderRejectedError struct {
 	OrderID int64
 	Reason  string
+	Kind    RejectReason
+	Err     error // underlying API error, if any
 }
 
 func (e *OrderRejectedError) Error() string {
-	return fmt.Sprintf("order %d rejected: %s", e.OrderID, e.Reason)
+	if e.OrderID == 0 {
+		return fmt.Sprintf("order rejected (%s): %s", e.Kind, e.Reason)
+	}
+	return fmt.Sprintf("order %d rejected (%s): %s", e.OrderID, e.Kind, e.Reason)
+}
+
+func (e *OrderRejectedError) Unwrap() error {
+	return e.Err
 }
 
 // PlaceLimitOrderWithFallback places a limit order and falls back to market if not filled
 // timeoutSeconds: how long to wait for limit order to fill before converting to market
+// If req.LimitPrice is set it is treated as the intended (signal) price; market fallback
+// fills are checked against it (or the aggressive limit price) for excessive slippage.
 func (c *Client) PlaceLimitOrderWithFallback(req *OrderRequest, symbol string, timeoutSeconds int) (*Order, error) {
 	// Store original bracket fields - we only attach them to the first order
 	hasBracket := req.BracketStopLossPrice != "" || req.BracketTakeProfitPrice != ""
@@ -307,6 +573,7 @@ func (c *Client) PlaceLimitOrderWithFallback(req *OrderRequest, symbol string, t
 	originalSLLimit := req.BracketStopLossLimitPrice
 	originalTPLimit := req.BracketTakeProfitLimitPrice
 	originalSize := req.Size
+	intendedPrice, _ := strconv.ParseFloat(req.LimitPrice, 64)
 
 	// First, try aggressive limit order
 	limitOrder, err := c.PlaceAggressiveLimitOrder(req, symbol, 0.01)
@@ -322,7 +589,11 @@ func (c *Client) PlaceLimitOrderWithFallback(req *OrderRequest, symbol string, t
 			BracketTakeProfitPrice:      originalTP,
 			BracketTakeProfitLimitPrice: originalTPLimit,
 		}
-		return c.PlaceOrder(marketReq)
+		return c.placeMarketWithSlippageCheck(marketReq, intendedPrice)
+	}
+
+	if intendedPrice <= 0 {
+		intendedPrice, _ = strconv.ParseFloat(limitOrder.LimitPrice, 64)
 	}
 
 	// Wait for fill
@@ -337,7 +608,7 @@ func (c *Client) PlaceLimitOrderWithFallback(req *OrderRequest, symbol string, t
 	if err != nil {
 		// Other error during polling - try to cancel and verify state
 		finalOrder, safeToReplace := c.waitForCancelConfirmation(limitOrder.ID, req.ProductID)
-		if finalOrder != nil && finalOrder.State == "filled" {
+		if finalOrder != nil && ParseOrderState(finalOrder.State).IsFilled() {
 			return finalOrder, nil
 		}
 		if !safeToReplace {
@@ -355,7 +626,7 @@ func (c *Client) PlaceLimitOrderWithFallback(req *OrderRequest, symbol string, t
 			BracketTakeProfitPrice:      originalTP,
 			BracketTakeProfitLimitPrice: originalTPLimit,
 		}
-		return c.PlaceOrder(marketReq)
+		return c.placeMarketWithSlippageCheck(marketReq, intendedPrice)
 	}
 
 	if filledOrder != nil {
@@ -364,7 +635,7 @@ func (c *Client) PlaceLimitOrderWithFallback(req *OrderRequest, symbol string, t
 
 	// Timed out - cancel and verify state before placing market
 	finalOrder, safeToReplace := c.waitForCancelConfirmation(limitOrder.ID, req.ProductID)
-	if finalOrder != nil && finalOrder.State == "filled" {
+	if finalOrder != nil && ParseOrderState(finalOrder.State).IsFilled() {
 		return finalOrder, nil
 	}
 	if !safeToReplace {
@@ -398,7 +669,136 @@ func (c *Client) PlaceLimitOrderWithFallback(req *OrderRequest, symbol string, t
 		marketReq.BracketTakeProfitLimitPrice = originalTPLimit
 	}
 
-	return c.PlaceOrder(marketReq)
+	return c.placeMarketWithSlippageCheck(marketReq, intendedPrice)
+}
+
+// ExcessiveSlippageError indicates a fill deviated from the intended price by more than
+// MaxSlippageBps and the resulting position was immediately flattened
+type ExcessiveSlippageError struct {
+	OrderID       int64
+	IntendedPrice float64
+	FillPrice     float64
+	SlippageBps   float64
+}
+
+func (e *ExcessiveSlippageError) Error() string {
+	return fmt.Sprintf("order %d filled at %.4f vs intended %.4f (%.1f bps slippage), position flattened",
+		e.OrderID, e.FillPrice, e.IntendedPrice, e.SlippageBps)
+}
+
+// fillPrice returns the order's average fill price. Market fills often report it only after
+// the placement response, so it falls back to re-reading the order and then to the position's
+// entry price when the position consists of exactly this fill. It returns 0 when unknown.
+func (c *Client) fillPrice(order *Order, req *OrderRequest) float64 {
+	if price, err := strconv.ParseFloat(order.AverageFillPrice, 64); err == nil && price > 0 {
+		return price
+	}
+	if latest, err := c.GetOrderByID(order.ID); err == nil {
+		if price, err := strconv.ParseFloat(latest.AverageFillPrice, 64); err == nil && price > 0 {
+			return price
+		}
+	}
+	if req.ReduceOnly {
+		return 0 // A closing fill leaves no entry price behind
+	}
+
+	filled := order.Size - order.UnfilledSize
+	if filled <= 0 {
+		filled = req.Size
+	}
+	if req.Side == "sell" {
+		filled = -filled
+	}
+	pos, err := c.GetPosition(req.ProductID)
+	if err != nil || pos.Size != filled {
+		return 0 // The position holds more than this fill, so its entry price is a blend
+	}
+	price, err := strconv.ParseFloat(pos.EntryPrice, 64)
+	if err != nil {
+		return 0
+	}
+	return price
+}
+
+// AdverseSlippageBps returns how far a fill moved against the order side in basis points
+// Positive means a worse fill (paid more on a buy, received less on a sell)
+func AdverseSlippageBps(side string, intendedPrice, fillPrice float64) float64 {
+	if intendedPrice <= 0 || fillPrice <= 0 {
+		return 0
+	}
+	diff := fillPrice - intendedPrice
+	if side == "sell" {
+		diff = -diff
+	}
+	return diff / intendedPrice * 10000
+}
+
+// placeMarketWithSlippageCheck places a fallback market order and verifies its fill price
+func (c *Client) placeMarketWithSlippageCheck(req *OrderRequest, intendedPrice float64) (*Order, error) {
+	order, err := c.PlaceOrder(req)
+	if err != nil {
+		return nil, err
+	}
+	if err := c.checkFillSlippage(order, req, intendedPrice); err != nil {
+		return nil, err
+	}
+	return order, nil
+}
+
+// checkFillSlippage compares the average fill price to the intended price and, when the
+// deviation exceeds MaxSlippageBps, warns and optionally flattens the fill (reduce-only)
+func (c *Client) checkFillSlippage(order *Order, req *OrderRequest, intendedPrice float64) error {
+	maxBps := c.cfg.MaxSlippageBps
+	if maxBps <= 0 || intendedPrice <= 0 || order == nil {
+		return nil
+	}
+	fillPrice := c.fillPrice(order, req)
+	if fillPrice <= 0 {
+		log.Printf("WARNING: order %d fill price unknown, slippage not checked", order.ID)
+		return nil
+	}
+
+	slipBps := AdverseSlippageBps(req.Side, intendedPrice, fillPrice)
+	if slipBps <= maxBps {
+		return nil
+	}
+
+	log.Printf("WARNING: order %d filled at %.4f vs intended %.4f (%.1f bps slippage > %.1f bps max)",
+		order.ID, fillPrice, intendedPrice, slipBps, maxBps)
+
+	// Never try to flatten a fill that was itself closing a position
+	if !c.cfg.AbortOnExcessiveSlippage || req.ReduceOnly {
+		return nil
+	}
+
+	filled := order.Size - order.UnfilledSize
+	if filled <= 0 {
+		filled = req.Size
+	}
+	closeReq := &OrderRequest{
+		ProductID:  req.ProductID,
+		Size:       filled,
+		Side:       oppositeSide(req.Side),
+		OrderType:  "market_order",
+		ReduceOnly: true,
+	}
+	if _, err := c.PlaceOrder(closeReq); err != nil {
+		return fmt.Errorf("failed to flatten order %d after excessive slippage: %w", order.ID, err)
+	}
+
+	return &ExcessiveSlippageError{
+		OrderID:       order.ID,
+		IntendedPrice: intendedPrice,
+		FillPrice:     fillPrice,
+		SlippageBps:   slipBps,
+	}
+}
+
+func oppositeSide(side string) string {
+	if side == "buy" {
+		return "sell"
+	}
+	return "buy"
 }
 
 // waitForCancelConfirmation cancels an order and waits for confirmation
@@ -418,10 +818,11 @@ func (c *Client) waitForCancelConfirmation(orderID int64, productID int) (*Order
 		}
 
 		// Terminal states where we know the final outcome
-		if order.State == "filled" {
+		state := ParseOrderState(order.State)
+		if state.IsFilled() {
 			return order, false // Already filled, don't replace
 		}
-		if order.State == "cancelled" {
+		if state == OrderStateCancelled {
 			return order, true // Cancelled, safe to replace
 		}
 
@@ -434,10 +835,11 @@ func (c *Client) waitForCancelConfirmation(orderID int64, productID int) (*Order
 		return nil, false // Can't verify, not safe
 	}
 
-	if order.State == "filled" {
+	state := ParseOrderState(order.State)
+	if state.IsFilled() {
 		return order, false
 	}
-	if order.State == "cancelled" {
+	if state == OrderStateCancelled {
 		return order, true
 	}
 
@@ -1,7 +1,13 @@
 package delta
 
 import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
 	"testing"
+	"time"
 )
 
 func TestRoundToTickSize(t *testing.T) {
@@ -31,6 +37,60 @@ func TestRoundToTickSize(t *testing.T) {
 	}
 }
 
+func TestRoundBracketPrices(t *testing.T) {
+	tests := []struct {
+		name           string
+		side           string
+		sl, tp         float64
+		policy         string
+		wantSL, wantTP string
+	}{
+		// Long: stop rounds down (further from entry), target rounds down (not beyond the level)
+		{"Long conservative", "buy", 49123.7, 51234.6, BracketRoundingConservative, "49123.5", "51234.5"},
+		{"Short conservative", "sell", 50876.2, 48765.4, BracketRoundingConservative, "50876.5", "48765.5"},
+		{"Default is conservative", "buy", 49123.7, 51234.6, "", "49123.5", "51234.5"},
+		{"Nearest", "buy", 49123.7, 51234.6, BracketRoundingNearest, "49123.5", "51234.5"},
+		{"Nearest rounds up", "buy", 49123.8, 51234.8, BracketRoundingNearest, "49124.0", "51235.0"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sl, tp := RoundBracketPrices(tt.side, tt.sl, tt.tp, "0.5", tt.policy)
+			if sl != tt.wantSL || tp != tt.wantTP {
+				t.Errorf("RoundBracketPrices() = %s/%s, want %s/%s", sl, tp, tt.wantSL, tt.wantTP)
+			}
+		})
+	}
+
+	// Floating point must not push an on-tick price a tick further out
+	if sl, tp := RoundBracketPrices("buy", 50000.3, 50100.3, "0.1", ""); sl != "50000.3" || tp != "50100.3" {
+		t.Errorf("on-tick prices = %s/%s, want 50000.3/50100.3", sl, tp)
+	}
+}
+
+func TestFormatPrice(t *testing.T) {
+	tests := []struct {
+		name    string
+		price   float64
+		product *Product
+		want    string
+	}{
+		{"Sub-dollar tick", 0.123456, &Product{TickSize: "0.001"}, "0.123"},
+		{"Sub-dollar tick rounds", 0.0875, &Product{TickSize: "0.0005"}, "0.0875"},
+		{"Large tick", 50012.3, &Product{TickSize: "5"}, "50010"},
+		{"Half-unit tick", 50000.74, &Product{TickSize: "0.5"}, "50000.5"},
+		{"Unknown tick keeps precision", 0.00012345, nil, "0.00012345"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := FormatPrice(tt.price, tt.product); got != tt.want {
+				t.Errorf("FormatPrice() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
 func TestOrderRequest_Validation(t *testing.T) {
 	// Simple test for OrderRequest fields
 	req := &OrderRequest{
@@ -45,3 +105,382 @@ func TestOrderRequest_Validation(t *testing.T) {
 		t.Error("OrderRequest fields not set correctly")
 	}
 }
+
+func TestEditBracket_RequestBody(t *testing.T) {
+	var gotMethod, gotPath string
+	var gotBody map[string]interface{}
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		gotMethod = r.Method
+		gotPath = r.URL.Path
+		_ = json.NewDecoder(r.Body).Decode(&gotBody)
+		w.Write([]byte(`{"success":true,"result":{}}`))
+	})
+
+	if err := c.EditBracket(42, 27, "50000.5", ""); err != nil {
+		t.Fatalf("EditBracket() error = %v", err)
+	}
+
+	if gotMethod != http.MethodPut || gotPath != "/v2/orders/bracket" {
+		t.Fatalf("unexpected request: %s %s", gotMethod, gotPath)
+	}
+	if gotBody["id"] != float64(42) || gotBody["product_id"] != float64(27) {
+		t.Errorf("unexpected ids in body: %#v", gotBody)
+	}
+	if gotBody["bracket_stop_loss_price"] != "50000.5" {
+		t.Errorf("stop loss = %v, want 50000.5", gotBody["bracket_stop_loss_price"])
+	}
+	if _, ok := gotBody["bracket_take_profit_price"]; ok {
+		t.Error("empty take profit should be omitted from the body")
+	}
+}
+
+func TestEditBracket_NotFound(t *testing.T) {
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadRequest)
+		w.Write([]byte(`{"success":false,"error":{"code":"open_order_not_found","message":"order not found"}}`))
+	})
+
+	err := c.EditBracket(42, 27, "50000.5", "51000.0")
+	if !errors.Is(err, ErrBracketNotFound) {
+		t.Fatalf("expected ErrBracketNotFound, got %v", err)
+	}
+}
+
+func TestCheckFillSlippage_WithinTolerance(t *testing.T) {
+	requests := 0
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		w.Write([]byte(`{"success":true,"result":{}}`))
+	})
+	c.cfg.MaxSlippageBps = 20
+	c.cfg.AbortOnExcessiveSlippage = true
+
+	req := &OrderRequest{ProductID: 27, Size: 10, Side: "buy"}
+	order := &Order{ID: 1, Size: 10, AverageFillPrice: "50050"} // 10 bps worse
+
+	if err := c.checkFillSlippage(order, req, 50000); err != nil {
+		t.Fatalf("expected no error within tolerance, got %v", err)
+	}
+	if requests != 0 {
+		t.Errorf("expected no flatten order, got %d requests", requests)
+	}
+}
+
+func TestCheckFillSlippage_ExceededFlattens(t *testing.T) {
+	var closeBody map[string]interface{}
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		_ = json.NewDecoder(r.Body).Decode(&closeBody)
+		w.Write([]byte(`{"success":true,"result":{"id":2,"state":"filled"}}`))
+	})
+	c.cfg.MaxSlippageBps = 20
+	c.cfg.AbortOnExcessiveSlippage = true
+
+	req := &OrderRequest{ProductID: 27, Size: 10, Side: "sell"}
+	order := &Order{ID: 1, Size: 10, AverageFillPrice: "49800"} // 40 bps worse for a sell
+
+	err := c.checkFillSlippage(order, req, 50000)
+	var slipErr *ExcessiveSlippageError
+	if !errors.As(err, &slipErr) {
+		t.Fatalf("expected ExcessiveSlippageError, got %v", err)
+	}
+	if slipErr.SlippageBps < 39.9 || slipErr.SlippageBps > 40.1 {
+		t.Errorf("slippage = %.2f bps, want 40", slipErr.SlippageBps)
+	}
+	if closeBody["side"] != "buy" || closeBody["reduce_only"] != true || closeBody["size"] != float64(10) {
+		t.Errorf("unexpected flatten order: %#v", closeBody)
+	}
+}
+
+func TestCheckFillSlippage_FallsBackToPositionEntry(t *testing.T) {
+	var closeBody map[string]interface{}
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.Method == http.MethodGet && r.URL.Path == "/v2/orders/1":
+			w.Write([]byte(`{"success":true,"result":{"id":1,"size":10,"state":"closed"}}`))
+		case r.Method == http.MethodGet && r.URL.Path == "/v2/positions":
+			w.Write([]byte(`{"success":true,"result":{"product_id":27,"size":10,"entry_price":"50200"}}`))
+		case r.Method == http.MethodPost && r.URL.Path == "/v2/orders":
+			_ = json.NewDecoder(r.Body).Decode(&closeBody)
+			w.Write([]byte(`{"success":true,"result":{"id":2,"state":"filled"}}`))
+		default:
+			t.Errorf("unexpected request %s %s", r.Method, r.URL.Path)
+		}
+	})
+	c.cfg.MaxSlippageBps = 20
+	c.cfg.AbortOnExcessiveSlippage = true
+
+	// Placement reported no fill price; the position's entry shows 40 bps worse for a buy
+	req := &OrderRequest{ProductID: 27, Size: 10, Side: "buy"}
+	order := &Order{ID: 1, Size: 10}
+
+	err := c.checkFillSlippage(order, req, 50000)
+	var slipErr *ExcessiveSlippageError
+	if !errors.As(err, &slipErr) || slipErr.FillPrice != 50200 {
+		t.Fatalf("expected ExcessiveSlippageError at 50200, got %v", err)
+	}
+	if closeBody["side"] != "sell" || closeBody["reduce_only"] != true {
+		t.Errorf("unexpected flatten order: %#v", closeBody)
+	}
+}
+
+// stopEntryHandler serves a product with tick 0.5 and captures the order body
+func stopEntryHandler(gotBody *map[string]interface{}) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.Method == http.MethodGet && r.URL.Path == "/v2/products/BTCUSD":
+			w.Write([]byte(`{"success":true,"result":{"id":27,"symbol":"BTCUSD","tick_size":"0.5"}}`))
+		case r.Method == http.MethodPost && r.URL.Path == "/v2/orders":
+			_ = json.NewDecoder(r.Body).Decode(gotBody)
+			w.Write([]byte(`{"success":true,"result":{"id":7,"state":"pending"}}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}
+}
+
+func TestPlaceStopEntryOrder_StopMarket(t *testing.T) {
+	var gotBody map[string]interface{}
+	c := newTestClient(t, stopEntryHandler(&gotBody))
+
+	req := &OrderRequest{ProductSymbol: "BTCUSD", Size: 3, Side: "buy", LimitPrice: "50010"}
+	if _, err := c.PlaceStopEntryOrder(req, "50000.2", false); err != nil {
+		t.Fatalf("PlaceStopEntryOrder() error = %v", err)
+	}
+
+	if gotBody["order_type"] != "market_order" {
+		t.Errorf("order_type = %v, want market_order", gotBody["order_type"])
+	}
+	if gotBody["stop_order_type"] != "stop_loss_order" {
+		t.Errorf("stop_order_type = %v, want stop_loss_order", gotBody["stop_order_type"])
+	}
+	// Buy-stop trigger rounds up so it never sits below resistance
+	if gotBody["stop_price"] != "50000.5" {
+		t.Errorf("stop_price = %v, want 50000.5", gotBody["stop_price"])
+	}
+	if _, ok := gotBody["limit_price"]; ok {
+		t.Errorf("stop-market should not send a limit price, got %v", gotBody["limit_price"])
+	}
+}
+
+func TestPlaceStopEntryOrder_StopLimit(t *testing.T) {
+	var gotBody map[string]interface{}
+	c := newTestClient(t, stopEntryHandler(&gotBody))
+
+	req := &OrderRequest{ProductSymbol: "BTCUSD", Size: 3, Side: "sell", LimitPrice: "48990.7"}
+	if _, err := c.PlaceStopEntryOrder(req, "49000.7", true); err != nil {
+		t.Fatalf("PlaceStopEntryOrder() error = %v", err)
+	}
+
+	if gotBody["order_type"] != "limit_order" {
+		t.Errorf("order_type = %v, want limit_order", gotBody["order_type"])
+	}
+	if gotBody["stop_order_type"] != "stop_loss_order" {
+		t.Errorf("stop_order_type = %v, want stop_loss_order", gotBody["stop_order_type"])
+	}
+	if gotBody["stop_price"] != "49000.5" {
+		t.Errorf("stop_price = %v, want 49000.5", gotBody["stop_price"])
+	}
+	if gotBody["limit_price"] != "48990.5" {
+		t.Errorf("limit_price = %v, want 48990.5", gotBody["limit_price"])
+	}
+	if gotBody["time_in_force"] != "gtc" {
+		t.Errorf("time_in_force = %v, want gtc", gotBody["time_in_force"])
+	}
+}
+
+func TestPlaceStopEntryOrder_StopLimitNeedsLimitPrice(t *testing.T) {
+	c := newTestClient(t, stopEntryHandler(new(map[string]interface{})))
+
+	req := &OrderRequest{ProductSymbol: "BTCUSD", Size: 1, Side: "buy"}
+	if _, err := c.PlaceStopEntryOrder(req, "50000", true); err == nil {
+		t.Error("expected error for stop-limit without a limit price")
+	}
+}
+
+func TestGenerateClientOrderID_Stable(t *testing.T) {
+	candle := time.Date(2024, 3, 1, 12, 30, 0, 0, time.UTC)
+
+	id := GenerateClientOrderID("BTCUSD", "buy", 5, candle, 1, "scalp")
+	if again := GenerateClientOrderID("BTCUSD", "buy", 5, candle, 1, "scalp"); again != id {
+		t.Errorf("same order intent changed the ID: %s vs %s", id, again)
+	}
+	if len(id) > 32 {
+		t.Errorf("client_order_id %q exceeds 32 characters", id)
+	}
+
+	variants := []string{
+		GenerateClientOrderID("ETHUSD", "buy", 5, candle, 1, "scalp"),
+		GenerateClientOrderID("BTCUSD", "sell", 5, candle, 1, "scalp"),
+		GenerateClientOrderID("BTCUSD", "buy", 6, candle, 1, "scalp"),
+		GenerateClientOrderID("BTCUSD", "buy", 5, candle.Add(time.Minute), 1, "scalp"),
+		GenerateClientOrderID("BTCUSD", "buy", 5, candle, 2, "scalp"), // Re-entry on the same candle
+		GenerateClientOrderID("BTCUSD", "buy", 5, candle, 1, "grid:50000"),
+	}
+	for _, v := range variants {
+		if v == id {
+			t.Errorf("different order intent produced the same ID %s", id)
+		}
+	}
+}
+
+func TestGetOrderHistory_QueriesProductSince(t *testing.T) {
+	var query url.Values
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/v2/orders/history" {
+			t.Errorf("path = %s, want /v2/orders/history", r.URL.Path)
+		}
+		query = r.URL.Query()
+		w.Write([]byte(`{"success":true,"result":[{"id":7,"side":"sell","stop_order_type":"take_profit_order","state":"closed","average_fill_price":"50500"}]}`))
+	})
+
+	since := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+	orders, err := c.GetOrderHistory(27, since)
+	if err != nil {
+		t.Fatalf("GetOrderHistory() error = %v", err)
+	}
+	if query.Get("product_ids") != "27" || query.Get("start_time") != fmt.Sprint(since.UnixMicro()) {
+		t.Errorf("query = %v, want product 27 since %d", query, since.UnixMicro())
+	}
+	if len(orders) != 1 || orders[0].AverageFillPrice != "50500" {
+		t.Errorf("orders = %+v, want the filled take-profit", orders)
+	}
+}
+
+func TestPlaceOrder_DuplicateClientOrderIDReturnsExisting(t *testing.T) {
+	var posts int
+	var fetchedPath string
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case http.MethodPost:
+			posts++
+			w.WriteHeader(http.StatusBadRequest)
+			w.Write([]byte(`{"success":false,"error":{"code":"duplicate_client_order_id","message":"client order id already exists"}}`))
+		case http.MethodGet:
+			fetchedPath = r.URL.Path
+			w.Write([]byte(`{"success":true,"result":{"id":99,"state":"open","client_order_id":"dgabc"}}`))
+		}
+	})
+
+	order, err := c.PlaceOrder(&OrderRequest{ProductID: 27, Size: 1, Side: "buy", OrderType: "limit_order", LimitPrice: "50000", ClientOrderID: "dgabc"})
+	if err != nil {
+		t.Fatalf("PlaceOrder() error = %v", err)
+	}
+	if order.ID != 99 {
+		t.Errorf("order ID = %d, want existing order 99", order.ID)
+	}
+	if fetchedPath != "/v2/orders/client_order_id/dgabc" {
+		t.Errorf("fetched %q, want lookup by client order id", fetchedPath)
+	}
+	if posts != 1 {
+		t.Errorf("expected a single POST, got %d", posts)
+	}
+}
+
+func TestPlaceOrder_DuplicateWithoutClientOrderIDFails(t *testing.T) {
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadRequest)
+		w.Write([]byte(`{"success":false,"error":{"code":"duplicate_client_order_id","message":"dup"}}`))
+	})
+
+	if _, err := c.PlaceOrder(&OrderRequest{ProductID: 27, Size: 1, Side: "buy"}); err == nil {
+		t.Error("expected error when no client_order_id was sent")
+	}
+}
+
+func TestPlaceOrder_DuplicateOfClosedOrderFails(t *testing.T) {
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodPost {
+			w.WriteHeader(http.StatusBadRequest)
+			w.Write([]byte(`{"success":false,"error":{"code":"duplicate_client_order_id","message":"client order id already exists"}}`))
+			return
+		}
+		w.Write([]byte(`{"success":true,"result":{"id":99,"state":"closed","client_order_id":"dgabc"}}`))
+	})
+
+	order, err := c.PlaceOrder(&OrderRequest{ProductID: 27, Size: 1, Side: "buy", OrderType: "limit_order", LimitPrice: "50000", ClientOrderID: "dgabc"})
+	if err == nil {
+		t.Fatalf("PlaceOrder() returned closed order %d as a new order, want an error", order.ID)
+	}
+}
+
+func TestPlaceOrder_TransientFailureReturnsAcceptedOrder(t *testing.T) {
+	defer func(backoff time.Duration) { requestBackoff = backoff }(requestBackoff)
+	requestBackoff = time.Millisecond
+
+	var gotPath string
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodPost {
+			w.WriteHeader(http.StatusBadGateway)
+			return
+		}
+		gotPath = r.URL.Path
+		w.Write([]byte(`{"success":true,"result":{"id":77,"state":"open","client_order_id":"dgabc"}}`))
+	})
+
+	order, err := c.PlaceOrder(&OrderRequest{ProductID: 27, Size: 1, Side: "buy", OrderType: "limit_order", LimitPrice: "50000", ClientOrderID: "dgabc"})
+	if err != nil {
+		t.Fatalf("PlaceOrder() error = %v, want the order accepted behind the 502s", err)
+	}
+	if order.ID != 77 || gotPath != "/v2/orders/client_order_id/dgabc" {
+		t.Errorf("order %d looked up at %q, want 77 via the client order ID", order.ID, gotPath)
+	}
+}
+
+func TestPlaceOrder_TransientFailureWithoutOrderFails(t *testing.T) {
+	defer func(backoff time.Duration) { requestBackoff = backoff }(requestBackoff)
+	requestBackoff = time.Millisecond
+
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodPost {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.WriteHeader(http.StatusNotFound)
+		w.Write([]byte(`{"success":false,"error":{"code":"not_found"}}`))
+	})
+
+	_, err := c.PlaceOrder(&OrderRequest{ProductID: 27, Size: 1, Side: "buy", OrderType: "market_order", ClientOrderID: "dgabc"})
+	if !IsTransient(err) {
+		t.Fatalf("PlaceOrder() error = %v, want the transient placement error", err)
+	}
+}
+
+func TestSetLeverage_SkipsUnchanged(t *testing.T) {
+	calls := 0
+	fail := false
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		if fail {
+			w.WriteHeader(http.StatusBadRequest)
+			w.Write([]byte(`{"success":false,"error":{"code":"leverage_limit_exceeded","message":"too high"}}`))
+			return
+		}
+		w.Write([]byte(`{"success":true,"result":{}}`))
+	})
+
+	for i := 0; i < 3; i++ {
+		if err := c.SetLeverage(27, 10); err != nil {
+			t.Fatalf("SetLeverage() error = %v", err)
+		}
+	}
+	if calls != 1 {
+		t.Fatalf("requests = %d after repeated SetLeverage(27, 10), want 1", calls)
+	}
+
+	if err := c.SetLeverage(27, 5); err != nil || calls != 2 {
+		t.Fatalf("changed leverage: err = %v, requests = %d, want a second request", err, calls)
+	}
+	if err := c.ForceSetLeverage(27, 5); err != nil || calls != 3 {
+		t.Fatalf("forced leverage: err = %v, requests = %d, want a third request", err, calls)
+	}
+
+	// A failure drops the cached value, so the same leverage is sent again afterwards
+	fail = true
+	if err := c.SetLeverage(27, 20); err == nil {
+		t.Fatal("expected error from rejected leverage")
+	}
+	fail = false
+	if err := c.SetLeverage(27, 5); err != nil || calls != 5 {
+		t.Errorf("after failure: err = %v, requests = %d, want 5", err, calls)
+	}
+}
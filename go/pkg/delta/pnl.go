@@ -0,0 +1,37 @@
+package delta
+
+import (
+	"math"
+	"strconv"
+)
+
+// ComputeUnrealizedPnL is the PnL of a linear futures position of size contracts entered at
+// entryPrice and marked at markPrice: contracts * contract value * (mark - entry), negated
+// for shorts. A negative size is read as short whatever side says, matching the API's signed
+// Position.Size. Returns 0 when the product's contract value can't be parsed.
+func ComputeUnrealizedPnL(side string, size int, entryPrice, markPrice float64, product *Product) float64 {
+	cv, err := ParseContractValue(product)
+	if err != nil || size == 0 {
+		return 0
+	}
+	contracts := math.Abs(float64(size))
+	pnl := contracts * cv * (markPrice - entryPrice)
+	if side == "sell" || size < 0 {
+		pnl = -pnl
+	}
+	return pnl
+}
+
+// UnrealizedPnLAt recomputes the position's unrealized PnL at markPrice with
+// ComputeUnrealizedPnL, so live figures follow the same convention as the backtester
+func (p Position) UnrealizedPnLAt(markPrice float64, product *Product) float64 {
+	entry, err := strconv.ParseFloat(p.EntryPrice, 64)
+	if err != nil {
+		return 0
+	}
+	side := "buy"
+	if p.Size < 0 {
+		side = "sell"
+	}
+	return ComputeUnrealizedPnL(side, p.Size, entry, markPrice, product)
+}
@@ -0,0 +1,46 @@
+package delta
+
+import (
+	"math"
+	"testing"
+)
+
+func TestComputeUnrealizedPnL(t *testing.T) {
+	btc := &Product{Symbol: "BTCUSD", ContractValue: "0.001"}
+	tests := []struct {
+		name        string
+		side        string
+		size        int
+		entry, mark float64
+		want        float64
+	}{
+		{"long gains as price rises", "buy", 10, 50000, 50500, 5},
+		{"long loses as price falls", "buy", 10, 50000, 49500, -5},
+		{"short gains as price falls", "sell", 10, 50000, 49500, 5},
+		{"short loses as price rises", "sell", 10, 50000, 50500, -5},
+		{"negative size is short", "", -10, 50000, 49500, 5},
+		{"flat", "buy", 0, 50000, 50500, 0},
+	}
+	for _, tt := range tests {
+		if got := ComputeUnrealizedPnL(tt.side, tt.size, tt.entry, tt.mark, btc); math.Abs(got-tt.want) > 1e-9 {
+			t.Errorf("%s: got %.6f, want %.6f", tt.name, got, tt.want)
+		}
+	}
+
+	if got := ComputeUnrealizedPnL("buy", 10, 50000, 50500, &Product{ContractValue: "bad"}); got != 0 {
+		t.Errorf("unparseable contract value: got %.4f, want 0", got)
+	}
+}
+
+func TestPosition_UnrealizedPnLAtMatchesCompute(t *testing.T) {
+	eth := &Product{Symbol: "ETHUSD", ContractValue: "0.01"}
+	long := Position{Size: 25, EntryPrice: "3000"}
+	short := Position{Size: -25, EntryPrice: "3000"}
+
+	if got, want := long.UnrealizedPnLAt(3100, eth), ComputeUnrealizedPnL("buy", 25, 3000, 3100, eth); got != want || math.Abs(got-25) > 1e-9 {
+		t.Errorf("long: got %.4f, want %.4f (25)", got, want)
+	}
+	if got, want := short.UnrealizedPnLAt(3100, eth), ComputeUnrealizedPnL("sell", 25, 3000, 3100, eth); got != want || math.Abs(got+25) > 1e-9 {
+		t.Errorf("short: got %.4f, want %.4f (-25)", got, want)
+	}
+}
@@ -0,0 +1,48 @@
+package delta
+
+import (
+	"fmt"
+	"time"
+)
+
+// ProductTradeable reports whether new orders may be placed on p at now, and why not when
+// they may not: the product must be live, active and operational, and a dated contract must
+// settle more than minTimeToSettle after now. Fields the exchange leaves out are not checked.
+func ProductTradeable(p *Product, now time.Time, minTimeToSettle time.Duration) (bool, string) {
+	if p == nil {
+		return false, "no product"
+	}
+	if p.State != "" && p.State != "live" {
+		return false, fmt.Sprintf("product state is %s", p.State)
+	}
+	if p.IsActive != nil && !*p.IsActive {
+		return false, "product is not active"
+	}
+	if p.TradingStatus != "" && p.TradingStatus != "operational" {
+		return false, fmt.Sprintf("trading status is %s", p.TradingStatus)
+	}
+	if p.SettlementTime == "" {
+		return true, ""
+	}
+	settles, err := time.Parse(time.RFC3339Nano, p.SettlementTime)
+	if err != nil {
+		return false, fmt.Sprintf("unparseable settlement time %q", p.SettlementTime)
+	}
+	if left := settles.Sub(now); left <= minTimeToSettle {
+		if left <= 0 {
+			return false, fmt.Sprintf("settled at %s", settles.UTC().Format(time.RFC3339))
+		}
+		return false, fmt.Sprintf("settles in %v at %s", left.Round(time.Minute), settles.UTC().Format(time.RFC3339))
+	}
+	return true, ""
+}
+
+// IsProductTradeable fetches symbol's product and checks it with ProductTradeable against
+// MinTimeToSettlement. A failed fetch reports the symbol as not tradeable.
+func (c *Client) IsProductTradeable(symbol string) (bool, string) {
+	product, err := c.GetProductBySymbol(symbol)
+	if err != nil {
+		return false, fmt.Sprintf("failed to get product: %v", err)
+	}
+	return ProductTradeable(product, time.Now(), c.cfg.MinTimeToSettlement)
+}
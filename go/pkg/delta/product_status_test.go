@@ -0,0 +1,52 @@
+package delta
+
+import (
+	"net/http"
+	"testing"
+	"time"
+)
+
+func TestProductTradeable(t *testing.T) {
+	now := time.Date(2024, 6, 28, 10, 0, 0, 0, time.UTC)
+	inactive := false
+	tests := []struct {
+		name       string
+		product    *Product
+		wantOK     bool
+		wantReason string
+	}{
+		{"perpetual without status fields", &Product{Symbol: "BTCUSD"}, true, ""},
+		{"live and operational", &Product{State: "live", TradingStatus: "operational"}, true, ""},
+		{"expired", &Product{State: "expired"}, false, "product state is expired"},
+		{"upcoming", &Product{State: "upcoming"}, false, "product state is upcoming"},
+		{"inactive", &Product{IsActive: &inactive}, false, "product is not active"},
+		{"halted", &Product{State: "live", TradingStatus: "disrupted_post_only"}, false, "trading status is disrupted_post_only"},
+		{"settles later", &Product{SettlementTime: "2024-06-28T12:00:00Z"}, true, ""},
+		{"settles soon", &Product{SettlementTime: "2024-06-28T10:45:00Z"}, false, "settles in 45m0s at 2024-06-28T10:45:00Z"},
+		{"settled", &Product{SettlementTime: "2024-06-28T08:00:00Z"}, false, "settled at 2024-06-28T08:00:00Z"},
+		{"bad settlement time", &Product{SettlementTime: "soon"}, false, `unparseable settlement time "soon"`},
+		{"nil", nil, false, "no product"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ok, reason := ProductTradeable(tt.product, now, time.Hour)
+			if ok != tt.wantOK || reason != tt.wantReason {
+				t.Errorf("ProductTradeable() = %v, %q; want %v, %q", ok, reason, tt.wantOK, tt.wantReason)
+			}
+		})
+	}
+}
+
+func TestIsProductTradeable(t *testing.T) {
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/v2/products/XRPUSD" {
+			t.Errorf("unexpected path %s", r.URL.Path)
+		}
+		w.Write([]byte(`{"success":true,"result":{"id":5,"symbol":"XRPUSD","is_active":false,"state":"live"}}`))
+	})
+
+	ok, reason := c.IsProductTradeable("XRPUSD")
+	if ok || reason != "product is not active" {
+		t.Errorf("IsProductTradeable() = %v, %q; want false, %q", ok, reason, "product is not active")
+	}
+}
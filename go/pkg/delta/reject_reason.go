@@ -0,0 +1,89 @@
+package delta
+
+import (
+	"errors"
+	"strings"
+)
+
+// RejectReason classifies why the exchange refused an order, so callers can react
+// (e.g. back off on margin, re-round on price) instead of treating every rejection alike
+type RejectReason string
+
+const (
+	RejectUnknown            RejectReason = "unknown"
+	RejectInsufficientMargin RejectReason = "insufficient_margin"
+	RejectInvalidPrice       RejectReason = "invalid_price"
+	RejectInvalidSize        RejectReason = "invalid_size"
+	RejectReduceOnly         RejectReason = "reduce_only"
+	RejectPostOnly           RejectReason = "post_only"
+	RejectPositionLimit      RejectReason = "position_limit"
+	RejectLiquidation        RejectReason = "liquidation"
+	RejectInvalidProduct     RejectReason = "invalid_product"
+	RejectRateLimited        RejectReason = "rate_limited"
+)
+
+// rejectKeywords maps fragments of Delta error codes/messages to a reason. Codes arrive in
+// both snake_case ("insufficient_margin") and CamelCase ("InsufficientMargin"), so matching
+// is done on a lowercased string with separators removed. Order matters: the first match wins.
+var rejectKeywords = []struct {
+	fragment string
+	reason   RejectReason
+}{
+	{"insufficientmargin", RejectInsufficientMargin},
+	{"insufficientbalance", RejectInsufficientMargin},
+	{"notenoughmargin", RejectInsufficientMargin},
+	{"reduceonly", RejectReduceOnly},
+	{"postonly", RejectPostOnly},
+	{"immediateliquidation", RejectLiquidation},
+	{"bankruptcy", RejectLiquidation},
+	{"positionlimit", RejectPositionLimit},
+	{"risklimit", RejectPositionLimit},
+	{"exceedssizelimit", RejectPositionLimit},
+	{"ordersizeexceeds", RejectInvalidSize},
+	{"invalidsize", RejectInvalidSize},
+	{"minsize", RejectInvalidSize},
+	{"ticksize", RejectInvalidPrice},
+	{"invalidprice", RejectInvalidPrice},
+	{"invalidlimitprice", RejectInvalidPrice},
+	{"pricebandbreached", RejectInvalidPrice},
+	{"invalidproduct", RejectInvalidProduct},
+	{"invalidcontract", RejectInvalidProduct},
+	{"ratelimit", RejectRateLimited},
+	{"toomanyrequests", RejectRateLimited},
+}
+
+// ParseRejectReason maps a Delta API error code and message to a RejectReason.
+// The code is checked first; the message is only used when the code is unrecognised.
+func ParseRejectReason(code, message string) RejectReason {
+	for _, s := range []string{code, message} {
+		normalized := normalizeRejectText(s)
+		if normalized == "" {
+			continue
+		}
+		for _, kw := range rejectKeywords {
+			if strings.Contains(normalized, kw.fragment) {
+				return kw.reason
+			}
+		}
+	}
+	return RejectUnknown
+}
+
+func normalizeRejectText(s string) string {
+	s = strings.ToLower(s)
+	return strings.NewReplacer("_", "", "-", "", " ", "").Replace(s)
+}
+
+// RejectReasonOf extracts the rejection reason from an error returned by order placement.
+// Returns RejectUnknown for errors that are not exchange rejections.
+func RejectReasonOf(err error) RejectReason {
+	var rejectedErr *OrderRejectedError
+	if errors.As(err, &rejectedErr) {
+		return rejectedErr.Kind
+	}
+	var apiErr *APIError
+	if errors.As(err, &apiErr) {
+		return ParseRejectReason(apiErr.Code, apiErr.Message)
+	}
+	return RejectUnknown
+}
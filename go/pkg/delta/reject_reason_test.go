@@ -0,0 +1,73 @@
+package delta
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+)
+
+func TestParseRejectReason(t *testing.T) {
+	tests := []struct {
+		code    string
+		message string
+		want    RejectReason
+	}{
+		{"insufficient_margin", "Insufficient margin to place order", RejectInsufficientMargin},
+		{"InsufficientMargin", "", RejectInsufficientMargin},
+		{"bad_request", "Insufficient balance in wallet", RejectInsufficientMargin},
+		{"reduce_only_order_not_allowed", "", RejectReduceOnly},
+		{"ImmediateExecutionPostOnlyOrder", "", RejectPostOnly},
+		{"ImmediateLiquidationOrder", "", RejectLiquidation},
+		{"LowerthanBankruptcy", "", RejectLiquidation},
+		{"OrderExceedsSizeLimit", "", RejectPositionLimit},
+		{"risk_limits_breached", "", RejectPositionLimit},
+		{"OrderSizeExceedsAvailable", "", RejectInvalidSize},
+		{"invalid_limit_price", "", RejectInvalidPrice},
+		{"bad_request", "price is not a multiple of tick size", RejectInvalidPrice},
+		{"InvalidProduct", "", RejectInvalidProduct},
+		{"ip_blocked_for_rate_limit", "", RejectRateLimited},
+		{"something_new", "unexpected", RejectUnknown},
+		{"", "", RejectUnknown},
+	}
+
+	for _, tt := range tests {
+		if got := ParseRejectReason(tt.code, tt.message); got != tt.want {
+			t.Errorf("ParseRejectReason(%q, %q) = %s, want %s", tt.code, tt.message, got, tt.want)
+		}
+	}
+}
+
+func TestRejectReasonOf(t *testing.T) {
+	wrapped := fmt.Errorf("placing: %w", &OrderRejectedError{Kind: RejectPostOnly})
+	if got := RejectReasonOf(wrapped); got != RejectPostOnly {
+		t.Errorf("RejectReasonOf(OrderRejectedError) = %s, want post_only", got)
+	}
+	if got := RejectReasonOf(&APIError{Code: "insufficient_margin"}); got != RejectInsufficientMargin {
+		t.Errorf("RejectReasonOf(APIError) = %s, want insufficient_margin", got)
+	}
+	if got := RejectReasonOf(errors.New("timeout")); got != RejectUnknown {
+		t.Errorf("RejectReasonOf(plain error) = %s, want unknown", got)
+	}
+}
+
+func TestPlaceOrder_RejectionCarriesReason(t *testing.T) {
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadRequest)
+		w.Write([]byte(`{"success":false,"error":{"code":"insufficient_margin","message":"Insufficient margin"}}`))
+	})
+
+	_, err := c.PlaceOrder(&OrderRequest{ProductID: 27, Size: 1, Side: "buy"})
+	var rejectedErr *OrderRejectedError
+	if !errors.As(err, &rejectedErr) {
+		t.Fatalf("expected OrderRejectedError, got %v", err)
+	}
+	if rejectedErr.Kind != RejectInsufficientMargin {
+		t.Errorf("Kind = %s, want insufficient_margin", rejectedErr.Kind)
+	}
+	// The raw API error stays reachable for callers matching on codes
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) || apiErr.Code != "insufficient_margin" {
+		t.Errorf("expected wrapped APIError, got %v", err)
+	}
+}
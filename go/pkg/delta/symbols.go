@@ -0,0 +1,145 @@
+package delta
+
+import (
+	"strings"
+	"sync"
+)
+
+// SymbolInfo describes how a Delta symbol maps onto other venues
+type SymbolInfo struct {
+	Underlying string // Base asset, e.g. "BTC"
+	Perp       bool   // Perpetual future (vs spot/option/dated future)
+	Binance    string // Binance USDT-M perp symbol; empty derives <Underlying>USDT
+	OKX        string // OKX swap instrument; empty derives <Underlying>-USDT-SWAP
+}
+
+// defaultSymbolTable lists the Delta symbols with known cross-venue mappings
+var defaultSymbolTable = map[string]SymbolInfo{
+	"BTCUSD":  {Underlying: "BTC", Perp: true},
+	"BTCINR":  {Underlying: "BTC", Perp: true},
+	"ETHUSD":  {Underlying: "ETH", Perp: true},
+	"ETHINR":  {Underlying: "ETH", Perp: true},
+	"SOLUSD":  {Underlying: "SOL", Perp: true},
+	"SOLINR":  {Underlying: "SOL", Perp: true},
+	"XRPUSD":  {Underlying: "XRP", Perp: true},
+	"DOGEUSD": {Underlying: "DOGE", Perp: true},
+	// Binance lists low-priced assets in 1000-unit contracts
+	"PEPEUSD": {Underlying: "PEPE", Perp: true, Binance: "1000PEPEUSDT"},
+}
+
+// perpQuoteSuffixes are stripped, longest first, to derive the underlying of unlisted perps
+var perpQuoteSuffixes = []string{"USDT", "USD", "INR"}
+
+// SymbolRegistry resolves Delta symbols (and user-registered aliases) to their underlying
+// asset and the equivalent symbols on external venues
+type SymbolRegistry struct {
+	mu      sync.RWMutex
+	symbols map[string]SymbolInfo
+	aliases map[string]string
+}
+
+// DefaultSymbols is the registry used by the data and funding fetchers
+var DefaultSymbols = NewSymbolRegistry()
+
+// NewSymbolRegistry creates a registry loaded with the built-in symbol table
+func NewSymbolRegistry() *SymbolRegistry {
+	r := &SymbolRegistry{
+		symbols: make(map[string]SymbolInfo, len(defaultSymbolTable)),
+		aliases: make(map[string]string),
+	}
+	for sym, info := range defaultSymbolTable {
+		r.symbols[sym] = info
+	}
+	return r
+}
+
+// Register adds or replaces the mapping for a symbol
+func (r *SymbolRegistry) Register(symbol string, info SymbolInfo) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.symbols[normalizeSymbol(symbol)] = info
+}
+
+// RegisterAlias makes alias resolve to symbol (e.g. "XBTUSD" -> "BTCUSD")
+func (r *SymbolRegistry) RegisterAlias(alias, symbol string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.aliases[normalizeSymbol(alias)] = normalizeSymbol(symbol)
+}
+
+// Normalize upper-cases and trims a symbol and resolves registered aliases
+func (r *SymbolRegistry) Normalize(symbol string) string {
+	sym := normalizeSymbol(symbol)
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	if target, ok := r.aliases[sym]; ok {
+		return target
+	}
+	return sym
+}
+
+// lookup returns the table entry for a symbol, or one derived from its quote suffix.
+// ok is false when the symbol is neither listed nor recognisable as a perp.
+func (r *SymbolRegistry) lookup(symbol string) (SymbolInfo, string, bool) {
+	sym := r.Normalize(symbol)
+	r.mu.RLock()
+	info, ok := r.symbols[sym]
+	r.mu.RUnlock()
+	if ok {
+		return info, sym, true
+	}
+
+	// Options and dated futures use dashed symbols (e.g. C-BTC-90000-310124)
+	if strings.Contains(sym, "-") {
+		return SymbolInfo{}, sym, false
+	}
+	for _, quote := range perpQuoteSuffixes {
+		if base := strings.TrimSuffix(sym, quote); base != sym && base != "" {
+			return SymbolInfo{Underlying: base, Perp: true}, sym, true
+		}
+	}
+	return SymbolInfo{}, sym, false
+}
+
+// Underlying returns the base asset, or the normalized symbol if it cannot be determined
+func (r *SymbolRegistry) Underlying(symbol string) string {
+	info, sym, ok := r.lookup(symbol)
+	if !ok {
+		return sym
+	}
+	return info.Underlying
+}
+
+// IsPerp reports whether the symbol is a perpetual future
+func (r *SymbolRegistry) IsPerp(symbol string) bool {
+	info, _, ok := r.lookup(symbol)
+	return ok && info.Perp
+}
+
+// ToBinance returns the Binance USDT-M perpetual symbol, or the normalized symbol if unmapped
+func (r *SymbolRegistry) ToBinance(symbol string) string {
+	info, sym, ok := r.lookup(symbol)
+	switch {
+	case !ok:
+		return sym
+	case info.Binance != "":
+		return info.Binance
+	}
+	return info.Underlying + "USDT"
+}
+
+// ToOKX returns the OKX USDT swap instrument ID, or the normalized symbol if unmapped
+func (r *SymbolRegistry) ToOKX(symbol string) string {
+	info, sym, ok := r.lookup(symbol)
+	switch {
+	case !ok:
+		return sym
+	case info.OKX != "":
+		return info.OKX
+	}
+	return info.Underlying + "-USDT-SWAP"
+}
+
+func normalizeSymbol(symbol string) string {
+	return strings.ToUpper(strings.TrimSpace(symbol))
+}
@@ -0,0 +1,66 @@
+package delta
+
+import "testing"
+
+func TestSymbolRegistry_KnownMappings(t *testing.T) {
+	r := NewSymbolRegistry()
+
+	tests := []struct {
+		symbol     string
+		binance    string
+		okx        string
+		underlying string
+		perp       bool
+	}{
+		{"BTCUSD", "BTCUSDT", "BTC-USDT-SWAP", "BTC", true},
+		{"ETHUSD", "ETHUSDT", "ETH-USDT-SWAP", "ETH", true},
+		{"SOLUSD", "SOLUSDT", "SOL-USDT-SWAP", "SOL", true},
+		{"btcinr", "BTCUSDT", "BTC-USDT-SWAP", "BTC", true},
+		{"PEPEUSD", "1000PEPEUSDT", "PEPE-USDT-SWAP", "PEPE", true},
+		// Unlisted perps derive their mapping from the quote suffix instead of passing through
+		{"AVAXUSD", "AVAXUSDT", "AVAX-USDT-SWAP", "AVAX", true},
+		// Unrecognised symbols pass through unchanged
+		{"UNKNOWN", "UNKNOWN", "UNKNOWN", "UNKNOWN", false},
+		{"C-BTC-90000-310124", "C-BTC-90000-310124", "C-BTC-90000-310124", "C-BTC-90000-310124", false},
+	}
+
+	for _, tt := range tests {
+		if got := r.ToBinance(tt.symbol); got != tt.binance {
+			t.Errorf("ToBinance(%s) = %s, want %s", tt.symbol, got, tt.binance)
+		}
+		if got := r.ToOKX(tt.symbol); got != tt.okx {
+			t.Errorf("ToOKX(%s) = %s, want %s", tt.symbol, got, tt.okx)
+		}
+		if got := r.Underlying(tt.symbol); got != tt.underlying {
+			t.Errorf("Underlying(%s) = %s, want %s", tt.symbol, got, tt.underlying)
+		}
+		if got := r.IsPerp(tt.symbol); got != tt.perp {
+			t.Errorf("IsPerp(%s) = %v, want %v", tt.symbol, got, tt.perp)
+		}
+	}
+}
+
+func TestSymbolRegistry_CustomAlias(t *testing.T) {
+	r := NewSymbolRegistry()
+	r.RegisterAlias("XBTUSD", "BTCUSD")
+	r.Register("MYTOKEN", SymbolInfo{Underlying: "MTK", Perp: true, Binance: "MTKUSDT"})
+	r.RegisterAlias("mtk", "MYTOKEN")
+
+	if got := r.Normalize(" xbtusd "); got != "BTCUSD" {
+		t.Errorf("Normalize(xbtusd) = %s, want BTCUSD", got)
+	}
+	if got := r.ToBinance("XBTUSD"); got != "BTCUSDT" {
+		t.Errorf("ToBinance(XBTUSD) = %s, want BTCUSDT", got)
+	}
+	if got := r.ToBinance("MTK"); got != "MTKUSDT" {
+		t.Errorf("ToBinance(MTK) = %s, want MTKUSDT", got)
+	}
+	if got := r.ToOKX("MTK"); got != "MTK-USDT-SWAP" {
+		t.Errorf("ToOKX(MTK) = %s, want MTK-USDT-SWAP", got)
+	}
+
+	// Aliases are per registry
+	if got := NewSymbolRegistry().Normalize("XBTUSD"); got != "XBTUSD" {
+		t.Errorf("fresh registry resolved alias: %s", got)
+	}
+}
@@ -0,0 +1,86 @@
+package delta
+
+import (
+	"fmt"
+	"time"
+)
+
+// twapOffsetPct is the aggressive-limit offset used for each TWAP child order
+const twapOffsetPct = 0.01
+
+// ExecuteTWAP splits req.Size into slices child orders placed interval apart, each as an
+// aggressive limit order. The remainder of an uneven split goes on the last slice. If a
+// child is rejected the remaining slices are abandoned and the orders placed so far are
+// returned together with the error. Requests go through the client's rate limiter.
+func (c *Client) ExecuteTWAP(req *OrderRequest, symbol string, slices int, interval time.Duration) ([]*Order, error) {
+	sizes, err := twapSliceSizes(req.Size, slices)
+	if err != nil {
+		return nil, err
+	}
+
+	orders := make([]*Order, 0, len(sizes))
+	for i, size := range sizes {
+		if i > 0 && interval > 0 {
+			time.Sleep(interval)
+		}
+
+		child := *req
+		child.Size = size
+		if req.ClientOrderID != "" {
+			child.ClientOrderID = twapChildOrderID(req.ClientOrderID, i)
+		}
+
+		order, err := c.PlaceAggressiveLimitOrder(&child, symbol, twapOffsetPct)
+		if err != nil {
+			return orders, fmt.Errorf("twap slice %d/%d failed: %w", i+1, len(sizes), err)
+		}
+		orders = append(orders, order)
+
+		if ParseOrderState(order.State) == OrderStateRejected {
+			return orders, fmt.Errorf("twap slice %d/%d: %w", i+1, len(sizes),
+				&OrderRejectedError{OrderID: order.ID, Reason: "order rejected by exchange", Kind: RejectUnknown})
+		}
+	}
+
+	return orders, nil
+}
+
+// TotalFilledSize returns the number of contracts filled across orders
+func TotalFilledSize(orders []*Order) int {
+	filled := 0
+	for _, o := range orders {
+		if o != nil {
+			filled += o.Size - o.UnfilledSize
+		}
+	}
+	return filled
+}
+
+// twapSliceSizes splits total into n equal slices with the remainder added to the last one
+func twapSliceSizes(total, n int) ([]int, error) {
+	if total <= 0 {
+		return nil, fmt.Errorf("twap size must be positive, got %d", total)
+	}
+	if n <= 0 {
+		return nil, fmt.Errorf("twap slices must be positive, got %d", n)
+	}
+	if n > total {
+		n = total // Never place zero-size children
+	}
+
+	sizes := make([]int, n)
+	for i := range sizes {
+		sizes[i] = total / n
+	}
+	sizes[n-1] += total % n
+	return sizes, nil
+}
+
+// twapChildOrderID derives a per-slice client_order_id within Delta's 32 character limit
+func twapChildOrderID(parent string, index int) string {
+	suffix := fmt.Sprintf("-%d", index)
+	if len(parent)+len(suffix) > 32 {
+		parent = parent[:32-len(suffix)]
+	}
+	return parent + suffix
+}
@@ -0,0 +1,111 @@
+package delta
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestTwapSliceSizes(t *testing.T) {
+	tests := []struct {
+		total, n int
+		want     []int
+	}{
+		{10, 3, []int{3, 3, 4}},
+		{9, 3, []int{3, 3, 3}},
+		{2, 5, []int{1, 1}},
+		{7, 1, []int{7}},
+	}
+	for _, tt := range tests {
+		got, err := twapSliceSizes(tt.total, tt.n)
+		if err != nil {
+			t.Fatalf("twapSliceSizes(%d, %d) error = %v", tt.total, tt.n, err)
+		}
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("twapSliceSizes(%d, %d) = %v, want %v", tt.total, tt.n, got, tt.want)
+		}
+	}
+
+	if _, err := twapSliceSizes(10, 0); err == nil {
+		t.Error("expected error for zero slices")
+	}
+}
+
+// twapHandler serves a BTCUSD book and records child order sizes; rejectAt (1-based)
+// makes that child fail with an API error
+func twapHandler(sizes *[]int, rejectAt int) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.URL.Path == "/v2/l2orderbook/BTCUSD":
+			w.Write([]byte(`{"success":true,"result":{"buy":[{"price":"50000","size":10}],"sell":[{"price":"50001","size":10}]}}`))
+		case r.URL.Path == "/v2/products/BTCUSD":
+			w.Write([]byte(`{"success":true,"result":{"id":27,"symbol":"BTCUSD","tick_size":"0.5"}}`))
+		case r.Method == http.MethodPost && r.URL.Path == "/v2/orders":
+			var body OrderRequest
+			_ = json.NewDecoder(r.Body).Decode(&body)
+			*sizes = append(*sizes, body.Size)
+			if len(*sizes) == rejectAt {
+				w.WriteHeader(http.StatusBadRequest)
+				w.Write([]byte(`{"success":false,"error":{"code":"insufficient_margin","message":"Insufficient margin"}}`))
+				return
+			}
+			resp, _ := json.Marshal(map[string]interface{}{
+				"success": true,
+				"result":  Order{ID: int64(len(*sizes)), Size: body.Size, State: "closed"},
+			})
+			w.Write(resp)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}
+}
+
+func TestExecuteTWAP_SplitsSize(t *testing.T) {
+	var sizes []int
+	c := newTestClient(t, twapHandler(&sizes, 0))
+
+	orders, err := c.ExecuteTWAP(&OrderRequest{ProductID: 27, Size: 10, Side: "buy"}, "BTCUSD", 3, 0)
+	if err != nil {
+		t.Fatalf("ExecuteTWAP() error = %v", err)
+	}
+	if !reflect.DeepEqual(sizes, []int{3, 3, 4}) {
+		t.Errorf("child sizes = %v, want [3 3 4]", sizes)
+	}
+	if len(orders) != 3 {
+		t.Fatalf("got %d orders, want 3", len(orders))
+	}
+	if filled := TotalFilledSize(orders); filled != 10 {
+		t.Errorf("TotalFilledSize = %d, want 10", filled)
+	}
+}
+
+func TestExecuteTWAP_RejectionStopsRemainingSlices(t *testing.T) {
+	var sizes []int
+	c := newTestClient(t, twapHandler(&sizes, 2))
+
+	orders, err := c.ExecuteTWAP(&OrderRequest{ProductID: 27, Size: 12, Side: "sell"}, "BTCUSD", 4, 0)
+	var rejectedErr *OrderRejectedError
+	if !errors.As(err, &rejectedErr) {
+		t.Fatalf("expected OrderRejectedError, got %v", err)
+	}
+	if len(sizes) != 2 {
+		t.Errorf("placed %d children, want 2 (stop after rejection)", len(sizes))
+	}
+	if len(orders) != 1 || TotalFilledSize(orders) != 3 {
+		t.Errorf("got %d orders filling %d, want 1 order filling 3", len(orders), TotalFilledSize(orders))
+	}
+}
+
+func TestTwapChildOrderID(t *testing.T) {
+	parent := GenerateClientOrderID("BTCUSD", "buy", 10, time.Time{}, 0, "twap")
+	child := twapChildOrderID(parent, 11)
+	if len(child) > 32 {
+		t.Errorf("child id %q exceeds 32 characters", child)
+	}
+	if child == twapChildOrderID(parent, 1) {
+		t.Error("children must have distinct ids")
+	}
+}
@@ -17,7 +17,12 @@ type Product struct {
 	ImpactSize        int    `json:"impact_size"`
 	MakerCommission   string `json:"maker_commission_rate"`
 	TakerCommission   string `json:"taker_commission_rate"`
-	IsActive          bool   `json:"is_active"`
+	IsActive          *bool  `json:"is_active,omitempty"` // nil when the response leaves it out
+
+	// Trading status, see ProductTradeable
+	State          string `json:"state"`           // "live", "expired", "upcoming"
+	TradingStatus  string `json:"trading_status"`  // "operational", "disrupted_cancel_only", "disrupted_post_only"
+	SettlementTime string `json:"settlement_time"` // RFC 3339; empty for perpetuals
 }
 
 // Asset represents an asset on Delta Exchange
@@ -45,6 +50,30 @@ type Ticker struct {
 	FundingRate float64 `json:"funding_rate,string"` // 8-hourly funding rate for perpetuals
 }
 
+// Price sources for exits and PnL: Delta liquidates and triggers stops off the mark price,
+// which can sit away from the last traded price in thin or fast markets
+const (
+	PriceSourceMark = "mark"
+	PriceSourceLast = "last"
+)
+
+// ReferencePrice returns the ticker's mark or last price per source, falling back to the
+// other when the chosen one is missing. Unknown sources use mark.
+func (t *Ticker) ReferencePrice(source string) float64 {
+	return SelectPrice(source, t.MarkPrice, t.Close)
+}
+
+// SelectPrice picks mark or last per source, falling back to the other when the chosen one is 0
+func SelectPrice(source string, mark, last float64) float64 {
+	if source == PriceSourceLast {
+		mark, last = last, mark
+	}
+	if mark > 0 {
+		return mark
+	}
+	return last
+}
+
 // Candle represents OHLCV data
 type Candle struct {
 	Time   int64   `json:"time"`
@@ -57,22 +86,23 @@ type Candle struct {
 
 // Order represents an order on Delta Exchange
 type Order struct {
-	ID             int64  `json:"id"`
-	UserID         int64  `json:"user_id"`
-	Size           int    `json:"size"`
-	UnfilledSize   int    `json:"unfilled_size"`
-	Side           string `json:"side"` // "buy" or "sell"
-	OrderType      string `json:"order_type"`
-	LimitPrice     string `json:"limit_price"`
-	StopOrderType  string `json:"stop_order_type,omitempty"`
-	StopPrice      string `json:"stop_price,omitempty"`
-	PaidCommission string `json:"paid_commission"`
-	ReduceOnly     bool   `json:"reduce_only"`
-	ClientOrderID  string `json:"client_order_id,omitempty"`
-	State          string `json:"state"`
-	CreatedAt      string `json:"created_at"`
-	ProductID      int    `json:"product_id"`
-	ProductSymbol  string `json:"product_symbol"`
+	ID               int64  `json:"id"`
+	UserID           int64  `json:"user_id"`
+	Size             int    `json:"size"`
+	UnfilledSize     int    `json:"unfilled_size"`
+	Side             string `json:"side"` // "buy" or "sell"
+	OrderType        string `json:"order_type"`
+	LimitPrice       string `json:"limit_price"`
+	StopOrderType    string `json:"stop_order_type,omitempty"`
+	StopPrice        string `json:"stop_price,omitempty"`
+	AverageFillPrice string `json:"average_fill_price,omitempty"`
+	PaidCommission   string `json:"paid_commission"`
+	ReduceOnly       bool   `json:"reduce_only"`
+	ClientOrderID    string `json:"client_order_id,omitempty"`
+	State            string `json:"state"`
+	CreatedAt        string `json:"created_at"`
+	ProductID        int    `json:"product_id"`
+	ProductSymbol    string `json:"product_symbol"`
 }
 
 // Position represents a position on Delta Exchange
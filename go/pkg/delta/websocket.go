@@ -3,10 +3,14 @@ package delta
 import (
 	"crypto/tls"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"log"
+	"math/rand"
+	"net"
 	"net/http"
 	"net/url"
+	"strings"
 	"sync"
 	"time"
 
@@ -14,6 +18,14 @@ import (
 	"github.com/kasyap/delta-go/go/config"
 )
 
+const (
+	defaultPingInterval = 30 * time.Second
+	defaultPongTimeout  = 30 * time.Second
+
+	defaultReconnectBackoff    = time.Second
+	defaultReconnectMaxBackoff = 30 * time.Second
+)
+
 type subscription struct {
 	name    string
 	symbols []string
@@ -32,9 +44,13 @@ type WebSocketClient struct {
 	onTicker           func(Ticker)
 	onCandle           func(Candle)
 	onCandleWithSymbol func(symbol string, candle Candle) // Enhanced callback with symbol
+	onCandleWithRes    func(symbol, resolution string, candle Candle)
 	onOrderbook        func(json.RawMessage)
 	onFundingRate      func(FundingRateUpdate)
 	onError            func(error)
+	onReconnect        func()
+	onReconnectFailed  func(attempt int, err error)
+	onReconnectGiveUp  func(attempts int, err error)
 
 	// State
 	mu           sync.RWMutex
@@ -44,6 +60,17 @@ type WebSocketClient struct {
 	closeOnce    sync.Once
 	writeMu      sync.Mutex
 	started      bool
+
+	// Liveness: a missing pong within pongTimeout after a ping trips the read deadline
+	pingInterval time.Duration
+	pongTimeout  time.Duration
+	lastPong     time.Time
+
+	// Reconnection: jittered exponential backoff, giving up after maxReconnectAttempts
+	// consecutive failures (0 = never)
+	reconnectBackoff     time.Duration
+	reconnectMaxBackoff  time.Duration
+	maxReconnectAttempts int
 }
 
 // FundingRateUpdate represents a funding rate update message
@@ -63,12 +90,58 @@ type WebSocketMessage struct {
 
 // NewWebSocketClient creates a new WebSocket client
 func NewWebSocketClient(cfg *config.Config) *WebSocketClient {
-	return &WebSocketClient{
-		cfg:           cfg,
-		url:           cfg.WebSocketURL,
-		subscriptions: []subscription{},
-		stopChan:      make(chan struct{}),
+	ws := &WebSocketClient{
+		cfg:                  cfg,
+		url:                  cfg.WebSocketURL,
+		subscriptions:        []subscription{},
+		stopChan:             make(chan struct{}),
+		pingInterval:         defaultPingInterval,
+		pongTimeout:          defaultPongTimeout,
+		reconnectBackoff:     defaultReconnectBackoff,
+		reconnectMaxBackoff:  defaultReconnectMaxBackoff,
+		maxReconnectAttempts: cfg.MaxReconnectAttempts,
+	}
+	if cfg.WSReconnectBackoff > 0 {
+		ws.reconnectBackoff = cfg.WSReconnectBackoff
+	}
+	if cfg.WSReconnectMaxBackoff > 0 {
+		ws.reconnectMaxBackoff = cfg.WSReconnectMaxBackoff
 	}
+	return ws
+}
+
+// SetReconnectPolicy overrides the reconnect backoff and attempt limit (call before Connect).
+// Delays double from backoff up to maxBackoff, jittered to between half and all of each step;
+// maxAttempts consecutive failures close the client (0 = retry forever).
+func (ws *WebSocketClient) SetReconnectPolicy(backoff, maxBackoff time.Duration, maxAttempts int) {
+	ws.mu.Lock()
+	defer ws.mu.Unlock()
+	ws.reconnectBackoff = backoff
+	ws.reconnectMaxBackoff = maxBackoff
+	ws.maxReconnectAttempts = maxAttempts
+}
+
+// SetPingTimeouts overrides the ping interval and pong timeout (call before Connect)
+func (ws *WebSocketClient) SetPingTimeouts(pingInterval, pongTimeout time.Duration) {
+	ws.mu.Lock()
+	defer ws.mu.Unlock()
+	ws.pingInterval = pingInterval
+	ws.pongTimeout = pongTimeout
+}
+
+// LastPong returns when the last pong (or connect) was seen on the current connection
+func (ws *WebSocketClient) LastPong() time.Time {
+	ws.mu.RLock()
+	defer ws.mu.RUnlock()
+	return ws.lastPong
+}
+
+// readTimeout is how long a connection may be silent (no message or pong) before it is
+// considered dead: one ping interval plus the pong grace period
+func (ws *WebSocketClient) readTimeout() time.Duration {
+	ws.mu.RLock()
+	defer ws.mu.RUnlock()
+	return ws.pingInterval + ws.pongTimeout
 }
 
 // OnTicker sets the ticker callback
@@ -86,6 +159,12 @@ func (ws *WebSocketClient) OnCandleWithSymbol(callback func(symbol string, candl
 	ws.onCandleWithSymbol = callback
 }
 
+// OnCandleWithSymbolResolution sets the candle callback with symbol and resolution context,
+// so candles from several candlestick_<resolution> subscriptions can be kept apart
+func (ws *WebSocketClient) OnCandleWithSymbolResolution(callback func(symbol, resolution string, candle Candle)) {
+	ws.onCandleWithRes = callback
+}
+
 // OnOrderbook sets the orderbook callback
 func (ws *WebSocketClient) OnOrderbook(callback func(json.RawMessage)) {
 	ws.onOrderbook = callback
@@ -100,6 +179,22 @@ func (ws *WebSocketClient) OnError(callback func(error)) {
 	ws.onError = callback
 }
 
+// OnReconnectFailed sets the callback invoked after each failed reconnection attempt
+func (ws *WebSocketClient) OnReconnectFailed(callback func(attempt int, err error)) {
+	ws.onReconnectFailed = callback
+}
+
+// OnReconnectGiveUp sets the callback invoked once the reconnect attempt limit is exhausted;
+// the client is closed by then
+func (ws *WebSocketClient) OnReconnectGiveUp(callback func(attempts int, err error)) {
+	ws.onReconnectGiveUp = callback
+}
+
+// OnReconnect sets the callback invoked after a successful reconnection
+func (ws *WebSocketClient) OnReconnect(callback func()) {
+	ws.onReconnect = callback
+}
+
 // Connect establishes WebSocket connection
 func (ws *WebSocketClient) Connect() error {
 	// Create custom TLS config that forces HTTP/1.1 (disables ALPN for HTTP/2)
@@ -133,10 +228,19 @@ func (ws *WebSocketClient) Connect() error {
 		return fmt.Errorf("websocket dial failed: %v", err)
 	}
 
+	conn.SetPongHandler(func(string) error {
+		ws.mu.Lock()
+		ws.lastPong = time.Now()
+		ws.mu.Unlock()
+		return conn.SetReadDeadline(time.Now().Add(ws.readTimeout()))
+	})
+	_ = conn.SetReadDeadline(time.Now().Add(ws.readTimeout()))
+
 	ws.mu.Lock()
 	oldConn := ws.conn
 	ws.conn = conn
 	ws.isConnected = true
+	ws.lastPong = time.Now()
 	startLoops := !ws.started
 	ws.started = true
 	subs := make([]subscription, len(ws.subscriptions))
@@ -250,7 +354,12 @@ func (ws *WebSocketClient) readMessages() {
 
 			_, message, err := conn.ReadMessage()
 			if err != nil {
-				log.Printf("WebSocket read error: %v", err)
+				var netErr net.Error
+				if errors.As(err, &netErr) && netErr.Timeout() {
+					log.Printf("WebSocket silent for %v (no data or pong) - reconnecting", ws.readTimeout())
+				} else {
+					log.Printf("WebSocket read error: %v", err)
+				}
 				if ws.onError != nil {
 					ws.onError(err)
 				}
@@ -258,6 +367,7 @@ func (ws *WebSocketClient) readMessages() {
 				continue
 			}
 
+			_ = conn.SetReadDeadline(time.Now().Add(ws.readTimeout()))
 			ws.handleMessage(message)
 		}
 	}
@@ -281,7 +391,7 @@ func (ws *WebSocketClient) handleMessage(data []byte) {
 		}
 
 	case containsSubstr(msg.Type, "candlestick") || containsSubstr(msg.Channel, "candlestick"):
-		if ws.onCandle != nil || ws.onCandleWithSymbol != nil {
+		if ws.onCandle != nil || ws.onCandleWithSymbol != nil || ws.onCandleWithRes != nil {
 			var candle Candle
 			if err := json.Unmarshal(msg.Data, &candle); err == nil {
 				if ws.onCandle != nil {
@@ -290,6 +400,9 @@ func (ws *WebSocketClient) handleMessage(data []byte) {
 				if ws.onCandleWithSymbol != nil {
 					ws.onCandleWithSymbol(msg.Symbol, candle)
 				}
+				if ws.onCandleWithRes != nil {
+					ws.onCandleWithRes(msg.Symbol, candleResolution(msg), candle)
+				}
 			}
 		}
 
@@ -319,7 +432,11 @@ func (ws *WebSocketClient) handleMessage(data []byte) {
 
 // heartbeat sends periodic pings to keep connection alive
 func (ws *WebSocketClient) heartbeat() {
-	ticker := time.NewTicker(30 * time.Second)
+	ws.mu.RLock()
+	interval := ws.pingInterval
+	ws.mu.RUnlock()
+
+	ticker := time.NewTicker(interval)
 	defer ticker.Stop()
 
 	for {
@@ -344,7 +461,8 @@ func (ws *WebSocketClient) heartbeat() {
 	}
 }
 
-// reconnect attempts to reconnect with exponential backoff
+// reconnect attempts to reconnect with jittered exponential backoff. Once maxReconnectAttempts
+// attempts in a row have failed it closes the client and calls the give-up callback.
 func (ws *WebSocketClient) reconnect() {
 	ws.mu.Lock()
 	if ws.reconnecting {
@@ -353,21 +471,36 @@ func (ws *WebSocketClient) reconnect() {
 	}
 	ws.reconnecting = true
 	ws.isConnected = false
+	backoff := ws.reconnectBackoff
+	maxBackoff := ws.reconnectMaxBackoff
+	maxAttempts := ws.maxReconnectAttempts
 	ws.mu.Unlock()
 
-	backoff := time.Second
-	maxBackoff := 30 * time.Second
+	attempt := 0
 
 	for {
 		select {
 		case <-ws.stopChan:
 			return
 		default:
-			log.Printf("Attempting to reconnect in %v...", backoff)
-			time.Sleep(backoff)
+			delay := jitterBackoff(backoff)
+			log.Printf("Attempting to reconnect in %v...", delay)
+			time.Sleep(delay)
 
 			if err := ws.Connect(); err != nil {
 				log.Printf("Reconnection failed: %v", err)
+				attempt++
+				if ws.onReconnectFailed != nil {
+					ws.onReconnectFailed(attempt, err)
+				}
+				if maxAttempts > 0 && attempt >= maxAttempts {
+					log.Printf("Giving up on WebSocket after %d failed reconnects", attempt)
+					ws.Close()
+					if ws.onReconnectGiveUp != nil {
+						ws.onReconnectGiveUp(attempt, err)
+					}
+					return
+				}
 				backoff *= 2
 				if backoff > maxBackoff {
 					backoff = maxBackoff
@@ -380,11 +513,24 @@ func (ws *WebSocketClient) reconnect() {
 			ws.mu.Unlock()
 
 			log.Println("Successfully reconnected")
+			if ws.onReconnect != nil {
+				ws.onReconnect()
+			}
 			return
 		}
 	}
 }
 
+// jitterBackoff spreads a backoff step over [d/2, d] so clients dropped together don't
+// reconnect in lockstep
+func jitterBackoff(d time.Duration) time.Duration {
+	if d <= 1 {
+		return d
+	}
+	half := d / 2
+	return half + time.Duration(rand.Int63n(int64(d-half)+1))
+}
+
 // Close closes the WebSocket connection (idempotent - safe to call multiple times)
 func (ws *WebSocketClient) Close() {
 	ws.closeOnce.Do(func() {
@@ -409,6 +555,17 @@ func (ws *WebSocketClient) IsConnected() bool {
 }
 
 // helper function
+// candleResolution extracts the resolution from a candlestick_<resolution> channel name,
+// checking the channel before the message type. Returns "" if neither carries one.
+func candleResolution(msg WebSocketMessage) string {
+	for _, name := range []string{msg.Channel, msg.Type} {
+		if res, ok := strings.CutPrefix(name, "candlestick_"); ok && res != "" {
+			return res
+		}
+	}
+	return ""
+}
+
 func containsSubstr(s, substr string) bool {
 	for i := 0; i <= len(s)-len(substr); i++ {
 		if s[i:i+len(substr)] == substr {
@@ -1,8 +1,14 @@
 package delta
 
 import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
 	"testing"
+	"time"
 
+	"github.com/gorilla/websocket"
 	"github.com/kasyap/delta-go/go/config"
 )
 
@@ -26,3 +32,138 @@ func TestWebSocketSubscribe_Deduplicates(t *testing.T) {
 		t.Fatalf("unexpected symbols: %#v", ws.subscriptions[0].symbols)
 	}
 }
+
+func TestWebSocket_ReconnectsWhenPongsStop(t *testing.T) {
+	var connections int32
+	release := make(chan struct{})
+	defer close(release)
+
+	upgrader := websocket.Upgrader{}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		conn, err := upgrader.Upgrade(w, r, nil)
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+
+		if atomic.AddInt32(&connections, 1) == 1 {
+			// Half-open: never read, so pings are never answered
+			<-release
+			return
+		}
+		// Healthy: reading lets the default ping handler answer with pongs
+		for {
+			if _, _, err := conn.ReadMessage(); err != nil {
+				return
+			}
+		}
+	}))
+	defer srv.Close()
+
+	ws := NewWebSocketClient(&config.Config{WebSocketURL: "ws" + strings.TrimPrefix(srv.URL, "http")})
+	ws.SetPingTimeouts(50*time.Millisecond, 100*time.Millisecond)
+	reconnected := make(chan struct{}, 1)
+	ws.OnReconnect(func() { reconnected <- struct{}{} })
+
+	if err := ws.Connect(); err != nil {
+		t.Fatalf("connect: %v", err)
+	}
+	defer ws.Close()
+	connectedAt := ws.LastPong()
+
+	select {
+	case <-reconnected:
+	case <-time.After(5 * time.Second):
+		t.Fatal("expected client to reconnect after missing pongs")
+	}
+	if got := atomic.LoadInt32(&connections); got < 2 {
+		t.Fatalf("expected a second connection, got %d", got)
+	}
+
+	// The healthy server answers pings, so lastPong keeps advancing
+	deadline := time.Now().Add(2 * time.Second)
+	for !ws.LastPong().After(connectedAt.Add(200 * time.Millisecond)) {
+		if time.Now().After(deadline) {
+			t.Fatal("expected pongs on the new connection")
+		}
+		time.Sleep(20 * time.Millisecond)
+	}
+}
+
+func TestWebSocket_GivesUpAfterMaxReconnectAttempts(t *testing.T) {
+	var requests int32
+	upgrader := websocket.Upgrader{}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(&requests, 1) > 1 {
+			// Every reconnect is refused
+			http.Error(w, "unavailable", http.StatusServiceUnavailable)
+			return
+		}
+		conn, err := upgrader.Upgrade(w, r, nil)
+		if err != nil {
+			return
+		}
+		conn.Close() // Drop the first connection to start reconnecting
+	}))
+	defer srv.Close()
+
+	ws := NewWebSocketClient(&config.Config{WebSocketURL: "ws" + strings.TrimPrefix(srv.URL, "http")})
+	ws.SetReconnectPolicy(time.Millisecond, 4*time.Millisecond, 3)
+	var failures int32
+	ws.OnReconnectFailed(func(int, error) { atomic.AddInt32(&failures, 1) })
+	gaveUp := make(chan int, 1)
+	ws.OnReconnectGiveUp(func(attempts int, err error) { gaveUp <- attempts })
+
+	if err := ws.Connect(); err != nil {
+		t.Fatalf("connect: %v", err)
+	}
+	defer ws.Close()
+
+	select {
+	case attempts := <-gaveUp:
+		if attempts != 3 {
+			t.Errorf("gave up after %d attempts, want 3", attempts)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("expected the client to give up reconnecting")
+	}
+	if got := atomic.LoadInt32(&failures); got != 3 {
+		t.Errorf("failed-attempt callbacks = %d, want 3", got)
+	}
+
+	// Closed on giving up: no further dials
+	time.Sleep(50 * time.Millisecond)
+	if got := atomic.LoadInt32(&requests); got != 4 {
+		t.Errorf("server requests = %d, want 1 connection + 3 refused reconnects", got)
+	}
+	if ws.IsConnected() {
+		t.Error("client still reports connected after giving up")
+	}
+}
+
+func TestJitterBackoff(t *testing.T) {
+	for i := 0; i < 100; i++ {
+		if d := jitterBackoff(time.Second); d < 500*time.Millisecond || d > time.Second {
+			t.Fatalf("jitterBackoff(1s) = %v, want within [500ms, 1s]", d)
+		}
+	}
+}
+
+func TestWebSocket_CandleResolutionRouting(t *testing.T) {
+	ws := NewWebSocketClient(&config.Config{WebSocketURL: "wss://example"})
+	got := make(map[string][]Candle)
+	ws.OnCandleWithSymbolResolution(func(symbol, resolution string, candle Candle) {
+		got[symbol+"/"+resolution] = append(got[symbol+"/"+resolution], candle)
+	})
+
+	ws.handleMessage([]byte(`{"type":"candlestick_5m","symbol":"BTCUSD","data":{"time":300,"close":100}}`))
+	ws.handleMessage([]byte(`{"type":"candlestick_1h","symbol":"BTCUSD","data":{"time":3600,"close":200}}`))
+	ws.handleMessage([]byte(`{"type":"update","channel":"candlestick_1h","symbol":"BTCUSD","data":{"time":7200,"close":300}}`))
+
+	if c := got["BTCUSD/5m"]; len(c) != 1 || c[0].Close != 100 {
+		t.Errorf("5m candles = %+v, want one with close 100", c)
+	}
+	if c := got["BTCUSD/1h"]; len(c) != 2 || c[0].Close != 200 || c[1].Close != 300 {
+		t.Errorf("1h candles = %+v, want closes 200, 300", c)
+	}
+}
@@ -34,6 +34,9 @@ type MarketFeatures struct {
 	Imbalance   float64
 	ImbalanceMA float64
 
+	// Rank of SpreadBps among the symbol's recent spreads, 0-100 (0 until enough history)
+	SpreadPercentile float64
+
 	HistoricalVol float64
 	ImpliedVol    float64
 	IVPremium     float64
@@ -51,6 +54,16 @@ type MarketFeatures struct {
 	HMMConfidence float64
 }
 
+// ReferencePrice returns MarkPrice or SpotPrice (last traded) per delta.PriceSourceMark/Last
+func (f MarketFeatures) ReferencePrice(source string) float64 {
+	return delta.SelectPrice(source, f.MarkPrice, f.SpotPrice)
+}
+
+// RegimeDetector classifies the market regime from a candle series (e.g. an HMM model)
+type RegimeDetector interface {
+	DetectRegime(symbol string, candles []delta.Candle) (delta.MarketRegime, float64, error)
+}
+
 type OBISnapshot struct {
 	Timestamp time.Time
 	Imbalance float64
@@ -63,15 +76,90 @@ type Engine struct {
 	maxOBISnapshots  int
 	imbalancePeriod  int
 	imbalanceHistory []float64
+
+	// Imbalance classification thresholds
+	directionThreshold   float64 // |ImbalanceMA| above this is bullish/bearish
+	persistenceThreshold float64 // |Imbalance| a snapshot must exceed to count as persistent
+	persistenceRequired  int     // consecutive snapshots needed for a persistent imbalance
+
+	// Rolling per-symbol spread history (bps) behind SpreadPercentile
+	spreads      map[string][]float64
+	spreadWindow int
+
+	// Order book depth behind Imbalance: levels summed per side, and the distance from mid
+	// (bps) over which a level's weight decays by 1/e (0 = every level counts fully)
+	depthLevels   int
+	depthDecayBps float64
+}
+
+// minSpreadSamples is the spread history needed before a percentile is reported
+const minSpreadSamples = 20
+
+// EngineOption configures an Engine at construction
+type EngineOption func(*Engine)
+
+// WithDirectionThreshold sets the ImbalanceMA level for bullish/bearish classification (default 0.3)
+func WithDirectionThreshold(threshold float64) EngineOption {
+	return func(e *Engine) {
+		e.directionThreshold = threshold
+	}
+}
+
+// WithImbalancePersistence sets the per-snapshot imbalance threshold and the number of
+// consecutive snapshots required for a persistent imbalance (defaults 0.6 and 5)
+func WithImbalancePersistence(threshold float64, snapshots int) EngineOption {
+	return func(e *Engine) {
+		e.persistenceThreshold = threshold
+		e.persistenceRequired = snapshots
+	}
+}
+
+// WithSpreadWindow sets how many recent spread snapshots per symbol SpreadPercentile ranks
+// against (default 300)
+func WithSpreadWindow(snapshots int) EngineOption {
+	return func(e *Engine) {
+		e.spreadWindow = snapshots
+	}
 }
 
-func NewEngine() *Engine {
-	return &Engine{
-		maxOBISnapshots: 60,
-		imbalancePeriod: 10,
+// WithDepthLevels sets how many order book levels per side Imbalance sums (default 10)
+func WithDepthLevels(levels int) EngineOption {
+	return func(e *Engine) {
+		e.depthLevels = levels
 	}
 }
 
+// WithDepthWeighting weights each level's notional by exp(-distance/decayBps), distance
+// being the level's bps from mid, so near-touch liquidity dominates a sparse book (0 = off)
+func WithDepthWeighting(decayBps float64) EngineOption {
+	return func(e *Engine) {
+		e.depthDecayBps = decayBps
+	}
+}
+
+func NewEngine(opts ...EngineOption) *Engine {
+	e := &Engine{
+		maxOBISnapshots:      60,
+		imbalancePeriod:      10,
+		directionThreshold:   0.3,
+		persistenceThreshold: 0.6,
+		persistenceRequired:  5,
+		spreads:              make(map[string][]float64),
+		spreadWindow:         300,
+		depthLevels:          10,
+	}
+	for _, opt := range opts {
+		opt(e)
+	}
+	if e.persistenceRequired < 1 {
+		e.persistenceRequired = 1
+	}
+	if e.depthLevels < 1 {
+		e.depthLevels = 1
+	}
+	return e
+}
+
 func (e *Engine) ComputeFeaturesWithFunding(
 	orderbook *delta.Orderbook,
 	ticker *delta.Ticker,
@@ -140,7 +228,7 @@ func (e *Engine) ComputeFeatures(
 			f.SpreadBps = (f.Spread / mid) * 10000
 		}
 
-		bidDepth, askDepth := e.computeDepth(orderbook, 10)
+		bidDepth, askDepth := e.computeDepth(orderbook, mid)
 		f.BidDepth = bidDepth
 		f.AskDepth = askDepth
 		if bidDepth+askDepth > 0 {
@@ -157,6 +245,9 @@ func (e *Engine) ComputeFeatures(
 			e.obi = e.obi[len(e.obi)-e.maxOBISnapshots:]
 		}
 		f.ImbalanceMA = e.computeImbalanceMA()
+		if f.Symbol != "" {
+			f.SpreadPercentile = e.recordSpread(f.Symbol, f.SpreadBps)
+		}
 		e.mu.Unlock()
 	}
 
@@ -166,6 +257,54 @@ func (e *Engine) ComputeFeatures(
 	return f
 }
 
+// Reset clears the OBI history, e.g. before replaying the same data again
+func (e *Engine) Reset() {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.obi = nil
+	e.imbalanceHistory = nil
+	e.spreads = make(map[string][]float64)
+}
+
+// recordSpread adds a spread snapshot to the symbol's window and returns its percentile.
+// Caller holds e.mu.
+func (e *Engine) recordSpread(symbol string, spreadBps float64) float64 {
+	window := append(e.spreads[symbol], spreadBps)
+	if e.spreadWindow > 0 && len(window) > e.spreadWindow {
+		window = window[len(window)-e.spreadWindow:]
+	}
+	e.spreads[symbol] = window
+	return spreadPercentile(window)
+}
+
+// CurrentSpreadPercentile returns where the symbol's latest spread ranks among its recent
+// spreads, 0-100: near 100 means abnormally wide. Returns 0 until minSpreadSamples
+// snapshots have been seen.
+func (e *Engine) CurrentSpreadPercentile(symbol string) float64 {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+	return spreadPercentile(e.spreads[symbol])
+}
+
+// spreadPercentile ranks the last value of window against the earlier ones, counting ties
+// as half
+func spreadPercentile(window []float64) float64 {
+	if len(window) < minSpreadSamples {
+		return 0
+	}
+	latest := window[len(window)-1]
+	rank := 0.0
+	for _, v := range window[:len(window)-1] {
+		switch {
+		case v < latest:
+			rank++
+		case v == latest:
+			rank += 0.5
+		}
+	}
+	return rank / float64(len(window)-1) * 100
+}
+
 func (e *Engine) AddOBISnapshot(s OBISnapshot) {
 	e.mu.Lock()
 	defer e.mu.Unlock()
@@ -175,14 +314,22 @@ func (e *Engine) AddOBISnapshot(s OBISnapshot) {
 	}
 }
 
-func (e *Engine) computeDepth(ob *delta.Orderbook, levels int) (bidDepth, askDepth float64) {
-	for i := 0; i < levels && i < len(ob.Buy); i++ {
-		bidDepth += float64(ob.Buy[i].Size) * parseFloat(ob.Buy[i].Price)
-	}
-	for i := 0; i < levels && i < len(ob.Sell); i++ {
-		askDepth += float64(ob.Sell[i].Size) * parseFloat(ob.Sell[i].Price)
+// computeDepth sums the notional of the top depthLevels levels per side, weighted by
+// distance from mid when depth weighting is on
+func (e *Engine) computeDepth(ob *delta.Orderbook, mid float64) (bidDepth, askDepth float64) {
+	side := func(levels []delta.OrderbookEntry) float64 {
+		depth := 0.0
+		for i := 0; i < e.depthLevels && i < len(levels); i++ {
+			price := parseFloat(levels[i].Price)
+			notional := float64(levels[i].Size) * price
+			if e.depthDecayBps > 0 && mid > 0 {
+				notional *= math.Exp(-math.Abs(price-mid) / mid * 10000 / e.depthDecayBps)
+			}
+			depth += notional
+		}
+		return depth
 	}
-	return
+	return side(ob.Buy), side(ob.Sell)
 }
 
 func (e *Engine) computeImbalanceMA() float64 {
@@ -244,8 +391,6 @@ func (e *Engine) detectDominantDriver(f MarketFeatures) (DriverType, float64) {
 	const (
 		basisThreshold     = 0.15
 		ivPremiumThreshold = 0.10
-		imbalanceThreshold = 0.6
-		persistenceReq     = 5
 	)
 
 	if f.BasisAnnualized > basisThreshold {
@@ -259,11 +404,11 @@ func (e *Engine) detectDominantDriver(f MarketFeatures) (DriverType, float64) {
 	}
 
 	e.mu.RLock()
-	persistent := e.isImbalancePersistent(imbalanceThreshold, persistenceReq)
+	persistent := e.isImbalancePersistent(e.persistenceThreshold, e.persistenceRequired)
 	e.mu.RUnlock()
 
 	if persistent {
-		strength := math.Abs(f.ImbalanceMA) / imbalanceThreshold
+		strength := math.Abs(f.ImbalanceMA) / e.persistenceThreshold
 		return DriverOrderImbalance, math.Min(strength, 1.0)
 	}
 
@@ -293,15 +438,37 @@ func (e *Engine) GetImbalanceDirection() string {
 		return "neutral"
 	}
 	avg := e.computeImbalanceMA()
-	if avg > 0.3 {
+	if avg > e.directionThreshold {
 		return "bullish"
 	}
-	if avg < -0.3 {
+	if avg < -e.directionThreshold {
 		return "bearish"
 	}
 	return "neutral"
 }
 
+// GetImbalanceStrength returns a signed persistence score in [-1, 1] over the last
+// persistenceRequired snapshots. Each snapshot contributes its imbalance relative to the
+// persistence threshold (capped at ±1), so ±1 means a fully persistent imbalance and
+// missing history counts as neutral.
+func (e *Engine) GetImbalanceStrength() float64 {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+	if len(e.obi) == 0 || e.persistenceThreshold <= 0 {
+		return 0
+	}
+
+	start := len(e.obi) - e.persistenceRequired
+	if start < 0 {
+		start = 0
+	}
+	sum := 0.0
+	for i := start; i < len(e.obi); i++ {
+		sum += math.Max(-1, math.Min(1, e.obi[i].Imbalance/e.persistenceThreshold))
+	}
+	return sum / float64(e.persistenceRequired)
+}
+
 func (e *Engine) GetOBISnapshots() []OBISnapshot {
 	e.mu.RLock()
 	defer e.mu.RUnlock()
@@ -2,6 +2,7 @@ package features
 
 import (
 	"math"
+	"strconv"
 	"testing"
 	"time"
 
@@ -72,3 +73,146 @@ func TestEngine_ImbalanceHistory(t *testing.T) {
 		t.Errorf("Expected last imbalance 1.0, got %f", snapshots[4].Imbalance)
 	}
 }
+
+func TestEngine_DirectionThresholdOption(t *testing.T) {
+	def := NewEngine()
+	custom := NewEngine(WithDirectionThreshold(0.1))
+	for _, e := range []*Engine{def, custom} {
+		for i := 0; i < 10; i++ {
+			e.AddOBISnapshot(OBISnapshot{Imbalance: 0.2})
+		}
+	}
+
+	if got := def.GetImbalanceDirection(); got != "neutral" {
+		t.Errorf("default threshold: direction = %s, want neutral", got)
+	}
+	if got := custom.GetImbalanceDirection(); got != "bullish" {
+		t.Errorf("0.1 threshold: direction = %s, want bullish", got)
+	}
+}
+
+func TestEngine_ImbalancePersistenceOption(t *testing.T) {
+	def := NewEngine()
+	custom := NewEngine(WithImbalancePersistence(0.4, 3))
+	for _, e := range []*Engine{def, custom} {
+		for i := 0; i < 3; i++ {
+			e.AddOBISnapshot(OBISnapshot{Imbalance: -0.5})
+		}
+	}
+
+	f := MarketFeatures{ImbalanceMA: -0.5}
+	if driver, _ := def.detectDominantDriver(f); driver != DriverNone {
+		t.Errorf("default persistence: driver = %s, want none", driver)
+	}
+	if driver, _ := custom.detectDominantDriver(f); driver != DriverOrderImbalance {
+		t.Errorf("custom persistence: driver = %s, want order_imbalance", driver)
+	}
+}
+
+func TestEngine_GetImbalanceStrength(t *testing.T) {
+	e := NewEngine(WithImbalancePersistence(0.5, 4))
+	if got := e.GetImbalanceStrength(); got != 0 {
+		t.Errorf("empty history strength = %f, want 0", got)
+	}
+
+	// Two snapshots of full-strength selling: half the window is bearish
+	e.AddOBISnapshot(OBISnapshot{Imbalance: -0.8})
+	e.AddOBISnapshot(OBISnapshot{Imbalance: -0.6})
+	if got := e.GetImbalanceStrength(); math.Abs(got+0.5) > 1e-9 {
+		t.Errorf("partial history strength = %f, want -0.5", got)
+	}
+
+	for i := 0; i < 4; i++ {
+		e.AddOBISnapshot(OBISnapshot{Imbalance: 0.9})
+	}
+	if got := e.GetImbalanceStrength(); got != 1 {
+		t.Errorf("persistent bid imbalance strength = %f, want 1", got)
+	}
+
+	e.AddOBISnapshot(OBISnapshot{Imbalance: 0})
+	if got := e.GetImbalanceStrength(); math.Abs(got-0.75) > 1e-9 {
+		t.Errorf("fading imbalance strength = %f, want 0.75", got)
+	}
+}
+
+func TestEngine_SpreadPercentileFlagsWidening(t *testing.T) {
+	e := NewEngine(WithSpreadWindow(50))
+	book := func(spread float64) *delta.Orderbook {
+		return &delta.Orderbook{
+			Buy:  []delta.OrderbookEntry{{Price: "50000", Size: 10}},
+			Sell: []delta.OrderbookEntry{{Price: strconv.FormatFloat(50000+spread, 'f', 2, 64), Size: 10}},
+		}
+	}
+	tick := &delta.Ticker{Symbol: "BTCUSD", Close: 50000}
+
+	// Normal spreads alternate between 5 and 10 dollars
+	var f MarketFeatures
+	for i := 0; i < 40; i++ {
+		f = e.ComputeFeatures(book(5+float64(i%2)*5), tick, nil, time.Time{}, 0)
+	}
+	if f.SpreadPercentile <= 0 || f.SpreadPercentile >= 90 {
+		t.Fatalf("percentile of a normal spread = %.1f, want mid-range", f.SpreadPercentile)
+	}
+
+	f = e.ComputeFeatures(book(40), tick, nil, time.Time{}, 0)
+	if f.SpreadPercentile < 99 {
+		t.Errorf("percentile after a sudden widening = %.1f, want ~100", f.SpreadPercentile)
+	}
+	if got := e.CurrentSpreadPercentile("BTCUSD"); got != f.SpreadPercentile {
+		t.Errorf("CurrentSpreadPercentile = %.1f, want %.1f", got, f.SpreadPercentile)
+	}
+	if got := e.CurrentSpreadPercentile("ETHUSD"); got != 0 {
+		t.Errorf("CurrentSpreadPercentile with no history = %.1f, want 0", got)
+	}
+}
+
+func TestEngine_SpreadPercentileNeedsHistory(t *testing.T) {
+	e := NewEngine()
+	ob := &delta.Orderbook{
+		Buy:  []delta.OrderbookEntry{{Price: "50000", Size: 10}},
+		Sell: []delta.OrderbookEntry{{Price: "50100", Size: 10}},
+	}
+	f := e.ComputeFeatures(ob, &delta.Ticker{Symbol: "BTCUSD"}, nil, time.Time{}, 0)
+	if f.SpreadPercentile != 0 {
+		t.Errorf("percentile from one snapshot = %.1f, want 0", f.SpreadPercentile)
+	}
+}
+
+// layeredBook is bid-heavy at the top 5 levels and ask-heavy over the 15 below them
+func layeredBook() *delta.Orderbook {
+	ob := &delta.Orderbook{Symbol: "BTCUSD"}
+	for i := 0; i < 20; i++ {
+		bid, ask := 1, 10
+		if i < 5 {
+			bid, ask = 10, 2
+		}
+		ob.Buy = append(ob.Buy, delta.OrderbookEntry{Price: strconv.Itoa(50000 - i*5), Size: bid})
+		ob.Sell = append(ob.Sell, delta.OrderbookEntry{Price: strconv.Itoa(50001 + i*5), Size: ask})
+	}
+	return ob
+}
+
+func TestComputeFeatures_DepthLevels(t *testing.T) {
+	ticker := &delta.Ticker{Symbol: "BTCUSD"}
+
+	shallow := NewEngine(WithDepthLevels(5)).ComputeFeatures(layeredBook(), ticker, nil, time.Time{}, 0)
+	deep := NewEngine(WithDepthLevels(20)).ComputeFeatures(layeredBook(), ticker, nil, time.Time{}, 0)
+	if shallow.Imbalance <= 0.5 {
+		t.Errorf("5-level imbalance = %.3f, want strongly bid-heavy", shallow.Imbalance)
+	}
+	if deep.Imbalance >= 0 {
+		t.Errorf("20-level imbalance = %.3f, want ask-heavy", deep.Imbalance)
+	}
+
+	// Sizes are summed per level in contracts x price: top 5 bids 5x10, asks 5x2
+	if want := 10.0 * (50000 + 49995 + 49990 + 49985 + 49980); math.Abs(shallow.BidDepth-want) > 1e-6 {
+		t.Errorf("5-level bid depth = %.0f, want %.0f", shallow.BidDepth, want)
+	}
+
+	// Weighting by distance from mid lets the near-touch bids dominate all 20 levels again
+	weighted := NewEngine(WithDepthLevels(20), WithDepthWeighting(2)).ComputeFeatures(layeredBook(), ticker, nil, time.Time{}, 0)
+	if weighted.Imbalance <= 0 || weighted.Imbalance >= shallow.Imbalance {
+		t.Errorf("weighted 20-level imbalance = %.3f, want bid-heavy but below the 5-level %.3f",
+			weighted.Imbalance, shallow.Imbalance)
+	}
+}
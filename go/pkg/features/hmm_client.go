@@ -0,0 +1,148 @@
+package features
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/kasyap/delta-go/go/pkg/delta"
+)
+
+// HMM endpoint auth modes
+const (
+	HMMAuthGCE    = "gce"    // Identity token from the GCE metadata server (default)
+	HMMAuthBearer = "bearer" // Static bearer token
+	HMMAuthAPIKey = "apikey" // Static key in an API-key header
+	HMMAuthNone   = "none"   // No auth header
+)
+
+const (
+	defaultHMMAPIKeyHeader = "X-API-Key"
+	gceIdentityURL         = "http://metadata.google.internal/computeMetadata/v1/instance/service-accounts/default/identity"
+)
+
+// HMMClientConfig configures the remote HMM regime service
+type HMMClientConfig struct {
+	Endpoint     string
+	AuthMode     string // gce, bearer, apikey or none; empty means gce
+	Token        string // Bearer token or API key, depending on AuthMode
+	APIKeyHeader string // Header carrying the API key (default X-API-Key)
+	Timeout      time.Duration
+}
+
+// HMMClient is a RegimeDetector backed by a remote HMM service
+type HMMClient struct {
+	cfg         HMMClientConfig
+	httpClient  *http.Client
+	metadataURL string // GCE identity endpoint, overridable in tests
+}
+
+type hmmResponse struct {
+	Regime     delta.MarketRegime `json:"regime"`
+	Confidence float64            `json:"confidence"`
+}
+
+// NewHMMClient creates a client for the HMM service at cfg.Endpoint
+func NewHMMClient(cfg HMMClientConfig) (*HMMClient, error) {
+	switch cfg.AuthMode {
+	case "":
+		cfg.AuthMode = HMMAuthGCE
+	case HMMAuthGCE, HMMAuthNone:
+	case HMMAuthBearer, HMMAuthAPIKey:
+		if cfg.Token == "" {
+			return nil, fmt.Errorf("hmm auth mode %q requires a token", cfg.AuthMode)
+		}
+	default:
+		return nil, fmt.Errorf("unknown hmm auth mode %q", cfg.AuthMode)
+	}
+	if cfg.APIKeyHeader == "" {
+		cfg.APIKeyHeader = defaultHMMAPIKeyHeader
+	}
+	if cfg.Timeout <= 0 {
+		cfg.Timeout = 10 * time.Second
+	}
+	return &HMMClient{
+		cfg:         cfg,
+		httpClient:  &http.Client{Timeout: cfg.Timeout},
+		metadataURL: gceIdentityURL,
+	}, nil
+}
+
+// DetectRegime posts the candles to the HMM service and returns its regime and confidence
+func (c *HMMClient) DetectRegime(symbol string, candles []delta.Candle) (delta.MarketRegime, float64, error) {
+	body, err := json.Marshal(delta.CandlesToHMMInput(candles, symbol))
+	if err != nil {
+		return "", 0, fmt.Errorf("failed to encode hmm request: %w", err)
+	}
+
+	req, err := http.NewRequest(http.MethodPost, c.cfg.Endpoint, bytes.NewReader(body))
+	if err != nil {
+		return "", 0, fmt.Errorf("failed to create hmm request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if err := c.setAuthHeader(req); err != nil {
+		return "", 0, err
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return "", 0, fmt.Errorf("hmm request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		msg, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return "", 0, fmt.Errorf("hmm service returned %d: %s", resp.StatusCode, bytes.TrimSpace(msg))
+	}
+
+	var out hmmResponse
+	if err := json.NewDecoder(resp.Body).Decode(&out); err != nil {
+		return "", 0, fmt.Errorf("failed to decode hmm response: %w", err)
+	}
+	return out.Regime, out.Confidence, nil
+}
+
+// setAuthHeader adds the header for the configured auth mode
+func (c *HMMClient) setAuthHeader(req *http.Request) error {
+	switch c.cfg.AuthMode {
+	case HMMAuthGCE:
+		token, err := c.getIdentityToken()
+		if err != nil {
+			return err
+		}
+		req.Header.Set("Authorization", "Bearer "+token)
+	case HMMAuthBearer:
+		req.Header.Set("Authorization", "Bearer "+c.cfg.Token)
+	case HMMAuthAPIKey:
+		req.Header.Set(c.cfg.APIKeyHeader, c.cfg.Token)
+	}
+	return nil
+}
+
+// getIdentityToken fetches a GCE identity token with the HMM endpoint as audience
+func (c *HMMClient) getIdentityToken() (string, error) {
+	req, err := http.NewRequest(http.MethodGet, c.metadataURL+"?audience="+url.QueryEscape(c.cfg.Endpoint), nil)
+	if err != nil {
+		return "", fmt.Errorf("failed to create identity token request: %w", err)
+	}
+	req.Header.Set("Metadata-Flavor", "Google")
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("failed to fetch identity token: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("metadata server returned %d", resp.StatusCode)
+	}
+	token, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return "", fmt.Errorf("failed to read identity token: %w", err)
+	}
+	return string(bytes.TrimSpace(token)), nil
+}
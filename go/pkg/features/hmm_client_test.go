@@ -0,0 +1,74 @@
+package features
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/kasyap/delta-go/go/pkg/delta"
+)
+
+func TestHMMClient_AuthModes(t *testing.T) {
+	metadata := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Metadata-Flavor") != "Google" || r.URL.Query().Get("audience") == "" {
+			http.Error(w, "bad metadata request", http.StatusBadRequest)
+			return
+		}
+		w.Write([]byte("gce-token\n"))
+	}))
+	defer metadata.Close()
+
+	tests := []struct {
+		name   string
+		cfg    HMMClientConfig
+		header string
+		want   string
+	}{
+		{"gce default", HMMClientConfig{}, "Authorization", "Bearer gce-token"},
+		{"bearer", HMMClientConfig{AuthMode: HMMAuthBearer, Token: "static"}, "Authorization", "Bearer static"},
+		{"apikey", HMMClientConfig{AuthMode: HMMAuthAPIKey, Token: "k1"}, "X-API-Key", "k1"},
+		{"apikey custom header", HMMClientConfig{AuthMode: HMMAuthAPIKey, Token: "k2", APIKeyHeader: "X-Hmm-Key"}, "X-Hmm-Key", "k2"},
+		{"none", HMMClientConfig{AuthMode: HMMAuthNone}, "Authorization", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got http.Header
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				got = r.Header.Clone()
+				w.Write([]byte(`{"regime":"high_volatility","confidence":0.8}`))
+			}))
+			defer srv.Close()
+
+			cfg := tt.cfg
+			cfg.Endpoint = srv.URL
+			c, err := NewHMMClient(cfg)
+			if err != nil {
+				t.Fatalf("NewHMMClient: %v", err)
+			}
+			c.metadataURL = metadata.URL
+
+			regime, conf, err := c.DetectRegime("BTCUSD", []delta.Candle{{Time: 1, Close: 100}})
+			if err != nil {
+				t.Fatalf("DetectRegime: %v", err)
+			}
+			if regime != delta.RegimeHighVol || conf != 0.8 {
+				t.Errorf("got %s/%.2f, want high_volatility/0.80", regime, conf)
+			}
+			if v := got.Get(tt.header); v != tt.want {
+				t.Errorf("%s = %q, want %q", tt.header, v, tt.want)
+			}
+			if tt.cfg.AuthMode == HMMAuthNone && got.Get("X-API-Key") != "" {
+				t.Error("unexpected API key header in none mode")
+			}
+		})
+	}
+}
+
+func TestNewHMMClient_ValidatesMode(t *testing.T) {
+	if _, err := NewHMMClient(HMMClientConfig{AuthMode: "oauth"}); err == nil {
+		t.Error("expected error for unknown auth mode")
+	}
+	if _, err := NewHMMClient(HMMClientConfig{AuthMode: HMMAuthBearer}); err == nil {
+		t.Error("expected error for bearer mode without token")
+	}
+}
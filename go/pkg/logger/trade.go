@@ -0,0 +1,9 @@
+package logger
+
+import "log/slog"
+
+// WithTrade returns the default logger tagged with a trade's symbol and strategy, so
+// records from one trade path can be filtered on KeySymbol/KeyStrategy
+func WithTrade(symbol, strategy string) *slog.Logger {
+	return slog.Default().With(KeySymbol, symbol, KeyStrategy, strategy)
+}
@@ -0,0 +1,34 @@
+package logger
+
+import (
+	"bytes"
+	"encoding/json"
+	"log/slog"
+	"testing"
+)
+
+func TestWithTrade_Attributes(t *testing.T) {
+	var buf bytes.Buffer
+	prev := slog.Default()
+	slog.SetDefault(slog.New(slog.NewJSONHandler(&buf, nil)))
+	defer slog.SetDefault(prev)
+
+	WithTrade("BTCUSD", "fee_aware_scalper").Info("Scalp entry", KeyAction, "buy", KeyOrderID, int64(42))
+
+	var rec map[string]interface{}
+	if err := json.Unmarshal(buf.Bytes(), &rec); err != nil {
+		t.Fatalf("record %q is not JSON: %v", buf.String(), err)
+	}
+	want := map[string]interface{}{
+		KeySymbol:   "BTCUSD",
+		KeyStrategy: "fee_aware_scalper",
+		KeyAction:   "buy",
+		KeyOrderID:  float64(42),
+		"msg":       "Scalp entry",
+	}
+	for k, v := range want {
+		if rec[k] != v {
+			t.Errorf("%s = %v, want %v (record %s)", k, rec[k], v, buf.String())
+		}
+	}
+}
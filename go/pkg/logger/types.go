@@ -9,6 +9,12 @@ const (
 	KeyTraceID     = "trace_id"
 	KeyComponent   = "component"
 	KeyEnvironment = "environment"
+
+	// Trade context, see WithTrade
+	KeySymbol   = "symbol"
+	KeyStrategy = "strategy"
+	KeyAction   = "action"
+	KeyOrderID  = "order_id"
 )
 
 // TradeEvent represents a trade execution or update
@@ -0,0 +1,55 @@
+package risk
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// fundingInterval is Delta's perpetual funding cadence: 00:00, 08:00 and 16:00 UTC
+const fundingInterval = 8 * time.Hour
+
+// EventFreeze blocks new entries while spreads are likely to blow out: within Window either
+// side of each funding boundary (with Funding set) and of each scheduled event in Events
+type EventFreeze struct {
+	Window  time.Duration // 0 = off
+	Funding bool
+	Events  []time.Time
+}
+
+// Active reports whether t falls inside a freeze window
+func (f EventFreeze) Active(t time.Time) bool {
+	if f.Window <= 0 {
+		return false
+	}
+	if f.Funding {
+		// Truncate aligns to the zero time, a UTC midnight, so this is the last funding boundary
+		sinceLast := t.Sub(t.Truncate(fundingInterval))
+		if sinceLast <= f.Window || fundingInterval-sinceLast <= f.Window {
+			return true
+		}
+	}
+	for _, ev := range f.Events {
+		if d := t.Sub(ev); d >= -f.Window && d <= f.Window {
+			return true
+		}
+	}
+	return false
+}
+
+// ParseEventTimes parses comma-separated RFC3339 timestamps
+func ParseEventTimes(s string) ([]time.Time, error) {
+	var times []time.Time
+	for _, part := range strings.Split(s, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+		t, err := time.Parse(time.RFC3339, part)
+		if err != nil {
+			return nil, fmt.Errorf("invalid event time %q: %w", part, err)
+		}
+		times = append(times, t)
+	}
+	return times, nil
+}
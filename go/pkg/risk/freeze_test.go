@@ -0,0 +1,42 @@
+package risk
+
+import (
+	"testing"
+	"time"
+)
+
+func TestEventFreeze_Active(t *testing.T) {
+	cpi := time.Date(2024, 3, 12, 12, 30, 0, 0, time.UTC)
+	f := EventFreeze{Window: 10 * time.Minute, Funding: true, Events: []time.Time{cpi}}
+
+	tests := []struct {
+		at   time.Time
+		want bool
+	}{
+		{time.Date(2024, 3, 12, 7, 52, 0, 0, time.UTC), true},  // 8m before the 08:00 funding
+		{time.Date(2024, 3, 12, 8, 10, 0, 0, time.UTC), true},  // Window edge after
+		{time.Date(2024, 3, 12, 8, 11, 0, 0, time.UTC), false}, // Just outside
+		{time.Date(2024, 3, 12, 23, 55, 0, 0, time.UTC), true}, // Before midnight funding
+		{time.Date(2024, 3, 12, 12, 25, 0, 0, time.UTC), true}, // Scheduled event
+		{time.Date(2024, 3, 12, 12, 41, 0, 0, time.UTC), false},
+	}
+	for _, tt := range tests {
+		if got := f.Active(tt.at); got != tt.want {
+			t.Errorf("Active(%s) = %v, want %v", tt.at.Format("15:04"), got, tt.want)
+		}
+	}
+
+	if (EventFreeze{Funding: true}).Active(time.Date(2024, 3, 12, 8, 0, 0, 0, time.UTC)) {
+		t.Error("zero window should never freeze")
+	}
+}
+
+func TestParseEventTimes(t *testing.T) {
+	times, err := ParseEventTimes("2024-03-12T12:30:00Z, 2024-03-20T18:00:00Z")
+	if err != nil || len(times) != 2 || times[1].Hour() != 18 {
+		t.Fatalf("ParseEventTimes = %v, %v", times, err)
+	}
+	if _, err := ParseEventTimes("tomorrow"); err == nil {
+		t.Error("expected an error for a malformed time")
+	}
+}
@@ -0,0 +1,50 @@
+package risk
+
+import (
+	"sync"
+	"time"
+)
+
+// OrderGovernor is a last-resort brake on runaway order placement: it caps placement
+// attempts per strategy over a rolling minute, independent of the API rate limiter
+type OrderGovernor struct {
+	limit  int
+	window time.Duration
+
+	mu       sync.Mutex
+	attempts map[string][]time.Time // Strategy key -> attempt times within the window
+}
+
+// NewOrderGovernor allows up to limit attempts per strategy per rolling minute.
+// A non-positive limit disables the governor.
+func NewOrderGovernor(limit int) *OrderGovernor {
+	return &OrderGovernor{
+		limit:    limit,
+		window:   time.Minute,
+		attempts: make(map[string][]time.Time),
+	}
+}
+
+// Allow records an attempt for key at now and reports whether it is within the limit.
+// Blocked attempts are not recorded, so the strategy is allowed again once its oldest
+// attempt leaves the window.
+func (g *OrderGovernor) Allow(key string, now time.Time) bool {
+	if g.limit <= 0 {
+		return true
+	}
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	recent := g.attempts[key][:0]
+	for _, t := range g.attempts[key] {
+		if now.Sub(t) < g.window {
+			recent = append(recent, t)
+		}
+	}
+	if len(recent) >= g.limit {
+		g.attempts[key] = recent
+		return false
+	}
+	g.attempts[key] = append(recent, now)
+	return true
+}
@@ -0,0 +1,41 @@
+package risk
+
+import (
+	"testing"
+	"time"
+)
+
+func TestOrderGovernor_BlocksWithinRollingMinute(t *testing.T) {
+	g := NewOrderGovernor(3)
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	for i := 0; i < 3; i++ {
+		if !g.Allow("scalp", now.Add(time.Duration(i)*10*time.Second)) {
+			t.Fatalf("attempt %d blocked, want allowed", i+1)
+		}
+	}
+	if g.Allow("scalp", now.Add(30*time.Second)) {
+		t.Error("4th attempt within a minute allowed")
+	}
+	if !g.Allow("grid", now.Add(30*time.Second)) {
+		t.Error("other strategy blocked by scalp's budget")
+	}
+
+	// The first attempt leaves the window at +60s, freeing one slot
+	if !g.Allow("scalp", now.Add(60*time.Second)) {
+		t.Error("attempt after the window rolled blocked")
+	}
+	if g.Allow("scalp", now.Add(65*time.Second)) {
+		t.Error("attempt allowed while the window is full again")
+	}
+}
+
+func TestOrderGovernor_Disabled(t *testing.T) {
+	g := NewOrderGovernor(0)
+	now := time.Now()
+	for i := 0; i < 100; i++ {
+		if !g.Allow("scalp", now) {
+			t.Fatal("disabled governor blocked an attempt")
+		}
+	}
+}
@@ -8,6 +8,7 @@ import (
 	"time"
 
 	"github.com/kasyap/delta-go/go/config"
+	"github.com/kasyap/delta-go/go/pkg/alert"
 	"github.com/kasyap/delta-go/go/pkg/delta"
 	"github.com/kasyap/delta-go/go/pkg/logger"
 )
@@ -20,6 +21,7 @@ type RiskManager struct {
 	mu              sync.RWMutex
 	peakBalance     float64
 	currentBalance  float64
+	balanceKnown    bool // UpdateBalance has been called at least once
 	currentDrawdown float64
 	lastTradeTime   time.Time
 
@@ -34,6 +36,30 @@ type RiskManager struct {
 	circuitBrokenAt     time.Time
 	isDailyLimitHit     bool
 	dailyLimitResetTime time.Time
+
+	// Daily profit lock (DailyProfitTargetPct)
+	isProfitTargetHit   bool
+	profitLockResetTime time.Time
+
+	// Absolute equity floor (MinEquityFloor)
+	isBelowEquityFloor bool
+
+	// Consecutive loss streak
+	consecutiveLosses   int
+	isLossStreakHit     bool
+	lossStreakResetTime time.Time
+
+	// Last stop-loss exit per symbol, for the post-stop cooldown
+	lastStopLoss map[string]time.Time
+
+	// Last close on an opposite signal per symbol, when reversals are disabled
+	lastReversal map[string]time.Time
+
+	// Leverage applied on the exchange and the drawdown band it was chosen for
+	leverage     int
+	leverageBand int
+
+	alerter alert.Alerter
 }
 
 // NewRiskManager creates a new risk manager
@@ -42,9 +68,20 @@ func NewRiskManager(cfg *config.Config) *RiskManager {
 		cfg:            cfg,
 		dailyLossLimit: cfg.DailyLossLimitPct,
 		currentDay:     time.Now().Truncate(24 * time.Hour),
+		lastStopLoss:   make(map[string]time.Time),
+		lastReversal:   make(map[string]time.Time),
+		leverage:       cfg.Leverage,
+		alerter:        alert.Nop{},
 	}
 }
 
+// SetAlerter installs the alerter notified when the circuit breaker trips
+func (rm *RiskManager) SetAlerter(a alert.Alerter) {
+	rm.mu.Lock()
+	defer rm.mu.Unlock()
+	rm.alerter = a
+}
+
 // UpdateBalance updates the current balance and calculates drawdown
 func (rm *RiskManager) UpdateBalance(balance float64) {
 	rm.mu.Lock()
@@ -58,6 +95,7 @@ func (rm *RiskManager) UpdateBalance(balance float64) {
 		rm.dailyStartBalance = balance
 		rm.dailyPnL = 0
 		rm.isDailyLimitHit = false
+		rm.isProfitTargetHit = false
 		slog.Info("New trading day started", "balance", balance)
 	}
 
@@ -67,6 +105,7 @@ func (rm *RiskManager) UpdateBalance(balance float64) {
 	}
 
 	rm.currentBalance = balance
+	rm.balanceKnown = true
 
 	// Calculate daily P&L percentage
 	if rm.dailyStartBalance > 0 {
@@ -83,6 +122,21 @@ func (rm *RiskManager) UpdateBalance(balance float64) {
 		slog.Error("Daily loss limit hit", "pnl_pct", rm.dailyPnL, "limit_pct", rm.dailyLossLimit, "reset_at", rm.dailyLimitResetTime)
 	}
 
+	// Check daily profit target
+	if target := rm.cfg.DailyProfitTargetPct; target > 0 && rm.dailyPnL >= target && !rm.isProfitTargetHit {
+		rm.isProfitTargetHit = true
+		rm.profitLockResetTime = today.Add(24 * time.Hour)
+		action := "no new positions"
+		if rm.cfg.ProfitLockMode == "reduce" {
+			action = fmt.Sprintf("size reduced to %.0f%%", rm.cfg.ProfitLockSizeMult*100)
+		}
+		msg := fmt.Sprintf("DAILY PROFIT TARGET REACHED: Daily P&L %.2f%% reached target %.2f%%, %s until %v",
+			rm.dailyPnL, target, action, rm.profitLockResetTime)
+		logger.ConsoleLog("INFO", msg)
+		rm.alerter.Alert(alert.LevelInfo, msg)
+		slog.Info("Daily profit target reached", "pnl_pct", rm.dailyPnL, "target_pct", target, "mode", rm.cfg.ProfitLockMode)
+	}
+
 	if balance > rm.peakBalance {
 		rm.peakBalance = balance
 	}
@@ -99,6 +153,7 @@ func (rm *RiskManager) UpdateBalance(balance float64) {
 			msg := fmt.Sprintf("CIRCUIT BREAKER TRIGGERED: Drawdown %.2f%% exceeds max %.2f%%",
 				rm.currentDrawdown, rm.cfg.MaxDrawdownPct)
 			logger.ConsoleLog("ERROR", msg)
+			rm.alerter.Alert(alert.LevelError, msg)
 			slog.Error("Circuit breaker triggered", "drawdown_pct", rm.currentDrawdown, "max_drawdown_pct", rm.cfg.MaxDrawdownPct)
 		}
 	}
@@ -109,6 +164,22 @@ func (rm *RiskManager) CanTrade() (bool, string) {
 	rm.mu.Lock()
 	defer rm.mu.Unlock()
 
+	// Absolute equity floor, independent of the percentage limits
+	if floor := rm.cfg.MinEquityFloor; floor > 0 && rm.balanceKnown && rm.currentBalance < floor {
+		if !rm.isBelowEquityFloor {
+			rm.isBelowEquityFloor = true
+			msg := fmt.Sprintf("EQUITY FLOOR HIT: equity %.2f below floor %.2f - no new positions", rm.currentBalance, floor)
+			logger.ConsoleLog("ERROR", msg)
+			rm.alerter.Alert(alert.LevelError, msg)
+			slog.Error("Equity floor hit", "equity", rm.currentBalance, "floor", floor)
+		}
+		return false, fmt.Sprintf("equity %.2f below floor %.2f", rm.currentBalance, floor)
+	}
+	if rm.isBelowEquityFloor {
+		rm.isBelowEquityFloor = false
+		slog.Info("Equity back above floor - trading resumed", "equity", rm.currentBalance)
+	}
+
 	// Check daily loss limit first
 	if rm.isDailyLimitHit {
 		if time.Now().After(rm.dailyLimitResetTime) {
@@ -124,6 +195,28 @@ func (rm *RiskManager) CanTrade() (bool, string) {
 			rm.dailyPnL, hoursRemaining)
 	}
 
+	if rm.isProfitTargetHit && time.Now().After(rm.profitLockResetTime) {
+		rm.isProfitTargetHit = false
+		slog.Info("Daily profit lock reset")
+	}
+	if rm.isProfitTargetHit && rm.cfg.ProfitLockMode != "reduce" {
+		hoursRemaining := time.Until(rm.profitLockResetTime).Hours()
+		return false, fmt.Sprintf("daily profit target reached (%.2f%%), resets in %.1f hours",
+			rm.dailyPnL, hoursRemaining)
+	}
+
+	if rm.isLossStreakHit {
+		if time.Now().After(rm.lossStreakResetTime) {
+			rm.isLossStreakHit = false
+			rm.consecutiveLosses = 0
+			slog.Info("Loss streak pause reset - trading resumed")
+		} else {
+			hoursRemaining := time.Until(rm.lossStreakResetTime).Hours()
+			return false, fmt.Sprintf("%d consecutive losses, resets in %.1f hours",
+				rm.consecutiveLosses, hoursRemaining)
+		}
+	}
+
 	if rm.isCircuitBroken {
 		// Auto-reset after 24 hours
 		if time.Since(rm.circuitBrokenAt) > 24*time.Hour {
@@ -140,12 +233,14 @@ func (rm *RiskManager) CanTrade() (bool, string) {
 	return true, ""
 }
 
-// CalculatePositionSize calculates the position size based on risk parameters and market regime
+// CalculatePositionSize calculates the position size based on risk parameters, market regime
+// and signal confidence
 func (rm *RiskManager) CalculatePositionSize(
 	balance float64,
 	entryPrice float64,
 	stopLossPrice float64,
 	regime delta.MarketRegime,
+	confidence float64,
 	product *delta.Product,
 ) int {
 	rm.mu.RLock()
@@ -160,7 +255,7 @@ func (rm *RiskManager) CalculatePositionSize(
 
 	// Adjust risk based on regime
 	regimeMultiplier := rm.getRegimeMultiplier(regime)
-	adjustedRisk := riskAmount * regimeMultiplier
+	adjustedRisk := riskAmount * regimeMultiplier * rm.confidenceMultiplier(confidence) * rm.lossStreakMultiplier() * rm.profitLockMultiplier()
 
 	contractValue, err := delta.ParseContractValue(product)
 	if err != nil {
@@ -198,6 +293,186 @@ func (rm *RiskManager) CalculatePositionSize(
 	return size
 }
 
+// CalculatePyramidSize sizes an add-on unit so the aggregate position (existing units plus
+// the add) stays within the per-trade risk budget and the max position limit. Existing
+// units are risked from their blended entry to the new stop; a stop already beyond the
+// blended entry locks in profit and consumes no budget.
+func (rm *RiskManager) CalculatePyramidSize(
+	balance float64,
+	existingSize int,
+	existingEntry float64,
+	entryPrice float64,
+	stopLossPrice float64,
+	regime delta.MarketRegime,
+	product *delta.Product,
+) int {
+	rm.mu.RLock()
+	defer rm.mu.RUnlock()
+
+	if balance <= 0 || entryPrice <= 0 || stopLossPrice <= 0 {
+		return 0
+	}
+
+	contractValue, err := delta.ParseContractValue(product)
+	if err != nil {
+		contractValue = 1.0
+	}
+
+	riskBudget := balance * (rm.cfg.RiskPerTradePct / 100) * rm.getRegimeMultiplier(regime)
+
+	// Long positions have the stop below entry, shorts above
+	isLong := stopLossPrice < entryPrice
+	existingRiskPerContract := existingEntry - stopLossPrice
+	if !isLong {
+		existingRiskPerContract = stopLossPrice - existingEntry
+	}
+	if existingRiskPerContract > 0 {
+		riskBudget -= existingRiskPerContract * contractValue * float64(existingSize)
+	}
+
+	riskPerContract := math.Abs(entryPrice-stopLossPrice) * contractValue
+	if riskBudget <= 0 || riskPerContract <= 0 {
+		return 0
+	}
+	size := int(math.Floor(riskBudget / riskPerContract))
+
+	// Max position limit applies to the aggregate exposure
+	maxAdd := rm.calculateMaxSize(balance, entryPrice, product) - existingSize
+	if size > maxAdd {
+		size = maxAdd
+	}
+	if size < 1 {
+		return 0
+	}
+	return size
+}
+
+// ConfidenceMultiplier returns the fraction of the risk budget to use for a signal of the
+// given confidence (1.0 when confidence sizing is off)
+func (rm *RiskManager) ConfidenceMultiplier(confidence float64) float64 {
+	rm.mu.RLock()
+	defer rm.mu.RUnlock()
+	return rm.confidenceMultiplier(confidence)
+}
+
+func (rm *RiskManager) confidenceMultiplier(confidence float64) float64 {
+	curve := rm.cfg.ConfidenceSizing
+	if curve != "linear" && curve != "quadratic" {
+		return 1.0
+	}
+
+	floor := math.Max(0, math.Min(1, rm.cfg.ConfidenceSizeFloor))
+	minConf := rm.cfg.MinConfidence
+	if minConf >= 1 {
+		return 1.0
+	}
+	if confidence <= minConf {
+		return floor
+	}
+	if confidence >= 1 {
+		return 1.0
+	}
+
+	t := (confidence - minConf) / (1 - minConf)
+	if curve == "quadratic" {
+		t *= t
+	}
+	return floor + (1-floor)*t
+}
+
+// LossStreakMultiplier returns the fraction of the risk budget to use given the current
+// losing streak (1.0 when loss streak sizing is off or the last trade won)
+func (rm *RiskManager) LossStreakMultiplier() float64 {
+	rm.mu.RLock()
+	defer rm.mu.RUnlock()
+	return rm.lossStreakMultiplier()
+}
+
+func (rm *RiskManager) lossStreakMultiplier() float64 {
+	factor, floor := rm.cfg.LossStreakFactor, rm.cfg.LossStreakFloor
+	if rm.consecutiveLosses == 0 || factor <= 0 || factor >= 1 || floor <= 0 || floor > 1 {
+		return 1.0
+	}
+
+	scale := math.Pow(factor, float64(rm.consecutiveLosses))
+	switch rm.cfg.LossStreakSizing {
+	case "anti-martingale":
+		return math.Max(floor, scale)
+	case "martingale":
+		return math.Min(1/floor, 1/scale)
+	default:
+		return 1.0
+	}
+}
+
+// ProfitLockMultiplier returns ProfitLockSizeMult while the daily profit lock is engaged in
+// reduce mode, else 1.0
+func (rm *RiskManager) ProfitLockMultiplier() float64 {
+	rm.mu.RLock()
+	defer rm.mu.RUnlock()
+	return rm.profitLockMultiplier()
+}
+
+func (rm *RiskManager) profitLockMultiplier() float64 {
+	mult := rm.cfg.ProfitLockSizeMult
+	if !rm.isProfitTargetHit || rm.cfg.ProfitLockMode != "reduce" || mult <= 0 || mult > 1 ||
+		time.Now().After(rm.profitLockResetTime) {
+		return 1.0
+	}
+	return mult
+}
+
+// TargetLeverage maps drawdown to leverage: the configured Leverage at the peak, reduced by
+// LeverageStepPerBand for each full LeverageDrawdownBandPct of drawdown, floored at MinLeverage
+func (rm *RiskManager) TargetLeverage(currentDrawdownPct float64) int {
+	target := rm.cfg.Leverage - rm.leverageBandFor(currentDrawdownPct)*rm.cfg.LeverageStepPerBand
+	floor := rm.cfg.MinLeverage
+	if floor < 1 {
+		floor = 1
+	}
+	if target < floor {
+		target = floor
+	}
+	if target > rm.cfg.Leverage {
+		target = rm.cfg.Leverage
+	}
+	return target
+}
+
+func (rm *RiskManager) leverageBandFor(drawdownPct float64) int {
+	if rm.cfg.LeverageDrawdownBandPct <= 0 || drawdownPct <= 0 {
+		return 0
+	}
+	return int(drawdownPct / rm.cfg.LeverageDrawdownBandPct)
+}
+
+// LeverageUpdate returns the leverage for the current drawdown and whether it should be
+// applied: only when drawdown has moved into a different band than the last CommitLeverage,
+// so equity wobbling around a band edge within one band does not re-set leverage
+func (rm *RiskManager) LeverageUpdate() (leverage int, changed bool) {
+	rm.mu.RLock()
+	defer rm.mu.RUnlock()
+
+	band := rm.leverageBandFor(rm.currentDrawdown)
+	target := rm.TargetLeverage(rm.currentDrawdown)
+	return target, band != rm.leverageBand && target != rm.leverage
+}
+
+// CommitLeverage records that leverage is now set on the exchange for the current band
+func (rm *RiskManager) CommitLeverage(leverage int) {
+	rm.mu.Lock()
+	defer rm.mu.Unlock()
+	rm.leverage = leverage
+	rm.leverageBand = rm.leverageBandFor(rm.currentDrawdown)
+}
+
+// Leverage returns the leverage currently applied
+func (rm *RiskManager) Leverage() int {
+	rm.mu.RLock()
+	defer rm.mu.RUnlock()
+	return rm.leverage
+}
+
 // getRegimeMultiplier returns position size multiplier based on market regime
 func (rm *RiskManager) getRegimeMultiplier(regime delta.MarketRegime) float64 {
 	switch regime {
@@ -219,7 +494,7 @@ func (rm *RiskManager) getRegimeMultiplier(regime delta.MarketRegime) float64 {
 // calculateMaxSize calculates maximum position size based on account limits
 func (rm *RiskManager) calculateMaxSize(balance float64, price float64, product *delta.Product) int {
 	// Max position as percentage of balance
-	maxNotional := balance * (rm.cfg.MaxPositionPct / 100) * float64(rm.cfg.Leverage)
+	maxNotional := balance * (rm.cfg.MaxPositionPct / 100) * float64(rm.leverage)
 
 	// Use helper to convert notional to contracts
 	maxSize, err := delta.NotionalToContracts(maxNotional, price, product)
@@ -308,12 +583,19 @@ func (rm *RiskManager) GetRiskMetrics() map[string]interface{} {
 	defer rm.mu.RUnlock()
 
 	return map[string]interface{}{
-		"current_balance":  rm.currentBalance,
-		"peak_balance":     rm.peakBalance,
-		"current_drawdown": rm.currentDrawdown,
-		"max_drawdown":     rm.cfg.MaxDrawdownPct,
-		"circuit_broken":   rm.isCircuitBroken,
-		"last_trade_time":  rm.lastTradeTime,
+		"current_balance":    rm.currentBalance,
+		"peak_balance":       rm.peakBalance,
+		"current_drawdown":   rm.currentDrawdown,
+		"max_drawdown":       rm.cfg.MaxDrawdownPct,
+		"circuit_broken":     rm.isCircuitBroken,
+		"last_trade_time":    rm.lastTradeTime,
+		"consecutive_losses": rm.consecutiveLosses,
+		"loss_streak_paused": rm.isLossStreakHit,
+		"loss_streak_sizing": rm.lossStreakMultiplier(),
+		"leverage":           rm.leverage,
+		"below_equity_floor": rm.isBelowEquityFloor,
+		"profit_target_hit":  rm.isProfitTargetHit,
+		"profit_lock_sizing": rm.profitLockMultiplier(),
 	}
 }
 
@@ -324,6 +606,90 @@ func (rm *RiskManager) RecordTrade() {
 	rm.lastTradeTime = time.Now()
 }
 
+// RecordTradeResult tracks the losing streak from closed trade PnL. A win resets the
+// streak; hitting MaxConsecutiveLosses pauses trading until the next day.
+func (rm *RiskManager) RecordTradeResult(pnl float64) {
+	rm.mu.Lock()
+	defer rm.mu.Unlock()
+
+	switch {
+	case pnl > 0:
+		rm.consecutiveLosses = 0
+		return
+	case pnl < 0:
+		rm.consecutiveLosses++
+	default:
+		return
+	}
+
+	limit := rm.cfg.MaxConsecutiveLosses
+	if limit <= 0 || rm.consecutiveLosses < limit || rm.isLossStreakHit {
+		return
+	}
+
+	rm.isLossStreakHit = true
+	rm.lossStreakResetTime = time.Now().Truncate(24 * time.Hour).Add(24 * time.Hour)
+	msg := fmt.Sprintf("LOSS STREAK LIMIT HIT: %d consecutive losing trades. Trading paused until %v",
+		rm.consecutiveLosses, rm.lossStreakResetTime)
+	logger.ConsoleLog("ERROR", msg)
+	slog.Error("Consecutive loss limit hit", "losses", rm.consecutiveLosses, "max", limit, "reset_at", rm.lossStreakResetTime)
+}
+
+// RecordStopLoss starts the post-stop cooldown for a symbol
+func (rm *RiskManager) RecordStopLoss(symbol string, at time.Time) {
+	rm.mu.Lock()
+	defer rm.mu.Unlock()
+	rm.lastStopLoss[symbol] = at
+}
+
+// StopCooldownRemaining returns how long new entries on symbol stay blocked after its last
+// stop-loss exit (0 when no cooldown is configured or it has expired)
+func (rm *RiskManager) StopCooldownRemaining(symbol string, now time.Time) time.Duration {
+	rm.mu.RLock()
+	defer rm.mu.RUnlock()
+
+	stoppedAt, ok := rm.lastStopLoss[symbol]
+	if !ok || rm.cfg.StopCooldown <= 0 {
+		return 0
+	}
+	if remaining := stoppedAt.Add(rm.cfg.StopCooldown).Sub(now); remaining > 0 {
+		return remaining
+	}
+	return 0
+}
+
+// RecordReversalExit starts the reversal cooldown for a symbol closed on an opposite signal
+func (rm *RiskManager) RecordReversalExit(symbol string, at time.Time) {
+	rm.mu.Lock()
+	defer rm.mu.Unlock()
+	rm.lastReversal[symbol] = at
+}
+
+// ReversalCooldownRemaining returns how long new entries on symbol stay blocked after it was
+// closed on an opposite signal (0 when no cooldown is configured or it has expired)
+func (rm *RiskManager) ReversalCooldownRemaining(symbol string, now time.Time) time.Duration {
+	rm.mu.RLock()
+	defer rm.mu.RUnlock()
+
+	closedAt, ok := rm.lastReversal[symbol]
+	if !ok || rm.cfg.ReversalCooldown <= 0 {
+		return 0
+	}
+	if remaining := closedAt.Add(rm.cfg.ReversalCooldown).Sub(now); remaining > 0 {
+		return remaining
+	}
+	return 0
+}
+
+// ResetLossStreak manually clears the losing streak and any pause it triggered
+func (rm *RiskManager) ResetLossStreak() {
+	rm.mu.Lock()
+	defer rm.mu.Unlock()
+	rm.consecutiveLosses = 0
+	rm.isLossStreakHit = false
+	slog.Info("Loss streak manually reset")
+}
+
 // ResetCircuitBreaker manually resets the circuit breaker
 func (rm *RiskManager) ResetCircuitBreaker() {
 	rm.mu.Lock()
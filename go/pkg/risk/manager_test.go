@@ -1,10 +1,12 @@
 package risk
 
 import (
+	"strings"
 	"testing"
 	"time"
 
 	"github.com/kasyap/delta-go/go/config"
+	"github.com/kasyap/delta-go/go/pkg/alert"
 	"github.com/kasyap/delta-go/go/pkg/delta"
 )
 
@@ -66,6 +68,7 @@ func TestCalculatePositionSize_DoesNotScaleWithLeverage(t *testing.T) {
 		100,
 		98,
 		delta.RegimeRanging,
+		1.0,
 		&delta.Product{ContractValue: "1"},
 	)
 
@@ -87,6 +90,7 @@ func TestCalculatePositionSize_UsesContractValue(t *testing.T) {
 		100,
 		98,
 		delta.RegimeRanging,
+		1.0,
 		&delta.Product{ContractValue: "0.1"},
 	)
 
@@ -108,6 +112,7 @@ func TestCalculatePositionSize_ReturnsZeroWhenRiskTooSmall(t *testing.T) {
 		100,
 		0,
 		delta.RegimeRanging,
+		1.0,
 		&delta.Product{ContractValue: "1"},
 	)
 
@@ -146,3 +151,439 @@ func TestRiskManager_DrawdownCircuitBreaker(t *testing.T) {
 		t.Error("Expected isCircuitBroken to be true")
 	}
 }
+
+func TestCalculatePyramidSize_UsesAggregateExposure(t *testing.T) {
+	rm := NewRiskManager(&config.Config{
+		RiskPerTradePct: 1,
+		StopLossPct:     2,
+		Leverage:        10,
+		MaxPositionPct:  100,
+	})
+	product := &delta.Product{ContractValue: "1"}
+
+	// Stop trails to 100 (the blended entry): existing units risk nothing, add risks 2/contract
+	if size := rm.CalculatePyramidSize(1000, 5, 100, 102, 100, delta.RegimeRanging, product); size != 5 {
+		t.Fatalf("size mismatch: got=%d want=%d", size, 5)
+	}
+
+	// Stop below the blended entry: existing 4 contracts risk 1 each, leaving 6 of the 10 budget
+	if size := rm.CalculatePyramidSize(1000, 4, 100, 102, 99, delta.RegimeRanging, product); size != 2 {
+		t.Fatalf("size mismatch: got=%d want=%d", size, 2)
+	}
+
+	// Budget already consumed by the existing position
+	if size := rm.CalculatePyramidSize(1000, 10, 100, 102, 99, delta.RegimeRanging, product); size != 0 {
+		t.Fatalf("size mismatch: got=%d want=%d", size, 0)
+	}
+
+	// Max position limit applies to the aggregate: 10 contracts max, 9 already held
+	rm.cfg.MaxPositionPct = 10
+	if size := rm.CalculatePyramidSize(1000, 9, 99, 100, 99, delta.RegimeRanging, product); size != 1 {
+		t.Fatalf("size mismatch: got=%d want=%d", size, 1)
+	}
+}
+
+func TestRecordTradeResult_PausesAfterConsecutiveLosses(t *testing.T) {
+	rm := NewRiskManager(&config.Config{MaxConsecutiveLosses: 3})
+
+	rm.RecordTradeResult(-1)
+	rm.RecordTradeResult(-2)
+	if can, reason := rm.CanTrade(); !can {
+		t.Fatalf("expected trading allowed after 2 losses, got: %s", reason)
+	}
+
+	rm.RecordTradeResult(-0.5)
+	can, reason := rm.CanTrade()
+	if can {
+		t.Fatal("expected trading paused after 3 consecutive losses")
+	}
+	if reason == "" {
+		t.Error("expected a pause reason")
+	}
+
+	metrics := rm.GetRiskMetrics()
+	if got := metrics["consecutive_losses"].(int); got != 3 {
+		t.Errorf("consecutive_losses = %d, want 3", got)
+	}
+	if !metrics["loss_streak_paused"].(bool) {
+		t.Error("expected loss_streak_paused in metrics")
+	}
+
+	// Pause lifts at the next day
+	rm.mu.Lock()
+	rm.lossStreakResetTime = time.Now().Add(-time.Minute)
+	rm.mu.Unlock()
+	if can, reason := rm.CanTrade(); !can {
+		t.Fatalf("expected trading to resume after reset time, got: %s", reason)
+	}
+	if got := rm.GetRiskMetrics()["consecutive_losses"].(int); got != 0 {
+		t.Errorf("streak after daily reset = %d, want 0", got)
+	}
+}
+
+func TestRecordTradeResult_WinResetsStreak(t *testing.T) {
+	rm := NewRiskManager(&config.Config{MaxConsecutiveLosses: 3})
+
+	rm.RecordTradeResult(-1)
+	rm.RecordTradeResult(-1)
+	rm.RecordTradeResult(2)
+	if got := rm.GetRiskMetrics()["consecutive_losses"].(int); got != 0 {
+		t.Fatalf("streak after win = %d, want 0", got)
+	}
+
+	rm.RecordTradeResult(-1)
+	rm.RecordTradeResult(-1)
+	if can, reason := rm.CanTrade(); !can {
+		t.Errorf("expected trading allowed: win should have broken the streak, got: %s", reason)
+	}
+}
+
+func TestResetLossStreak_ClearsPause(t *testing.T) {
+	rm := NewRiskManager(&config.Config{MaxConsecutiveLosses: 1})
+
+	rm.RecordTradeResult(-1)
+	if can, _ := rm.CanTrade(); can {
+		t.Fatal("expected pause after 1 loss")
+	}
+
+	rm.ResetLossStreak()
+	if can, reason := rm.CanTrade(); !can {
+		t.Errorf("expected trading allowed after manual reset, got: %s", reason)
+	}
+}
+
+type recordingAlerter struct {
+	levels []string
+}
+
+func (r *recordingAlerter) Alert(level, message string) {
+	r.levels = append(r.levels, level)
+}
+
+func TestRiskManager_AlertsOnCircuitBreaker(t *testing.T) {
+	rm := NewRiskManager(&config.Config{MaxDrawdownPct: 10.0, DailyLossLimitPct: -50})
+	rec := &recordingAlerter{}
+	rm.SetAlerter(rec)
+
+	rm.UpdateBalance(100)
+	rm.UpdateBalance(89)
+	rm.UpdateBalance(88) // already tripped, no repeat alert
+
+	if len(rec.levels) != 1 || rec.levels[0] != alert.LevelError {
+		t.Errorf("alerts = %v, want one %s alert", rec.levels, alert.LevelError)
+	}
+}
+
+func TestCalculatePositionSize_ScalesWithConfidenceLinear(t *testing.T) {
+	rm := NewRiskManager(&config.Config{
+		RiskPerTradePct:     1,
+		StopLossPct:         2,
+		Leverage:            10,
+		MaxPositionPct:      100,
+		ConfidenceSizing:    "linear",
+		MinConfidence:       0.4,
+		ConfidenceSizeFloor: 0.2,
+	})
+	product := &delta.Product{ContractValue: "0.1"}
+
+	low := rm.CalculatePositionSize(1000, 100, 98, delta.RegimeRanging, 0.5, product)
+	high := rm.CalculatePositionSize(1000, 100, 98, delta.RegimeRanging, 0.9, product)
+	full := rm.CalculatePositionSize(1000, 100, 98, delta.RegimeRanging, 1.0, product)
+
+	if low >= high {
+		t.Errorf("0.5-confidence size %d should be smaller than 0.9-confidence size %d", low, high)
+	}
+	if full != 50 {
+		t.Errorf("full-confidence size = %d, want the full budget of 50", full)
+	}
+	// 0.5 is 1/6 of the way from 0.4 to 1.0: 0.2 + 0.8/6 of the budget
+	if low != 16 {
+		t.Errorf("0.5-confidence size = %d, want 16", low)
+	}
+}
+
+func TestConfidenceMultiplier_Curves(t *testing.T) {
+	cfg := &config.Config{ConfidenceSizing: "quadratic", MinConfidence: 0.5, ConfidenceSizeFloor: 0.25}
+	rm := NewRiskManager(cfg)
+
+	if got := rm.ConfidenceMultiplier(0.3); got != 0.25 {
+		t.Errorf("below min confidence = %v, want floor 0.25", got)
+	}
+	// Halfway: linear would be 0.625, quadratic is 0.25 + 0.75*0.25
+	if got := rm.ConfidenceMultiplier(0.75); got != 0.4375 {
+		t.Errorf("quadratic at 0.75 = %v, want 0.4375", got)
+	}
+
+	cfg.ConfidenceSizing = "off"
+	if got := rm.ConfidenceMultiplier(0.1); got != 1.0 {
+		t.Errorf("disabled multiplier = %v, want 1.0", got)
+	}
+}
+
+func TestStopCooldownRemaining(t *testing.T) {
+	rm := NewRiskManager(&config.Config{StopCooldown: 30 * time.Minute})
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	if got := rm.StopCooldownRemaining("BTCUSD", now); got != 0 {
+		t.Fatalf("cooldown before any stop = %v, want 0", got)
+	}
+
+	rm.RecordStopLoss("BTCUSD", now)
+	if got := rm.StopCooldownRemaining("BTCUSD", now.Add(10*time.Minute)); got != 20*time.Minute {
+		t.Errorf("remaining = %v, want 20m", got)
+	}
+	if got := rm.StopCooldownRemaining("ETHUSD", now.Add(10*time.Minute)); got != 0 {
+		t.Errorf("other symbol blocked for %v", got)
+	}
+	if got := rm.StopCooldownRemaining("BTCUSD", now.Add(30*time.Minute)); got != 0 {
+		t.Errorf("remaining after expiry = %v, want 0", got)
+	}
+}
+
+func TestReversalCooldownRemaining(t *testing.T) {
+	rm := NewRiskManager(&config.Config{ReversalCooldown: 15 * time.Minute})
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	if got := rm.ReversalCooldownRemaining("BTCUSD", now); got != 0 {
+		t.Fatalf("cooldown before any reversal close = %v, want 0", got)
+	}
+
+	rm.RecordReversalExit("BTCUSD", now)
+	if got := rm.ReversalCooldownRemaining("BTCUSD", now.Add(5*time.Minute)); got != 10*time.Minute {
+		t.Errorf("remaining = %v, want 10m", got)
+	}
+	if got := rm.StopCooldownRemaining("BTCUSD", now.Add(5*time.Minute)); got != 0 {
+		t.Errorf("reversal close started the stop cooldown: %v", got)
+	}
+	if got := rm.ReversalCooldownRemaining("BTCUSD", now.Add(15*time.Minute)); got != 0 {
+		t.Errorf("remaining after expiry = %v, want 0", got)
+	}
+}
+
+func TestCalculatePositionSize_AntiMartingaleShrinksOnLossStreak(t *testing.T) {
+	rm := NewRiskManager(&config.Config{
+		RiskPerTradePct:  1,
+		StopLossPct:      2,
+		MaxPositionPct:   100,
+		Leverage:         10,
+		LossStreakSizing: "anti-martingale",
+		LossStreakFactor: 0.5,
+		LossStreakFloor:  0.2,
+	})
+	size := func() int {
+		// $100 risk budget over $1 risk per contract
+		return rm.CalculatePositionSize(10000, 100, 99, delta.RegimeRanging, 1.0, &delta.Product{ContractValue: "1"})
+	}
+
+	if got := size(); got != 100 {
+		t.Fatalf("size with no streak = %d, want 100", got)
+	}
+	rm.RecordTradeResult(-10)
+	rm.RecordTradeResult(-10)
+	if got := size(); got != 25 {
+		t.Errorf("size after two losses = %d, want 25 (0.5^2)", got)
+	}
+	rm.RecordTradeResult(-10)
+	rm.RecordTradeResult(-10)
+	if got := size(); got != 20 {
+		t.Errorf("size after four losses = %d, want floor 20", got)
+	}
+	rm.RecordTradeResult(10)
+	if got := size(); got != 100 {
+		t.Errorf("size after a win = %d, want full 100", got)
+	}
+}
+
+func TestLossStreakMultiplier_MartingaleIsCapped(t *testing.T) {
+	rm := NewRiskManager(&config.Config{
+		LossStreakSizing: "martingale",
+		LossStreakFactor: 0.5,
+		LossStreakFloor:  0.25,
+	})
+	rm.RecordTradeResult(-1)
+	if got := rm.LossStreakMultiplier(); got != 2 {
+		t.Errorf("multiplier after one loss = %v, want 2", got)
+	}
+	rm.RecordTradeResult(-1)
+	rm.RecordTradeResult(-1)
+	if got := rm.LossStreakMultiplier(); got != 4 {
+		t.Errorf("multiplier after three losses = %v, want cap 4", got)
+	}
+}
+
+func TestTargetLeverage_DrawdownBands(t *testing.T) {
+	rm := NewRiskManager(&config.Config{
+		Leverage:                10,
+		LeverageDrawdownBandPct: 2,
+		LeverageStepPerBand:     2,
+		MinLeverage:             3,
+	})
+
+	tests := []struct {
+		drawdown float64
+		want     int
+	}{
+		{0, 10},
+		{1.9, 10},
+		{2, 8},
+		{5.5, 6},
+		{7, 4},
+		{9, 3}, // Floored at MinLeverage
+		{40, 3},
+	}
+	for _, tt := range tests {
+		if got := rm.TargetLeverage(tt.drawdown); got != tt.want {
+			t.Errorf("TargetLeverage(%.1f) = %d, want %d", tt.drawdown, got, tt.want)
+		}
+	}
+
+	fixed := NewRiskManager(&config.Config{Leverage: 10})
+	if got := fixed.TargetLeverage(50); got != 10 {
+		t.Errorf("with no band configured TargetLeverage = %d, want fixed 10", got)
+	}
+}
+
+func TestLeverageUpdate_OnlyOnBandChange(t *testing.T) {
+	rm := NewRiskManager(&config.Config{
+		MaxDrawdownPct:          50,
+		DailyLossLimitPct:       -50,
+		Leverage:                10,
+		LeverageDrawdownBandPct: 2,
+		LeverageStepPerBand:     2,
+		MinLeverage:             1,
+	})
+
+	rm.UpdateBalance(1000)
+	if _, changed := rm.LeverageUpdate(); changed {
+		t.Fatal("no drawdown should not change leverage")
+	}
+
+	rm.UpdateBalance(970) // 3% drawdown, band 1
+	lev, changed := rm.LeverageUpdate()
+	if !changed || lev != 8 {
+		t.Fatalf("LeverageUpdate() = %d, %v, want 8, true", lev, changed)
+	}
+	rm.CommitLeverage(lev)
+
+	// Equity wobbles inside the same band: no re-set
+	for _, balance := range []float64{975, 962, 979} {
+		rm.UpdateBalance(balance)
+		if lev, changed := rm.LeverageUpdate(); changed {
+			t.Errorf("balance %.0f: LeverageUpdate() = %d, changed within band", balance, lev)
+		}
+	}
+	if rm.Leverage() != 8 {
+		t.Errorf("Leverage() = %d, want 8", rm.Leverage())
+	}
+
+	rm.UpdateBalance(995) // Recovered to band 0
+	if lev, changed := rm.LeverageUpdate(); !changed || lev != 10 {
+		t.Errorf("after recovery LeverageUpdate() = %d, %v, want 10, true", lev, changed)
+	}
+}
+
+func TestCanTrade_EquityFloor(t *testing.T) {
+	rm := NewRiskManager(&config.Config{
+		MaxDrawdownPct:    50,
+		DailyLossLimitPct: -50,
+		MinEquityFloor:    95,
+	})
+	rec := &recordingAlerter{}
+	rm.SetAlerter(rec)
+
+	rm.UpdateBalance(100)
+	if can, reason := rm.CanTrade(); !can {
+		t.Fatalf("above floor: CanTrade() = false (%s)", reason)
+	}
+
+	// A 6% drawdown is well inside the percentage limits but breaches the dollar floor
+	rm.UpdateBalance(94)
+	for i := 0; i < 2; i++ {
+		if can, _ := rm.CanTrade(); can {
+			t.Fatal("below floor: CanTrade() = true")
+		}
+	}
+	if len(rec.levels) != 1 {
+		t.Errorf("alerts = %d, want 1 for the whole breach", len(rec.levels))
+	}
+
+	rm.UpdateBalance(96)
+	if can, reason := rm.CanTrade(); !can {
+		t.Errorf("recovered: CanTrade() = false (%s)", reason)
+	}
+
+	// An emptied account is below any floor
+	rm.UpdateBalance(0)
+	if can, reason := rm.CanTrade(); can || !strings.Contains(reason, "floor") {
+		t.Errorf("zero balance: CanTrade() = %v (%s), want blocked by the floor", can, reason)
+	}
+}
+
+func TestRiskManager_DailyProfitTargetStopsEntries(t *testing.T) {
+	rm := NewRiskManager(&config.Config{MaxDrawdownPct: 50, DailyLossLimitPct: -50, DailyProfitTargetPct: 3, ProfitLockMode: "stop"})
+	rec := &recordingAlerter{}
+	rm.SetAlerter(rec)
+
+	rm.UpdateBalance(100)
+	rm.UpdateBalance(102.9)
+	if can, reason := rm.CanTrade(); !can {
+		t.Fatalf("blocked below the profit target: %s", reason)
+	}
+
+	rm.UpdateBalance(103.5)
+	can, reason := rm.CanTrade()
+	if can || !strings.Contains(reason, "profit target reached") {
+		t.Fatalf("CanTrade() = %v, %q; want blocked by the profit target", can, reason)
+	}
+	if len(rec.levels) != 1 || rec.levels[0] != alert.LevelInfo {
+		t.Errorf("alerts = %v, want one %s alert", rec.levels, alert.LevelInfo)
+	}
+
+	// Giving back gains doesn't lift the lock for the day
+	rm.UpdateBalance(101)
+	if can, _ := rm.CanTrade(); can {
+		t.Error("lock lifted intraday after P&L fell back below the target")
+	}
+
+	// Next day: daily tracking restarts from the current balance
+	rm.currentDay = rm.currentDay.Add(-24 * time.Hour)
+	rm.UpdateBalance(101)
+	if can, reason := rm.CanTrade(); !can {
+		t.Errorf("still blocked on the next day: %s", reason)
+	}
+}
+
+func TestRiskManager_DailyProfitTargetReducesSize(t *testing.T) {
+	rm := NewRiskManager(&config.Config{
+		MaxDrawdownPct:       50,
+		DailyLossLimitPct:    -50,
+		DailyProfitTargetPct: 3,
+		ProfitLockMode:       "reduce",
+		ProfitLockSizeMult:   0.5,
+		RiskPerTradePct:      1,
+		StopLossPct:          2,
+		Leverage:             10,
+		MaxPositionPct:       100,
+	})
+	product := &delta.Product{ContractValue: "1"}
+	size := func() int { return rm.CalculatePositionSize(1000, 100, 98, delta.RegimeRanging, 1.0, product) }
+
+	rm.UpdateBalance(100)
+	if got := size(); got != 5 {
+		t.Fatalf("size before target = %d, want 5", got)
+	}
+
+	rm.UpdateBalance(104)
+	if can, reason := rm.CanTrade(); !can {
+		t.Errorf("reduce mode blocked entries: %s", reason)
+	}
+	if got := size(); got != 2 {
+		t.Errorf("size after target = %d, want 2 (half the risk budget)", got)
+	}
+
+	rm.currentDay = rm.currentDay.Add(-24 * time.Hour)
+	rm.UpdateBalance(104)
+	if got := size(); got != 5 {
+		t.Errorf("size on the next day = %d, want full size 5", got)
+	}
+}
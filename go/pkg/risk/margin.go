@@ -0,0 +1,52 @@
+package risk
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+
+	"github.com/kasyap/delta-go/go/pkg/delta"
+)
+
+// RequiredMargin is the initial margin a new position of notional locks: notional at the
+// configured leverage, or the product's initial margin (a percentage of notional) when that
+// is stricter
+func (rm *RiskManager) RequiredMargin(notional float64, product *delta.Product) float64 {
+	rate := 1.0
+	if lev := rm.Leverage(); lev > 0 {
+		rate = 1 / float64(lev)
+	}
+	if product != nil {
+		if im, err := strconv.ParseFloat(product.InitialMargin, 64); err == nil && im > 0 {
+			rate = math.Max(rate, im/100)
+		}
+	}
+	return notional * rate
+}
+
+// AvailableMargin is the wallet balance left after the margin held by open positions,
+// floored at zero. balance is the wallet's total balance, not the exchange's available
+// balance (which already nets out position margin).
+func (rm *RiskManager) AvailableMargin(balance float64, openPositions []delta.Position) float64 {
+	used := 0.0
+	for _, p := range openPositions {
+		if p.Size == 0 {
+			continue
+		}
+		if m, err := strconv.ParseFloat(p.Margin, 64); err == nil {
+			used += m
+		}
+	}
+	return math.Max(balance-used, 0)
+}
+
+// CheckMargin returns an error when a new position of notional would need more margin than
+// is free given the open positions
+func (rm *RiskManager) CheckMargin(notional float64, product *delta.Product, balance float64, openPositions []delta.Position) error {
+	required := rm.RequiredMargin(notional, product)
+	available := rm.AvailableMargin(balance, openPositions)
+	if required > available {
+		return fmt.Errorf("insufficient margin: need %.2f, %.2f free", required, available)
+	}
+	return nil
+}
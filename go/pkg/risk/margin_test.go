@@ -0,0 +1,49 @@
+package risk
+
+import (
+	"math"
+	"testing"
+
+	"github.com/kasyap/delta-go/go/config"
+	"github.com/kasyap/delta-go/go/pkg/delta"
+)
+
+func TestAvailableMargin_SubtractsOpenPositions(t *testing.T) {
+	rm := NewRiskManager(&config.Config{Leverage: 10})
+	positions := []delta.Position{
+		{ProductSymbol: "BTCUSD", Size: 5, Margin: "120.5"},
+		{ProductSymbol: "ETHUSD", Size: -3, Margin: "79.5"},
+		{ProductSymbol: "SOLUSD", Size: 0, Margin: "50"}, // Closed, ignored
+	}
+
+	if got := rm.AvailableMargin(1000, positions); math.Abs(got-800) > 1e-9 {
+		t.Errorf("available margin = %.2f, want 800", got)
+	}
+	if got := rm.AvailableMargin(150, positions); got != 0 {
+		t.Errorf("available margin = %.2f, want 0 when over-committed", got)
+	}
+}
+
+func TestRequiredMargin_UsesStricterOfLeverageAndProduct(t *testing.T) {
+	rm := NewRiskManager(&config.Config{Leverage: 10})
+
+	if got := rm.RequiredMargin(5000, &delta.Product{InitialMargin: "2"}); got != 500 {
+		t.Errorf("required margin at 10x = %.2f, want 500", got)
+	}
+	if got := rm.RequiredMargin(5000, &delta.Product{InitialMargin: "20"}); got != 1000 {
+		t.Errorf("required margin with 20%% product margin = %.2f, want 1000", got)
+	}
+}
+
+func TestCheckMargin(t *testing.T) {
+	rm := NewRiskManager(&config.Config{Leverage: 10})
+	positions := []delta.Position{{Size: 2, Margin: "700"}}
+	product := &delta.Product{InitialMargin: "1"}
+
+	if err := rm.CheckMargin(3000, product, 1000, positions); err != nil {
+		t.Errorf("300 required of 300 free: %v", err)
+	}
+	if err := rm.CheckMargin(3100, product, 1000, positions); err == nil {
+		t.Error("expected insufficient margin for 310 required of 300 free")
+	}
+}
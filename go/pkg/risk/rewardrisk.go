@@ -0,0 +1,34 @@
+package risk
+
+// RewardRisk is the distance from price to target over the distance from price to stop for
+// an entry on side ("buy" or "sell"). Returns 0 when either leg is missing or sits on the
+// wrong side of the entry.
+func RewardRisk(side string, price, stop, target float64) float64 {
+	if price <= 0 || stop <= 0 || target <= 0 {
+		return 0
+	}
+	risk, reward := price-stop, target-price
+	if side == "sell" {
+		risk, reward = stop-price, price-target
+	}
+	if risk <= 0 || reward <= 0 {
+		return 0
+	}
+	return reward / risk
+}
+
+// DefaultBracket fills a missing stop or target at stopPct/targetPct from price on side's
+// adverse/favourable side; legs already set are returned unchanged
+func DefaultBracket(side string, price, stop, target, stopPct, targetPct float64) (float64, float64) {
+	dir := 1.0
+	if side == "sell" {
+		dir = -1
+	}
+	if stop <= 0 && stopPct > 0 {
+		stop = price * (1 - dir*stopPct/100)
+	}
+	if target <= 0 && targetPct > 0 {
+		target = price * (1 + dir*targetPct/100)
+	}
+	return stop, target
+}
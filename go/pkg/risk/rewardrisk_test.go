@@ -0,0 +1,36 @@
+package risk
+
+import (
+	"math"
+	"testing"
+)
+
+func TestRewardRisk(t *testing.T) {
+	cases := []struct {
+		side                string
+		price, stop, target float64
+		want                float64
+	}{
+		{"buy", 100, 98, 104, 2},
+		{"sell", 100, 102, 99, 0.5},
+		{"buy", 100, 0, 104, 0},    // No stop
+		{"buy", 100, 101, 104, 0},  // Stop above a long entry
+		{"sell", 100, 102, 101, 0}, // Target above a short entry
+	}
+	for _, c := range cases {
+		if got := RewardRisk(c.side, c.price, c.stop, c.target); math.Abs(got-c.want) > 1e-9 {
+			t.Errorf("RewardRisk(%s, %v, %v, %v) = %v, want %v", c.side, c.price, c.stop, c.target, got, c.want)
+		}
+	}
+}
+
+func TestDefaultBracket(t *testing.T) {
+	stop, target := DefaultBracket("sell", 100, 0, 0, 2, 4)
+	if math.Abs(stop-102) > 1e-9 || math.Abs(target-96) > 1e-9 {
+		t.Errorf("sell defaults = %v/%v, want 102/96", stop, target)
+	}
+	stop, target = DefaultBracket("buy", 100, 97, 0, 2, 4)
+	if stop != 97 || math.Abs(target-104) > 1e-9 {
+		t.Errorf("buy with a stop = %v/%v, want 97/104", stop, target)
+	}
+}
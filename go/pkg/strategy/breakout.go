@@ -0,0 +1,170 @@
+package strategy
+
+import (
+	"github.com/kasyap/delta-go/go/pkg/delta"
+	"github.com/kasyap/delta-go/go/pkg/features"
+)
+
+type BreakoutConfig struct {
+	Lookback         int     // Bars forming the range to break (20)
+	VolumeMultiplier float64 // Breakout bar volume vs the range's average (1.5x)
+	MinBodyRatio     float64 // Breakout bar body / range (0.5)
+	MinImbalance     float64 // ImbalanceMA that must agree with the break in AnalyzeWithFeatures (0.1)
+	ATRPeriod        int
+	StopATR          float64 // Stop distance in ATRs
+	TargetATR        float64 // Target distance in ATRs
+	MinHistoricalVol float64 // Annualized HistoricalVol counted as high volatility without an HMM regime (0.8)
+	Enabled          bool
+}
+
+func DefaultBreakoutConfig() BreakoutConfig {
+	return BreakoutConfig{
+		Lookback:         20,
+		VolumeMultiplier: 1.5,
+		MinBodyRatio:     0.5,
+		MinImbalance:     0.1,
+		ATRPeriod:        14,
+		StopATR:          1.5,
+		TargetATR:        3.0,
+		MinHistoricalVol: 0.8,
+		Enabled:          true,
+	}
+}
+
+// HighVolBreakoutStrategy trades closes beyond the recent range, confirmed by a volume
+// spike and a full-bodied breakout bar
+type HighVolBreakoutStrategy struct {
+	cfg BreakoutConfig
+	ti  *TechnicalIndicators
+}
+
+func NewHighVolBreakoutStrategy(cfg BreakoutConfig) *HighVolBreakoutStrategy {
+	return &HighVolBreakoutStrategy{cfg: cfg, ti: NewIndicators()}
+}
+
+func (s *HighVolBreakoutStrategy) Name() string {
+	return "high_vol_breakout"
+}
+
+// Config returns the breakout's current configuration
+func (s *HighVolBreakoutStrategy) Config() BreakoutConfig {
+	return s.cfg
+}
+
+// ParamKeys lists the keys UpdateParams reads
+func (s *HighVolBreakoutStrategy) ParamKeys() []string {
+	return []string{"lookback", "volume_multiplier", "min_body_ratio", "min_imbalance", "atr_period",
+		"stop_atr", "target_atr", "min_historical_vol", "enabled"}
+}
+
+// UpdateParams keys: lookback, volume_multiplier, min_body_ratio, min_imbalance, atr_period,
+// stop_atr, target_atr, min_historical_vol, enabled. Unknown keys and mistyped values are ignored.
+func (s *HighVolBreakoutStrategy) UpdateParams(params map[string]interface{}) {
+	setIntParam(params, "lookback", &s.cfg.Lookback)
+	setFloatParam(params, "volume_multiplier", &s.cfg.VolumeMultiplier)
+	setFloatParam(params, "min_body_ratio", &s.cfg.MinBodyRatio)
+	setFloatParam(params, "min_imbalance", &s.cfg.MinImbalance)
+	setIntParam(params, "atr_period", &s.cfg.ATRPeriod)
+	setFloatParam(params, "stop_atr", &s.cfg.StopATR)
+	setFloatParam(params, "target_atr", &s.cfg.TargetATR)
+	setFloatParam(params, "min_historical_vol", &s.cfg.MinHistoricalVol)
+	setBoolParam(params, "enabled", &s.cfg.Enabled)
+}
+
+// InHighVol reports whether f is a high volatility market: the HMM regime when one is known
+// (live), otherwise HistoricalVol of at least MinHistoricalVol (backtests have no HMM)
+func (s *HighVolBreakoutStrategy) InHighVol(f features.MarketFeatures) bool {
+	if f.HMMRegime != "" {
+		return f.HMMRegime == delta.RegimeHighVol
+	}
+	return s.cfg.MinHistoricalVol > 0 && f.HistoricalVol >= s.cfg.MinHistoricalVol
+}
+
+// Analyze confirms breakouts with candles only (volume and body)
+func (s *HighVolBreakoutStrategy) Analyze(f features.MarketFeatures, candles []delta.Candle) Signal {
+	if !s.cfg.Enabled {
+		return Signal{Action: ActionNone, Reason: "breakout disabled"}
+	}
+	if err := RequireCandles(candles, s.cfg.Lookback+1); err != nil {
+		return Signal{Action: ActionNone, Reason: "insufficient history", Err: err}
+	}
+
+	last := candles[len(candles)-1]
+	rangeBars := candles[len(candles)-1-s.cfg.Lookback : len(candles)-1]
+	rangeHigh, rangeLow := rangeBars[0].High, rangeBars[0].Low
+	avgVolume := 0.0
+	for _, c := range rangeBars {
+		rangeHigh = max(rangeHigh, c.High)
+		if c.Low < rangeLow {
+			rangeLow = c.Low
+		}
+		avgVolume += c.Volume
+	}
+	avgVolume /= float64(len(rangeBars))
+
+	var side string
+	switch {
+	case last.Close > rangeHigh && last.Close > last.Open:
+		side = "buy"
+	case last.Close < rangeLow && last.Close < last.Open:
+		side = "sell"
+	default:
+		return Signal{Action: ActionNone, Reason: "no breakout"}
+	}
+
+	if avgVolume <= 0 || last.Volume < avgVolume*s.cfg.VolumeMultiplier {
+		return Signal{Action: ActionNone, Reason: "breakout without volume"}
+	}
+	if barRange := last.High - last.Low; barRange <= 0 || abs(last.Close-last.Open)/barRange < s.cfg.MinBodyRatio {
+		return Signal{Action: ActionNone, Reason: "breakout bar body too small"}
+	}
+
+	highs, lows, closes := make([]float64, len(candles)), make([]float64, len(candles)), make([]float64, len(candles))
+	for i, c := range candles {
+		highs[i], lows[i], closes[i] = c.High, c.Low, c.Close
+	}
+	atr := s.ti.ATRLast(highs, lows, closes, s.cfg.ATRPeriod)
+	if atr <= 0 {
+		atr = last.High - last.Low
+	}
+
+	if side == "buy" {
+		return Signal{
+			Action:     ActionBuy,
+			Side:       side,
+			Confidence: 0.65,
+			Price:      last.Close,
+			StopLoss:   last.Close - s.cfg.StopATR*atr,
+			TakeProfit: last.Close + s.cfg.TargetATR*atr,
+			Reason:     "range breakout up on volume",
+		}
+	}
+	return Signal{
+		Action:     ActionSell,
+		Side:       side,
+		Confidence: 0.65,
+		Price:      last.Close,
+		StopLoss:   last.Close + s.cfg.StopATR*atr,
+		TakeProfit: last.Close - s.cfg.TargetATR*atr,
+		Reason:     "range breakout down on volume",
+	}
+}
+
+// AnalyzeWithFeatures additionally requires order book pressure to agree with the break:
+// ImbalanceMA of at least MinImbalance toward bids for up-breaks, toward asks for down-breaks
+func (s *HighVolBreakoutStrategy) AnalyzeWithFeatures(f features.MarketFeatures, candles []delta.Candle) Signal {
+	sig := s.Analyze(f, candles)
+	if sig.Action == ActionNone {
+		return sig
+	}
+
+	pressure := f.ImbalanceMA
+	if sig.Side == "sell" {
+		pressure = -pressure
+	}
+	if pressure < s.cfg.MinImbalance {
+		return Signal{Action: ActionNone, Reason: "order book imbalance opposes breakout"}
+	}
+	sig.Reason += " with order book confirmation"
+	return sig
+}
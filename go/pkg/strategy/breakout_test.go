@@ -0,0 +1,77 @@
+package strategy
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/kasyap/delta-go/go/pkg/delta"
+	"github.com/kasyap/delta-go/go/pkg/features"
+)
+
+// breakoutCandles builds a flat 100-101 range and ends with a high-volume, full-bodied bar
+// closing beyond it (up or down)
+func breakoutCandles(up bool) []delta.Candle {
+	candles := make([]delta.Candle, 0, 25)
+	for i := 0; i < 24; i++ {
+		candles = append(candles, delta.Candle{Time: int64(i * 300), Open: 100.5, High: 101, Low: 100, Close: 100.5, Volume: 100})
+	}
+	last := delta.Candle{Time: 24 * 300, Open: 100.8, High: 103.2, Low: 100.7, Close: 103, Volume: 300}
+	if !up {
+		last = delta.Candle{Time: 24 * 300, Open: 100.2, High: 100.3, Low: 97.8, Close: 98, Volume: 300}
+	}
+	return append(candles, last)
+}
+
+func TestHighVolBreakout_OBIConfirmation(t *testing.T) {
+	s := NewHighVolBreakoutStrategy(DefaultBreakoutConfig())
+
+	tests := []struct {
+		name        string
+		up          bool
+		imbalanceMA float64
+		want        SignalAction
+	}{
+		{"up-break with bid-heavy book", true, 0.3, ActionBuy},
+		{"up-break with ask-heavy book", true, -0.3, ActionNone},
+		{"up-break with neutral book", true, 0.02, ActionNone},
+		{"down-break with ask-heavy book", false, -0.3, ActionSell},
+		{"down-break with bid-heavy book", false, 0.3, ActionNone},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			candles := breakoutCandles(tt.up)
+			f := features.MarketFeatures{Symbol: "BTCUSD", ImbalanceMA: tt.imbalanceMA}
+
+			// Valid breakout on candles alone, regardless of the book
+			if sig := s.Analyze(f, candles); sig.Action == ActionNone {
+				t.Fatalf("Analyze() = none (%s), want a breakout", sig.Reason)
+			}
+			if sig := s.AnalyzeWithFeatures(f, candles); sig.Action != tt.want {
+				t.Errorf("AnalyzeWithFeatures() = %v (%s), want %v", sig.Action, sig.Reason, tt.want)
+			}
+		})
+	}
+}
+
+func TestHighVolBreakout_RequiresVolume(t *testing.T) {
+	s := NewHighVolBreakoutStrategy(DefaultBreakoutConfig())
+	candles := breakoutCandles(true)
+	candles[len(candles)-1].Volume = 110
+
+	if sig := s.Analyze(features.MarketFeatures{}, candles); sig.Action != ActionNone {
+		t.Errorf("expected low-volume breakout to be ignored, got %v", sig.Action)
+	}
+}
+
+func TestHighVolBreakout_ReportsInsufficientHistory(t *testing.T) {
+	s := NewHighVolBreakoutStrategy(DefaultBreakoutConfig())
+	sig := s.Analyze(features.MarketFeatures{}, breakoutCandles(true)[:10])
+
+	var herr *InsufficientHistoryError
+	if !errors.Is(sig.Err, ErrInsufficientHistory) || !errors.As(sig.Err, &herr) {
+		t.Fatalf("Analyze() err = %v, want an InsufficientHistoryError", sig.Err)
+	}
+	if herr.Required != 21 || herr.Available != 10 {
+		t.Errorf("need %d, have %d; want need 21, have 10", herr.Required, herr.Available)
+	}
+}
@@ -0,0 +1,53 @@
+package strategy
+
+import (
+	"sync"
+	"time"
+)
+
+// Clock supplies the current time to strategies with time-based logic, so holding periods
+// follow simulated time in a backtest and wall-clock time live
+type Clock interface {
+	Now() time.Time
+}
+
+// ClockSetter is implemented by strategies that read a Clock
+type ClockSetter interface {
+	SetClock(c Clock)
+}
+
+// RealClock is the wall clock
+type RealClock struct{}
+
+func (RealClock) Now() time.Time { return time.Now() }
+
+// SimClock is a manually advanced clock for backtests and tests
+type SimClock struct {
+	mu  sync.RWMutex
+	now time.Time
+}
+
+// NewSimClock creates a simulated clock starting at start
+func NewSimClock(start time.Time) *SimClock {
+	return &SimClock{now: start}
+}
+
+func (c *SimClock) Now() time.Time {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.now
+}
+
+// Set moves the clock to t
+func (c *SimClock) Set(t time.Time) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.now = t
+}
+
+// Advance moves the clock forward by d
+func (c *SimClock) Advance(d time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.now = c.now.Add(d)
+}
@@ -9,21 +9,24 @@ type DriverSelector struct {
 	scalper       *FeeAwareScalper
 	fundingArb    *FundingArbitrageStrategy
 	gridTrader    *GridTradingStrategy
+	breakout      *HighVolBreakoutStrategy
 	selector      *StrategySelector
 	featureEngine *features.Engine
 }
 
 type DriverSelectorConfig struct {
-	ScalperConfig ScalperConfig
-	FundingConfig FundingArbitrageConfig
-	GridConfig    GridConfig
+	ScalperConfig  ScalperConfig
+	FundingConfig  FundingArbitrageConfig
+	GridConfig     GridConfig
+	BreakoutConfig BreakoutConfig // Zero value leaves breakouts disabled
 }
 
 func DefaultDriverSelectorConfig() DriverSelectorConfig {
 	return DriverSelectorConfig{
-		ScalperConfig: DefaultScalperConfig(),
-		FundingConfig: DefaultFundingArbitrageConfig(),
-		GridConfig:    DefaultGridConfig(),
+		ScalperConfig:  DefaultScalperConfig(),
+		FundingConfig:  DefaultFundingArbitrageConfig(),
+		GridConfig:     DefaultGridConfig(),
+		BreakoutConfig: DefaultBreakoutConfig(),
 	}
 }
 
@@ -32,12 +35,14 @@ func NewDriverSelector(cfg DriverSelectorConfig) *DriverSelector {
 	scalper := NewFeeAwareScalper(cfg.ScalperConfig, engine)
 	fundingArb := NewFundingArbitrageStrategy(cfg.FundingConfig)
 	gridTrader := NewGridTradingStrategy(cfg.GridConfig, "")
+	breakout := NewHighVolBreakoutStrategy(cfg.BreakoutConfig)
 
 	return &DriverSelector{
 		scalper:       scalper,
 		fundingArb:    fundingArb,
 		gridTrader:    gridTrader,
-		selector:      NewStrategySelector(scalper, fundingArb, gridTrader),
+		breakout:      breakout,
+		selector:      NewStrategySelector(scalper, fundingArb, gridTrader, breakout),
 		featureEngine: engine,
 	}
 }
@@ -56,6 +61,23 @@ func (d *DriverSelector) SelectStrategy(f features.MarketFeatures, candles []del
 	}, signal
 }
 
+// RequiredHistory returns the most candles any of the driver strategies needs, at least
+// DefaultHistory
+func (d *DriverSelector) RequiredHistory() int {
+	n := DefaultHistory
+	for _, s := range []Strategy{d.scalper, d.fundingArb, d.gridTrader, d.breakout} {
+		if h := RequiredHistory(s); h > n {
+			n = h
+		}
+	}
+	return n
+}
+
+// SetClock installs c on the strategies with time-based logic
+func (d *DriverSelector) SetClock(c Clock) {
+	d.selector.SetClock(c)
+}
+
 func (d *DriverSelector) GetScalper() *FeeAwareScalper {
 	return d.scalper
 }
@@ -68,6 +90,10 @@ func (d *DriverSelector) GetGridTrader() *GridTradingStrategy {
 	return d.gridTrader
 }
 
+func (d *DriverSelector) GetBreakout() *HighVolBreakoutStrategy {
+	return d.breakout
+}
+
 // SelectedStrategy represents the chosen strategy for a symbol
 type SelectedStrategy struct {
 	Name           string
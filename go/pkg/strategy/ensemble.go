@@ -0,0 +1,132 @@
+package strategy
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/kasyap/delta-go/go/pkg/delta"
+	"github.com/kasyap/delta-go/go/pkg/features"
+)
+
+// EnsembleStrategy runs several sub-strategies and only trades when a quorum agrees on direction
+type EnsembleStrategy struct {
+	members      []Strategy
+	minAgreement int
+}
+
+// NewEnsembleStrategy creates an ensemble that needs minAgreement members to vote the same side.
+// minAgreement is clamped to [1, len(members)].
+func NewEnsembleStrategy(members []Strategy, minAgreement int) *EnsembleStrategy {
+	if minAgreement < 1 {
+		minAgreement = 1
+	}
+	if minAgreement > len(members) {
+		minAgreement = len(members)
+	}
+	return &EnsembleStrategy{
+		members:      members,
+		minAgreement: minAgreement,
+	}
+}
+
+func (e *EnsembleStrategy) Name() string {
+	return "ensemble"
+}
+
+// Analyze collects a vote from every member and blends the agreeing signals
+func (e *EnsembleStrategy) Analyze(f features.MarketFeatures, candles []delta.Candle) Signal {
+	var buys, sells []namedSignal
+	for _, m := range e.members {
+		sig := m.Analyze(f, candles)
+		switch sig.Action {
+		case ActionBuy:
+			buys = append(buys, namedSignal{m.Name(), sig})
+		case ActionSell:
+			sells = append(sells, namedSignal{m.Name(), sig})
+		}
+	}
+
+	// A side wins only with a quorum and more votes than the other side
+	switch {
+	case len(buys) >= e.minAgreement && len(buys) > len(sells):
+		return e.blend(ActionBuy, "buy", buys)
+	case len(sells) >= e.minAgreement && len(sells) > len(buys):
+		return e.blend(ActionSell, "sell", sells)
+	}
+
+	return Signal{
+		Action: ActionNone,
+		Reason: fmt.Sprintf("ensemble: no quorum (buy %d, sell %d, need %d of %d)", len(buys), len(sells), e.minAgreement, len(e.members)),
+	}
+}
+
+type namedSignal struct {
+	name string
+	sig  Signal
+}
+
+// blend averages confidence and keeps the most conservative stop and target.
+// For longs that is the highest stop and lowest target; for shorts the reverse.
+func (e *EnsembleStrategy) blend(action SignalAction, side string, votes []namedSignal) Signal {
+	out := Signal{Action: action, Side: side}
+	names := make([]string, 0, len(votes))
+
+	var confSum float64
+	for _, v := range votes {
+		names = append(names, v.name)
+		confSum += v.sig.Confidence
+		if out.Price == 0 {
+			out.Price = v.sig.Price
+		}
+
+		if v.sig.StopLoss > 0 {
+			if out.StopLoss == 0 ||
+				(side == "buy" && v.sig.StopLoss > out.StopLoss) ||
+				(side == "sell" && v.sig.StopLoss < out.StopLoss) {
+				out.StopLoss = v.sig.StopLoss
+			}
+		}
+		if v.sig.TakeProfit > 0 {
+			if out.TakeProfit == 0 ||
+				(side == "buy" && v.sig.TakeProfit < out.TakeProfit) ||
+				(side == "sell" && v.sig.TakeProfit > out.TakeProfit) {
+				out.TakeProfit = v.sig.TakeProfit
+			}
+		}
+	}
+
+	out.Confidence = confSum / float64(len(votes))
+	out.Reason = fmt.Sprintf("ensemble %d/%d %s: %s", len(votes), len(e.members), side, strings.Join(names, ", "))
+	return out
+}
+
+// ParamKeys lists the keys UpdateParams routes to the members
+func (e *EnsembleStrategy) ParamKeys() []string {
+	return routedParamKeys(e.members)
+}
+
+// UpdateParams routes params to the members: "<member name>.<key>" reaches only the named
+// member, an unprefixed key only the one member that reads it
+func (e *EnsembleStrategy) UpdateParams(params map[string]interface{}) {
+	for i, p := range routeParams(params, e.members) {
+		e.members[i].UpdateParams(p)
+	}
+}
+
+// SetClock installs c on every member that reads a Clock
+func (e *EnsembleStrategy) SetClock(c Clock) {
+	for _, m := range e.members {
+		if cs, ok := m.(ClockSetter); ok {
+			cs.SetClock(c)
+		}
+	}
+}
+
+// Reset clears the state of every member that implements Resetter
+func (e *EnsembleStrategy) Reset() {
+	for _, m := range e.members {
+		if r, ok := m.(Resetter); ok {
+			r.Reset()
+		}
+	}
+}
@@ -0,0 +1,103 @@
+package strategy
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"github.com/kasyap/delta-go/go/pkg/delta"
+	"github.com/kasyap/delta-go/go/pkg/features"
+)
+
+// fixedStrategy always returns the same signal
+type fixedStrategy struct {
+	name   string
+	signal Signal
+	resets int
+}
+
+func (s *fixedStrategy) Name() string { return s.name }
+func (s *fixedStrategy) Analyze(features.MarketFeatures, []delta.Candle) Signal {
+	return s.signal
+}
+func (s *fixedStrategy) UpdateParams(map[string]interface{}) {}
+func (s *fixedStrategy) Reset()                              { s.resets++ }
+
+func TestEnsembleStrategy_QuorumBuy(t *testing.T) {
+	e := NewEnsembleStrategy([]Strategy{
+		&fixedStrategy{name: "a", signal: Signal{Action: ActionBuy, Side: "buy", Confidence: 0.8, Price: 100, StopLoss: 95, TakeProfit: 110}},
+		&fixedStrategy{name: "b", signal: Signal{Action: ActionBuy, Side: "buy", Confidence: 0.6, Price: 100, StopLoss: 97, TakeProfit: 120}},
+		&fixedStrategy{name: "c", signal: Signal{Action: ActionNone}},
+	}, 2)
+
+	sig := e.Analyze(features.MarketFeatures{}, nil)
+	if sig.Action != ActionBuy || sig.Side != "buy" {
+		t.Fatalf("Action = %s/%s, want buy", sig.Action, sig.Side)
+	}
+	if math.Abs(sig.Confidence-0.7) > 1e-9 {
+		t.Errorf("Confidence = %.3f, want 0.7", sig.Confidence)
+	}
+	if sig.StopLoss != 97 {
+		t.Errorf("StopLoss = %.0f, want tightest long stop 97", sig.StopLoss)
+	}
+	if sig.TakeProfit != 110 {
+		t.Errorf("TakeProfit = %.0f, want nearest long target 110", sig.TakeProfit)
+	}
+}
+
+func TestEnsembleStrategy_ConservativeShortLevels(t *testing.T) {
+	e := NewEnsembleStrategy([]Strategy{
+		&fixedStrategy{name: "a", signal: Signal{Action: ActionSell, Side: "sell", Confidence: 0.9, StopLoss: 105, TakeProfit: 90}},
+		&fixedStrategy{name: "b", signal: Signal{Action: ActionSell, Side: "sell", Confidence: 0.7, StopLoss: 103, TakeProfit: 80}},
+	}, 2)
+
+	sig := e.Analyze(features.MarketFeatures{}, nil)
+	if sig.Action != ActionSell {
+		t.Fatalf("Action = %s, want sell", sig.Action)
+	}
+	if sig.StopLoss != 103 || sig.TakeProfit != 90 {
+		t.Errorf("levels = %.0f/%.0f, want 103/90", sig.StopLoss, sig.TakeProfit)
+	}
+}
+
+func TestEnsembleStrategy_SplitVoteIsNone(t *testing.T) {
+	e := NewEnsembleStrategy([]Strategy{
+		&fixedStrategy{name: "a", signal: Signal{Action: ActionBuy, Side: "buy", Confidence: 0.9}},
+		&fixedStrategy{name: "b", signal: Signal{Action: ActionSell, Side: "sell", Confidence: 0.9}},
+		&fixedStrategy{name: "c", signal: Signal{Action: ActionNone}},
+	}, 2)
+
+	if sig := e.Analyze(features.MarketFeatures{}, nil); sig.Action != ActionNone {
+		t.Errorf("Action = %s, want none on a split vote", sig.Action)
+	}
+
+	// With a quorum of one, a tie still must not pick a side
+	e = NewEnsembleStrategy(e.members, 1)
+	if sig := e.Analyze(features.MarketFeatures{}, nil); sig.Action != ActionNone {
+		t.Errorf("Action = %s, want none on a tied vote", sig.Action)
+	}
+}
+
+func TestEnsembleStrategy_ResetsMembers(t *testing.T) {
+	a := &fixedStrategy{name: "a"}
+	b := &fixedStrategy{name: "b"}
+	NewEnsembleStrategy([]Strategy{a, b}, 2).Reset()
+	if a.resets != 1 || b.resets != 1 {
+		t.Errorf("resets = %d/%d, want 1/1", a.resets, b.resets)
+	}
+}
+
+func TestEnsembleStrategy_SetsMemberClocks(t *testing.T) {
+	funding := NewFundingArbitrageStrategy(DefaultFundingArbitrageConfig())
+	e := NewEnsembleStrategy([]Strategy{&fixedStrategy{name: "a"}, funding}, 1)
+
+	clock := NewSimClock(time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC))
+	e.SetClock(clock)
+	funding.RecordEntry("BTCUSD", "sell", 0.20, 0)
+
+	clock.Advance(25 * time.Hour)
+	sig := funding.Analyze(features.MarketFeatures{Symbol: "BTCUSD", BasisAnnualized: 0.20}, nil)
+	if sig.Action != ActionClose {
+		t.Errorf("expected max-holding close on the ensemble's clock, got %v (%s)", sig.Action, sig.Reason)
+	}
+}
@@ -13,6 +13,9 @@ type FundingArbitrageConfig struct {
 	ExitThresholdAnnualized  float64 // 5% annualized
 	MaxHoldingHours          float64 // 24h timeout
 	MaxPositionPct           float64 // 33% of portfolio
+	PriceStopPct             float64 // Close on an adverse price move of this % (0 = off)
+	PriceTargetPct           float64 // Close on a favorable price move of this % (0 = off)
+	PriceSource              string  // delta.PriceSourceMark or PriceSourceLast for the price exits (empty = mark)
 	Enabled                  bool
 }
 
@@ -27,15 +30,17 @@ func DefaultFundingArbitrageConfig() FundingArbitrageConfig {
 }
 
 type FundingPosition struct {
-	Symbol    string
-	Side      string
-	EntryTime time.Time
-	EntryRate float64
+	Symbol     string
+	Side       string
+	EntryTime  time.Time
+	EntryRate  float64
+	EntryPrice float64 // 0 if unknown; disables the price stop/target
 }
 
 type FundingArbitrageStrategy struct {
 	cfg       FundingArbitrageConfig
 	positions map[string]*FundingPosition
+	clock     Clock
 }
 
 func NewFundingArbitrageStrategy(cfg FundingArbitrageConfig) *FundingArbitrageStrategy {
@@ -45,13 +50,24 @@ func NewFundingArbitrageStrategy(cfg FundingArbitrageConfig) *FundingArbitrageSt
 	return &FundingArbitrageStrategy{
 		cfg:       cfg,
 		positions: make(map[string]*FundingPosition),
+		clock:     RealClock{},
 	}
 }
 
+// SetClock sets the clock used for entry times and the max holding period
+func (s *FundingArbitrageStrategy) SetClock(c Clock) {
+	s.clock = c
+}
+
 func (s *FundingArbitrageStrategy) Name() string {
 	return "funding_arbitrage"
 }
 
+// Reset forgets all tracked funding positions
+func (s *FundingArbitrageStrategy) Reset() {
+	s.positions = make(map[string]*FundingPosition)
+}
+
 func (s *FundingArbitrageStrategy) Analyze(f features.MarketFeatures, candles []delta.Candle) Signal {
 	if !s.cfg.Enabled {
 		return Signal{Action: ActionNone, Reason: "funding arb disabled"}
@@ -61,6 +77,10 @@ func (s *FundingArbitrageStrategy) Analyze(f features.MarketFeatures, candles []
 
 	// Check existing position
 	if pos, exists := s.positions[f.Symbol]; exists {
+		// The perp leg is unhedged, so cap price risk regardless of funding
+		if sig, hit := s.checkPriceExit(pos, f); hit {
+			return sig
+		}
 		if abs(fundingAnn) < s.cfg.ExitThresholdAnnualized {
 			return Signal{
 				Action:     ActionClose,
@@ -69,7 +89,7 @@ func (s *FundingArbitrageStrategy) Analyze(f features.MarketFeatures, candles []
 				Reason:     "funding dropped below exit threshold",
 			}
 		}
-		if time.Since(pos.EntryTime).Hours() > s.cfg.MaxHoldingHours {
+		if s.clock.Now().Sub(pos.EntryTime).Hours() > s.cfg.MaxHoldingHours {
 			return Signal{
 				Action:     ActionClose,
 				Side:       oppositeSide(pos.Side),
@@ -99,24 +119,65 @@ func (s *FundingArbitrageStrategy) Analyze(f features.MarketFeatures, candles []
 	return Signal{Action: ActionNone, Reason: "funding below threshold"}
 }
 
-func (s *FundingArbitrageStrategy) UpdateParams(params map[string]interface{}) {
-	if v, ok := params["entry_threshold"].(float64); ok {
-		s.cfg.EntryThresholdAnnualized = v
+// checkPriceExit returns a close signal if price moved PriceStopPct against or
+// PriceTargetPct in favor of the position since entry
+func (s *FundingArbitrageStrategy) checkPriceExit(pos *FundingPosition, f features.MarketFeatures) (Signal, bool) {
+	price := f.ReferencePrice(s.cfg.PriceSource)
+	if pos.EntryPrice <= 0 || price <= 0 {
+		return Signal{}, false
+	}
+
+	movePct := (price - pos.EntryPrice) / pos.EntryPrice * 100
+	if pos.Side == "sell" {
+		movePct = -movePct
 	}
-	if v, ok := params["exit_threshold"].(float64); ok {
-		s.cfg.ExitThresholdAnnualized = v
+
+	if s.cfg.PriceStopPct > 0 && movePct <= -s.cfg.PriceStopPct {
+		return Signal{
+			Action:     ActionClose,
+			Side:       oppositeSide(pos.Side),
+			Confidence: 0.9,
+			Price:      price,
+			Reason:     "funding position price stop hit",
+		}, true
 	}
-	if v, ok := params["enabled"].(bool); ok {
-		s.cfg.Enabled = v
+	if s.cfg.PriceTargetPct > 0 && movePct >= s.cfg.PriceTargetPct {
+		return Signal{
+			Action:     ActionClose,
+			Side:       oppositeSide(pos.Side),
+			Confidence: 0.8,
+			Price:      price,
+			Reason:     "funding position price target hit",
+		}, true
 	}
+	return Signal{}, false
+}
+
+// ParamKeys lists the keys UpdateParams reads
+func (s *FundingArbitrageStrategy) ParamKeys() []string {
+	return []string{"entry_threshold", "exit_threshold", "max_holding_hours", "max_position_pct",
+		"price_stop_pct", "price_target_pct", "enabled"}
+}
+
+// UpdateParams keys: entry_threshold, exit_threshold, max_holding_hours, max_position_pct,
+// price_stop_pct, price_target_pct, enabled. Unknown keys and mistyped values are ignored.
+func (s *FundingArbitrageStrategy) UpdateParams(params map[string]interface{}) {
+	setFloatParam(params, "entry_threshold", &s.cfg.EntryThresholdAnnualized)
+	setFloatParam(params, "exit_threshold", &s.cfg.ExitThresholdAnnualized)
+	setFloatParam(params, "max_holding_hours", &s.cfg.MaxHoldingHours)
+	setFloatParam(params, "max_position_pct", &s.cfg.MaxPositionPct)
+	setFloatParam(params, "price_stop_pct", &s.cfg.PriceStopPct)
+	setFloatParam(params, "price_target_pct", &s.cfg.PriceTargetPct)
+	setBoolParam(params, "enabled", &s.cfg.Enabled)
 }
 
-func (s *FundingArbitrageStrategy) RecordEntry(symbol, side string, rate float64) {
+func (s *FundingArbitrageStrategy) RecordEntry(symbol, side string, rate, entryPrice float64) {
 	s.positions[symbol] = &FundingPosition{
-		Symbol:    symbol,
-		Side:      side,
-		EntryTime: time.Now(),
-		EntryRate: rate,
+		Symbol:     symbol,
+		Side:       side,
+		EntryTime:  s.clock.Now(),
+		EntryRate:  rate,
+		EntryPrice: entryPrice,
 	}
 }
 
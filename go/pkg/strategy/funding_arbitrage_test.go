@@ -4,6 +4,7 @@ import (
 	"testing"
 	"time"
 
+	"github.com/kasyap/delta-go/go/pkg/delta"
 	"github.com/kasyap/delta-go/go/pkg/features"
 )
 
@@ -42,7 +43,7 @@ func TestFundingArbitrage_Analyze(t *testing.T) {
 	}
 
 	// 4. Position Exit on Converged Funding
-	s.RecordEntry(symbol, "buy", -0.20)
+	s.RecordEntry(symbol, "buy", -0.20, 0)
 	f.BasisAnnualized = -0.04 // -4% (converged below 5% threshold)
 	sig = s.Analyze(f, nil)
 	if sig.Action != ActionClose || sig.Side != "sell" {
@@ -50,7 +51,7 @@ func TestFundingArbitrage_Analyze(t *testing.T) {
 	}
 
 	// 5. Position Exit on Timeout
-	s.RecordEntry(symbol, "sell", 0.20)
+	s.RecordEntry(symbol, "sell", 0.20, 0)
 	s.positions[symbol].EntryTime = time.Now().Add(-25 * time.Hour)
 	f.BasisAnnualized = 0.10 // Still above 5% exit, but timeout applies
 	sig = s.Analyze(f, nil)
@@ -58,3 +59,90 @@ func TestFundingArbitrage_Analyze(t *testing.T) {
 		t.Errorf("Expected ActionClose/buy (closing short) due to timeout, got %v/%v", sig.Action, sig.Side)
 	}
 }
+
+func TestFundingArbitrage_PriceStopIgnoresFunding(t *testing.T) {
+	cfg := DefaultFundingArbitrageConfig()
+	cfg.PriceStopPct = 2
+	cfg.PriceTargetPct = 5
+	s := NewFundingArbitrageStrategy(cfg)
+
+	// Short to earn positive funding; funding stays well above the exit threshold
+	s.RecordEntry("BTCUSD", "sell", 0.30, 50000)
+	f := features.MarketFeatures{Symbol: "BTCUSD", BasisAnnualized: 0.30, MarkPrice: 50500}
+
+	if sig := s.Analyze(f, nil); sig.Action != ActionNone {
+		t.Fatalf("1%% adverse move: Action = %s, want none", sig.Action)
+	}
+
+	f.MarkPrice = 51100 // +2.2% against the short
+	sig := s.Analyze(f, nil)
+	if sig.Action != ActionClose || sig.Side != "buy" {
+		t.Fatalf("Expected ActionClose/buy on price stop, got %v/%v (%s)", sig.Action, sig.Side, sig.Reason)
+	}
+	if sig.Reason != "funding position price stop hit" {
+		t.Errorf("Reason = %q, want price stop", sig.Reason)
+	}
+}
+
+func TestFundingArbitrage_PriceStopSource(t *testing.T) {
+	// Last traded price spikes through the stop while mark stays inside it
+	f := features.MarketFeatures{Symbol: "BTCUSD", BasisAnnualized: 0.30, MarkPrice: 50500, SpotPrice: 51200}
+
+	for _, tc := range []struct {
+		source string
+		want   SignalAction
+	}{
+		{delta.PriceSourceMark, ActionNone},
+		{delta.PriceSourceLast, ActionClose},
+	} {
+		cfg := DefaultFundingArbitrageConfig()
+		cfg.PriceStopPct = 2
+		cfg.PriceSource = tc.source
+		s := NewFundingArbitrageStrategy(cfg)
+		s.RecordEntry("BTCUSD", "sell", 0.30, 50000)
+
+		if sig := s.Analyze(f, nil); sig.Action != tc.want {
+			t.Errorf("source %s: Action = %s, want %s", tc.source, sig.Action, tc.want)
+		}
+	}
+}
+
+func TestFundingArbitrage_PriceTarget(t *testing.T) {
+	cfg := DefaultFundingArbitrageConfig()
+	cfg.PriceTargetPct = 5
+	s := NewFundingArbitrageStrategy(cfg)
+
+	s.RecordEntry("BTCUSD", "buy", -0.30, 50000)
+	f := features.MarketFeatures{Symbol: "BTCUSD", BasisAnnualized: -0.30, MarkPrice: 40000}
+	// No stop configured: a large adverse move keeps holding
+	if sig := s.Analyze(f, nil); sig.Action != ActionNone {
+		t.Errorf("stop disabled: Action = %s, want none", sig.Action)
+	}
+
+	f.MarkPrice = 52600 // +5.2% for the long
+	if sig := s.Analyze(f, nil); sig.Action != ActionClose || sig.Side != "sell" {
+		t.Errorf("Expected ActionClose/sell on price target, got %v/%v", sig.Action, sig.Side)
+	}
+}
+
+func TestFundingArbitrage_MaxHoldingUsesClock(t *testing.T) {
+	cfg := DefaultFundingArbitrageConfig()
+	cfg.MaxHoldingHours = 24
+	s := NewFundingArbitrageStrategy(cfg)
+
+	clock := NewSimClock(time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC))
+	s.SetClock(clock)
+	s.RecordEntry("BTCUSD", "sell", 0.20, 0)
+
+	f := features.MarketFeatures{Symbol: "BTCUSD", BasisAnnualized: 0.20}
+	clock.Advance(23 * time.Hour)
+	if sig := s.Analyze(f, nil); sig.Action != ActionNone {
+		t.Fatalf("expected hold at 23h of simulated time, got %v (%s)", sig.Action, sig.Reason)
+	}
+
+	clock.Advance(2 * time.Hour)
+	sig := s.Analyze(f, nil)
+	if sig.Action != ActionClose || sig.Reason != "max holding time exceeded" {
+		t.Errorf("expected max-holding close at 25h, got %v (%s)", sig.Action, sig.Reason)
+	}
+}
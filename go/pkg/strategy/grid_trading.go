@@ -8,12 +8,22 @@ import (
 	"github.com/kasyap/delta-go/go/pkg/features"
 )
 
+// Grid level spacing modes
+const (
+	GridSpacingArithmetic = "arithmetic" // Equal dollar steps
+	GridSpacingGeometric  = "geometric"  // Equal percentage steps
+)
+
 type GridConfig struct {
 	GridLevels           int     // 10 levels
 	GridRangePct         float64 // 3% each side
+	GridRangeUpPct       float64 // Range above center; 0 uses GridRangePct
+	GridRangeDownPct     float64 // Range below center; 0 uses GridRangePct
+	GridSpacing          string  // GridSpacingArithmetic (default) or GridSpacingGeometric
 	PositionSizePerLevel int     // Contracts per level
 	MaxVolatilityPct     float64 // Exit if vol > 50%
 	MinVolatilityPct     float64 // Enter if vol < 30%
+	MaxInventory         int     // Cap per symbol on net filled plus resting same-side contracts; 0 = unlimited
 	Enabled              bool
 }
 
@@ -21,6 +31,7 @@ func DefaultGridConfig() GridConfig {
 	return GridConfig{
 		GridLevels:           10,
 		GridRangePct:         3.0,
+		GridSpacing:          GridSpacingArithmetic,
 		PositionSizePerLevel: 1,
 		MaxVolatilityPct:     50.0,
 		MinVolatilityPct:     30.0,
@@ -31,7 +42,9 @@ func DefaultGridConfig() GridConfig {
 type GridLevel struct {
 	Price    float64
 	Side     string
+	Symbol   string // Symbol the level's order was placed on, set by AssignOrder
 	OrderID  int64
+	Size     int // Contracts ordered at this level, set by AssignOrder
 	IsActive bool
 }
 
@@ -41,12 +54,14 @@ type GridTradingStrategy struct {
 	IsActive    bool
 	symbol      string
 	centerPrice float64
+	inventory   map[string]int // Net filled contracts per symbol: positive long, negative short
 }
 
 func NewGridTradingStrategy(cfg GridConfig, symbol string) *GridTradingStrategy {
 	return &GridTradingStrategy{
-		cfg:    cfg,
-		symbol: symbol,
+		cfg:       cfg,
+		symbol:    symbol,
+		inventory: make(map[string]int),
 	}
 }
 
@@ -54,6 +69,14 @@ func (g *GridTradingStrategy) Name() string {
 	return "grid_trading"
 }
 
+// Reset deactivates the grid and clears its levels
+func (g *GridTradingStrategy) Reset() {
+	g.levels = nil
+	g.IsActive = false
+	g.centerPrice = 0
+	g.inventory = make(map[string]int)
+}
+
 func (g *GridTradingStrategy) Analyze(f features.MarketFeatures, candles []delta.Candle) Signal {
 	if !g.cfg.Enabled {
 		return Signal{Action: ActionNone, Reason: "grid disabled"}
@@ -82,7 +105,12 @@ func (g *GridTradingStrategy) Analyze(f features.MarketFeatures, candles []delta
 	// Recenter Logic (Trend Following)
 	// If price drifts near the edge of the grid, reset to follow the trend
 	driftPct := math.Abs(midPrice-g.centerPrice) / g.centerPrice * 100
-	if driftPct > g.cfg.GridRangePct*0.8 {
+	downPct, upPct := g.rangePcts()
+	edgePct := upPct
+	if midPrice < g.centerPrice {
+		edgePct = downPct
+	}
+	if driftPct > edgePct*0.8 {
 		g.IsActive = false
 		return Signal{Action: ActionClose, Reason: "grid recentering"}
 	}
@@ -122,14 +150,22 @@ func (g *GridTradingStrategy) Analyze(f features.MarketFeatures, candles []delta
 	return Signal{Action: ActionNone, Reason: "grid monitoring"}
 }
 
+// CalculateLevels spreads GridLevels prices from the bottom to the top of the range around
+// midPrice, in equal dollar steps or, with geometric spacing, equal percentage steps
 func (g *GridTradingStrategy) CalculateLevels(midPrice float64) []GridLevel {
 	levels := make([]GridLevel, g.cfg.GridLevels)
-	rangeAmt := midPrice * (g.cfg.GridRangePct / 100)
-	step := (rangeAmt * 2) / float64(g.cfg.GridLevels-1)
+	downPct, upPct := g.rangePcts()
+	lower := midPrice * (1 - downPct/100)
+	upper := midPrice * (1 + upPct/100)
+	steps := float64(g.cfg.GridLevels - 1)
 
-	startPrice := midPrice - rangeAmt
+	step := (upper - lower) / steps
+	ratio := math.Pow(upper/lower, 1/steps)
 	for i := 0; i < g.cfg.GridLevels; i++ {
-		price := startPrice + (float64(i) * step)
+		price := lower + float64(i)*step
+		if g.cfg.GridSpacing == GridSpacingGeometric {
+			price = lower * math.Pow(ratio, float64(i))
+		}
 		side := "buy"
 		if price > midPrice {
 			side = "sell"
@@ -139,16 +175,38 @@ func (g *GridTradingStrategy) CalculateLevels(midPrice float64) []GridLevel {
 	return levels
 }
 
-func (g *GridTradingStrategy) UpdateParams(params map[string]interface{}) {
-	if v, ok := params["grid_levels"].(int); ok {
-		g.cfg.GridLevels = v
-	}
-	if v, ok := params["grid_range"].(float64); ok {
-		g.cfg.GridRangePct = v
+// rangePcts returns the grid's range below and above center, in percent
+func (g *GridTradingStrategy) rangePcts() (down, up float64) {
+	down, up = g.cfg.GridRangeDownPct, g.cfg.GridRangeUpPct
+	if down <= 0 {
+		down = g.cfg.GridRangePct
 	}
-	if v, ok := params["enabled"].(bool); ok {
-		g.cfg.Enabled = v
+	if up <= 0 {
+		up = g.cfg.GridRangePct
 	}
+	return down, up
+}
+
+// ParamKeys lists the keys UpdateParams reads
+func (g *GridTradingStrategy) ParamKeys() []string {
+	return []string{"grid_levels", "grid_range", "grid_range_up", "grid_range_down", "grid_spacing",
+		"position_size_per_level", "max_volatility_pct", "min_volatility_pct", "max_inventory", "enabled"}
+}
+
+// UpdateParams keys: grid_levels, grid_range, grid_range_up, grid_range_down, grid_spacing,
+// position_size_per_level, max_volatility_pct, min_volatility_pct, max_inventory, enabled.
+// Unknown keys and mistyped values are ignored.
+func (g *GridTradingStrategy) UpdateParams(params map[string]interface{}) {
+	setIntParam(params, "grid_levels", &g.cfg.GridLevels)
+	setFloatParam(params, "grid_range", &g.cfg.GridRangePct)
+	setFloatParam(params, "grid_range_up", &g.cfg.GridRangeUpPct)
+	setFloatParam(params, "grid_range_down", &g.cfg.GridRangeDownPct)
+	setStringParam(params, "grid_spacing", &g.cfg.GridSpacing)
+	setIntParam(params, "position_size_per_level", &g.cfg.PositionSizePerLevel)
+	setFloatParam(params, "max_volatility_pct", &g.cfg.MaxVolatilityPct)
+	setFloatParam(params, "min_volatility_pct", &g.cfg.MinVolatilityPct)
+	setIntParam(params, "max_inventory", &g.cfg.MaxInventory)
+	setBoolParam(params, "enabled", &g.cfg.Enabled)
 }
 
 func (g *GridTradingStrategy) IsEnabled() bool {
@@ -159,19 +217,95 @@ func (g *GridTradingStrategy) GetLevels() []GridLevel {
 	return g.levels
 }
 
+// AssignOrder records the exchange order placed on symbol for level i
+func (g *GridTradingStrategy) AssignOrder(i int, symbol string, orderID int64, size int) {
+	if i < 0 || i >= len(g.levels) {
+		return
+	}
+	g.levels[i].Symbol = symbol
+	g.levels[i].OrderID = orderID
+	g.levels[i].Size = size
+}
+
+// OnFill deactivates the filled level and adds its size to its symbol's inventory
 func (g *GridTradingStrategy) OnFill(orderID int64) Signal {
 	for i, level := range g.levels {
 		if level.OrderID == orderID {
 			// Level filled, place counter order
 			g.levels[i].IsActive = false
+			g.RecordFill(level.Symbol, level.Side, level.Size)
 
 			// Logic to place counter order at adjacent level
 			// This would be handled by a higher-level controller usually
 			return Signal{
 				Action: ActionNone, // Placeholder
-				Reason: fmt.Sprintf("level at %f filled", level.Price),
+				Reason: fmt.Sprintf("level at %f filled, inventory %d", level.Price, g.inventory[level.Symbol]),
 			}
 		}
 	}
 	return Signal{Action: ActionNone}
 }
+
+// ReleaseOrder frees the level of a cancelled order so it no longer counts as resting. The
+// filled part of a partially filled order is added to the inventory.
+func (g *GridTradingStrategy) ReleaseOrder(orderID int64, filled int) {
+	for i, level := range g.levels {
+		if level.OrderID != orderID {
+			continue
+		}
+		if filled > 0 {
+			g.RecordFill(level.Symbol, level.Side, filled)
+		}
+		g.levels[i].OrderID = 0
+		g.levels[i].Size = 0
+		return
+	}
+}
+
+// RecordFill adds a filled grid order to symbol's net inventory. A zero size counts as
+// PositionSizePerLevel.
+func (g *GridTradingStrategy) RecordFill(symbol, side string, size int) {
+	if size <= 0 {
+		size = g.cfg.PositionSizePerLevel
+	}
+	if side == "sell" {
+		size = -size
+	}
+	g.inventory[symbol] += size
+}
+
+// Inventory returns symbol's net filled contracts, positive when long
+func (g *GridTradingStrategy) Inventory(symbol string) int {
+	return g.inventory[symbol]
+}
+
+// restingSize is the size of symbol's placed, unfilled orders on side
+func (g *GridTradingStrategy) restingSize(symbol, side string) int {
+	total := 0
+	for _, level := range g.levels {
+		if level.IsActive && level.OrderID != 0 && level.Symbol == symbol && level.Side == side {
+			total += level.Size
+		}
+	}
+	return total
+}
+
+// CanPlace reports whether an order of size on side keeps symbol's exposure within
+// MaxInventory, counting resting same-side orders as if they had filled. Orders that
+// shrink the inventory are always allowed.
+func (g *GridTradingStrategy) CanPlace(symbol, side string, size int) bool {
+	if g.cfg.MaxInventory <= 0 || g.IsReduceOnly(symbol, side) {
+		return true
+	}
+	exposure := g.inventory[symbol]
+	if exposure < 0 {
+		exposure = -exposure
+	}
+	return exposure+g.restingSize(symbol, side)+size <= g.cfg.MaxInventory
+}
+
+// IsReduceOnly reports whether an order on side would shrink symbol's current inventory
+func (g *GridTradingStrategy) IsReduceOnly(symbol, side string) bool {
+	inventory := g.inventory[symbol]
+	return (side == "sell" && inventory > 0) || (side == "buy" && inventory < 0)
+}
@@ -1,6 +1,7 @@
 package strategy
 
 import (
+	"math"
 	"testing"
 
 	"github.com/kasyap/delta-go/go/pkg/features"
@@ -31,6 +32,58 @@ func TestGridTrading_CalculateLevels(t *testing.T) {
 	}
 }
 
+func TestGridTrading_CalculateLevels_Spacing(t *testing.T) {
+	cfg := DefaultGridConfig()
+	cfg.GridLevels = 5
+	cfg.GridRangePct = 10.0
+
+	arith := NewGridTradingStrategy(cfg, "BTCUSD").CalculateLevels(100)
+	cfg.GridSpacing = GridSpacingGeometric
+	geo := NewGridTradingStrategy(cfg, "BTCUSD").CalculateLevels(100)
+
+	// Both span 90..110; arithmetic steps by $5, geometric by a constant ratio of (110/90)^(1/4)
+	ratio := math.Pow(110.0/90.0, 0.25)
+	for i := range arith {
+		wantArith := 90 + 5*float64(i)
+		wantGeo := 90 * math.Pow(ratio, float64(i))
+		if math.Abs(arith[i].Price-wantArith) > 1e-9 {
+			t.Errorf("arithmetic level %d = %f, want %f", i, arith[i].Price, wantArith)
+		}
+		if math.Abs(geo[i].Price-wantGeo) > 1e-9 {
+			t.Errorf("geometric level %d = %f, want %f", i, geo[i].Price, wantGeo)
+		}
+	}
+	if math.Abs(geo[4].Price-110) > 1e-9 {
+		t.Errorf("geometric top = %f, want 110", geo[4].Price)
+	}
+	// Geometric levels bunch below center, where a percentage is fewer dollars
+	if geo[1].Price-geo[0].Price >= arith[1].Price-arith[0].Price {
+		t.Errorf("geometric bottom step %f not tighter than arithmetic %f",
+			geo[1].Price-geo[0].Price, arith[1].Price-arith[0].Price)
+	}
+}
+
+func TestGridTrading_CalculateLevels_AsymmetricRange(t *testing.T) {
+	cfg := DefaultGridConfig()
+	cfg.GridLevels = 5
+	cfg.GridRangeDownPct = 2.0
+	cfg.GridRangeUpPct = 6.0
+
+	levels := NewGridTradingStrategy(cfg, "BTCUSD").CalculateLevels(100)
+	if math.Abs(levels[0].Price-98) > 1e-9 || math.Abs(levels[4].Price-106) > 1e-9 {
+		t.Fatalf("range = %f..%f, want 98..106", levels[0].Price, levels[4].Price)
+	}
+	buys := 0
+	for _, l := range levels {
+		if l.Side == "buy" {
+			buys++
+		}
+	}
+	if buys != 2 {
+		t.Errorf("buy levels = %d, want 2 (98, 100)", buys)
+	}
+}
+
 func TestGridTrading_Analyze_Activation(t *testing.T) {
 	cfg := DefaultGridConfig()
 	cfg.MaxVolatilityPct = 50.0
@@ -69,3 +122,72 @@ func TestGridTrading_Analyze_Activation(t *testing.T) {
 		t.Errorf("Expected ActionClose on deactivation, got %v", sig.Action)
 	}
 }
+
+func TestGridTrading_InventoryCapBlocksSameSideLevels(t *testing.T) {
+	cfg := DefaultGridConfig()
+	cfg.MaxInventory = 3
+	g := NewGridTradingStrategy(cfg, "BTCUSD")
+	g.levels = g.CalculateLevels(50000)
+
+	// Buy levels fill one after another in a falling market
+	buys := 0
+	for i, level := range g.levels {
+		if level.Side != "buy" {
+			continue
+		}
+		if !g.CanPlace("BTCUSD", "buy", 1) {
+			break
+		}
+		g.AssignOrder(i, "BTCUSD", int64(100+i), 1)
+		g.OnFill(int64(100 + i))
+		buys++
+	}
+
+	if buys != 3 || g.Inventory("BTCUSD") != 3 {
+		t.Fatalf("filled %d buys, inventory %d; want both capped at 3", buys, g.Inventory("BTCUSD"))
+	}
+	if g.CanPlace("BTCUSD", "buy", 1) {
+		t.Error("buy level allowed past the inventory cap")
+	}
+	if !g.CanPlace("BTCUSD", "sell", 1) || !g.IsReduceOnly("BTCUSD", "sell") {
+		t.Error("sell level should be allowed and reduce-only while long")
+	}
+	if g.IsReduceOnly("BTCUSD", "buy") {
+		t.Error("buy tagged reduce-only while long")
+	}
+
+	g.RecordFill("BTCUSD", "sell", 1)
+	if g.Inventory("BTCUSD") != 2 || !g.CanPlace("BTCUSD", "buy", 1) {
+		t.Errorf("after a sell fill inventory = %d, buy allowed = %v; want 2, true", g.Inventory("BTCUSD"), g.CanPlace("BTCUSD", "buy", 1))
+	}
+}
+
+func TestGridTrading_InventoryCapCountsRestingOrders(t *testing.T) {
+	cfg := DefaultGridConfig()
+	cfg.MaxInventory = 3
+	g := NewGridTradingStrategy(cfg, "")
+	g.levels = g.CalculateLevels(50000)
+
+	// A fresh grid places every buy level at once, before any fills
+	placed := 0
+	for i, level := range g.levels {
+		if level.Side == "buy" && g.CanPlace("BTCUSD", "buy", 1) {
+			g.AssignOrder(i, "BTCUSD", int64(100+i), 1)
+			placed++
+		}
+	}
+	if placed != 3 {
+		t.Fatalf("placed %d resting buys, want 3 at the cap", placed)
+	}
+
+	// Another symbol has its own inventory
+	if !g.CanPlace("ETHUSD", "buy", 1) {
+		t.Error("BTCUSD orders blocked an ETHUSD level")
+	}
+
+	// A cancelled resting order frees its room
+	g.ReleaseOrder(100, 0)
+	if !g.CanPlace("BTCUSD", "buy", 1) {
+		t.Error("buy still blocked after a resting order was cancelled")
+	}
+}
@@ -2,6 +2,7 @@ package strategy
 
 import (
 	"math"
+	"strings"
 
 	"github.com/kasyap/delta-go/go/pkg/delta"
 )
@@ -130,3 +131,112 @@ func maxOfSlice(data []float64) float64 {
 	}
 	return maxVal
 }
+
+// setFloatParam sets *dst from params[key] when it holds a number. Parameter grids loaded
+// from JSON carry every number as float64, so ints are accepted too.
+func setFloatParam(params map[string]interface{}, key string, dst *float64) {
+	switch v := params[key].(type) {
+	case float64:
+		*dst = v
+	case int:
+		*dst = float64(v)
+	}
+}
+
+// setIntParam sets *dst from params[key] when it holds an int or a whole float64
+func setIntParam(params map[string]interface{}, key string, dst *int) {
+	switch v := params[key].(type) {
+	case int:
+		*dst = v
+	case float64:
+		if v == math.Trunc(v) {
+			*dst = int(v)
+		}
+	}
+}
+
+// setBoolParam sets *dst from params[key] when it holds a bool
+func setBoolParam(params map[string]interface{}, key string, dst *bool) {
+	if v, ok := params[key].(bool); ok {
+		*dst = v
+	}
+}
+
+// setStringParam sets *dst from params[key] when it holds a string
+func setStringParam(params map[string]interface{}, key string, dst *string) {
+	if v, ok := params[key].(string); ok {
+		*dst = v
+	}
+}
+
+// routeParams splits params among the members of a composite strategy, aligned with members.
+// A "name.key" key goes to the member whose Name is name, with the prefix stripped, and an
+// unprefixed key to the one member that reads it. A key several members read (e.g.
+// "enabled") must be prefixed, so one strategy's setting never reaches the others. Members
+// that don't list their keys (no ParamKeyer) get every unprefixed key.
+func routeParams(params map[string]interface{}, members []Strategy) []map[string]interface{} {
+	routed := make([]map[string]interface{}, len(members))
+	readers := make(map[string][]int)
+	for i, m := range members {
+		routed[i] = make(map[string]interface{})
+		if keyer, ok := m.(ParamKeyer); ok {
+			for _, k := range keyer.ParamKeys() {
+				readers[k] = append(readers[k], i)
+			}
+		}
+	}
+
+	for key, v := range params {
+		if name, sub, ok := strings.Cut(key, "."); ok && routeToNamed(routed, members, name, sub, v) {
+			continue
+		}
+		if r := readers[key]; len(r) == 1 {
+			routed[r[0]][key] = v
+		}
+		for i, m := range members {
+			if _, ok := m.(ParamKeyer); !ok {
+				routed[i][key] = v
+			}
+		}
+	}
+	return routed
+}
+
+// routeToNamed sets key to v for the members named name, reporting whether there were any
+func routeToNamed(routed []map[string]interface{}, members []Strategy, name, key string, v interface{}) bool {
+	found := false
+	for i, m := range members {
+		if m.Name() == name {
+			routed[i][key] = v
+			found = true
+		}
+	}
+	return found
+}
+
+// routedParamKeys lists the keys routeParams passes on to members: "name.key" for every key
+// a member reads, plus the unprefixed keys only one member reads
+func routedParamKeys(members []Strategy) []string {
+	var keys []string
+	readers := make(map[string]int)
+	for _, m := range members {
+		keyer, ok := m.(ParamKeyer)
+		if !ok {
+			continue
+		}
+		for _, k := range keyer.ParamKeys() {
+			keys = append(keys, m.Name()+"."+k)
+			readers[k]++
+		}
+	}
+	for _, m := range members {
+		if keyer, ok := m.(ParamKeyer); ok {
+			for _, k := range keyer.ParamKeys() {
+				if readers[k] == 1 {
+					keys = append(keys, k)
+				}
+			}
+		}
+	}
+	return keys
+}
@@ -0,0 +1,38 @@
+package strategy
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/kasyap/delta-go/go/pkg/delta"
+)
+
+// ErrInsufficientHistory is matched (via errors.Is) by every InsufficientHistoryError
+var ErrInsufficientHistory = errors.New("insufficient history")
+
+// InsufficientHistoryError reports how much history a computation needed vs what it got
+type InsufficientHistoryError struct {
+	Required  int
+	Available int
+}
+
+func (e *InsufficientHistoryError) Error() string {
+	return fmt.Sprintf("insufficient history: need %d, have %d", e.Required, e.Available)
+}
+
+func (e *InsufficientHistoryError) Is(target error) bool {
+	return target == ErrInsufficientHistory
+}
+
+// RequireHistory returns an InsufficientHistoryError if available < required
+func RequireHistory(available, required int) error {
+	if available < required {
+		return &InsufficientHistoryError{Required: required, Available: available}
+	}
+	return nil
+}
+
+// RequireCandles returns an InsufficientHistoryError if there are fewer than n candles
+func RequireCandles(candles []delta.Candle, n int) error {
+	return RequireHistory(len(candles), n)
+}
@@ -0,0 +1,31 @@
+package strategy
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/kasyap/delta-go/go/pkg/delta"
+)
+
+func TestRequireCandles(t *testing.T) {
+	candles := make([]delta.Candle, 12)
+
+	if err := RequireCandles(candles, 12); err != nil {
+		t.Errorf("RequireCandles(12 of 12) error = %v", err)
+	}
+
+	err := RequireCandles(candles, 50)
+	if !errors.Is(err, ErrInsufficientHistory) {
+		t.Fatalf("expected ErrInsufficientHistory, got %v", err)
+	}
+
+	// Counts survive wrapping so callers can report them
+	var histErr *InsufficientHistoryError
+	if !errors.As(fmt.Errorf("regime: %w", err), &histErr) {
+		t.Fatalf("expected InsufficientHistoryError, got %T", err)
+	}
+	if histErr.Required != 50 || histErr.Available != 12 {
+		t.Errorf("counts = need %d have %d, want need 50 have 12", histErr.Required, histErr.Available)
+	}
+}
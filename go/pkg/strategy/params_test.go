@@ -0,0 +1,153 @@
+package strategy
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/kasyap/delta-go/go/pkg/features"
+)
+
+func TestUpdateParams_SetsEveryKey(t *testing.T) {
+	scalper := NewFeeAwareScalper(DefaultScalperConfig(), features.NewEngine())
+	scalper.UpdateParams(map[string]interface{}{
+		"imbalance_threshold":    0.7,
+		"persistence_snapshots":  4.0, // JSON-decoded ints arrive as float64
+		"min_spread_bps":         2.0,
+		"max_spread_bps":         12.0,
+		"target_profit_bps":      25.0,
+		"max_loss_bps":           15.0,
+		"confirmation_price_pct": 0.2,
+		"enabled":                false,
+	})
+	wantScalper := DefaultScalperConfig()
+	wantScalper.ImbalanceThreshold, wantScalper.PersistenceSnapshots = 0.7, 4
+	wantScalper.MinSpreadBps, wantScalper.MaxSpreadBps = 2, 12
+	wantScalper.TargetProfitBps, wantScalper.MaxLossBps = 25, 15
+	wantScalper.ConfirmationPricePct, wantScalper.Enabled = 0.2, false
+	if !reflect.DeepEqual(scalper.cfg, wantScalper) {
+		t.Errorf("scalper cfg = %+v, want %+v", scalper.cfg, wantScalper)
+	}
+
+	funding := NewFundingArbitrageStrategy(DefaultFundingArbitrageConfig())
+	funding.UpdateParams(map[string]interface{}{
+		"entry_threshold":   0.2,
+		"exit_threshold":    0.08,
+		"max_holding_hours": 12,
+		"max_position_pct":  20.0,
+		"price_stop_pct":    1.5,
+		"price_target_pct":  3.0,
+		"enabled":           false,
+	})
+	wantFunding := DefaultFundingArbitrageConfig()
+	wantFunding.EntryThresholdAnnualized, wantFunding.ExitThresholdAnnualized = 0.2, 0.08
+	wantFunding.MaxHoldingHours, wantFunding.MaxPositionPct = 12, 20
+	wantFunding.PriceStopPct, wantFunding.PriceTargetPct = 1.5, 3
+	wantFunding.Enabled = false
+	if !reflect.DeepEqual(funding.cfg, wantFunding) {
+		t.Errorf("funding cfg = %+v, want %+v", funding.cfg, wantFunding)
+	}
+
+	grid := NewGridTradingStrategy(DefaultGridConfig(), "BTCUSD")
+	grid.UpdateParams(map[string]interface{}{
+		"grid_levels":             8,
+		"grid_range":              2.0,
+		"grid_range_up":           3.0,
+		"grid_range_down":         1.0,
+		"grid_spacing":            GridSpacingGeometric,
+		"position_size_per_level": 2.0,
+		"max_volatility_pct":      40.0,
+		"min_volatility_pct":      20.0,
+		"enabled":                 false,
+	})
+	wantGrid := DefaultGridConfig()
+	wantGrid.GridLevels, wantGrid.GridRangePct = 8, 2
+	wantGrid.GridRangeUpPct, wantGrid.GridRangeDownPct = 3, 1
+	wantGrid.GridSpacing, wantGrid.PositionSizePerLevel = GridSpacingGeometric, 2
+	wantGrid.MaxVolatilityPct, wantGrid.MinVolatilityPct = 40, 20
+	wantGrid.Enabled = false
+	if !reflect.DeepEqual(grid.cfg, wantGrid) {
+		t.Errorf("grid cfg = %+v, want %+v", grid.cfg, wantGrid)
+	}
+
+	breakout := NewHighVolBreakoutStrategy(DefaultBreakoutConfig())
+	breakout.UpdateParams(map[string]interface{}{
+		"lookback":           30.0,
+		"volume_multiplier":  2.0,
+		"min_body_ratio":     0.6,
+		"min_imbalance":      0.2,
+		"atr_period":         10,
+		"stop_atr":           1.0,
+		"target_atr":         2.5,
+		"min_historical_vol": 1.2,
+		"enabled":            false,
+	})
+	wantBreakout := BreakoutConfig{
+		Lookback: 30, VolumeMultiplier: 2, MinBodyRatio: 0.6, MinImbalance: 0.2,
+		ATRPeriod: 10, StopATR: 1, TargetATR: 2.5, MinHistoricalVol: 1.2, Enabled: false,
+	}
+	if !reflect.DeepEqual(breakout.cfg, wantBreakout) {
+		t.Errorf("breakout cfg = %+v, want %+v", breakout.cfg, wantBreakout)
+	}
+}
+
+func TestUpdateParams_IgnoresUnknownAndMistypedKeys(t *testing.T) {
+	params := map[string]interface{}{
+		"rsi_oversold":        30.0,
+		"bb_stddev":           2.0,
+		"grid_levels":         7.5,   // Not a whole number
+		"imbalance_threshold": "0.9", // Wrong type
+		"enabled":             "no",
+	}
+
+	scalper := NewFeeAwareScalper(DefaultScalperConfig(), features.NewEngine())
+	funding := NewFundingArbitrageStrategy(DefaultFundingArbitrageConfig())
+	grid := NewGridTradingStrategy(DefaultGridConfig(), "BTCUSD")
+	breakout := NewHighVolBreakoutStrategy(DefaultBreakoutConfig())
+	NewStrategySelector(scalper, funding, grid, breakout).UpdateParams(params)
+
+	if !reflect.DeepEqual(scalper.cfg, DefaultScalperConfig()) {
+		t.Errorf("scalper cfg changed: %+v", scalper.cfg)
+	}
+	if !reflect.DeepEqual(funding.cfg, DefaultFundingArbitrageConfig()) {
+		t.Errorf("funding cfg changed: %+v", funding.cfg)
+	}
+	if !reflect.DeepEqual(grid.cfg, DefaultGridConfig()) {
+		t.Errorf("grid cfg changed: %+v", grid.cfg)
+	}
+	if !reflect.DeepEqual(breakout.cfg, DefaultBreakoutConfig()) {
+		t.Errorf("breakout cfg changed: %+v", breakout.cfg)
+	}
+}
+
+func TestStrategySelector_UpdateParamsRoutesByStrategy(t *testing.T) {
+	scalper := NewFeeAwareScalper(DefaultScalperConfig(), features.NewEngine())
+	funding := NewFundingArbitrageStrategy(DefaultFundingArbitrageConfig())
+	grid := NewGridTradingStrategy(DefaultGridConfig(), "BTCUSD")
+	breakout := NewHighVolBreakoutStrategy(DefaultBreakoutConfig())
+	selector := NewStrategySelector(scalper, funding, grid, breakout)
+
+	selector.UpdateParams(map[string]interface{}{
+		"fee_aware_scalper.enabled": false,
+		"enabled":                   false, // Read by every strategy: ambiguous without a prefix
+		"imbalance_threshold":       0.7,   // Only the scalper reads it
+		"grid_trading.grid_levels":  9,
+	})
+
+	if scalper.cfg.Enabled || scalper.cfg.ImbalanceThreshold != 0.7 {
+		t.Errorf("scalper cfg = %+v, want disabled with imbalance threshold 0.7", scalper.cfg)
+	}
+	if !funding.cfg.Enabled || !grid.cfg.Enabled || !breakout.cfg.Enabled {
+		t.Error("another strategy's enabled=false switched off the rest")
+	}
+	if grid.cfg.GridLevels != 9 {
+		t.Errorf("grid levels = %d, want 9", grid.cfg.GridLevels)
+	}
+
+	keys := make(map[string]bool)
+	for _, k := range selector.ParamKeys() {
+		keys[k] = true
+	}
+	if !keys["fee_aware_scalper.enabled"] || !keys["imbalance_threshold"] || keys["enabled"] {
+		t.Errorf("ParamKeys() = %v, want prefixed keys and only unambiguous bare keys", selector.ParamKeys())
+	}
+}
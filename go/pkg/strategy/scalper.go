@@ -12,10 +12,12 @@ type ScalperConfig struct {
 	PersistenceSnapshots int
 	MinSpreadBps         float64
 	MaxSpreadBps         float64
+	MaxSpreadPercentile  float64 // Skip entries while the spread ranks above this percentile of its recent range (0 = off)
 	TargetProfitBps      float64
 	MaxLossBps           float64
-	ScalpWindowBTC       time.Duration
-	ScalpWindowOther     time.Duration
+	ScalpWindowBTC       time.Duration            // Fallback window for BTC contracts
+	ScalpWindowOther     time.Duration            // Fallback window for all other symbols
+	FeeWindows           map[string]time.Duration // Per-symbol overrides, e.g. "SOLUSD": 10m
 	ConfirmationPricePct float64
 	Enabled              bool
 }
@@ -26,10 +28,12 @@ func DefaultScalperConfig() ScalperConfig {
 		PersistenceSnapshots: 2,
 		MinSpreadBps:         1.0,
 		MaxSpreadBps:         10.0,
+		MaxSpreadPercentile:  90,
 		TargetProfitBps:      20.0,
 		MaxLossBps:           15.0,
 		ScalpWindowBTC:       30 * time.Minute,
 		ScalpWindowOther:     15 * time.Minute,
+		FeeWindows:           map[string]time.Duration{},
 		ConfirmationPricePct: 0.02,
 		Enabled:              true,
 	}
@@ -40,6 +44,7 @@ type FeeAwareScalper struct {
 	ti         *TechnicalIndicators
 	engine     *features.Engine
 	entryTimes map[string]time.Time
+	clock      Clock
 }
 
 func NewFeeAwareScalper(cfg ScalperConfig, engine *features.Engine) *FeeAwareScalper {
@@ -48,23 +53,49 @@ func NewFeeAwareScalper(cfg ScalperConfig, engine *features.Engine) *FeeAwareSca
 		ti:         NewIndicators(),
 		engine:     engine,
 		entryTimes: make(map[string]time.Time),
+		clock:      RealClock{},
 	}
 }
 
+// SetClock sets the clock used for fee-window timing
+func (s *FeeAwareScalper) SetClock(c Clock) {
+	s.clock = c
+}
+
 func (s *FeeAwareScalper) Name() string {
 	return "fee_aware_scalper"
 }
 
+// Reset clears recorded entry times
+func (s *FeeAwareScalper) Reset() {
+	s.entryTimes = make(map[string]time.Time)
+}
+
+// Config returns the scalper's current configuration
+func (s *FeeAwareScalper) Config() ScalperConfig {
+	return s.cfg
+}
+
+// ParamKeys lists the keys UpdateParams reads
+func (s *FeeAwareScalper) ParamKeys() []string {
+	return []string{"imbalance_threshold", "persistence_snapshots", "min_spread_bps", "max_spread_bps",
+		"max_spread_percentile", "target_profit_bps", "max_loss_bps", "confirmation_price_pct", "enabled"}
+}
+
+// UpdateParams keys: imbalance_threshold, persistence_snapshots, min_spread_bps,
+// max_spread_bps, max_spread_percentile, target_profit_bps, max_loss_bps,
+// confirmation_price_pct, enabled.
+// Unknown keys and mistyped values are ignored.
 func (s *FeeAwareScalper) UpdateParams(params map[string]interface{}) {
-	if v, ok := params["imbalance_threshold"].(float64); ok {
-		s.cfg.ImbalanceThreshold = v
-	}
-	if v, ok := params["persistence_snapshots"].(int); ok {
-		s.cfg.PersistenceSnapshots = v
-	}
-	if v, ok := params["enabled"].(bool); ok {
-		s.cfg.Enabled = v
-	}
+	setFloatParam(params, "imbalance_threshold", &s.cfg.ImbalanceThreshold)
+	setIntParam(params, "persistence_snapshots", &s.cfg.PersistenceSnapshots)
+	setFloatParam(params, "min_spread_bps", &s.cfg.MinSpreadBps)
+	setFloatParam(params, "max_spread_bps", &s.cfg.MaxSpreadBps)
+	setFloatParam(params, "max_spread_percentile", &s.cfg.MaxSpreadPercentile)
+	setFloatParam(params, "target_profit_bps", &s.cfg.TargetProfitBps)
+	setFloatParam(params, "max_loss_bps", &s.cfg.MaxLossBps)
+	setFloatParam(params, "confirmation_price_pct", &s.cfg.ConfirmationPricePct)
+	setBoolParam(params, "enabled", &s.cfg.Enabled)
 }
 
 func (s *FeeAwareScalper) Analyze(f features.MarketFeatures, candles []delta.Candle) Signal {
@@ -82,10 +113,13 @@ func (s *FeeAwareScalper) Analyze(f features.MarketFeatures, candles []delta.Can
 	if f.SpreadBps > s.cfg.MaxSpreadBps {
 		return Signal{Action: ActionNone, Reason: "spread too wide"}
 	}
+	if s.cfg.MaxSpreadPercentile > 0 && f.SpreadPercentile > s.cfg.MaxSpreadPercentile {
+		return Signal{Action: ActionNone, Reason: "spread abnormally wide for its recent range"}
+	}
 
 	snapshots := s.engine.GetOBISnapshots()
-	if len(snapshots) < s.cfg.PersistenceSnapshots {
-		return Signal{Action: ActionNone, Reason: "insufficient OBI history"}
+	if err := RequireHistory(len(snapshots), s.cfg.PersistenceSnapshots); err != nil {
+		return Signal{Action: ActionNone, Reason: "insufficient OBI history", Err: err}
 	}
 
 	persistent, direction := s.checkPersistence(snapshots)
@@ -126,7 +160,7 @@ func (s *FeeAwareScalper) Analyze(f features.MarketFeatures, candles []delta.Can
 
 func (s *FeeAwareScalper) checkPersistence(snapshots []features.OBISnapshot) (bool, string) {
 	required := s.cfg.PersistenceSnapshots
-	if len(snapshots) < required {
+	if RequireHistory(len(snapshots), required) != nil {
 		return false, ""
 	}
 
@@ -151,7 +185,7 @@ func (s *FeeAwareScalper) checkPersistence(snapshots []features.OBISnapshot) (bo
 }
 
 func (s *FeeAwareScalper) checkPriceConfirmation(snapshots []features.OBISnapshot, direction string) bool {
-	if len(snapshots) < s.cfg.PersistenceSnapshots {
+	if RequireHistory(len(snapshots), s.cfg.PersistenceSnapshots) != nil {
 		return false
 	}
 
@@ -171,7 +205,11 @@ func (s *FeeAwareScalper) checkPriceConfirmation(snapshots []features.OBISnapsho
 	return priceChange < -s.cfg.ConfirmationPricePct/100
 }
 
+// GetFeeWindow returns the symbol's configured fee window, falling back to the BTC/other defaults
 func (s *FeeAwareScalper) GetFeeWindow(symbol string) time.Duration {
+	if w, ok := s.cfg.FeeWindows[symbol]; ok {
+		return w
+	}
 	if symbol == "BTCUSD" || symbol == "BTCINR" {
 		return s.cfg.ScalpWindowBTC
 	}
@@ -179,7 +217,7 @@ func (s *FeeAwareScalper) GetFeeWindow(symbol string) time.Duration {
 }
 
 func (s *FeeAwareScalper) RecordEntry(symbol string) {
-	s.entryTimes[symbol] = time.Now()
+	s.entryTimes[symbol] = s.clock.Now()
 }
 
 func (s *FeeAwareScalper) RecordExit(symbol string) {
@@ -192,7 +230,7 @@ func (s *FeeAwareScalper) ShouldCloseForFees(symbol string) bool {
 		return false
 	}
 	window := s.GetFeeWindow(symbol)
-	return time.Since(entryTime) < window
+	return s.clock.Now().Sub(entryTime) < window
 }
 
 func (s *FeeAwareScalper) IsEnabled() bool {
@@ -1,6 +1,7 @@
 package strategy
 
 import (
+	"errors"
 	"testing"
 	"time"
 
@@ -23,6 +24,27 @@ func TestFeeAwareScalper_FeeWindow(t *testing.T) {
 	}
 }
 
+func TestFeeAwareScalper_PerSymbolFeeWindow(t *testing.T) {
+	cfg := DefaultScalperConfig()
+	cfg.FeeWindows = map[string]time.Duration{"SOLUSD": 10 * time.Minute}
+	scalper := NewFeeAwareScalper(cfg, nil)
+
+	if w := scalper.GetFeeWindow("SOLUSD"); w != 10*time.Minute {
+		t.Errorf("Expected 10m for SOL, got %v", w)
+	}
+	if w := scalper.GetFeeWindow("BTCUSD"); w != 30*time.Minute {
+		t.Errorf("Expected BTC fallback of 30m, got %v", w)
+	}
+	if w := scalper.GetFeeWindow("XRPUSD"); w != 15*time.Minute {
+		t.Errorf("Expected default of 15m for unknown symbol, got %v", w)
+	}
+
+	scalper.entryTimes["SOLUSD"] = time.Now().Add(-12 * time.Minute)
+	if scalper.ShouldCloseForFees("SOLUSD") {
+		t.Error("Expected SOL fee window to have expired after 12m")
+	}
+}
+
 func TestFeeAwareScalper_EntryExit(t *testing.T) {
 	scalper := NewFeeAwareScalper(DefaultScalperConfig(), nil)
 	symbol := "BTCUSD"
@@ -92,10 +114,18 @@ func TestFeeAwareScalper_AnalyzeWithFeatures(t *testing.T) {
 	}
 	f.SpreadBps = 5.0
 
+	// Spread in the top of its recent range
+	f.SpreadPercentile = 95
+	sig = scalper.Analyze(f, nil)
+	if sig.Action != ActionNone || sig.Reason != "spread abnormally wide for its recent range" {
+		t.Errorf("Expected abnormal spread reason, got %v", sig.Reason)
+	}
+	f.SpreadPercentile = 50
+
 	// 3. Insufficient OBI history
 	sig = scalper.Analyze(f, nil)
-	if sig.Action != ActionNone || sig.Reason != "insufficient OBI history" {
-		t.Errorf("Expected insufficient OBI, got %v", sig.Reason)
+	if sig.Action != ActionNone || !errors.Is(sig.Err, ErrInsufficientHistory) {
+		t.Errorf("Expected insufficient OBI, got %v (%v)", sig.Reason, sig.Err)
 	}
 
 	// 4. Bullish Setup
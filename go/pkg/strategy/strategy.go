@@ -17,6 +17,10 @@ type Signal struct {
 	StopLoss   float64
 	TakeProfit float64
 	Reason     string
+	Err        error // Why no signal could be computed, e.g. an InsufficientHistoryError
+
+	// Bracket tick rounding for this order, delta.BracketRounding* (empty = configured default)
+	BracketRounding string
 }
 
 // Strategy interface for backtest compatibility
@@ -26,11 +30,42 @@ type Strategy interface {
 	UpdateParams(params map[string]interface{})
 }
 
+// Resetter is implemented by strategies that carry state between bars, so one instance
+// can be replayed over the same data (e.g. backtest cost sensitivity runs)
+type Resetter interface {
+	Reset()
+}
+
+// ParamKeyer is implemented by strategies that can list the keys their UpdateParams reads
+type ParamKeyer interface {
+	ParamKeys() []string
+}
+
+// DefaultHistory is how many candles strategies are handed unless one declares more
+const DefaultHistory = 200
+
+// HistoryRequirer is implemented by strategies whose indicators need more than
+// DefaultHistory candles to warm up (e.g. EMA200, long ADX periods)
+type HistoryRequirer interface {
+	RequiredHistory() int
+}
+
+// RequiredHistory returns the candles s needs: its declared requirement, at least DefaultHistory
+func RequiredHistory(s Strategy) int {
+	if hr, ok := s.(HistoryRequirer); ok && hr.RequiredHistory() > DefaultHistory {
+		return hr.RequiredHistory()
+	}
+	return DefaultHistory
+}
+
 // Manager manages multiple strategies for backtest compatibility
 type Manager struct {
 	mu               sync.RWMutex
 	strategies       map[string]Strategy
+	order            []string // registration order, for a deterministic fallback
+	defaultStrategy  string
 	regimeStrategies map[delta.MarketRegime]string
+	regimeFallbacks  map[delta.MarketRegime][]string
 }
 
 // NewManager creates a new strategy manager
@@ -38,6 +73,7 @@ func NewManager() *Manager {
 	return &Manager{
 		strategies:       make(map[string]Strategy),
 		regimeStrategies: make(map[delta.MarketRegime]string),
+		regimeFallbacks:  make(map[delta.MarketRegime][]string),
 	}
 }
 
@@ -45,9 +81,70 @@ func NewManager() *Manager {
 func (m *Manager) RegisterStrategy(s Strategy) {
 	m.mu.Lock()
 	defer m.mu.Unlock()
+	if _, exists := m.strategies[s.Name()]; !exists {
+		m.order = append(m.order, s.Name())
+	}
 	m.strategies[s.Name()] = s
 }
 
+// SetDefaultStrategy sets the strategy used for regimes without a mapping.
+// Without one, the first registered strategy is used.
+func (m *Manager) SetDefaultStrategy(name string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.defaultStrategy = name
+}
+
+// SetClock installs c on every registered strategy that reads a Clock
+func (m *Manager) SetClock(c Clock) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	for _, s := range m.strategies {
+		if cs, ok := s.(ClockSetter); ok {
+			cs.SetClock(c)
+		}
+	}
+}
+
+// UpdateParams routes parameter updates to the registered strategies: "<strategy name>.<key>"
+// reaches only the named strategy, an unprefixed key only the one strategy that reads it
+func (m *Manager) UpdateParams(params map[string]interface{}) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	members := make([]Strategy, 0, len(m.order))
+	for _, name := range m.order {
+		members = append(members, m.strategies[name])
+	}
+	for i, p := range routeParams(params, members) {
+		members[i].UpdateParams(p)
+	}
+}
+
+// RequiredHistory returns the most candles any registered strategy needs, at least
+// DefaultHistory
+func (m *Manager) RequiredHistory() int {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	n := DefaultHistory
+	for _, s := range m.strategies {
+		if h := RequiredHistory(s); h > n {
+			n = h
+		}
+	}
+	return n
+}
+
+// Reset clears the state of every registered strategy that implements Resetter
+func (m *Manager) Reset() {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	for _, s := range m.strategies {
+		if r, ok := s.(Resetter); ok {
+			r.Reset()
+		}
+	}
+}
+
 // SetRegimeStrategy sets which strategy to use for a given regime
 func (m *Manager) SetRegimeStrategy(regime delta.MarketRegime, strategyName string) {
 	m.mu.Lock()
@@ -55,29 +152,46 @@ func (m *Manager) SetRegimeStrategy(regime delta.MarketRegime, strategyName stri
 	m.regimeStrategies[regime] = strategyName
 }
 
-// GetSignal gets a trading signal for the given regime (thread-safe)
+// SetRegimeFallbacks sets strategies to try, in order, when the primary strategy for a
+// regime returns ActionNone
+func (m *Manager) SetRegimeFallbacks(regime delta.MarketRegime, strategyNames ...string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.regimeFallbacks[regime] = append([]string(nil), strategyNames...)
+}
+
+// GetSignal gets a trading signal for the given regime (thread-safe). The regime's mapped
+// strategy (or the default) is asked first; if it returns ActionNone, the regime's fallbacks
+// are tried in order and the first actionable signal wins.
 func (m *Manager) GetSignal(f features.MarketFeatures, candles []delta.Candle) Signal {
 	m.mu.RLock()
-	// Attempt to get strategy for current regime
-	// MarketFeatures likely contains regime info, but for legacy Manager support
-	// we might need to rely on what's passed or default.
-	// Let's assume f.HMMRegime is the key if available, otherwise default.
-	strategyName, ok := m.regimeStrategies[f.HMMRegime]
+	primary, ok := m.regimeStrategies[f.HMMRegime]
 	if !ok {
-		// Fallback: just pick the first one
-		for name := range m.strategies {
-			strategyName = name
-			break
+		primary = m.defaultStrategy
+		if primary == "" && len(m.order) > 0 {
+			primary = m.order[0]
+		}
+	}
+	var chain []Strategy
+	for _, name := range append([]string{primary}, m.regimeFallbacks[f.HMMRegime]...) {
+		if s, exists := m.strategies[name]; exists {
+			chain = append(chain, s)
 		}
 	}
-	strategy, exists := m.strategies[strategyName]
 	m.mu.RUnlock()
 
-	if !exists {
+	if len(chain) == 0 {
 		return Signal{Action: ActionNone, Reason: "no strategy available"}
 	}
 
-	return strategy.Analyze(f, candles)
+	var signal Signal
+	for _, s := range chain {
+		signal = s.Analyze(f, candles)
+		if signal.Action != ActionNone {
+			return signal
+		}
+	}
+	return signal
 }
 
 // SignalAction represents what action to take
@@ -347,6 +461,66 @@ func (ti *TechnicalIndicators) ATRLast(highs, lows, closes []float64, period int
 	return atr
 }
 
+// divergencePivotStrength is the number of bars on each side a swing pivot must dominate
+const divergencePivotStrength = 2
+
+// DetectDivergence looks for regular divergence between price and an oscillator (e.g. RSI)
+// over the last lookback bars (0 = whole series). It compares the two most recent confirmed
+// swing pivots: bullish when price makes a lower low while the oscillator makes a higher low,
+// bearish when price makes a higher high while the oscillator makes a lower high.
+// The window should exclude the oscillator's warm-up bars.
+func (ti *TechnicalIndicators) DetectDivergence(prices, oscillator []float64, lookback int) (bullish, bearish bool) {
+	n := len(prices)
+	if n != len(oscillator) {
+		return false, false
+	}
+	start := 0
+	if lookback > 0 && lookback < n {
+		start = n - lookback
+	}
+	if n-start < 2*divergencePivotStrength+3 {
+		return false, false
+	}
+
+	lows := swingPivots(prices[start:], false)
+	if len(lows) >= 2 {
+		prev, last := start+lows[len(lows)-2], start+lows[len(lows)-1]
+		bullish = prices[last] < prices[prev] && oscillator[last] > oscillator[prev]
+	}
+
+	highs := swingPivots(prices[start:], true)
+	if len(highs) >= 2 {
+		prev, last := start+highs[len(highs)-2], start+highs[len(highs)-1]
+		bearish = prices[last] > prices[prev] && oscillator[last] < oscillator[prev]
+	}
+
+	return bullish, bearish
+}
+
+// swingPivots returns indices of confirmed swing highs (or lows): bars that are the strict
+// extreme of divergencePivotStrength bars on each side. Flat tops count once, at their first bar.
+func swingPivots(series []float64, high bool) []int {
+	k := divergencePivotStrength
+	var pivots []int
+	for i := k; i < len(series)-k; i++ {
+		isPivot := true
+		for j := i - k; j <= i+k && isPivot; j++ {
+			if j == i {
+				continue
+			}
+			if high {
+				isPivot = series[i] > series[j] || (j > i && series[i] == series[j])
+			} else {
+				isPivot = series[i] < series[j] || (j > i && series[i] == series[j])
+			}
+		}
+		if isPivot {
+			pivots = append(pivots, i)
+		}
+	}
+	return pivots
+}
+
 func abs(x float64) float64 {
 	if x < 0 {
 		return -x
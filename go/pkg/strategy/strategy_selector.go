@@ -12,13 +12,15 @@ type StrategySelector struct {
 	scalper    *FeeAwareScalper
 	fundingArb *FundingArbitrageStrategy
 	gridTrader *GridTradingStrategy
+	breakout   *HighVolBreakoutStrategy // nil to never trade breakouts
 }
 
-func NewStrategySelector(scalper *FeeAwareScalper, fundingArb *FundingArbitrageStrategy, gridTrader *GridTradingStrategy) *StrategySelector {
+func NewStrategySelector(scalper *FeeAwareScalper, fundingArb *FundingArbitrageStrategy, gridTrader *GridTradingStrategy, breakout *HighVolBreakoutStrategy) *StrategySelector {
 	return &StrategySelector{
 		scalper:    scalper,
 		fundingArb: fundingArb,
 		gridTrader: gridTrader,
+		breakout:   breakout,
 	}
 }
 
@@ -27,8 +29,9 @@ var debugCounter int
 // SelectBest chooses the best strategy based on objective market data
 // Priority order:
 // 1. Funding Arbitrage (if |basis| > 15% annualized)
-// 2. Grid Trading (if volatility is low < 30% and spread is tight)
-// 3. Fee-Aware Scalper (default fallback)
+// 2. High-Vol Breakout (in high volatility, see InHighVol, with order book confirmation)
+// 3. Grid Trading (if volatility is low < 30% and spread is tight)
+// 4. Fee-Aware Scalper (default fallback)
 func (s *StrategySelector) SelectBest(f features.MarketFeatures, candles []delta.Candle) (string, Signal) {
 	// 1. High Funding Check (Priority 1)
 	if math.Abs(f.BasisAnnualized) > 0.15 {
@@ -38,7 +41,15 @@ func (s *StrategySelector) SelectBest(f features.MarketFeatures, candles []delta
 		}
 	}
 
-	// 2. Ranging Market Check (Priority 2)
+	// 2. High Volatility Breakout (Priority 2)
+	if s.breakout != nil && s.breakout.InHighVol(f) {
+		sig := s.breakout.AnalyzeWithFeatures(f, candles)
+		if sig.Action != ActionNone {
+			return s.breakout.Name(), sig
+		}
+	}
+
+	// 3. Ranging Market Check (Priority 3)
 	// Check if grid trader is active or should be activated
 	if s.gridTrader.IsEnabled() {
 		// Log vol for debugging
@@ -52,7 +63,7 @@ func (s *StrategySelector) SelectBest(f features.MarketFeatures, candles []delta
 		}
 	}
 
-	// 3. Default: Fee-Aware Scalper
+	// 4. Default: Fee-Aware Scalper
 	sig := s.scalper.Analyze(f, candles)
 	return "fee_aware_scalper", sig
 }
@@ -67,9 +78,59 @@ func (s *StrategySelector) Name() string {
 	return "strategy_selector"
 }
 
+// Reset resets the sub-strategies
+func (s *StrategySelector) Reset() {
+	if s.scalper != nil {
+		s.scalper.Reset()
+	}
+	if s.fundingArb != nil {
+		s.fundingArb.Reset()
+	}
+	if s.gridTrader != nil {
+		s.gridTrader.Reset()
+	}
+}
+
+// SetClock installs c on each sub-strategy, so the backtest clock reaches them
+func (s *StrategySelector) SetClock(c Clock) {
+	if s.scalper != nil {
+		s.scalper.SetClock(c)
+	}
+	if s.fundingArb != nil {
+		s.fundingArb.SetClock(c)
+	}
+}
+
+// members returns the configured sub-strategies
+func (s *StrategySelector) members() []Strategy {
+	var members []Strategy
+	if s.scalper != nil {
+		members = append(members, s.scalper)
+	}
+	if s.fundingArb != nil {
+		members = append(members, s.fundingArb)
+	}
+	if s.gridTrader != nil {
+		members = append(members, s.gridTrader)
+	}
+	if s.breakout != nil {
+		members = append(members, s.breakout)
+	}
+	return members
+}
+
+// ParamKeys lists the keys UpdateParams routes, e.g. "fee_aware_scalper.enabled"
+func (s *StrategySelector) ParamKeys() []string {
+	return routedParamKeys(s.members())
+}
+
+// UpdateParams routes params to the sub-strategies: "<strategy name>.<key>" reaches only
+// the named strategy, an unprefixed key only the one sub-strategy that reads it
 func (s *StrategySelector) UpdateParams(params map[string]interface{}) {
-	// Delegate parameter updates to sub-strategies if keys match
-	// For now, empty implementation is sufficient for basic interface compliance
+	members := s.members()
+	for i, p := range routeParams(params, members) {
+		members[i].UpdateParams(p)
+	}
 }
 
 func (s *StrategySelector) GetScalper() *FeeAwareScalper {
@@ -83,3 +144,7 @@ func (s *StrategySelector) GetFundingArb() *FundingArbitrageStrategy {
 func (s *StrategySelector) GetGridTrader() *GridTradingStrategy {
 	return s.gridTrader
 }
+
+func (s *StrategySelector) GetBreakout() *HighVolBreakoutStrategy {
+	return s.breakout
+}
@@ -3,6 +3,7 @@ package strategy
 import (
 	"testing"
 
+	"github.com/kasyap/delta-go/go/pkg/delta"
 	"github.com/kasyap/delta-go/go/pkg/features"
 )
 
@@ -10,7 +11,7 @@ func TestStrategySelector_SelectBest(t *testing.T) {
 	scalper := NewFeeAwareScalper(DefaultScalperConfig(), nil)
 	fundingArb := NewFundingArbitrageStrategy(DefaultFundingArbitrageConfig())
 	gridTrader := NewGridTradingStrategy(DefaultGridConfig(), "")
-	selector := NewStrategySelector(scalper, fundingArb, gridTrader)
+	selector := NewStrategySelector(scalper, fundingArb, gridTrader, nil)
 
 	tests := []struct {
 		name     string
@@ -54,3 +55,39 @@ func TestStrategySelector_SelectBest(t *testing.T) {
 		})
 	}
 }
+
+func TestStrategySelector_TradesBreakoutsInHighVolRegime(t *testing.T) {
+	selector := NewStrategySelector(
+		NewFeeAwareScalper(DefaultScalperConfig(), nil),
+		NewFundingArbitrageStrategy(DefaultFundingArbitrageConfig()),
+		NewGridTradingStrategy(DefaultGridConfig(), ""),
+		NewHighVolBreakoutStrategy(DefaultBreakoutConfig()),
+	)
+	candles := breakoutCandles(true)
+	f := features.MarketFeatures{Symbol: "BTCUSD", HistoricalVol: 0.90, ImbalanceMA: 0.3, HMMRegime: delta.RegimeHighVol}
+
+	name, sig := selector.SelectBest(f, candles)
+	if name != "high_vol_breakout" || sig.Action != ActionBuy {
+		t.Fatalf("SelectBest() = %s %v (%s), want a high_vol_breakout buy", name, sig.Action, sig.Reason)
+	}
+
+	// Outside the high volatility regime, or against the book, the breakout is not taken
+	f.HMMRegime = delta.RegimeBull
+	if name, _ := selector.SelectBest(f, candles); name == "high_vol_breakout" {
+		t.Errorf("SelectBest() in a bull regime = %s", name)
+	}
+	f.HMMRegime, f.ImbalanceMA = delta.RegimeHighVol, -0.3
+	if name, _ := selector.SelectBest(f, candles); name == "high_vol_breakout" {
+		t.Errorf("SelectBest() against the book = %s", name)
+	}
+
+	// Without an HMM regime (backtests) historical volatility decides
+	f.HMMRegime, f.ImbalanceMA = "", 0.3
+	if name, _ := selector.SelectBest(f, candles); name != "high_vol_breakout" {
+		t.Errorf("SelectBest() at %.0f%% historical vol = %s, want a breakout", f.HistoricalVol*100, name)
+	}
+	f.HistoricalVol = 0.40
+	if name, _ := selector.SelectBest(f, candles); name == "high_vol_breakout" {
+		t.Errorf("SelectBest() at %.0f%% historical vol = %s", f.HistoricalVol*100, name)
+	}
+}
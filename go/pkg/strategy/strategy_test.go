@@ -0,0 +1,135 @@
+package strategy
+
+import (
+	"testing"
+
+	"github.com/kasyap/delta-go/go/pkg/delta"
+	"github.com/kasyap/delta-go/go/pkg/features"
+)
+
+func TestDetectDivergenceBullish(t *testing.T) {
+	ti := NewIndicators()
+
+	// Price: swing low at 90, bounce, then a lower low at 85
+	prices := []float64{100, 98, 95, 93, 91, 90, 92, 95, 96, 94, 91, 88, 85, 87, 90, 92}
+	// Oscillator: the second low is higher (momentum fading on the way down)
+	rsi := []float64{50, 45, 38, 32, 27, 22, 30, 40, 44, 40, 36, 32, 30, 36, 42, 48}
+
+	bullish, bearish := ti.DetectDivergence(prices, rsi, 0)
+	if !bullish {
+		t.Error("expected bullish divergence: price lower low, oscillator higher low")
+	}
+	if bearish {
+		t.Error("did not expect bearish divergence")
+	}
+}
+
+func TestDetectDivergenceBearish(t *testing.T) {
+	ti := NewIndicators()
+
+	prices := []float64{100, 102, 105, 107, 109, 110, 108, 105, 104, 106, 109, 112, 115, 113, 110, 108}
+	rsi := []float64{50, 55, 62, 68, 73, 78, 70, 60, 56, 60, 64, 68, 70, 64, 58, 52}
+
+	bullish, bearish := ti.DetectDivergence(prices, rsi, 0)
+	if !bearish {
+		t.Error("expected bearish divergence: price higher high, oscillator lower high")
+	}
+	if bullish {
+		t.Error("did not expect bullish divergence")
+	}
+}
+
+func TestDetectDivergenceNone(t *testing.T) {
+	ti := NewIndicators()
+
+	// Lower lows confirmed by lower oscillator lows: trend continuation, no divergence
+	prices := []float64{100, 98, 95, 93, 91, 90, 92, 95, 96, 94, 91, 88, 85, 87, 90, 92}
+	rsi := []float64{50, 45, 38, 32, 27, 30, 36, 44, 46, 40, 32, 25, 20, 28, 36, 44}
+
+	bullish, bearish := ti.DetectDivergence(prices, rsi, 0)
+	if bullish || bearish {
+		t.Errorf("expected no divergence, got bullish=%v bearish=%v", bullish, bearish)
+	}
+}
+
+func TestDetectDivergenceIgnoresWindowExtremes(t *testing.T) {
+	ti := NewIndicators()
+
+	// A steady decline has its window minimum at the last bar but no swing pivots,
+	// so a min/max-of-window check would be fooled by the oscillator flattening out
+	prices := []float64{100, 99, 98, 97, 96, 95, 94, 93, 92, 91, 90, 89}
+	rsi := []float64{40, 35, 30, 28, 27, 26, 26, 27, 28, 29, 30, 31}
+
+	if bullish, _ := ti.DetectDivergence(prices, rsi, 0); bullish {
+		t.Error("monotonic decline has no pivot lows and should not report divergence")
+	}
+}
+
+func TestDetectDivergenceLookbackAndMismatch(t *testing.T) {
+	ti := NewIndicators()
+
+	prices := []float64{100, 98, 95, 93, 91, 90, 92, 95, 96, 94, 91, 88, 85, 87, 90, 92}
+	rsi := []float64{50, 45, 38, 32, 27, 22, 30, 40, 44, 40, 36, 32, 30, 36, 42, 48}
+
+	// The first swing low falls outside an 8-bar window
+	if bullish, _ := ti.DetectDivergence(prices, rsi, 8); bullish {
+		t.Error("expected no divergence when only one pivot is inside the lookback")
+	}
+	if bullish, bearish := ti.DetectDivergence(prices, rsi[:10], 0); bullish || bearish {
+		t.Error("expected no divergence for mismatched series lengths")
+	}
+}
+
+func TestManager_DeterministicDefault(t *testing.T) {
+	buy := &fixedStrategy{name: "buyer", signal: Signal{Action: ActionBuy, Side: "buy"}}
+	sell := &fixedStrategy{name: "seller", signal: Signal{Action: ActionSell, Side: "sell"}}
+
+	// Without a default the first registered strategy is used, every time
+	m := NewManager()
+	m.RegisterStrategy(buy)
+	m.RegisterStrategy(sell)
+	for i := 0; i < 20; i++ {
+		if sig := m.GetSignal(features.MarketFeatures{}, nil); sig.Action != ActionBuy {
+			t.Fatalf("call %d: Action = %s, want first registered (buy)", i, sig.Action)
+		}
+	}
+
+	m.SetDefaultStrategy("seller")
+	if sig := m.GetSignal(features.MarketFeatures{}, nil); sig.Action != ActionSell {
+		t.Errorf("Action = %s, want default strategy (sell)", sig.Action)
+	}
+
+	// An explicit regime mapping still wins over the default
+	m.SetRegimeStrategy(delta.RegimeBull, "buyer")
+	if sig := m.GetSignal(features.MarketFeatures{HMMRegime: delta.RegimeBull}, nil); sig.Action != ActionBuy {
+		t.Errorf("Action = %s, want regime strategy (buy)", sig.Action)
+	}
+}
+
+func TestManager_RegimeFallbackChain(t *testing.T) {
+	idle := &fixedStrategy{name: "idle", signal: Signal{Action: ActionNone, Reason: "idle"}}
+	alsoIdle := &fixedStrategy{name: "also_idle", signal: Signal{Action: ActionNone, Reason: "also idle"}}
+	sell := &fixedStrategy{name: "seller", signal: Signal{Action: ActionSell, Side: "sell"}}
+
+	m := NewManager()
+	m.RegisterStrategy(idle)
+	m.RegisterStrategy(alsoIdle)
+	m.RegisterStrategy(sell)
+	m.SetRegimeStrategy(delta.RegimeBear, "idle")
+
+	bear := features.MarketFeatures{HMMRegime: delta.RegimeBear}
+	if sig := m.GetSignal(bear, nil); sig.Action != ActionNone {
+		t.Fatalf("Action = %s, want none without fallbacks", sig.Action)
+	}
+
+	m.SetRegimeFallbacks(delta.RegimeBear, "also_idle", "missing", "seller")
+	if sig := m.GetSignal(bear, nil); sig.Action != ActionSell {
+		t.Errorf("Action = %s, want sell from the fallback chain", sig.Action)
+	}
+
+	// When the whole chain is idle, the last strategy's reason is reported
+	m.SetRegimeFallbacks(delta.RegimeBear, "also_idle")
+	if sig := m.GetSignal(bear, nil); sig.Action != ActionNone || sig.Reason != "also idle" {
+		t.Errorf("got %s/%q, want none/%q", sig.Action, sig.Reason, "also idle")
+	}
+}